@@ -17,6 +17,7 @@ func main() {
 		constraint   string
 		testVersions []string
 		description  string
+		options      convert.Options
 	}{
 		// NPM (Node.js)
 		{
@@ -52,6 +53,62 @@ func main() {
 			constraint:   "^1.2.3",
 			testVersions: []string{"1.2.3", "1.3.0", "1.999.999", "2.0.0"},
 			description:  "Composer caret range",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_COMPOSER},
+		},
+		{
+			ecosystem:    "PHP/Composer",
+			constraint:   "~1.2",
+			testVersions: []string{"1.2.0", "1.9.5", "2.0.0"},
+			description:  "Composer tilde range (>=1.2 <2.0, unlike npm)",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_COMPOSER},
+		},
+		{
+			ecosystem:    "PHP/Composer",
+			constraint:   "^1.2@beta || dev-main",
+			testVersions: []string{"1.3.0-beta2", "1.3.0-alpha1", "dev-main", "2.0.0"},
+			description:  "Composer stability flag and branch version",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_COMPOSER},
+		},
+
+		// Rust (Cargo)
+		{
+			ecosystem:    "Rust/Cargo",
+			constraint:   "1.2.3",
+			testVersions: []string{"1.2.3", "1.9.0", "2.0.0", "1.3.0-beta.1"},
+			description:  "Cargo bare version (same as ^1.2.3)",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_CARGO},
+		},
+		{
+			ecosystem:    "Rust/Cargo",
+			constraint:   "^0.0.3",
+			testVersions: []string{"0.0.3", "0.0.4"},
+			description:  "Cargo caret on 0.0.x (exact)",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_CARGO},
+		},
+
+		// Debian and RPM packages
+		{
+			ecosystem:    "Debian",
+			constraint:   ">= 1:2.30-1ubuntu2",
+			testVersions: []string{"1:2.30-1ubuntu2", "1:2.30-1ubuntu10", "1:2.30~rc1-1", "2.40-1"},
+			description:  "dpkg relation with epoch and revision",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_DEBIAN},
+		},
+		{
+			ecosystem:    "RPM",
+			constraint:   ">= 2.30, < 2.31",
+			testVersions: []string{"2.30-1.el8", "2.30^git1-1", "2.30~rc1-1", "2.31-1"},
+			description:  "rpm requirements with releases and ^/~ versions",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_RPM},
+		},
+
+		// Calendar versions
+		{
+			ecosystem:    "CalVer",
+			constraint:   ">=22.04, <24.10",
+			testVersions: []string{"22.04", "24.04", "22.4", "24.10"},
+			description:  "CalVer range for the YY.0M format",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_CALVER, CalVerFormat: "YY.0M"},
 		},
 
 		// Maven (Java)
@@ -92,8 +149,9 @@ func main() {
 		{
 			ecosystem:    "C#/NuGet",
 			constraint:   "1.0.0-alpha",
-			testVersions: []string{"1.0.0-alpha", "1.0.0-beta", "1.0.0"},
+			testVersions: []string{"1.0.0-alpha", "1.0.0-ALPHA", "1.0-alpha", "1.0.0.0-alpha", "1.0.0-beta", "1.0.0"},
 			description:  "C# pre-release version",
+			options:      convert.Options{Ecosystem: convert.ECOSYSTEM_NUGET},
 		},
 
 		// Wildcards (Universal)
@@ -109,7 +167,7 @@ func main() {
 		fmt.Printf("### %s: %s\n", example.ecosystem, example.description)
 		fmt.Printf("Constraint: %s\n", example.constraint)
 
-		regex, err := convert.VersionToRegex(example.constraint)
+		regex, err := convert.VersionToRegexWithOptions(example.constraint, example.options)
 		if err != nil {
 			log.Printf("Error: %v\n", err)
 			continue
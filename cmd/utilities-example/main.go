@@ -84,4 +84,27 @@ func main() {
 			example.shouldMatch,
 			matches)
 	}
+
+	// Example 4: Checking and splitting versions with one match
+	fmt.Println("\n=== Capture Group Examples ===")
+
+	releaseTags, err := convert.VersionToRegexWithOptions(">=1.2.3", convert.Options{CaptureGroups: true})
+	if err != nil {
+		log.Fatalf("Error: %v", err)
+	}
+
+	for _, version := range []string{"1.4.0-rc.1+build.5", "2.0", "1.2.2"} {
+		components, ok := convert.ExtractComponents(releaseTags, version)
+		if !ok {
+			fmt.Printf("  ❌ %s\n", version)
+			continue
+		}
+		fmt.Printf("  ✅ %s → major=%q minor=%q patch=%q prerelease=%q build=%q\n",
+			version,
+			components.Major,
+			components.Minor,
+			components.Patch,
+			components.Prerelease,
+			components.Build)
+	}
 }
@@ -0,0 +1,163 @@
+// Package convert provides security advisory range handling functionality.
+// This file contains functions specific to the vulnerable version ranges of GitHub
+// Security Advisories and npm audit reports:
+//   - comparators are >=, >, <=, < and =, and a bare version means =
+//   - a space may separate an operator from its version: ">= 1.0.0"
+//   - comparators separated by commas or spaces must all hold: ">= 1.0.0, < 1.2.5"
+//     or ">=1.0.0 <1.0.4"
+//   - || separates alternatives: "< 0.3.1 || >= 1.0.0 < 1.0.4"
+//   - * alone affects every version
+//
+// Versions follow SemVer 2.0 with any number of components and an optional v prefix,
+// and every pre-release is compared: "< 1.2.5" includes 1.2.5-rc.1.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// advisoryComparatorRegex reads the next comparator of an alternative, with the
+// spaces and comma that follow it.
+var advisoryComparatorRegex = regexp.MustCompile(`^(>=|<=|>|<|=)?\s*([^\s,<>=|]+)\s*(,\s*)?`)
+
+// advisoryListRegex detects comparators separated by spaces, such as ">=1.0.0 <1.0.4".
+var advisoryListRegex = regexp.MustCompile(`[^\s<>=]\s+[<>=]`)
+
+// isAdvisoryRange reports whether a constraint is a list of comparators, which the
+// single-operator syntax of ECOSYSTEM_AUTO cannot hold. A single comparator with a
+// space after its operator, such as "< 1.2.5", keeps the operator syntax.
+func isAdvisoryRange(versionStr string) bool {
+	return strings.Contains(versionStr, ",") || strings.Contains(versionStr, "||") || advisoryListRegex.MatchString(versionStr)
+}
+
+// parseAdvisoryRange parses a vulnerable version range such as ">= 1.0.0, < 1.2.5".
+//
+// The returned constraint holds the normalized range, with comparators written
+// without spaces and separated by ", ": "< 0.3.1 || >= 1.0.0 < 1.0.4" is returned as
+// "<0.3.1 || >=1.0.0, <1.0.4".
+func parseAdvisoryRange(versionStr string) (*VersionConstraint, error) {
+	alternatives, err := parseAdvisoryAlternatives(versionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, len(alternatives))
+	for i, comparators := range alternatives {
+		normalized[i] = strings.Join(comparators, ", ")
+	}
+
+	return &VersionConstraint{
+		Operator: OP_ADVISORY_RANGE,
+		Version:  strings.Join(normalized, " || "),
+	}, nil
+}
+
+// parseAdvisoryAlternatives splits and validates the alternatives of a range, each
+// being a list of comparators such as ">=1.0.0" or "*".
+func parseAdvisoryAlternatives(versionStr string) ([][]string, error) {
+	if strings.TrimSpace(versionStr) == "" {
+		return nil, fmt.Errorf("empty advisory range")
+	}
+
+	var alternatives [][]string
+	for _, alternative := range strings.Split(versionStr, "||") {
+		alternative = strings.TrimSpace(alternative)
+		if alternative == "*" {
+			alternatives = append(alternatives, []string{"*"})
+			continue
+		}
+		if alternative == "" {
+			return nil, fmt.Errorf("empty alternative in advisory range: %q", versionStr)
+		}
+
+		var comparators []string
+		for rest := alternative; rest != ""; {
+			matches := advisoryComparatorRegex.FindStringSubmatch(rest)
+			if matches == nil {
+				return nil, fmt.Errorf("invalid advisory comparator: %q", rest)
+			}
+			if _, err := parseSemverBound(matches[2]); err != nil {
+				return nil, err
+			}
+			operator := matches[1]
+			if operator == "" {
+				operator = OP_EQUAL
+			}
+			comparators = append(comparators, operator+matches[2])
+
+			rest = rest[len(matches[0]):]
+			if matches[3] != "" && rest == "" {
+				return nil, fmt.Errorf("advisory range ends with a comma: %q", alternative)
+			}
+		}
+		alternatives = append(alternatives, comparators)
+	}
+	return alternatives, nil
+}
+
+// advisoryRanges returns the version ranges allowed by a normalized advisory range.
+func advisoryRanges(versionStr string) ([]versionRange, error) {
+	alternatives, err := parseAdvisoryAlternatives(versionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	order := semverPrereleaseOrder{}
+	var result []versionRange
+	for _, comparators := range alternatives {
+		ranges := []versionRange{{}}
+		for _, comparator := range comparators {
+			if comparator == "*" {
+				continue
+			}
+			operator := comparator[:len(comparator)-len(strings.TrimLeft(comparator, "<>="))]
+			bound, err := parseSemverBound(comparator[len(operator):])
+			if err != nil {
+				return nil, err
+			}
+			exclusive := *bound
+			exclusive.inclusive = false
+
+			var r versionRange
+			switch operator {
+			case OP_GREATER_EQUAL:
+				r.lower = bound
+			case OP_GREATER:
+				r.lower = &exclusive
+			case OP_LESS_EQUAL:
+				r.upper = bound
+			case OP_LESS:
+				r.upper = &exclusive
+			default:
+				r.lower, r.upper = bound, bound
+			}
+			ranges = intersectRanges(ranges, []versionRange{r}, order)
+		}
+		result = append(result, ranges...)
+	}
+	return result, nil
+}
+
+// advisoryRangeRegex creates a regex matching the versions inside an advisory range,
+// with an optional v prefix and build metadata.
+// Result: ^v?(?:0\.(?:[0-2]|3\.0)...|1\.0\.[0-3]...)(?:\+...)?$ matching 0.3.0, 1.0.3 and 1.0.4-rc.1 but not 0.3.1 or 1.0.4 (for "< 0.3.1 || >= 1.0.0 < 1.0.4")
+func advisoryRangeRegex(versionStr string, opts Options) (string, error) {
+	ranges, err := advisoryRanges(versionStr)
+	if err != nil {
+		return "", err
+	}
+	return semverRangesRegex(ranges, opts), nil
+}
+
+// semverRangesRegex creates a regex matching the SemVer versions inside any of the
+// ranges, whatever their number of components, with an optional v prefix and build
+// metadata. Every pre-release is matched, ordered before its release.
+func semverRangesRegex(ranges []versionRange, opts Options) string {
+	pattern, ok := seqBuilder{order: semverPrereleaseOrder{}, zeros: opts.LeadingZeros}.rangesPattern(ranges)
+	if !ok {
+		return NEVER_MATCH_PATTERN
+	}
+	return REGEX_START + "v?" + pattern + SEMVER_BUILD_META_PATTERN + REGEX_END
+}
@@ -0,0 +1,179 @@
+// Package convert provides tests for security advisory range handling functionality.
+// This file contains unit tests for advisory range parsing and regex generation.
+package convert
+
+import (
+	"testing"
+)
+
+// TestParseAdvisoryRange tests parsing and normalization of advisory ranges.
+func TestParseAdvisoryRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{">= 1.0.0, < 1.2.5", false, ">=1.0.0, <1.2.5"},
+		{"< 0.3.1 || >= 1.0.0 < 1.0.4", false, "<0.3.1 || >=1.0.0, <1.0.4"},
+		{"<0.3.1||>=1.0.0 <1.0.4", false, "<0.3.1 || >=1.0.0, <1.0.4"},
+		{"= 1.2.3", false, "=1.2.3"},
+		{"1.2.3", false, "=1.2.3"},
+		{"<= 2.15.2-beta.1", false, "<=2.15.2-beta.1"},
+		{"> v1.0.0", false, ">v1.0.0"},
+		{"*", false, "*"},
+		{"", true, ""},
+		{">= 1.0.0,", true, ""},
+		{"< 0.3.1 ||", true, ""},
+		{"== 1.2.3", true, ""},
+		{"^1.2.3", true, ""},
+		{">= 1.x", true, ""},
+		{">=", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := parseAdvisoryRange(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseAdvisoryRange(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAdvisoryRange(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if constraint.Operator != OP_ADVISORY_RANGE {
+				t.Errorf("expected operator %q, got %q", OP_ADVISORY_RANGE, constraint.Operator)
+			}
+			if constraint.Version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, constraint.Version)
+			}
+		})
+	}
+}
+
+// TestAdvisoryRangeDetection tests that comparator lists are recognized without an ecosystem.
+func TestAdvisoryRangeDetection(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{">= 1.0.0, < 1.2.5", OP_ADVISORY_RANGE},
+		{"< 0.3.1 || >= 1.0.0 < 1.0.4", OP_ADVISORY_RANGE},
+		{">=1.0.0 <2.0.0", OP_ADVISORY_RANGE},
+		{"1.2.3 || 2.0.0", OP_ADVISORY_RANGE},
+		{">= 1.2.3", OP_GREATER_EQUAL},
+		{"< 1.2.5", OP_LESS},
+		{"= 1.2.3", OP_EQUAL},
+		{">=1.2.3", OP_GREATER_EQUAL},
+		{"== 1.2.3", OP_EQUAL_EQUAL},
+		{"[1.0,2.0)", OP_MAVEN_RANGE},
+	}
+
+	for _, tt := range tests {
+		constraint, err := parseVersionConstraint(tt.input)
+		if err != nil {
+			t.Errorf("parseVersionConstraint(%q) returned unexpected error: %v", tt.input, err)
+			continue
+		}
+		if constraint.Operator != tt.expected {
+			t.Errorf("parseVersionConstraint(%q) operator = %q, expected %q", tt.input, constraint.Operator, tt.expected)
+		}
+	}
+}
+
+// TestAdvisoryRangeRegex tests regex generation for advisory ranges.
+func TestAdvisoryRangeRegex(t *testing.T) {
+	tests := []struct {
+		advisoryRange  string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			advisoryRange:  ">= 1.0.0, < 1.2.5",
+			shouldMatch:    []string{"1.0.0", "1.2.4", "1.2.5-rc.1", "v1.1.0", "1.1.0+build.3", "1.2"},
+			shouldNotMatch: []string{"0.9.9", "1.0.0-alpha", "1.2.5", "2.0.0"},
+		},
+		{
+			advisoryRange:  "< 0.3.1 || >= 1.0.0 < 1.0.4",
+			shouldMatch:    []string{"0.3.0", "0.0.1", "1.0.0", "1.0.3", "1.0.4-rc.1"},
+			shouldNotMatch: []string{"0.3.1", "0.9.0", "1.0.4", "2.0.0"},
+		},
+		{
+			advisoryRange:  "< 1.2.5",
+			shouldMatch:    []string{"1.2.5-rc.1", "v1.1.0", "0.0.1", "1.2.4+build.1"},
+			shouldNotMatch: []string{"1.2.5", "v1.2.5", "1.3.0"},
+		},
+		{
+			advisoryRange:  "= 1.2.3",
+			shouldMatch:    []string{"1.2.3", "v1.2.3", "1.2.3+build"},
+			shouldNotMatch: []string{"1.2.4", "1.2.3-rc.1"},
+		},
+		{
+			advisoryRange:  "> 2.0.0-beta.2, <= 2.0.0",
+			shouldMatch:    []string{"2.0.0-beta.10", "2.0.0-rc.1", "2.0.0"},
+			shouldNotMatch: []string{"2.0.0-beta.2", "2.0.0-alpha", "2.0.1"},
+		},
+		{
+			advisoryRange:  "*",
+			shouldMatch:    []string{"0.0.1", "12.4.0-rc.1"},
+			shouldNotMatch: []string{"latest"},
+		},
+		{
+			advisoryRange:  ">= 2.0.0, < 1.0.0",
+			shouldNotMatch: []string{"1.5.0", "2.0.0", "0.9.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.advisoryRange, func(t *testing.T) {
+			ecosystems := []Ecosystem{ECOSYSTEM_ADVISORY}
+			if isAdvisoryRange(tt.advisoryRange) {
+				// Lists of comparators are also detected without an ecosystem
+				ecosystems = append(ecosystems, ECOSYSTEM_AUTO)
+			}
+
+			for _, ecosystem := range ecosystems {
+				regex, err := VersionToRegexWithOptions(tt.advisoryRange, Options{Ecosystem: ecosystem})
+				if err != nil {
+					t.Fatalf("VersionToRegexWithOptions(%q, %q) returned unexpected error: %v", tt.advisoryRange, ecosystem, err)
+				}
+
+				for _, match := range tt.shouldMatch {
+					if !regex.MatchString(match) {
+						t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+					}
+				}
+
+				for _, noMatch := range tt.shouldNotMatch {
+					if regex.MatchString(noMatch) {
+						t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestExplainAdvisoryRange tests the descriptions of advisory ranges.
+func TestExplainAdvisoryRange(t *testing.T) {
+	tests := []struct {
+		advisoryRange string
+		expected      string
+	}{
+		{"< 0.3.1 || >= 1.0.0 < 1.0.4", "versions below 0.3.1 or at least 1.0.0 and below 1.0.4"},
+		{"= 1.2.3", "versions equal to 1.2.3"},
+		{"*", "any version"},
+	}
+
+	for _, tt := range tests {
+		explanation, err := Explain(tt.advisoryRange, Options{Ecosystem: ECOSYSTEM_ADVISORY})
+		if err != nil {
+			t.Errorf("Explain(%q) returned unexpected error: %v", tt.advisoryRange, err)
+			continue
+		}
+		if explanation != tt.expected {
+			t.Errorf("Explain(%q) = %q, expected %q", tt.advisoryRange, explanation, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package convert provides batch conversion of version constraints.
+// This file contains the readers of constraint lists (one constraint per line, CSV
+// or JSON with ids) and the conversion of whole lists by a pool of workers, for
+// inputs such as advisory databases with thousands of constraints.
+package convert
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// BatchFormat selects the syntax of a list of constraints.
+type BatchFormat string
+
+// Supported batch formats
+const (
+	// BATCH_LINES reads one constraint per line; ids are line numbers, and empty
+	// lines and lines starting with # are skipped
+	BATCH_LINES BatchFormat = "lines"
+	// BATCH_CSV reads id,constraint records, with an optional third ecosystem column
+	// and an optional id,constraint[,ecosystem] header; constraints containing
+	// commas, such as Maven ranges, are quoted
+	BATCH_CSV BatchFormat = "csv"
+	// BATCH_JSON reads an array of {"id", "constraint", "ecosystem"} objects, in
+	// which the ecosystem is optional
+	BATCH_JSON BatchFormat = "json"
+)
+
+// BATCH_FORMATS lists the supported batch formats.
+var BATCH_FORMATS = []BatchFormat{BATCH_LINES, BATCH_CSV, BATCH_JSON}
+
+// BatchInput is one constraint of a batch.
+type BatchInput struct {
+	// ID identifies the constraint in the results, such as an advisory id
+	ID string `json:"id"`
+	// Constraint is the version constraint to convert
+	Constraint string `json:"constraint"`
+	// Ecosystem overrides the ecosystem of the conversion options when not empty
+	Ecosystem Ecosystem `json:"ecosystem,omitempty"`
+}
+
+// BatchResult is the conversion of one constraint of a batch: a pattern, or the
+// error that prevented it.
+type BatchResult struct {
+	ID         string `json:"id"`
+	Constraint string `json:"constraint"`
+	Pattern    string `json:"pattern,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ParseBatchFormat returns the batch format with the given name, such as "csv".
+func ParseBatchFormat(name string) (BatchFormat, error) {
+	for _, format := range BATCH_FORMATS {
+		if strings.EqualFold(name, string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported batch format: %s", name)
+}
+
+// ReadBatch reads a list of constraints in the given format.
+//
+// Malformed input, such as invalid JSON or a CSV record with too few fields, is an
+// error for the whole list; invalid constraints are only reported by ConvertBatch,
+// for each input.
+//
+// Parameters:
+//   - r: The list of constraints
+//   - format: The syntax of the list
+//
+// Returns:
+//   - []BatchInput: The constraints with their ids, in the order of the list
+//   - error: Error if the list is malformed or an id is repeated
+//
+// Example:
+//
+//	inputs, err := ReadBatch(strings.NewReader("GHSA-1,>=1.0 <1.2.5\n"), BATCH_CSV)
+//	// inputs: [{ID: "GHSA-1", Constraint: ">=1.0 <1.2.5"}]
+func ReadBatch(r io.Reader, format BatchFormat) ([]BatchInput, error) {
+	var inputs []BatchInput
+	var err error
+	switch format {
+	case BATCH_LINES:
+		inputs, err = readBatchLines(r)
+	case BATCH_CSV:
+		inputs, err = readBatchCSV(r)
+	case BATCH_JSON:
+		inputs, err = readBatchJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported batch format: %s", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(inputs))
+	for _, input := range inputs {
+		if seen[input.ID] {
+			return nil, fmt.Errorf("duplicate batch id: %s", input.ID)
+		}
+		seen[input.ID] = true
+	}
+	return inputs, nil
+}
+
+// readBatchLines reads one constraint per line, identified by its line number.
+func readBatchLines(r io.Reader) ([]BatchInput, error) {
+	var inputs []BatchInput
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		constraint := strings.TrimSpace(scanner.Text())
+		if constraint == "" || strings.HasPrefix(constraint, "#") {
+			continue
+		}
+		inputs = append(inputs, BatchInput{ID: strconv.Itoa(line), Constraint: constraint})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	return inputs, nil
+}
+
+// readBatchCSV reads id,constraint[,ecosystem] records.
+func readBatchCSV(r io.Reader) ([]BatchInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var inputs []BatchInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return inputs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV constraints: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) < 2 || len(record) > 3 {
+			return nil, fmt.Errorf("line %d: expected id,constraint[,ecosystem], got %d fields", line, len(record))
+		}
+		if len(inputs) == 0 && strings.EqualFold(record[0], "id") && strings.EqualFold(record[1], "constraint") {
+			// Header
+			continue
+		}
+
+		input := BatchInput{ID: strings.TrimSpace(record[0]), Constraint: strings.TrimSpace(record[1])}
+		if len(record) == 3 {
+			input.Ecosystem = Ecosystem(strings.TrimSpace(record[2]))
+		}
+		if input.ID == "" {
+			return nil, fmt.Errorf("line %d: empty id", line)
+		}
+		inputs = append(inputs, input)
+	}
+}
+
+// readBatchJSON reads an array of constraint objects.
+func readBatchJSON(r io.Reader) ([]BatchInput, error) {
+	var inputs []BatchInput
+	if err := json.NewDecoder(r).Decode(&inputs); err != nil {
+		return nil, fmt.Errorf("failed to read JSON constraints: %w", err)
+	}
+	for i, input := range inputs {
+		if input.ID == "" {
+			return nil, fmt.Errorf("constraint %d has no id", i)
+		}
+	}
+	return inputs, nil
+}
+
+// ConvertBatch converts a list of constraints to patterns in the syntax of dialect,
+// using a pool of workers.
+//
+// Every input gets a result, in the order of inputs, holding either its pattern or
+// the error that prevented it, so one invalid constraint does not stop the others.
+// Inputs with an ecosystem are converted with that ecosystem instead of opts.Ecosystem.
+//
+// Parameters:
+//   - inputs: The constraints to convert
+//   - dialect: The regex syntax of the patterns
+//   - opts: Conversion options shared by every constraint
+//   - workers: The number of conversions run in parallel; 0 or less uses one per CPU
+//
+// Returns:
+//   - []BatchResult: The pattern or error of each input
+//
+// Example:
+//
+//	results := ConvertBatch([]BatchInput{{ID: "1", Constraint: "^1.2"}, {ID: "2", Constraint: ">=abc"}}, DIALECT_GO, Options{}, 0)
+//	// results[0].Pattern: ^1\.(?:0|[1-9]\d*)\.(?:0|[1-9]\d*)..., results[1].Error: failed to convert to regex: ...
+func ConvertBatch(inputs []BatchInput, dialect Dialect, opts Options, workers int) []BatchResult {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, len(inputs))
+
+	results := make([]BatchResult, len(inputs))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = convertBatchInput(inputs[i], dialect, opts)
+			}
+		}()
+	}
+	for i := range inputs {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// convertBatchInput converts one constraint of a batch.
+func convertBatchInput(input BatchInput, dialect Dialect, opts Options) BatchResult {
+	result := BatchResult{ID: input.ID, Constraint: input.Constraint}
+	if input.Ecosystem != "" {
+		opts.Ecosystem = input.Ecosystem
+	}
+
+	pattern, err := Pattern(input.Constraint, dialect, opts)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Pattern = pattern
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+// Package convert provides tests for batch conversion.
+// This file checks the readers of constraint lists and the conversion of whole lists.
+package convert
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestReadBatch tests the readers of each batch format.
+func TestReadBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		format   BatchFormat
+		expected []BatchInput
+	}{
+		{
+			name:     "lines",
+			input:    "^1.2.3\n\n# comment\n  >=2.0  \n",
+			format:   BATCH_LINES,
+			expected: []BatchInput{{ID: "1", Constraint: "^1.2.3"}, {ID: "4", Constraint: ">=2.0"}},
+		},
+		{
+			name:   "CSV with header",
+			input:  "id,constraint,ecosystem\nGHSA-1,\">=1.0, <1.2.5\",cargo\nGHSA-2,~1.2\n",
+			format: BATCH_CSV,
+			expected: []BatchInput{
+				{ID: "GHSA-1", Constraint: ">=1.0, <1.2.5", Ecosystem: ECOSYSTEM_CARGO},
+				{ID: "GHSA-2", Constraint: "~1.2"},
+			},
+		},
+		{
+			name:     "CSV without header",
+			input:    "a, \"[1.0,2.0)\"\n",
+			format:   BATCH_CSV,
+			expected: []BatchInput{{ID: "a", Constraint: "[1.0,2.0)"}},
+		},
+		{
+			name:   "JSON",
+			input:  `[{"id": "x", "constraint": "~> 2.1", "ecosystem": "ruby"}, {"id": "y", "constraint": "1.*"}]`,
+			format: BATCH_JSON,
+			expected: []BatchInput{
+				{ID: "x", Constraint: "~> 2.1", Ecosystem: ECOSYSTEM_RUBY},
+				{ID: "y", Constraint: "1.*"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inputs, err := ReadBatch(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("ReadBatch returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(inputs, tt.expected) {
+				t.Errorf("ReadBatch = %+v, expected %+v", inputs, tt.expected)
+			}
+		})
+	}
+}
+
+// TestReadBatchErrors tests malformed constraint lists.
+func TestReadBatchErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		format BatchFormat
+	}{
+		{"unknown format", "1.2.3", BatchFormat("yaml")},
+		{"CSV record without constraint", "GHSA-1\n", BATCH_CSV},
+		{"CSV record with extra fields", "a,1.2.3,cargo,x\n", BATCH_CSV},
+		{"CSV empty id", ",1.2.3\n", BATCH_CSV},
+		{"duplicate id", "a,1.2.3\na,1.2.4\n", BATCH_CSV},
+		{"invalid JSON", `[{"id": "a"`, BATCH_JSON},
+		{"JSON object without id", `[{"constraint": "1.2.3"}]`, BATCH_JSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if inputs, err := ReadBatch(strings.NewReader(tt.input), tt.format); err == nil {
+				t.Errorf("ReadBatch = %+v, expected an error", inputs)
+			}
+		})
+	}
+}
+
+// TestConvertBatch tests that every input gets its pattern or error, in order.
+func TestConvertBatch(t *testing.T) {
+	inputs := []BatchInput{
+		{ID: "caret", Constraint: "^1.2.3"},
+		{ID: "invalid", Constraint: ">=abc"},
+		{ID: "ruby", Constraint: "~> 2.1, >= 2.1.3", Ecosystem: ECOSYSTEM_RUBY},
+		{ID: "unknown ecosystem", Constraint: "1.2.3", Ecosystem: Ecosystem("conda")},
+	}
+	for i := range 100 {
+		inputs = append(inputs, BatchInput{ID: fmt.Sprint(i), Constraint: fmt.Sprintf("~1.%d", i)})
+	}
+
+	for _, workers := range []int{0, 1, 7} {
+		results := ConvertBatch(inputs, DIALECT_POSIX_ERE, Options{}, workers)
+		if len(results) != len(inputs) {
+			t.Fatalf("ConvertBatch with %d workers returned %d results, expected %d", workers, len(results), len(inputs))
+		}
+
+		for i, result := range results {
+			input := inputs[i]
+			if result.ID != input.ID || result.Constraint != input.Constraint {
+				t.Errorf("result %d is for %s %q, expected %s %q", i, result.ID, result.Constraint, input.ID, input.Constraint)
+			}
+
+			opts := Options{Ecosystem: input.Ecosystem}
+			expected, err := Pattern(input.Constraint, DIALECT_POSIX_ERE, opts)
+			switch {
+			case err != nil && (result.Error != err.Error() || result.Pattern != ""):
+				t.Errorf("result %s = %+v, expected error %q", input.ID, result, err)
+			case err == nil && (result.Pattern != expected || result.Error != ""):
+				t.Errorf("result %s = %+v, expected pattern %q", input.ID, result, expected)
+			}
+		}
+	}
+
+	results := ConvertBatch(inputs[:4], DIALECT_GO, Options{}, 2)
+	if results[1].Error == "" || results[3].Error == "" {
+		t.Errorf("invalid inputs should have errors: %+v, %+v", results[1], results[3])
+	}
+	if results := ConvertBatch(nil, DIALECT_GO, Options{}, 4); len(results) != 0 {
+		t.Errorf("ConvertBatch(nil) = %+v, expected no results", results)
+	}
+}
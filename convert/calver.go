@@ -0,0 +1,397 @@
+// Package convert provides calendar versioning (CalVer) functionality.
+// This file contains functions specific to CalVer constraints, where versions follow
+// a format spec built from the calver.org conventions:
+//   - YYYY (2024), YY (24, 106) and 0Y (06, 24) for years, YY and 0Y counting from 2000
+//   - MM (1-12) and 0M (01-12) for months
+//   - WW (1-53) and 0W (01-53) for weeks
+//   - DD (1-31) and 0D (01-31) for days
+//   - MAJOR, MINOR and MICRO for plain numbers
+//
+// joined by '.', '-' or '_', such as YYYY.MM.MICRO or YY.0M. Versions must have every
+// component of the format, with the right padding and within range, and may end with
+// a modifier starting with a letter (2023.3.post1, 24.04-rc1), which is ignored when
+// comparing versions.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CALVER_MODIFIER_PATTERN matches the optional modifier at the end of a CalVer
+// version, such as .post1, -rc1 or b2.
+const CALVER_MODIFIER_PATTERN = `(?:[-._+]?[A-Za-z][0-9A-Za-z._+-]*)?`
+
+// calverFormatRegex splits a CalVer format spec into components and separators.
+var calverFormatRegex = regexp.MustCompile(`^(YYYY|YY|0Y|MM|0M|WW|0W|DD|0D|MAJOR|MINOR|MICRO)([-._]?)`)
+
+// calverOperatorRegex splits a CalVer comparison into operator and version.
+var calverOperatorRegex = regexp.MustCompile(`^(==|!=|>=|<=|=|>|<)?\s*(\S+)$`)
+
+// calverComponent describes one component of a CalVer format.
+type calverComponent struct {
+	name string
+	// min and max limit the values of the component; a negative max is unbounded
+	min, max int
+	// width is the number of digits values are padded to with zeros
+	width int
+}
+
+// calverComponents lists the supported CalVer components.
+var calverComponents = map[string]calverComponent{
+	"YYYY":  {name: "YYYY", min: 1000, max: 9999, width: 4},
+	"YY":    {name: "YY", min: 0, max: -1, width: 1},
+	"0Y":    {name: "0Y", min: 0, max: -1, width: 2},
+	"MM":    {name: "MM", min: 1, max: 12, width: 1},
+	"0M":    {name: "0M", min: 1, max: 12, width: 2},
+	"WW":    {name: "WW", min: 1, max: 53, width: 1},
+	"0W":    {name: "0W", min: 1, max: 53, width: 2},
+	"DD":    {name: "DD", min: 1, max: 31, width: 1},
+	"0D":    {name: "0D", min: 1, max: 31, width: 2},
+	"MAJOR": {name: "MAJOR", min: 0, max: -1, width: 1},
+	"MINOR": {name: "MINOR", min: 0, max: -1, width: 1},
+	"MICRO": {name: "MICRO", min: 0, max: -1, width: 1},
+}
+
+// calverFormat is a parsed CalVer format spec.
+type calverFormat struct {
+	components []calverComponent
+	// separators holds the text before each component, "" for the first one
+	separators []string
+}
+
+// parseCalVerFormat parses a format spec such as "YYYY.MM.MICRO" or "YY.0M".
+func parseCalVerFormat(spec string) (calverFormat, error) {
+	if spec == "" {
+		return calverFormat{}, fmt.Errorf("CalVer mode requires a format such as YYYY.MM.MICRO")
+	}
+
+	var format calverFormat
+	separator := ""
+	for rest := spec; rest != ""; {
+		matches := calverFormatRegex.FindStringSubmatch(rest)
+		if matches == nil || (separator == "" && len(format.components) > 0) {
+			return calverFormat{}, fmt.Errorf("invalid CalVer format: %s", spec)
+		}
+		format.components = append(format.components, calverComponents[matches[1]])
+		format.separators = append(format.separators, separator)
+		separator = matches[2]
+		rest = rest[len(matches[0]):]
+	}
+	if separator != "" {
+		return calverFormat{}, fmt.Errorf("invalid CalVer format: %s", spec)
+	}
+	return format, nil
+}
+
+// String returns the format spec.
+func (f calverFormat) String() string {
+	var spec strings.Builder
+	for i, component := range f.components {
+		spec.WriteString(f.separators[i] + component.name)
+	}
+	return spec.String()
+}
+
+// parseVersion parses a version, or the leading components of one, into component
+// values. It checks the padding and range of every component.
+func (f calverFormat) parseVersion(version string) ([]int, error) {
+	var parts []int
+	rest := version
+	for i, component := range f.components {
+		if i > 0 {
+			if rest == "" {
+				break
+			}
+			if !strings.HasPrefix(rest, f.separators[i]) {
+				return nil, fmt.Errorf("invalid CalVer version %s for format %s", version, f)
+			}
+			rest = rest[len(f.separators[i]):]
+		}
+
+		end := 0
+		for end < len(rest) && isDigit(rest[end]) {
+			end++
+		}
+		value, err := component.parse(rest[:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid CalVer version %s: %w", version, err)
+		}
+		parts = append(parts, value)
+		rest = rest[end:]
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("invalid CalVer version %s for format %s", version, f)
+	}
+	return parts, nil
+}
+
+// parse checks the spelling of a component value and returns it.
+func (c calverComponent) parse(digits string) (int, error) {
+	if digits == "" {
+		return 0, fmt.Errorf("missing %s component", c.name)
+	}
+	if len(digits) < c.width || (len(digits) > c.width && digits[0] == '0') {
+		return 0, fmt.Errorf("%s component %s must have %d digits without extra leading zeros", c.name, digits, c.width)
+	}
+
+	value, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, fmt.Errorf("%s component %s: %w", c.name, digits, err)
+	}
+	if value < c.min || (c.max >= 0 && value > c.max) {
+		return 0, fmt.Errorf("%s component %s is out of range", c.name, digits)
+	}
+	return value, nil
+}
+
+// pattern matches the values of the component between lo and hi, as spelled in
+// versions. A negative hi means no upper limit.
+func (c calverComponent) pattern(lo, hi int) string {
+	lo = max(lo, c.min)
+	if c.max >= 0 && (hi < 0 || hi > c.max) {
+		hi = c.max
+	}
+
+	var patterns []string
+	limit := 1
+	for i := 0; i < c.width; i++ {
+		limit *= 10
+	}
+
+	// Values written with exactly width digits, zero-padded
+	if top := limit - 1; lo <= top {
+		if hi >= 0 && hi < top {
+			top = hi
+		}
+		if lo <= top {
+			padding := "%0" + strconv.Itoa(c.width) + "d"
+			patterns = append(patterns, sameLengthRange(fmt.Sprintf(padding, lo), fmt.Sprintf(padding, top))...)
+		}
+	}
+
+	// Longer values, without leading zeros
+	from := max(lo, limit)
+	switch {
+	case hi < 0:
+		digits := len(strconv.Itoa(from))
+		patterns = append(patterns, sameLengthRange(strconv.Itoa(from), strings.Repeat("9", digits))...)
+		patterns = append(patterns, fmt.Sprintf(`[1-9]\d{%d,}`, digits))
+	case from <= hi:
+		patterns = append(patterns, LEADING_ZEROS_REJECT.Range(strconv.Itoa(from), strconv.Itoa(hi)))
+	}
+
+	if len(patterns) == 0 {
+		return NEVER_MATCH
+	}
+	return joinPatterns(patterns)
+}
+
+// lowest completes the leading components parts with the smallest values of the
+// other components.
+func (f calverFormat) lowest(parts []int) []int {
+	full := append([]int{}, parts...)
+	for _, component := range f.components[len(parts):] {
+		full = append(full, component.min)
+	}
+	return full
+}
+
+// next returns the smallest full version after every version starting with parts,
+// or nil when there is none.
+func (f calverFormat) next(parts []int) []int {
+	next := append([]int{}, parts...)
+	for i := len(next) - 1; i >= 0; i-- {
+		component := f.components[i]
+		if next[i]++; component.max < 0 || next[i] <= component.max {
+			return f.lowest(next[:i+1])
+		}
+		next[i] = component.min
+	}
+	return nil
+}
+
+// parseCalVerConstraint parses a CalVer constraint such as ">=2024.10, <2025" for the
+// format given in the options.
+//
+// The whole constraint is validated, and the returned constraint holds it with
+// surrounding whitespace removed.
+func parseCalVerConstraint(versionStr string, opts Options) (*VersionConstraint, error) {
+	versionStr = strings.TrimSpace(versionStr)
+	format, err := parseCalVerFormat(opts.CalVerFormat)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := format.parseConstraint(versionStr); err != nil {
+		return nil, err
+	}
+
+	return &VersionConstraint{
+		Operator: OP_CALVER_CONSTRAINT,
+		Version:  versionStr,
+	}, nil
+}
+
+// calverConstraintRegex creates a regex matching the versions of the CalVer format
+// given in the options that satisfy a constraint.
+// Result: ^(?:2024\.(?:1[0-2])\.(?:\d|[1-9]\d{1,})|...)CALVER_MODIFIER$ matching 2024.10.0 and 2025.1.3 but not 2024.9.9 (for ">=2024.10" with YYYY.MM.MICRO)
+func calverConstraintRegex(constraint string, opts Options) (string, error) {
+	format, err := parseCalVerFormat(opts.CalVerFormat)
+	if err != nil {
+		return "", err
+	}
+	ranges, err := format.parseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	var alternatives []string
+	for _, r := range ranges {
+		var lower, upper []int
+		if r.lower != nil {
+			lower = calverValues(r.lower.parts)
+		}
+		if r.upper != nil {
+			upper = calverValues(r.upper.parts)
+		}
+		alternatives = append(alternatives, format.rangePattern(0, lower, upper)...)
+	}
+
+	pattern, ok := groupAlternatives(alternatives)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + CALVER_MODIFIER_PATTERN + REGEX_END, nil
+}
+
+// parseConstraint returns the ranges of versions allowed by a constraint, where
+// || separates alternatives and commas separate comparisons that must all hold.
+//
+// Ranges always have an inclusive lower bound and an exclusive upper bound with every
+// component, so 2024.10 in ">2024.10" becomes ">=2024.11.0" for YYYY.MM.MICRO.
+func (f calverFormat) parseConstraint(constraint string) ([]versionRange, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return nil, fmt.Errorf("empty CalVer constraint")
+	}
+
+	// CalVer bounds have no suffix, so any suffix order works for intersections
+	order := semverPrereleaseOrder{releasesOnly: true}
+	var result []versionRange
+	for _, alternative := range strings.Split(constraint, "||") {
+		ranges := []versionRange{{}}
+		for _, comparison := range strings.Split(alternative, ",") {
+			allowed, err := f.parseComparison(strings.TrimSpace(comparison))
+			if err != nil {
+				return nil, err
+			}
+			ranges = intersectRanges(ranges, allowed, order)
+		}
+		result = append(result, ranges...)
+	}
+	return result, nil
+}
+
+// parseComparison returns the ranges allowed by one comparison such as ">=2024.10".
+// A version without operator means ==, and may end with .* (2024.*).
+func (f calverFormat) parseComparison(comparison string) ([]versionRange, error) {
+	matches := calverOperatorRegex.FindStringSubmatch(comparison)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid CalVer comparison: %q", comparison)
+	}
+	operator, version := matches[1], matches[2]
+
+	if prefix, ok := strings.CutSuffix(version, ".*"); ok {
+		if operator != "" && operator != OP_EQUAL && operator != OP_EQUAL_EQUAL {
+			return nil, fmt.Errorf("wildcard version %s cannot be used with %s", version, operator)
+		}
+		version = prefix
+	}
+	parts, err := f.parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	// The versions starting with parts lie in [first, next)
+	first := decimalParts(f.lowest(parts))
+	below := []versionRange{{upper: &rangeBound{parts: first}}}
+	var next *rangeBound
+	var above []versionRange
+	if values := f.next(parts); values != nil {
+		parts := decimalParts(values)
+		next = &rangeBound{parts: parts}
+		above = []versionRange{{lower: &rangeBound{parts: parts, inclusive: true}}}
+	}
+
+	switch operator {
+	case OP_GREATER_EQUAL:
+		return []versionRange{{lower: &rangeBound{parts: first, inclusive: true}}}, nil
+	case OP_GREATER:
+		return above, nil
+	case OP_LESS:
+		return below, nil
+	case OP_LESS_EQUAL:
+		return []versionRange{{upper: next}}, nil
+	case OP_NOT_EQUAL:
+		return append(below, above...), nil
+	default:
+		return []versionRange{{lower: &rangeBound{parts: first, inclusive: true}, upper: next}}, nil
+	}
+}
+
+// calverValues converts the components of a range bound back to the component values
+// they were built from.
+func calverValues(parts []string) []int {
+	values := make([]int, len(parts))
+	for i, part := range parts {
+		values[i], _ = strconv.Atoi(part)
+	}
+	return values
+}
+
+// rangePattern returns alternatives for the components from index i on of the
+// versions between lower (inclusive) and upper (exclusive), knowing that the
+// components before i equal those of every non-nil bound.
+func (f calverFormat) rangePattern(i int, lower, upper []int) []string {
+	if i == len(f.components) {
+		// The version equals the bounds, which only the lower bound allows
+		if upper != nil {
+			return nil
+		}
+		return []string{""}
+	}
+
+	component := f.components[i]
+	separator := regexp.QuoteMeta(f.separators[i])
+	var alternatives []string
+	add := func(pattern string, rest []string) {
+		if tail, ok := groupAlternatives(rest); ok && pattern != NEVER_MATCH {
+			alternatives = append(alternatives, separator+pattern+tail)
+		}
+	}
+
+	switch {
+	case lower == nil && upper == nil:
+		add(component.pattern(component.min, -1), f.rangePattern(i+1, nil, nil))
+	case lower != nil && upper != nil && lower[i] == upper[i]:
+		add(component.pattern(lower[i], lower[i]), f.rangePattern(i+1, lower, upper))
+	default:
+		from, to := component.min, -1
+		if lower != nil {
+			from = lower[i] + 1
+			add(component.pattern(lower[i], lower[i]), f.rangePattern(i+1, lower, nil))
+		}
+		if upper != nil {
+			to = upper[i] - 1
+		}
+		if to >= 0 || upper == nil {
+			add(component.pattern(from, to), f.rangePattern(i+1, nil, nil))
+		}
+		if upper != nil {
+			add(component.pattern(upper[i], upper[i]), f.rangePattern(i+1, nil, upper))
+		}
+	}
+	return alternatives
+}
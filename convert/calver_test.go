@@ -0,0 +1,225 @@
+// Package convert provides tests for calendar versioning (CalVer) functionality.
+// This file contains unit tests for CalVer formats, version validation and
+// constraint regexes.
+package convert
+
+import (
+	"testing"
+)
+
+// TestParseCalVerFormat tests parsing of CalVer format specs.
+func TestParseCalVerFormat(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr bool
+	}{
+		{"YYYY.MM.MICRO", false},
+		{"YY.0M", false},
+		{"YYYY-0M-0D", false},
+		{"0Y_0W.MICRO", false},
+		{"MAJOR.YYYY.MINOR", false},
+		{"", true},
+		{"YYYY.", true},
+		{"YYYYMM", true},
+		{"YYYY.MONTH", true},
+		{"YYYY..MM", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			format, err := parseCalVerFormat(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseCalVerFormat(%q) expected error but got none", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCalVerFormat(%q) returned unexpected error: %v", tt.spec, err)
+			}
+			if format.String() != tt.spec {
+				t.Errorf("expected format %q, got %q", tt.spec, format.String())
+			}
+		})
+	}
+}
+
+// TestCalVerVersionValidation tests the validation of constraint versions.
+func TestCalVerVersionValidation(t *testing.T) {
+	tests := []struct {
+		format     string
+		constraint string
+		wantErr    bool
+	}{
+		{"YYYY.MM.MICRO", ">=2024.10.1", false},
+		{"YYYY.MM.MICRO", ">=2024.10", false},
+		{"YYYY.MM.MICRO", "2024.*", false},
+		{"YYYY.MM.MICRO", ">=2024.13", true},
+		{"YYYY.MM.MICRO", ">=2024.0", true},
+		{"YYYY.MM.MICRO", ">=2024.01", true},
+		{"YYYY.MM.MICRO", ">=24.1", true},
+		{"YYYY.MM.MICRO", ">=2024.1.01", true},
+		{"YYYY.MM.MICRO", ">=2024.1.1.1", true},
+		{"YYYY.MM.MICRO", ">=2023.3.post1", true},
+		{"YYYY.MM.MICRO", ">=2024.*", true},
+		{"YY.0M", ">=24.04", false},
+		{"YY.0M", ">=24.4", true},
+		{"YY.0M", ">=024.04", true},
+		{"YY.0M", "24.13", true},
+		{"YYYY-0M-0D", "<2024-02-31", false},
+		{"YYYY-0M-0D", "<2024-02-32", true},
+		{"YYYY-0M-0D", "<2024.02.01", true},
+		{"YYYY.MM", "", true},
+		{"", ">=2024.1", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.constraint, func(t *testing.T) {
+			_, err := VersionToRegexWithOptions(tt.constraint, Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: tt.format})
+			if tt.wantErr && err == nil {
+				t.Errorf("expected error for %q with format %q but got none", tt.constraint, tt.format)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("unexpected error for %q with format %q: %v", tt.constraint, tt.format, err)
+			}
+		})
+	}
+}
+
+// TestCalVerConstraintRegex tests CalVer constraints on typical versions.
+func TestCalVerConstraintRegex(t *testing.T) {
+	tests := []struct {
+		format         string
+		constraint     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			format:         "YYYY.MM.MICRO",
+			constraint:     ">=2024.10.1",
+			shouldMatch:    []string{"2024.10.1", "2024.11.0", "2025.1.0", "2024.10.1.post1"},
+			shouldNotMatch: []string{"2024.10.0", "2024.9.9", "2024.010.1", "2024.13.0", "2025.01.0", "2024.10"},
+		},
+		{
+			format:         "YYYY.MM.MICRO",
+			constraint:     ">2024.10",
+			shouldMatch:    []string{"2024.11.0", "2025.1.3"},
+			shouldNotMatch: []string{"2024.10.7", "2024.9.0"},
+		},
+		{
+			format:         "YYYY.MM.MICRO",
+			constraint:     ">=2024.12, <2025.2",
+			shouldMatch:    []string{"2024.12.0", "2025.1.15"},
+			shouldNotMatch: []string{"2024.11.3", "2025.2.0", "2024.13.0"},
+		},
+		{
+			format:         "YYYY.MM",
+			constraint:     "==2023.3",
+			shouldMatch:    []string{"2023.3", "2023.3.post1", "2023.3rc1"},
+			shouldNotMatch: []string{"2023.4", "2023.03", "2023.3.1"},
+		},
+		{
+			format:         "YY.0M",
+			constraint:     ">=22.04, <24.10",
+			shouldMatch:    []string{"22.04", "22.10", "24.04"},
+			shouldNotMatch: []string{"22.4", "20.04", "24.10", "24.13", "24.00"},
+		},
+		{
+			format:         "YY.0M",
+			constraint:     "!=23.10",
+			shouldMatch:    []string{"23.04", "24.04", "105.01"},
+			shouldNotMatch: []string{"23.10", "23.1"},
+		},
+		{
+			format:         "YYYY-0M-0D",
+			constraint:     "<=2024-02",
+			shouldMatch:    []string{"2024-02-29", "2023-12-31", "1999-01-01"},
+			shouldNotMatch: []string{"2024-03-01", "2024-2-01", "2024-02-32"},
+		},
+		{
+			format:         "YYYY.MM",
+			constraint:     ">2024.12, <2025.1",
+			shouldNotMatch: []string{"2024.12", "2025.1"},
+		},
+		{
+			format:         "YYYY.MM",
+			constraint:     "<2024.1 || >=2025.6",
+			shouldMatch:    []string{"2023.12", "2025.6", "2026.1"},
+			shouldNotMatch: []string{"2024.1", "2025.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+" "+tt.constraint, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.constraint, Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: tt.format})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
+
+// TestCalVerConstraintRegexOrdering compares CalVer regexes with component-wise
+// comparison over every version of a small format.
+func TestCalVerConstraintRegexOrdering(t *testing.T) {
+	format, err := parseCalVerFormat("0Y.MM.MICRO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []string
+	for _, year := range []string{"00", "09", "23", "99", "100", "123"} {
+		for _, month := range []string{"1", "2", "9", "10", "12"} {
+			for _, micro := range []string{"0", "1", "10"} {
+				versions = append(versions, year+"."+month+"."+micro)
+			}
+		}
+	}
+	invalid := []string{"9.1.0", "023.1.0", "23.0.0", "23.13.0", "23.01.0", "23.1.01", "23.1", "23.1.0.0"}
+
+	bounds := []string{"09", "23", "100", "23.1", "23.12", "99.9", "23.2.0", "23.12.10", "99.12.1", "00.1.0"}
+	operators := map[string]func(c int) bool{
+		"<":  func(c int) bool { return c < 0 },
+		"<=": func(c int) bool { return c <= 0 },
+		"==": func(c int) bool { return c == 0 },
+		"!=": func(c int) bool { return c != 0 },
+		">=": func(c int) bool { return c >= 0 },
+		">":  func(c int) bool { return c > 0 },
+	}
+
+	for operator, want := range operators {
+		for _, bound := range bounds {
+			constraint := operator + bound
+			regex, err := VersionToRegexWithOptions(constraint, Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: format.String()})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", constraint, err)
+			}
+			prefix, _ := format.parseVersion(bound)
+
+			for _, version := range versions {
+				parts, _ := format.parseVersion(version)
+				expected := want(compareParts(decimalParts(parts[:len(prefix)]), decimalParts(prefix)))
+				if regex.MatchString(version) != expected {
+					t.Errorf("%s: pattern %q matching %q: expected %v", constraint, regex.String(), version, expected)
+				}
+			}
+			for _, version := range invalid {
+				if regex.MatchString(version) {
+					t.Errorf("%s: pattern %q should not match invalid version %q", constraint, regex.String(), version)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,726 @@
+// Package convert provides named capture groups for generated patterns.
+// This file contains the pass that wraps the components of the versions matched by
+// a pattern in the named groups major, minor, patch, prerelease and build.
+//
+// Generated patterns mix the components in alternations and repetitions, such as
+// (?:[2-9]|1\.[3-9])(?:\.\d+)*, so groups cannot simply be inserted around pieces of
+// the pattern. Instead, the pass follows the characters each piece matches through
+// the states of a version (major, dot, minor, ..., pre-release, build) and wraps the
+// runs of characters that stay in one component. Where a piece leads to different
+// components depending on the characters it matches, its alternatives are expanded,
+// so the same group name may appear in several branches of the result.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+)
+
+// Names of the capture groups of the components of a version
+const (
+	// CAPTURE_MAJOR is the group of the first numeric component
+	CAPTURE_MAJOR = "major"
+	// CAPTURE_MINOR is the group of the second numeric component
+	CAPTURE_MINOR = "minor"
+	// CAPTURE_PATCH is the group of the third numeric component
+	CAPTURE_PATCH = "patch"
+	// CAPTURE_PRERELEASE is the group of the pre-release label, without its separator
+	CAPTURE_PRERELEASE = "prerelease"
+	// CAPTURE_BUILD is the group of the build metadata, without the leading +
+	CAPTURE_BUILD = "build"
+)
+
+// VersionComponents holds the components of a version matched by a regex generated
+// with Options.CaptureGroups. Components the version does not have are empty.
+type VersionComponents struct {
+	Major      string
+	Minor      string
+	Patch      string
+	Prerelease string
+	Build      string
+}
+
+// ExtractComponents matches version against a regex generated with
+// Options.CaptureGroups and returns the components it captured.
+//
+// A group name may appear in several alternatives of such a regex, only one of which
+// takes part in a match, so looking groups up with SubexpIndex is not enough. This
+// function returns the group of each name that matched.
+//
+// Parameters:
+//   - regex: A regex generated with Options.CaptureGroups
+//   - version: The version string to match and split
+//
+// Returns:
+//   - VersionComponents: The captured components, empty if the version does not match
+//   - bool: true if the version matches the regex, false otherwise
+//
+// Example:
+//
+//	regex, err := VersionToRegexWithOptions("^1.2.3", Options{CaptureGroups: true})
+//	if err != nil {
+//		return err
+//	}
+//
+//	components, ok := ExtractComponents(regex, "1.4.0-rc.1")
+//	// ok: true, components.Minor: "4", components.Prerelease: "rc.1"
+func ExtractComponents(regex *regexp.Regexp, version string) (VersionComponents, bool) {
+	match := regex.FindStringSubmatchIndex(version)
+	if match == nil {
+		return VersionComponents{}, false
+	}
+
+	var components VersionComponents
+	fields := map[string]*string{
+		CAPTURE_MAJOR:      &components.Major,
+		CAPTURE_MINOR:      &components.Minor,
+		CAPTURE_PATCH:      &components.Patch,
+		CAPTURE_PRERELEASE: &components.Prerelease,
+		CAPTURE_BUILD:      &components.Build,
+	}
+	for i, name := range regex.SubexpNames() {
+		if field, ok := fields[name]; ok && match[2*i] >= 0 {
+			*field = version[match[2*i]:match[2*i+1]]
+		}
+	}
+	return components, true
+}
+
+// CAPTURE_MAX_SEQUENCES bounds the number of sequences the capture pass may expand,
+// which grows with the number of alternatives that lead to different components.
+const CAPTURE_MAX_SEQUENCES = 5000
+
+// captureSegments lists the group names by segment; segment 0 is not captured
+var captureSegments = []string{"", CAPTURE_MAJOR, CAPTURE_MINOR, CAPTURE_PATCH, CAPTURE_PRERELEASE, CAPTURE_BUILD}
+
+// captureState is the position in a version reached after the characters matched so far.
+type captureState int
+
+const (
+	captureStart captureState = iota // before the first digit, where a v prefix may appear
+	captureMajor
+	captureMinor
+	capturePatch
+	captureRelease // fourth and later numeric components
+	captureMajorDot
+	captureMinorDot
+	capturePatchDot
+	captureReleaseDot
+	capturePrereleaseSeparator
+	capturePrerelease
+	captureBuildSeparator
+	captureBuild
+	captureOther // after text that does not start a version, such as dev-main
+)
+
+// captureStates is a set of capture states.
+type captureStates uint32
+
+// segment returns the index in captureSegments of the component the state is in.
+func (s captureState) segment() int {
+	switch s {
+	case captureMajor:
+		return 1
+	case captureMinor:
+		return 2
+	case capturePatch:
+		return 3
+	case capturePrerelease:
+		return 4
+	case captureBuild:
+		return 5
+	default:
+		return 0
+	}
+}
+
+// next returns the state reached by matching r in state s.
+func (s captureState) next(r rune) captureState {
+	digit := '0' <= r && r <= '9'
+	letter := 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+
+	switch {
+	case s == captureStart:
+		if digit {
+			return captureMajor
+		}
+		if r == 'v' || r == 'V' {
+			return captureStart
+		}
+		return captureOther
+	case s >= captureMajor && s <= captureRelease:
+		switch {
+		case digit:
+			return s
+		case r == '.':
+			return s - captureMajor + captureMajorDot
+		case r == '+':
+			return captureBuildSeparator
+		case letter:
+			return capturePrerelease
+		default:
+			return capturePrereleaseSeparator
+		}
+	case s >= captureMajorDot && s <= captureReleaseDot:
+		switch {
+		case digit:
+			return min(s-captureMajorDot+captureMinor, captureRelease)
+		case r == '+':
+			return captureBuildSeparator
+		default:
+			return capturePrerelease
+		}
+	case s == capturePrereleaseSeparator || s == capturePrerelease:
+		if r == '+' {
+			return captureBuildSeparator
+		}
+		return capturePrerelease
+	case s == captureBuildSeparator || s == captureBuild:
+		return captureBuild
+	default:
+		return captureOther
+	}
+}
+
+// add returns the set with s added.
+func (set captureStates) add(s captureState) captureStates {
+	return set | 1<<s
+}
+
+// list returns the states of the set in increasing order.
+func (set captureStates) list() []captureState {
+	var states []captureState
+	for s := captureStart; s <= captureOther; s++ {
+		if set&(1<<s) != 0 {
+			states = append(states, s)
+		}
+	}
+	return states
+}
+
+// captureFlow describes how a piece of a pattern moves through a version.
+type captureFlow struct {
+	exits    captureStates // states the piece can end in
+	segments uint8         // segments of the characters the piece can match
+	nullable bool          // whether the piece can match the empty string
+}
+
+// classExits splits the characters of a class by the state they lead to from s.
+// Characters outside ASCII all behave alike, so they are not enumerated.
+func classExits(ranges []rune, s captureState) map[captureState][]rune {
+	exits := make(map[captureState][]rune)
+	add := func(lo, hi rune) {
+		next := s.next(lo)
+		if r := exits[next]; len(r) > 0 && r[len(r)-1] == lo-1 {
+			r[len(r)-1] = hi
+			return
+		}
+		exits[next] = append(exits[next], lo, hi)
+	}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		for r := lo; r <= hi && r < 0x80; r++ {
+			add(r, r)
+		}
+		if hi >= 0x80 {
+			add(max(lo, 0x80), hi)
+		}
+	}
+	return exits
+}
+
+// sortedRanges returns disjoint rune ranges in increasing order, with adjacent ranges merged.
+func sortedRanges(ranges []rune) []rune {
+	pairs := make([][2]rune, 0, len(ranges)/2)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		pairs = append(pairs, [2]rune{ranges[i], ranges[i+1]})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i][0] < pairs[j][0] })
+	var sorted []rune
+	for _, pair := range pairs {
+		if n := len(sorted); n > 0 && sorted[n-1] == pair[0]-1 {
+			sorted[n-1] = pair[1]
+		} else {
+			sorted = append(sorted, pair[0], pair[1])
+		}
+	}
+	return sorted
+}
+
+// classRanges returns the ranges of a single-character piece, or nil for other pieces.
+func classRanges(re *syntax.Regexp) []rune {
+	switch re.Op {
+	case syntax.OpCharClass:
+		return re.Rune
+	case syntax.OpAnyChar:
+		return []rune{0, 0x10FFFF}
+	case syntax.OpAnyCharNotNL:
+		return []rune{0, '\n' - 1, '\n' + 1, 0x10FFFF}
+	default:
+		return nil
+	}
+}
+
+// flow returns how re moves through a version when it is entered in state s.
+// For repetitions, the exits may include states that cannot actually be reached,
+// which only makes the capture pass expand more than needed.
+func flow(re *syntax.Regexp, s captureState) captureFlow {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return captureFlow{}
+	case syntax.OpLiteral:
+		f := captureFlow{nullable: len(re.Rune) == 0}
+		for _, r := range re.Rune {
+			s = s.next(r)
+			f.segments |= 1 << s.segment()
+		}
+		f.exits = f.exits.add(s)
+		return f
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		var f captureFlow
+		for next := range classExits(classRanges(re), s) {
+			f.exits = f.exits.add(next)
+			f.segments |= 1 << next.segment()
+		}
+		return f
+	case syntax.OpCapture:
+		return flow(re.Sub[0], s)
+	case syntax.OpConcat:
+		f := captureFlow{exits: captureStates(0).add(s), nullable: true}
+		for _, sub := range re.Sub {
+			var exits captureStates
+			nullable := false
+			for _, state := range f.exits.list() {
+				subFlow := flow(sub, state)
+				exits |= subFlow.exits
+				f.segments |= subFlow.segments
+				nullable = nullable || subFlow.nullable
+			}
+			f.exits = exits
+			f.nullable = f.nullable && nullable
+		}
+		return f
+	case syntax.OpAlternate:
+		var f captureFlow
+		for _, sub := range re.Sub {
+			subFlow := flow(sub, s)
+			f.exits |= subFlow.exits
+			f.segments |= subFlow.segments
+			f.nullable = f.nullable || subFlow.nullable
+		}
+		return f
+	case syntax.OpQuest, syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		if re.Op == syntax.OpRepeat && re.Max == 0 {
+			return captureFlow{exits: captureStates(0).add(s), nullable: true}
+		}
+		body := flow(re.Sub[0], s)
+		f := body
+		if re.Op == syntax.OpQuest || re.Op == syntax.OpStar || re.Op == syntax.OpRepeat && re.Min == 0 {
+			f.exits = f.exits.add(s)
+			f.nullable = true
+		}
+		if re.Op == syntax.OpQuest || re.Op == syntax.OpRepeat && re.Max == 1 {
+			return f
+		}
+		// Repeat the body until no new state is reached
+		for reached := f.exits; ; {
+			for _, state := range reached.list() {
+				subFlow := flow(re.Sub[0], state)
+				f.exits |= subFlow.exits
+				f.segments |= subFlow.segments
+			}
+			if f.exits == reached {
+				return f
+			}
+			reached = f.exits
+		}
+	default:
+		// Empty matches and assertions do not move
+		return captureFlow{exits: captureStates(0).add(s), nullable: true}
+	}
+}
+
+// leading returns the segments of the first characters seq[i:] can match when it
+// is entered in state s, and whether it can match the empty string.
+func leading(seq []*syntax.Regexp, s captureState) (uint8, bool) {
+	var segments uint8
+	for _, re := range seq {
+		first, nullable := leadingOf(re, s)
+		segments |= first
+		if !nullable {
+			return segments, false
+		}
+	}
+	return segments, true
+}
+
+// leadingOf returns the segments of the first characters re can match when it is
+// entered in state s, and whether it can match the empty string.
+func leadingOf(re *syntax.Regexp, s captureState) (uint8, bool) {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return 0, false
+	case syntax.OpLiteral:
+		if len(re.Rune) == 0 {
+			return 0, true
+		}
+		return 1 << s.next(re.Rune[0]).segment(), false
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return flow(re, s).segments, false
+	case syntax.OpCapture:
+		return leadingOf(re.Sub[0], s)
+	case syntax.OpConcat:
+		return leading(re.Sub, s)
+	case syntax.OpAlternate:
+		var segments uint8
+		nullable := false
+		for _, sub := range re.Sub {
+			first, subNullable := leadingOf(sub, s)
+			segments |= first
+			nullable = nullable || subNullable
+		}
+		return segments, nullable
+	case syntax.OpQuest, syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		if re.Op == syntax.OpRepeat && re.Max == 0 {
+			return 0, true
+		}
+		segments, nullable := leadingOf(re.Sub[0], s)
+		return segments, nullable || re.Op == syntax.OpQuest || re.Op == syntax.OpStar || re.Op == syntax.OpRepeat && re.Min == 0
+	default:
+		return 0, true
+	}
+}
+
+// expand returns the alternatives of a piece that may lead to different
+// components, each as the sequence of pieces it stands for, in order of preference.
+func expand(re *syntax.Regexp, s captureState) [][]*syntax.Regexp {
+	body := []*syntax.Regexp{}
+	if len(re.Sub) > 0 {
+		body = append(body, re.Sub[0])
+	}
+	preferBody := func(alternatives ...[]*syntax.Regexp) [][]*syntax.Regexp {
+		if re.Flags&syntax.NonGreedy != 0 {
+			alternatives[0], alternatives[1] = alternatives[1], alternatives[0]
+		}
+		return alternatives
+	}
+	star := func(sub *syntax.Regexp) *syntax.Regexp {
+		return &syntax.Regexp{Op: syntax.OpStar, Flags: re.Flags, Sub: []*syntax.Regexp{sub}}
+	}
+
+	switch re.Op {
+	case syntax.OpAlternate:
+		alternatives := make([][]*syntax.Regexp, len(re.Sub))
+		for i, sub := range re.Sub {
+			alternatives[i] = []*syntax.Regexp{sub}
+		}
+		return alternatives
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		exits := classExits(classRanges(re), s)
+		states := make([]captureState, 0, len(exits))
+		for state := range exits {
+			states = append(states, state)
+		}
+		sort.Slice(states, func(i, j int) bool { return states[i] < states[j] })
+		alternatives := make([][]*syntax.Regexp, len(states))
+		for i, state := range states {
+			alternatives[i] = []*syntax.Regexp{{Op: syntax.OpCharClass, Rune: exits[state]}}
+		}
+		return alternatives
+	case syntax.OpQuest:
+		return preferBody(body, nil)
+	case syntax.OpStar:
+		// Peel the characters that stay in s off a class, as unrolling the star
+		// one character at a time would never end: [-+.\w]* in a pre-release is
+		// [-.\w]*(?:\+[-+.\w]*)?
+		if ranges := classRanges(re.Sub[0]); ranges != nil {
+			exits := classExits(ranges, s)
+			if stay, ok := exits[s]; ok && len(exits) > 1 {
+				var leave []rune
+				for state, r := range exits {
+					if state != s {
+						leave = append(leave, r...)
+					}
+				}
+				leaving := &syntax.Regexp{Op: syntax.OpCharClass, Rune: sortedRanges(leave)}
+				return [][]*syntax.Regexp{{
+					star(&syntax.Regexp{Op: syntax.OpCharClass, Rune: stay}),
+					{Op: syntax.OpQuest, Flags: re.Flags, Sub: []*syntax.Regexp{{Op: syntax.OpConcat, Sub: []*syntax.Regexp{leaving, re}}}},
+				}}
+			}
+		}
+		return preferBody(append(body, re), nil)
+	case syntax.OpPlus:
+		return [][]*syntax.Regexp{append(body, star(re.Sub[0]))}
+	case syntax.OpRepeat:
+		if re.Max == 0 {
+			return [][]*syntax.Regexp{nil}
+		}
+		var rest *syntax.Regexp
+		switch {
+		case re.Max == -1 && re.Min <= 1:
+			rest = star(re.Sub[0])
+		case re.Max == -1:
+			rest = &syntax.Regexp{Op: syntax.OpRepeat, Flags: re.Flags, Min: re.Min - 1, Max: -1, Sub: re.Sub}
+		default:
+			rest = &syntax.Regexp{Op: syntax.OpRepeat, Flags: re.Flags, Min: max(re.Min-1, 0), Max: re.Max - 1, Sub: re.Sub}
+		}
+		if re.Min > 0 {
+			return [][]*syntax.Regexp{append(body, rest)}
+		}
+		return preferBody(append(body, rest), nil)
+	default:
+		return [][]*syntax.Regexp{{re}}
+	}
+}
+
+// captureTagger wraps the components matched by sequences of pieces in named groups.
+type captureTagger struct {
+	sequences int
+	tagged    map[string]*syntax.Regexp
+}
+
+// captureComponents returns the un-anchored pattern re with the components of the
+// versions it matches wrapped in named groups.
+func captureComponents(re *syntax.Regexp) (*syntax.Regexp, error) {
+	t := &captureTagger{tagged: make(map[string]*syntax.Regexp)}
+	return t.sequence([]*syntax.Regexp{re}, captureStart)
+}
+
+// sequence returns the concatenation of seq, entered in state s, with its
+// components wrapped in named groups.
+func (t *captureTagger) sequence(seq []*syntax.Regexp, s captureState) (*syntax.Regexp, error) {
+	key := fmt.Sprintf("%d:%s", s, printRegexp(concatOf(seq), false))
+	if tagged, ok := t.tagged[key]; ok {
+		return tagged, nil
+	}
+	t.sequences++
+	if t.sequences > CAPTURE_MAX_SEQUENCES {
+		return nil, fmt.Errorf("pattern is too complex to capture its components")
+	}
+
+	tagged, err := t.tag(seq, s)
+	if err != nil {
+		return nil, err
+	}
+	t.tagged[key] = tagged
+	return tagged, nil
+}
+
+// tag follows seq from state s and wraps the runs of pieces that stay in one
+// component in a group, expanding the pieces after which components differ.
+func (t *captureTagger) tag(seq []*syntax.Regexp, s captureState) (*syntax.Regexp, error) {
+	var out []*syntax.Regexp
+	open := 0      // segment of the group being built, 0 for none
+	spanStart := 0 // index in out of the first piece of the group
+	spanIn := 0    // index in seq of the first piece of the group
+	spanEntry := s // state before the first piece of the group
+	closeSpan := func() {
+		if open != 0 {
+			group := &syntax.Regexp{Op: syntax.OpCapture, Name: captureSegments[open], Sub: []*syntax.Regexp{concatOf(out[spanStart:])}}
+			out = append(out[:spanStart], group)
+			open = 0
+		}
+	}
+	continues := func(i int, state captureState) bool {
+		segments, _ := leading(seq[i:], state)
+		return open != 0 && segments&(1<<open) != 0
+	}
+
+	for i := 0; i < len(seq); i++ {
+		re := seq[i]
+
+		// Split pieces that can be followed one character at a time
+		var pieces []*syntax.Regexp
+		switch {
+		case re.Op == syntax.OpConcat:
+			pieces = re.Sub
+		case re.Op == syntax.OpCapture:
+			pieces = re.Sub
+		case re.Op == syntax.OpLiteral && len(re.Rune) > 1:
+			for _, r := range re.Rune {
+				pieces = append(pieces, &syntax.Regexp{Op: syntax.OpLiteral, Flags: re.Flags, Rune: []rune{r}})
+			}
+		}
+		if pieces != nil {
+			seq = append(append(append([]*syntax.Regexp(nil), seq[:i]...), pieces...), seq[i+1:]...)
+			i--
+			continue
+		}
+
+		f := flow(re, s)
+		exits := f.exits.list()
+		if len(exits) == 0 {
+			// The piece never matches, so the state after it does not matter
+			out = append(out, re)
+			continue
+		}
+
+		// Pieces that stay in the current component, or lead to a single other one.
+		// A piece that may match nothing, such as 0*, leads to the component of the
+		// pieces after it when they start it anyway.
+		segment := -1
+		for candidate := range captureSegments {
+			if f.segments == 1<<candidate {
+				segment = candidate
+			}
+		}
+		stays := f.segments == 0 || segment == open
+		switches := segment >= 0 && !f.nullable
+		if segment >= 0 && f.nullable {
+			next, nullable := leading(seq[i+1:], s)
+			switches = next == 1<<segment && !nullable
+		}
+		if stays || switches {
+			if len(exits) == 1 || mergesAfter(seq[i+1:], exits) {
+				if !stays {
+					closeSpan()
+					if segment != 0 {
+						open, spanStart, spanIn, spanEntry = segment, len(out), i, s
+					}
+				}
+				out = append(out, re)
+				s = exits[0]
+				continue
+			}
+
+			// Several states may follow, which is fine as long as the rest of the
+			// sequence gets the same groups from all of them
+			if rest, ok, err := t.sameRest(seq[i+1:], exits); err != nil {
+				return nil, err
+			} else if ok {
+				if !stays {
+					closeSpan()
+					if segment != 0 {
+						open, spanStart = segment, len(out)
+					}
+				}
+				out = append(out, re)
+				closeSpan()
+				return concatOf(append(out, rest)), nil
+			}
+		}
+
+		if open != 0 && !continues(i, s) {
+			closeSpan()
+		}
+
+		// A piece after which the rest gets the same groups, such as an optional
+		// pre-release label, has its groups wrapped on its own
+		if open == 0 && len(seq) > 1 {
+			if rest, ok, err := t.sameRest(seq[i+1:], exits); err != nil {
+				return nil, err
+			} else if ok {
+				tagged, err := t.sequence([]*syntax.Regexp{re}, s)
+				if err != nil {
+					return nil, err
+				}
+				return concatOf(append(out, tagged, rest)), nil
+			}
+		}
+
+		// Otherwise, expand the alternatives of the piece, each followed by the rest
+		// of the sequence. The open group is expanded too, as it continues in them.
+		prefix, restart, entry := out, []*syntax.Regexp(nil), s
+		if open != 0 {
+			prefix, restart, entry = out[:spanStart], seq[spanIn:i], spanEntry
+		}
+		var branches []*syntax.Regexp
+		for _, alternative := range expand(re, s) {
+			branch := append(append(append([]*syntax.Regexp(nil), restart...), alternative...), seq[i+1:]...)
+			tagged, err := t.sequence(branch, entry)
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, tagged)
+		}
+		result := branches[0]
+		if len(branches) > 1 {
+			result = &syntax.Regexp{Op: syntax.OpAlternate, Sub: branches}
+		}
+		return concatOf(append(append([]*syntax.Regexp(nil), prefix...), result)), nil
+	}
+
+	closeSpan()
+	return concatOf(out), nil
+}
+
+// mergesAfter reports whether the first character of rest leads to the same state
+// from all the exits, so that the rest can be followed from any of them.
+func mergesAfter(rest []*syntax.Regexp, exits []captureState) bool {
+	if len(rest) == 0 {
+		return true
+	}
+	re, after := rest[0], rest[1:]
+	then := func(subs ...*syntax.Regexp) []*syntax.Regexp {
+		return append(append([]*syntax.Regexp(nil), subs...), after...)
+	}
+
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return true
+	case syntax.OpLiteral:
+		if len(re.Rune) == 0 {
+			return mergesAfter(after, exits)
+		}
+		for _, exit := range exits {
+			if exit.next(re.Rune[0]) != exits[0].next(re.Rune[0]) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		first := fmt.Sprint(classExits(classRanges(re), exits[0]))
+		for _, exit := range exits[1:] {
+			if fmt.Sprint(classExits(classRanges(re), exit)) != first {
+				return false
+			}
+		}
+		return true
+	case syntax.OpConcat, syntax.OpCapture:
+		return mergesAfter(then(re.Sub...), exits)
+	case syntax.OpAlternate:
+		for _, sub := range re.Sub {
+			if !mergesAfter(then(sub), exits) {
+				return false
+			}
+		}
+		return true
+	case syntax.OpQuest, syntax.OpStar, syntax.OpPlus, syntax.OpRepeat:
+		if re.Op == syntax.OpRepeat && re.Max == 0 {
+			return mergesAfter(after, exits)
+		}
+		optional := re.Op == syntax.OpQuest || re.Op == syntax.OpStar || re.Op == syntax.OpRepeat && re.Min == 0
+		if optional && !mergesAfter(after, exits) {
+			return false
+		}
+		return mergesAfter(then(re.Sub[0]), exits)
+	default:
+		return mergesAfter(after, exits)
+	}
+}
+
+// sameRest returns the rest of a sequence with its components wrapped in named
+// groups, if the rest gets the same groups whichever of the exits it is entered in
+// and the component of none of the exits continues into it.
+func (t *captureTagger) sameRest(rest []*syntax.Regexp, exits []captureState) (*syntax.Regexp, bool, error) {
+	var tagged *syntax.Regexp
+	printed := ""
+	for i, exit := range exits {
+		if segments, _ := leading(rest, exit); exit.segment() != 0 && segments&(1<<exit.segment()) != 0 {
+			return nil, false, nil
+		}
+		candidate, err := t.sequence(rest, exit)
+		if err != nil {
+			return nil, false, err
+		}
+		if i == 0 {
+			tagged, printed = candidate, printRegexp(candidate, false)
+		} else if printRegexp(candidate, false) != printed {
+			return nil, false, nil
+		}
+	}
+	return tagged, true, nil
+}
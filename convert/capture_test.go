@@ -0,0 +1,189 @@
+// Package convert provides tests for named capture group functionality.
+// This file checks that generated regexes capture the components of the versions
+// they match, and still match exactly the versions of the regexes without groups.
+package convert
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// capturedComponents returns the named groups that took part in the match of version.
+func capturedComponents(regex *regexp.Regexp, version string) (map[string]string, bool) {
+	match := regex.FindStringSubmatchIndex(version)
+	if match == nil {
+		return nil, false
+	}
+	components := make(map[string]string)
+	for i, name := range regex.SubexpNames() {
+		if name != "" && match[2*i] >= 0 {
+			components[name] = version[match[2*i]:match[2*i+1]]
+		}
+	}
+	return components, true
+}
+
+// TestCaptureGroups tests the components captured from matched versions.
+func TestCaptureGroups(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+		version    string
+		expected   map[string]string
+	}{
+		{"1.2.3", Options{}, "1.2.3", map[string]string{"major": "1", "minor": "2", "patch": "3"}},
+		{"^1.2.3", Options{}, "1.4.0-rc.1+build.5", map[string]string{"major": "1", "minor": "4", "patch": "0", "prerelease": "rc.1", "build": "build.5"}},
+		{">=1.2.3", Options{}, "10.0.1-alpha", map[string]string{"major": "10", "minor": "0", "patch": "1", "prerelease": "alpha"}},
+		{">=1.2.3", Options{}, "2", map[string]string{"major": "2"}},
+		{">=1.2.3", Options{}, "1.2.3.4", map[string]string{"major": "1", "minor": "2", "patch": "3"}},
+		{">=1.2.3", Options{}, "1.3+exp", map[string]string{"major": "1", "minor": "3", "build": "exp"}},
+		{"<120.0.6099.109", Options{}, "120.0.6099.20", map[string]string{"major": "120", "minor": "0", "patch": "6099"}},
+		{"~1.2", Options{LeadingZeros: LEADING_ZEROS_NUMERIC}, "01.002.7", map[string]string{"major": "01", "minor": "002", "patch": "7"}},
+		{"1.2.*", Options{}, "1.2.9-beta", map[string]string{"major": "1", "minor": "2", "patch": "9", "prerelease": "beta"}},
+		{"v1.2.3", Options{}, "v1.2.3-pre", map[string]string{"major": "1", "minor": "2", "patch": "3", "prerelease": "pre"}},
+		{"[1.0,2.0)", Options{}, "1.5-SNAPSHOT", map[string]string{"major": "1", "minor": "5", "prerelease": "SNAPSHOT"}},
+		{"[1.0,2.0)", Options{}, "1.0.Final", map[string]string{"major": "1", "minor": "0", "prerelease": "Final"}},
+		{"~> 2.1, >= 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}, "2.5.0.rc1", map[string]string{"major": "2", "minor": "5", "patch": "0", "prerelease": "rc1"}},
+		{"^1.2@beta", Options{Ecosystem: ECOSYSTEM_COMPOSER}, "1.3.0-beta2", map[string]string{"major": "1", "minor": "3", "patch": "0", "prerelease": "beta2"}},
+		{"^1.2@beta || dev-main", Options{Ecosystem: ECOSYSTEM_COMPOSER}, "dev-main", map[string]string{}},
+		{">=1.2.0-alpha, <1.5", Options{Ecosystem: ECOSYSTEM_CARGO}, "1.2.0-beta", map[string]string{"major": "1", "minor": "2", "patch": "0", "prerelease": "beta"}},
+		{">=2024.10, <2025", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM.MICRO"}, "2024.11.3", map[string]string{"major": "2024", "minor": "11", "patch": "3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			opts := tt.opts
+			opts.CaptureGroups = true
+			regex, err := VersionToRegexWithOptions(tt.constraint, opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			components, ok := capturedComponents(regex, tt.version)
+			if !ok {
+				t.Fatalf("regex %q should match %q", regex, tt.version)
+			}
+			if !reflect.DeepEqual(components, tt.expected) {
+				t.Errorf("regex %q captured %v from %q, expected %v", regex, components, tt.version, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCaptureGroupsEquivalence tests that capture groups do not change the versions
+// matched by the regexes of every supported ecosystem.
+func TestCaptureGroupsEquivalence(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+	}{
+		{">=1.2.3", Options{}},
+		{">1.2", Options{}},
+		{"<=1.2.3.4", Options{}},
+		{"<120.0.6099.109", Options{}},
+		{">=1.2.3", Options{VersionLength: LENGTH_STRICT}},
+		{">=1.2.3", Options{LeadingZeros: LEADING_ZEROS_REJECT}},
+		{"^1.2.3", Options{}},
+		{"~1.2.3", Options{}},
+		{"1.2.*", Options{}},
+		{"v1.2.3", Options{}},
+		{"1.2.3.4567", Options{}},
+		{"1.0.0-preview", Options{}},
+		{"[1.0,2.0)", Options{}},
+		{"(,1.0],[1.2,)", Options{}},
+		{"[1.0-alpha-1,1.0]", Options{MavenSnapshots: SNAPSHOTS_EXCLUDE}},
+		{"~> 2.1, >= 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}},
+		{"^1.2@beta || dev-main", Options{Ecosystem: ECOSYSTEM_COMPOSER}},
+		{">=1.0 <1.1 || >=1.2", Options{Ecosystem: ECOSYSTEM_COMPOSER}},
+		{"1.2.3", Options{Ecosystem: ECOSYSTEM_CARGO}},
+		{">=1.2.0-alpha, <1.5", Options{Ecosystem: ECOSYSTEM_CARGO}},
+		{">=2024.10, <2025", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM.MICRO"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			plain, err := VersionToRegexWithOptions(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			opts := tt.opts
+			opts.CaptureGroups = true
+			captured, err := VersionToRegexWithOptions(tt.constraint, opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) with capture groups returned unexpected error: %v", tt.constraint, err)
+			}
+
+			for _, version := range minimizeCorpus {
+				for _, candidate := range []string{version, strings.ToUpper(version)} {
+					if plain.MatchString(candidate) != captured.MatchString(candidate) {
+						t.Errorf("%q: %q and %q disagree on %q", tt.constraint, plain, captured, candidate)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestCaptureGroupsUnsupported tests the ecosystems whose versions have no major,
+// minor and patch components.
+func TestCaptureGroupsUnsupported(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+	}{
+		{">= 1:2.30-1ubuntu2", Options{Ecosystem: ECOSYSTEM_DEBIAN}},
+		{">= 1:2.30-1.el8", Options{Ecosystem: ECOSYSTEM_RPM}},
+		{">=1!2.0", Options{Ecosystem: ECOSYSTEM_PYPI}},
+		{">=2024-10", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY-0M"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			opts := tt.opts
+			opts.CaptureGroups = true
+			if _, err := VersionToPattern(tt.constraint, opts); err == nil {
+				t.Errorf("VersionToPattern(%q) should return an error with capture groups", tt.constraint)
+			}
+		})
+	}
+}
+
+// TestExtractComponents tests the extraction of components through regexes whose
+// group names appear in several alternatives.
+func TestExtractComponents(t *testing.T) {
+	regex := MustVersionToRegex(">=1.2.3")
+	captured, err := VersionToRegexWithOptions(">=1.2.3", Options{CaptureGroups: true})
+	if err != nil {
+		t.Fatalf("VersionToRegexWithOptions returned unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		version  string
+		expected VersionComponents
+		matches  bool
+	}{
+		{"2.0.0", VersionComponents{Major: "2", Minor: "0", Patch: "0"}, true},
+		{"1.2.4-beta.2+sha.5114f85", VersionComponents{Major: "1", Minor: "2", Patch: "4", Prerelease: "beta.2", Build: "sha.5114f85"}, true},
+		{"1.10", VersionComponents{Major: "1", Minor: "10"}, true},
+		{"1.2.2", VersionComponents{}, false},
+		{"not a version", VersionComponents{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			components, ok := ExtractComponents(captured, tt.version)
+			if ok != tt.matches || components != tt.expected {
+				t.Errorf("ExtractComponents(%q) = %+v, %v, expected %+v, %v", tt.version, components, ok, tt.expected, tt.matches)
+			}
+			if ok != regex.MatchString(tt.version) {
+				t.Errorf("ExtractComponents(%q) should match like the regex without groups", tt.version)
+			}
+		})
+	}
+
+	// Regexes without groups still tell whether versions match
+	if components, ok := ExtractComponents(regex, "1.3.0"); !ok || components != (VersionComponents{}) {
+		t.Errorf("ExtractComponents without groups = %+v, %v, expected no components and a match", components, ok)
+	}
+}
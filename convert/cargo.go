@@ -0,0 +1,257 @@
+// Package convert provides Rust Cargo version handling functionality.
+// This file contains functions specific to Cargo version requirements, as written
+// in Cargo.toml and interpreted by the semver crate:
+//   - a bare version is a caret requirement: 1.2.3 means ^1.2.3, not =1.2.3
+//   - ^ locks the first non-zero component: ^0.2.3 means >=0.2.3 <0.3.0 and
+//     ^0.0.3 means =0.0.3
+//   - ~1 means >=1.0.0 <2.0.0, while ~1.2 and ~1.2.3 stay within 1.2
+//   - = with a partial version matches all its sub-versions: =1.2 means >=1.2.0 <1.3.0
+//   - comparators separated by commas must all hold
+//
+// Versions follow SemVer 2.0 and a pre-release only matches when a comparator with
+// the same major.minor.patch has a pre-release too: >=1.2.3-alpha matches 1.2.3-beta
+// but not 1.3.0-beta.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// cargoComparatorRegex splits a comparator into operator and version.
+var cargoComparatorRegex = regexp.MustCompile(`^(=|>=|<=|>|<|~|\^)?\s*(\S+)$`)
+
+// cargoVersionRegex parses a possibly partial version or wildcard, such as 1, 1.2.*,
+// 1.2.3-alpha.1 or *.
+var cargoVersionRegex = regexp.MustCompile(`^(?:([*xX])|(0|[1-9]\d*)(?:\.(0|[1-9]\d*|[*xX]))?(?:\.(0|[1-9]\d*|[*xX]))?(?:-(` + SEMVER_IDENTIFIERS + `))?(?:\+` + SEMVER_IDENTIFIERS + `)?)$`)
+
+// cargoComparator is a single parsed comparator of a requirement.
+type cargoComparator struct {
+	operator string
+	// parts holds the given version components, without wildcards
+	parts      []string
+	prerelease string
+	// wildcard reports whether the version ended with *, x or X
+	wildcard bool
+}
+
+// parseCargoRequirement parses a Cargo version requirement such as "1.2.3" or
+// ">=1.2, <1.5".
+//
+// The returned constraint holds the normalized requirement, where bare versions
+// become explicit caret comparators: "1.2.3" is returned as "^1.2.3".
+func parseCargoRequirement(versionStr string) (*VersionConstraint, error) {
+	comparators, err := parseCargoComparators(versionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, len(comparators))
+	for i, comparator := range comparators {
+		normalized[i] = comparator.String()
+	}
+
+	return &VersionConstraint{
+		Operator: OP_CARGO_REQUIREMENT,
+		Version:  strings.Join(normalized, ", "),
+	}, nil
+}
+
+// parseCargoComparators splits and validates the comparators of a requirement.
+func parseCargoComparators(requirement string) ([]cargoComparator, error) {
+	requirement = strings.TrimSpace(requirement)
+	if requirement == "" {
+		return nil, fmt.Errorf("empty Cargo version requirement")
+	}
+
+	var comparators []cargoComparator
+	for _, entry := range strings.Split(requirement, ",") {
+		matches := cargoComparatorRegex.FindStringSubmatch(strings.TrimSpace(entry))
+		if matches == nil {
+			return nil, fmt.Errorf("invalid Cargo comparator: %q", strings.TrimSpace(entry))
+		}
+		comparator, err := parseCargoComparator(matches[1], matches[2])
+		if err != nil {
+			return nil, err
+		}
+		comparators = append(comparators, comparator)
+	}
+	return comparators, nil
+}
+
+// parseCargoComparator parses the version of a comparator. Wildcards are only
+// allowed without an operator or with =, and turn the comparator into an = on the
+// components before them: 1.2.* is =1.2.
+func parseCargoComparator(operator, version string) (cargoComparator, error) {
+	matches := cargoVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return cargoComparator{}, fmt.Errorf("invalid Cargo version: %s", version)
+	}
+
+	comparator := cargoComparator{operator: operator, prerelease: matches[5]}
+	if operator == "" {
+		comparator.operator = OP_CARET
+	}
+
+	comparator.wildcard = matches[1] != ""
+	for _, part := range matches[2:5] {
+		switch {
+		case part == "":
+		case strings.ContainsAny(part, "*xX"):
+			comparator.wildcard = true
+		case comparator.wildcard:
+			return cargoComparator{}, fmt.Errorf("invalid Cargo version: %s", version)
+		default:
+			comparator.parts = append(comparator.parts, trimDecimal(part))
+		}
+	}
+
+	if comparator.wildcard {
+		if operator != "" && operator != OP_EQUAL {
+			return cargoComparator{}, fmt.Errorf("wildcard version %s cannot be used with %s", version, operator)
+		}
+		comparator.operator = OP_EQUAL
+	}
+	if comparator.prerelease != "" && (comparator.wildcard || len(comparator.parts) < 3) {
+		return cargoComparator{}, fmt.Errorf("pre-release requires a full version: %s", version)
+	}
+	return comparator, nil
+}
+
+// String formats the comparator in its normalized form, e.g. ^1.2.3 or =1.2.*.
+func (c cargoComparator) String() string {
+	version := strings.Join(c.parts, ".")
+	switch {
+	case len(c.parts) == 0:
+		return "*"
+	case c.wildcard:
+		version += ".*"
+	}
+	if c.prerelease != "" {
+		version += "-" + c.prerelease
+	}
+	return c.operator + version
+}
+
+// cargoRequirementRegex creates a regex matching the versions allowed by a Cargo
+// version requirement, with optional build metadata.
+// Result: ^(?:1\.(?:...)...)(?:\+...)?$ matching 1.2.3, 1.9.0, 1.2.4+build but not 2.0.0 or 1.3.0-beta (for "^1.2.3")
+func cargoRequirementRegex(requirement string, opts Options) (string, error) {
+	comparators, err := parseCargoComparators(requirement)
+	if err != nil {
+		return "", err
+	}
+
+	pattern, ok := semverComparatorsPattern(comparators, opts)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + SEMVER_BUILD_META_PATTERN + REGEX_END, nil
+}
+
+// semverComparatorsPattern returns the un-anchored pattern of the versions allowed by
+// every comparator, without build metadata. As in Cargo and npm, a pre-release only
+// matches when a comparator with the same major.minor.patch has a pre-release too.
+func semverComparatorsPattern(comparators []cargoComparator, opts Options) (string, bool) {
+	order := semverPrereleaseOrder{}
+	ranges := []versionRange{{}}
+	for _, comparator := range comparators {
+		ranges = intersectRanges(ranges, []versionRange{comparator.versionRange()}, order)
+	}
+
+	// Releases may match anywhere in the ranges
+	var alternatives []string
+	releases := seqBuilder{fixed: 3, order: semverPrereleaseOrder{releasesOnly: true}, zeros: opts.LeadingZeros}
+	if pattern, ok := releases.rangesPattern(ranges); ok {
+		alternatives = append(alternatives, pattern)
+	}
+
+	// Pre-releases only match next to a comparator that names one
+	prereleases := seqBuilder{fixed: 3, order: order, zeros: opts.LeadingZeros}
+	seen := map[string]bool{}
+	for _, comparator := range comparators {
+		tuple := fmt.Sprint(comparator.parts)
+		if comparator.prerelease == "" || seen[tuple] {
+			continue
+		}
+		seen[tuple] = true
+
+		window := versionRange{
+			lower: &rangeBound{parts: comparator.parts, suffix: "-0", inclusive: true},
+			upper: &rangeBound{parts: comparator.parts},
+		}
+		if pattern, ok := prereleases.rangesPattern(intersectRanges(ranges, []versionRange{window}, order)); ok {
+			alternatives = append(alternatives, pattern)
+		}
+	}
+	return groupAlternatives(alternatives)
+}
+
+// versionRange returns the range of versions allowed by the comparator, ignoring
+// the pre-release rule.
+func (c cargoComparator) versionRange() versionRange {
+	n := len(c.parts)
+	if n == 0 {
+		return versionRange{}
+	}
+
+	version := &rangeBound{parts: c.parts, suffix: prereleaseSuffix(c.prerelease), inclusive: true}
+	exclusive := &rangeBound{parts: c.parts, suffix: version.suffix}
+	switch c.operator {
+	case OP_EQUAL:
+		if n == 3 {
+			return versionRange{lower: version, upper: version}
+		}
+		return versionRange{lower: version, upper: cargoBump(c.parts, n-1)}
+	case OP_GREATER:
+		if n == 3 {
+			return versionRange{lower: exclusive}
+		}
+		next := cargoBump(c.parts, n-1)
+		next.inclusive = true
+		return versionRange{lower: next}
+	case OP_GREATER_EQUAL:
+		return versionRange{lower: version}
+	case OP_LESS:
+		return versionRange{upper: exclusive}
+	case OP_LESS_EQUAL:
+		if n == 3 {
+			return versionRange{upper: version}
+		}
+		return versionRange{upper: cargoBump(c.parts, n-1)}
+	case OP_TILDE:
+		// ~1 allows minor updates, ~1.2 and ~1.2.3 only patch updates
+		return versionRange{lower: version, upper: cargoBump(c.parts, min(n-1, 1))}
+	default: // OP_CARET
+		// ^ allows updates that keep the first non-zero component; ^0.0 and ^0 keep
+		// the components that were given
+		position := 0
+		switch {
+		case c.parts[0] != "0" || n == 1:
+		case c.parts[1] != "0" || n == 2:
+			position = 1
+		default:
+			position = 2
+		}
+		return versionRange{lower: version, upper: cargoBump(c.parts, position)}
+	}
+}
+
+// cargoBump returns the exclusive bound made of parts with component i incremented
+// and the following components set to zero: cargoBump([1 2 3], 1) is <1.3.0.
+func cargoBump(parts []string, i int) *rangeBound {
+	bumped := make([]string, i+1)
+	copy(bumped, parts)
+	bumped[i] = incrementDecimal(bumped[i])
+	return &rangeBound{parts: bumped}
+}
+
+// prereleaseSuffix returns the SemVer suffix of a pre-release label: "" for a
+// release, "-alpha.1" for "alpha.1".
+func prereleaseSuffix(prerelease string) string {
+	if prerelease == "" {
+		return ""
+	}
+	return "-" + prerelease
+}
@@ -0,0 +1,161 @@
+// Package convert provides tests for Rust Cargo version handling functionality.
+// This file contains unit tests for requirement parsing and regex generation.
+package convert
+
+import (
+	"testing"
+)
+
+// TestParseCargoRequirement tests parsing and normalization of Cargo requirements.
+func TestParseCargoRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"1.2.3", false, "^1.2.3"},
+		{">= 1.2, < 1.5", false, ">=1.2, <1.5"},
+		{"=1.2.3-alpha.1+build", false, "=1.2.3-alpha.1"},
+		{"1.2.*", false, "=1.2.*"},
+		{"*", false, "*"},
+		{"~1", false, "~1"},
+		{"", true, ""},
+		{">=1.*", true, ""},
+		{"1.*.3", true, ""},
+		{"1.2-alpha", true, ""},
+		{"01.2.3", true, ""},
+		{"v1.2.3", true, ""},
+		{">=1.0,", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := parseCargoRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseCargoRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseCargoRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if constraint.Operator != OP_CARGO_REQUIREMENT {
+				t.Errorf("expected operator %q, got %q", OP_CARGO_REQUIREMENT, constraint.Operator)
+			}
+			if constraint.Version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, constraint.Version)
+			}
+		})
+	}
+}
+
+// TestCargoRequirementRegex tests regex generation for Cargo requirements.
+func TestCargoRequirementRegex(t *testing.T) {
+	tests := []struct {
+		requirement    string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			requirement:    "1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.2.4", "1.9.0", "1.2.3+build.5"},
+			shouldNotMatch: []string{"1.2.2", "2.0.0", "1.3.0-beta", "1.2", "1.2.3.4"},
+		},
+		{
+			requirement:    "^0.2.3",
+			shouldMatch:    []string{"0.2.3", "0.2.9"},
+			shouldNotMatch: []string{"0.2.2", "0.3.0", "1.0.0"},
+		},
+		{
+			requirement:    "^0.0.3",
+			shouldMatch:    []string{"0.0.3"},
+			shouldNotMatch: []string{"0.0.4", "0.0.2", "0.1.0"},
+		},
+		{
+			requirement:    "^0.0",
+			shouldMatch:    []string{"0.0.0", "0.0.9"},
+			shouldNotMatch: []string{"0.1.0"},
+		},
+		{
+			requirement:    "^0",
+			shouldMatch:    []string{"0.0.0", "0.9.9"},
+			shouldNotMatch: []string{"1.0.0"},
+		},
+		{
+			requirement:    "~1",
+			shouldMatch:    []string{"1.0.0", "1.9.9"},
+			shouldNotMatch: []string{"0.9.9", "2.0.0"},
+		},
+		{
+			requirement:    "~1.2",
+			shouldMatch:    []string{"1.2.0", "1.2.9"},
+			shouldNotMatch: []string{"1.1.9", "1.3.0"},
+		},
+		{
+			requirement:    "=1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.2.3+build"},
+			shouldNotMatch: []string{"1.2.4", "1.2.3-rc.1"},
+		},
+		{
+			requirement:    "=1.2",
+			shouldMatch:    []string{"1.2.0", "1.2.7"},
+			shouldNotMatch: []string{"1.3.0", "1.1.0"},
+		},
+		{
+			requirement:    ">1.2",
+			shouldMatch:    []string{"1.3.0", "2.0.0"},
+			shouldNotMatch: []string{"1.2.0", "1.2.9"},
+		},
+		{
+			requirement:    "<=1.2",
+			shouldMatch:    []string{"1.2.9", "0.1.0"},
+			shouldNotMatch: []string{"1.3.0"},
+		},
+		{
+			requirement:    ">=1.2, <1.5",
+			shouldMatch:    []string{"1.2.0", "1.4.99"},
+			shouldNotMatch: []string{"1.1.9", "1.5.0", "1.5.0-alpha"},
+		},
+		{
+			requirement:    ">=1.2.3-alpha.2",
+			shouldMatch:    []string{"1.2.3-alpha.2", "1.2.3-alpha.10", "1.2.3-beta", "1.2.3", "2.0.0"},
+			shouldNotMatch: []string{"1.2.3-alpha.1", "1.2.3-alpha", "1.3.0-beta", "1.2.2"},
+		},
+		{
+			requirement:    "1.*",
+			shouldMatch:    []string{"1.0.0", "1.5.2"},
+			shouldNotMatch: []string{"2.0.0", "0.9.0"},
+		},
+		{
+			requirement:    "*",
+			shouldMatch:    []string{"0.0.0", "10.2.3"},
+			shouldNotMatch: []string{"1.0.0-alpha", "1.0"},
+		},
+		{
+			requirement:    ">1.5, <1.2",
+			shouldNotMatch: []string{"1.3.0", "1.6.0", "1.1.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requirement, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.requirement, Options{Ecosystem: ECOSYSTEM_CARGO})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.requirement, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
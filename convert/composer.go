@@ -0,0 +1,492 @@
+// Package convert provides PHP Composer version handling functionality.
+// This file contains functions specific to Composer constraints, following the rules
+// of composer/semver:
+//   - ~1.2 means >=1.2 <2.0 and ~1.2.3 means >=1.2.3 <1.3 (unlike npm's tilde)
+//   - ^0.3 means >=0.3 <0.4 and ^0.0.3 means >=0.0.3 <0.0.4
+//   - 1.2.*, 1.2.x and hyphen ranges (1.0 - 2.0)
+//   - || or | between alternatives, and a space or comma between required constraints
+//   - stability suffixes ordered as dev < alpha < beta < RC < stable < patch
+//   - stability flags (^1.2@beta, @dev) and branch versions (dev-main, 2.x-dev)
+//
+// Like Composer's default minimum-stability, only stable versions match unless a
+// stability flag or a pre-release version in the constraint allows more. As in
+// Composer, stability flags take precedence over the stability of the versions.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Composer version suffix ranks, in composer/semver order
+const (
+	COMPOSER_RANK_DEV = iota
+	COMPOSER_RANK_ALPHA
+	COMPOSER_RANK_BETA
+	COMPOSER_RANK_RC
+	COMPOSER_RANK_STABLE
+	COMPOSER_RANK_PATCH
+)
+
+// COMPOSER_NO_NUMBER is the number of a suffix written without one (1.0.0-beta).
+// It sorts before every number, so 1.0.0-beta < 1.0.0-beta0.
+const COMPOSER_NO_NUMBER = -1
+
+// composerNoLimit marks an unbounded upper limit for suffix numbers.
+const composerNoLimit = -2
+
+// composerSuffixLabels lists the spellings of each suffix rank.
+var composerSuffixLabels = map[int][]string{
+	COMPOSER_RANK_DEV:    {"dev"},
+	COMPOSER_RANK_ALPHA:  {"alpha", "a"},
+	COMPOSER_RANK_BETA:   {"beta", "b"},
+	COMPOSER_RANK_RC:     {"rc"},
+	COMPOSER_RANK_STABLE: {"stable"},
+	COMPOSER_RANK_PATCH:  {"patch", "pl", "p"},
+}
+
+// composerStabilityFlags maps stability flags (@beta) to the least stable rank they allow.
+var composerStabilityFlags = map[string]int{
+	"dev":    COMPOSER_RANK_DEV,
+	"alpha":  COMPOSER_RANK_ALPHA,
+	"beta":   COMPOSER_RANK_BETA,
+	"rc":     COMPOSER_RANK_RC,
+	"stable": COMPOSER_RANK_STABLE,
+}
+
+// composerVersionRegex splits a Composer version into numeric components and suffix.
+var composerVersionRegex = regexp.MustCompile(`(?i)^v?(\d+(?:\.\d+){0,3})((?:[._-]?(?:stable|beta|b|rc|alpha|a|patch|pl|p)(?:[.-]?\d+)?|[._-]?dev)?)$`)
+
+// composerSuffixRegex parses a version suffix such as -beta2, -RC1, .p3 or -dev.
+var composerSuffixRegex = regexp.MustCompile(`(?i)^[._-]?([a-z]+)(?:[.-]?(\d+))?$`)
+
+// composerWildcardRegex recognizes wildcard constraints such as 1.2.*, 1.x or *.
+var composerWildcardRegex = regexp.MustCompile(`^v?(?:(\d+(?:\.\d+){0,2})\.)?[*xX]$`)
+
+// composerBranchRegex recognizes branch versions such as dev-main or 2.x-dev.
+var composerBranchRegex = regexp.MustCompile(`(?i)^(?:dev-\S+|v?\d+(?:\.\d+)*\.[x*]-dev)$`)
+
+// composerOperatorRegex splits a comparison into operator and version.
+var composerOperatorRegex = regexp.MustCompile(`^(<>|!=|>=|<=|==|=|>|<|~>|~|\^)?(.+)$`)
+
+// composerOperatorSpaceRegex finds spaces between an operator and its version.
+var composerOperatorSpaceRegex = regexp.MustCompile(`([<>=!~^]+)\s+`)
+
+// composerHyphenRegex recognizes hyphen ranges such as 1.0 - 2.0.
+var composerHyphenRegex = regexp.MustCompile(`^(\S+)\s+-\s+(\S+)$`)
+
+// composerSuffix is the parsed form of the text that follows a Composer version's
+// numeric components.
+type composerSuffix struct {
+	rank   int
+	number int // COMPOSER_NO_NUMBER when absent
+}
+
+// composerConstraint collects the parts of a Composer constraint.
+type composerConstraint struct {
+	ranges    []versionRange
+	branches  []string
+	stability int // least stable rank allowed by the versions
+	flag      int // least stable rank allowed by stability flags, -1 without flags
+}
+
+// parseComposerConstraint parses a Composer constraint such as "^1.2@beta || dev-main".
+//
+// The whole constraint is validated, and the returned constraint holds it with
+// surrounding whitespace removed.
+func parseComposerConstraint(versionStr string) (*VersionConstraint, error) {
+	versionStr = strings.TrimSpace(versionStr)
+	if _, err := parseComposerConstraintSet(versionStr); err != nil {
+		return nil, err
+	}
+
+	return &VersionConstraint{
+		Operator: OP_COMPOSER_CONSTRAINT,
+		Version:  versionStr,
+	}, nil
+}
+
+// composerConstraintRegex creates a regex matching the versions allowed by a Composer
+// constraint, with an optional v prefix.
+// Result: ^v?(?:1\.(?:...)|...)$ matching 1.2, 1.9.0, v1.5.3 but not 2.0.0 or 1.3.0-beta1 (for "~1.2")
+func composerConstraintRegex(constraint string, opts Options) (string, error) {
+	parsed, err := parseComposerConstraintSet(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	var alternatives []string
+	minimum := parsed.stability
+	if parsed.flag != -1 {
+		minimum = parsed.flag
+	}
+	builder := seqBuilder{order: composerSuffixOrder{minimum: minimum}, zeros: opts.LeadingZeros}
+	if pattern, ok := builder.rangesPattern(parsed.ranges); ok {
+		alternatives = append(alternatives, "v?"+pattern)
+	}
+	for _, branch := range parsed.branches {
+		alternatives = append(alternatives, regexp.QuoteMeta(branch))
+	}
+
+	pattern, ok := groupAlternatives(alternatives)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + REGEX_END, nil
+}
+
+// parseComposerConstraintSet parses every alternative of a Composer constraint.
+func parseComposerConstraintSet(constraint string) (*composerConstraint, error) {
+	constraint = strings.TrimSpace(constraint)
+	if constraint == "" {
+		return nil, fmt.Errorf("empty Composer constraint")
+	}
+
+	parsed := &composerConstraint{stability: COMPOSER_RANK_STABLE, flag: -1}
+	order := composerSuffixOrder{}
+	for _, alternative := range regexp.MustCompile(`\s*\|\|?\s*`).Split(constraint, -1) {
+		if alternative == "" {
+			return nil, fmt.Errorf("invalid Composer constraint: %s", constraint)
+		}
+
+		// Hyphen ranges contain spaces, so they are recognized before splitting
+		var pieces []string
+		if matches := composerHyphenRegex.FindStringSubmatch(alternative); matches != nil {
+			pieces = []string{matches[1] + " - " + matches[2]}
+		} else {
+			alternative = composerOperatorSpaceRegex.ReplaceAllString(alternative, "$1")
+			pieces = regexp.MustCompile(`\s*,\s*|\s+`).Split(alternative, -1)
+		}
+
+		ranges := []versionRange{{}}
+		for _, piece := range pieces {
+			pieceRanges, branch, err := parsed.parsePiece(piece)
+			if err != nil {
+				return nil, err
+			}
+			if branch != "" {
+				if len(pieces) > 1 {
+					return nil, fmt.Errorf("branch %s cannot be combined with other constraints", branch)
+				}
+				parsed.branches = append(parsed.branches, branch)
+				ranges = nil
+				continue
+			}
+			ranges = intersectRanges(ranges, pieceRanges, order)
+		}
+		parsed.ranges = append(parsed.ranges, ranges...)
+	}
+	return parsed, nil
+}
+
+// parsePiece parses a single Composer constraint without || or spaces, returning
+// either its version ranges or a branch name. Stability flags and pre-release
+// versions lower the stability the whole constraint allows.
+func (c *composerConstraint) parsePiece(piece string) ([]versionRange, string, error) {
+	flag := -1
+	if idx := strings.LastIndex(piece, "@"); idx != -1 {
+		rank, ok := composerStabilityFlags[strings.ToLower(piece[idx+1:])]
+		if !ok {
+			return nil, "", fmt.Errorf("invalid Composer stability flag: %s", piece[idx:])
+		}
+		flag = rank
+		if c.flag == -1 || rank < c.flag {
+			c.flag = rank
+		}
+		piece = piece[:idx]
+		if piece == "" {
+			piece = "*"
+		}
+	}
+
+	if composerBranchRegex.MatchString(piece) {
+		c.stability = min(c.stability, COMPOSER_RANK_DEV)
+		return nil, piece, nil
+	}
+
+	if matches := composerHyphenRegex.FindStringSubmatch(piece); matches != nil {
+		return c.hyphenRanges(matches[1], matches[2])
+	}
+
+	if matches := composerWildcardRegex.FindStringSubmatch(piece); matches != nil {
+		if matches[1] == "" {
+			return []versionRange{{}}, "", nil
+		}
+		parts, err := composerParts(matches[1])
+		if err != nil {
+			return nil, "", err
+		}
+		return []versionRange{composerSpan(parts, len(parts))}, "", nil
+	}
+
+	matches := composerOperatorRegex.FindStringSubmatch(piece)
+	if matches == nil {
+		return nil, "", fmt.Errorf("invalid Composer constraint: %s", piece)
+	}
+	operator, version := matches[1], matches[2]
+	bound, err := c.parseBound(version)
+	if err != nil {
+		return nil, "", err
+	}
+	parts := composerVersionRegex.FindStringSubmatch(version)[1]
+	given := strings.Count(parts, ".") + 1
+
+	switch operator {
+	case OP_TILDE, OP_PESSIMISTIC:
+		// ~1.2 allows the last given component to grow: >=1.2 <2.0
+		r := composerSpan(bound.parts, max(1, given-1))
+		if bound.suffix != "" {
+			r.lower = bound
+		}
+		return []versionRange{r}, "", nil
+	case OP_CARET:
+		// ^1.2.3 locks the first non-zero component: >=1.2.3 <2.0.0, ^0.3 is >=0.3 <0.4
+		position := 1
+		switch {
+		case bound.parts[0] != "0" || given < 2:
+		case componentAt(bound.parts, 1) != "0" || given < 3:
+			position = 2
+		default:
+			position = 3
+		}
+		r := composerSpan(bound.parts, position)
+		if bound.suffix != "" {
+			r.lower = bound
+		}
+		return []versionRange{r}, "", nil
+	}
+
+	// Like composer/semver, >= and < include the pre-releases of their version
+	// unless the version names a stability itself
+	if bound.suffix == "" {
+		switch {
+		case flag != -1 && operator != OP_EQUAL && operator != OP_EQUAL_EQUAL && operator != "":
+			bound.suffix = "-" + composerSuffixLabels[flag][0]
+		case operator == OP_GREATER_EQUAL || operator == OP_LESS:
+			bound.suffix = "-dev"
+		}
+	}
+	exclusive := *bound
+	exclusive.inclusive = false
+
+	switch operator {
+	case "", OP_EQUAL, OP_EQUAL_EQUAL:
+		return []versionRange{{lower: bound, upper: bound}}, "", nil
+	case OP_NOT_EQUAL, "<>":
+		return []versionRange{{upper: &exclusive}, {lower: &exclusive}}, "", nil
+	case OP_GREATER_EQUAL:
+		return []versionRange{{lower: bound}}, "", nil
+	case OP_GREATER:
+		return []versionRange{{lower: &exclusive}}, "", nil
+	case OP_LESS_EQUAL:
+		return []versionRange{{upper: bound}}, "", nil
+	default: // OP_LESS
+		return []versionRange{{upper: &exclusive}}, "", nil
+	}
+}
+
+// hyphenRanges returns the range of a hyphen constraint such as 1.0 - 2.0. A partial
+// upper version includes all its sub-versions: 1.0 - 2.0 means >=1.0 <2.1.
+func (c *composerConstraint) hyphenRanges(low, high string) ([]versionRange, string, error) {
+	lower, err := c.parseBound(low)
+	if err != nil {
+		return nil, "", err
+	}
+	upper, err := c.parseBound(high)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if lower.suffix == "" {
+		lower.suffix = "-dev"
+	}
+	given := strings.Count(composerVersionRegex.FindStringSubmatch(high)[1], ".") + 1
+	if upper.suffix == "" && given < 3 {
+		upper = composerSpan(upper.parts, given).upper
+	}
+	return []versionRange{{lower: lower, upper: upper}}, "", nil
+}
+
+// parseBound parses a Composer version into an inclusive range bound, and lowers the
+// constraint's stability when the version is a pre-release.
+func (c *composerConstraint) parseBound(version string) (*rangeBound, error) {
+	matches := composerVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid Composer version: %s", version)
+	}
+	parts, err := composerParts(matches[1])
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := parseComposerSuffix(matches[2])
+	c.stability = min(c.stability, suffix.rank)
+	return &rangeBound{parts: parts, suffix: matches[2], inclusive: true}, nil
+}
+
+// composerParts parses dot-separated numeric version components.
+func composerParts(version string) ([]string, error) {
+	parts, ok := parseDecimalParts(version)
+	if !ok {
+		return nil, fmt.Errorf("invalid Composer version: %s", version)
+	}
+	return parts, nil
+}
+
+// composerSpan returns the range from parts (including its dev versions) up to, but
+// excluding, the dev versions of parts with component position-1 incremented.
+// composerSpan([1 2], 1) is >=1.2.0.0-dev <2.0.0.0-dev.
+func composerSpan(parts []string, position int) versionRange {
+	upper := make([]string, position)
+	for i := range upper {
+		upper[i] = componentAt(parts, i)
+	}
+	upper[position-1] = incrementDecimal(upper[position-1])
+	return versionRange{
+		lower: &rangeBound{parts: parts, suffix: "-dev", inclusive: true},
+		upper: &rangeBound{parts: upper, suffix: "-dev"},
+	}
+}
+
+// parseComposerSuffix parses a suffix such as -beta2, -RC1, .p3 or -dev. Unknown
+// suffixes cannot come out of composerVersionRegex and are treated as stable.
+func parseComposerSuffix(suffix string) composerSuffix {
+	matches := composerSuffixRegex.FindStringSubmatch(suffix)
+	if matches == nil {
+		return composerSuffix{rank: COMPOSER_RANK_STABLE, number: COMPOSER_NO_NUMBER}
+	}
+
+	number := COMPOSER_NO_NUMBER
+	if matches[2] != "" {
+		number, _ = strconv.Atoi(matches[2])
+	}
+	label := strings.ToLower(matches[1])
+	for rank, labels := range composerSuffixLabels {
+		for _, known := range labels {
+			if label == known {
+				return composerSuffix{rank: rank, number: number}
+			}
+		}
+	}
+	return composerSuffix{rank: COMPOSER_RANK_STABLE, number: COMPOSER_NO_NUMBER}
+}
+
+// composerSuffixOrder implements suffixOrder for Composer stability suffixes.
+type composerSuffixOrder struct {
+	// minimum is the least stable rank that may match
+	minimum int
+}
+
+// compare orders two Composer suffixes by rank, then number.
+func (o composerSuffixOrder) compare(a, b string) int {
+	sa, sb := parseComposerSuffix(a), parseComposerSuffix(b)
+	if sa.rank != sb.rank {
+		return compareInts(sa.rank, sb.rank)
+	}
+	return compareInts(sa.number, sb.number)
+}
+
+// any matches every suffix at least as stable as the minimum stability.
+func (o composerSuffixOrder) any() string {
+	var alternatives []string
+	for rank := COMPOSER_RANK_DEV; rank <= COMPOSER_RANK_PATCH; rank++ {
+		alternatives = append(alternatives, o.rankPattern(rank, COMPOSER_NO_NUMBER, composerNoLimit))
+	}
+	pattern, _ := groupQualifiers(alternatives)
+	return pattern
+}
+
+// atLeast matches suffixes ordered after s (or equal to it when inclusive).
+func (o composerSuffixOrder) atLeast(s string, inclusive bool) (string, bool) {
+	q := parseComposerSuffix(s)
+	alternatives := []string{o.sameRank(q.rank, q.number, inclusive, composerNoLimit, true)}
+	for rank := q.rank + 1; rank <= COMPOSER_RANK_PATCH; rank++ {
+		alternatives = append(alternatives, o.rankPattern(rank, COMPOSER_NO_NUMBER, composerNoLimit))
+	}
+	return groupQualifiers(alternatives)
+}
+
+// atMost matches suffixes ordered before s (or equal to it when inclusive).
+func (o composerSuffixOrder) atMost(s string, inclusive bool) (string, bool) {
+	q := parseComposerSuffix(s)
+	var alternatives []string
+	for rank := COMPOSER_RANK_DEV; rank < q.rank; rank++ {
+		alternatives = append(alternatives, o.rankPattern(rank, COMPOSER_NO_NUMBER, composerNoLimit))
+	}
+	alternatives = append(alternatives, o.sameRank(q.rank, COMPOSER_NO_NUMBER, true, q.number, inclusive))
+	return groupQualifiers(alternatives)
+}
+
+// between matches suffixes ordered between lo and hi.
+func (o composerSuffixOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	qlo, qhi := parseComposerSuffix(lo), parseComposerSuffix(hi)
+	if qlo.rank > qhi.rank {
+		return "", false
+	}
+	if qlo.rank == qhi.rank {
+		return groupQualifiers([]string{o.sameRank(qlo.rank, qlo.number, loInclusive, qhi.number, hiInclusive)})
+	}
+
+	alternatives := []string{o.sameRank(qlo.rank, qlo.number, loInclusive, composerNoLimit, true)}
+	for rank := qlo.rank + 1; rank < qhi.rank; rank++ {
+		alternatives = append(alternatives, o.rankPattern(rank, COMPOSER_NO_NUMBER, composerNoLimit))
+	}
+	alternatives = append(alternatives, o.sameRank(qhi.rank, COMPOSER_NO_NUMBER, true, qhi.number, hiInclusive))
+	return groupQualifiers(alternatives)
+}
+
+// sameRank matches suffixes of the given rank whose number lies between lo and hi.
+// It returns NEVER_MATCH when no number fits.
+func (o composerSuffixOrder) sameRank(rank, lo int, loInclusive bool, hi int, hiInclusive bool) string {
+	if !loInclusive {
+		lo++
+	}
+	if hi != composerNoLimit && !hiInclusive {
+		if hi == COMPOSER_NO_NUMBER {
+			return NEVER_MATCH
+		}
+		hi--
+	}
+	if hi != composerNoLimit && lo > hi {
+		return NEVER_MATCH
+	}
+	return o.rankPattern(rank, lo, hi)
+}
+
+// rankPattern matches suffixes of the given rank whose number lies between lo and hi,
+// where COMPOSER_NO_NUMBER stands for a missing number and composerNoLimit for an
+// unbounded hi. Ranks less stable than the minimum never match.
+func (o composerSuffixOrder) rankPattern(rank, lo, hi int) string {
+	if rank < o.minimum {
+		return NEVER_MATCH
+	}
+
+	labels := `[._-]?(?i:` + strings.Join(composerSuffixLabels[rank], REGEX_OR) + `)`
+	switch rank {
+	case COMPOSER_RANK_DEV:
+		if lo > COMPOSER_NO_NUMBER {
+			return NEVER_MATCH
+		}
+		return labels
+	case COMPOSER_RANK_STABLE:
+		if lo > COMPOSER_NO_NUMBER {
+			return NEVER_MATCH
+		}
+		return `(?:` + labels + `)?`
+	}
+
+	switch {
+	case lo <= COMPOSER_NO_NUMBER && hi == COMPOSER_NO_NUMBER:
+		return labels
+	case lo <= COMPOSER_NO_NUMBER && hi == composerNoLimit:
+		return labels + `(?:[.-]?\d+)?`
+	case lo <= COMPOSER_NO_NUMBER:
+		return labels + `(?:[.-]?` + NumRange(0, hi) + `)?`
+	case hi == composerNoLimit:
+		return labels + `[.-]?` + NumGreaterOrEqual(lo)
+	default:
+		return labels + `[.-]?` + NumRange(lo, hi)
+	}
+}
@@ -0,0 +1,239 @@
+// Package convert provides tests for PHP Composer version handling functionality.
+// This file contains unit tests for Composer constraint parsing, stability ordering
+// and regex generation.
+package convert
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestParseComposerConstraint tests validation of Composer constraints.
+func TestParseComposerConstraint(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{" ^1.2@beta ", false, "^1.2@beta"},
+		{"~1.2 || dev-main", false, "~1.2 || dev-main"},
+		{">= 1.0, < 2.0", false, ">= 1.0, < 2.0"},
+		{"1.0 - 2.0", false, "1.0 - 2.0"},
+		{"@dev", false, "@dev"},
+		{"", true, ""},
+		{"~", true, ""},
+		{"foo", true, ""},
+		{"1.0 ||", true, ""},
+		{">=1.0@unstable", true, ""},
+		{"dev-main 1.0", true, ""},
+		{"1.2.3.4.5", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := parseComposerConstraint(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseComposerConstraint(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseComposerConstraint(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if constraint.Operator != OP_COMPOSER_CONSTRAINT {
+				t.Errorf("expected operator %q, got %q", OP_COMPOSER_CONSTRAINT, constraint.Operator)
+			}
+			if constraint.Version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, constraint.Version)
+			}
+		})
+	}
+}
+
+// TestComposerConstraintRegex tests regex generation for Composer constraints.
+func TestComposerConstraintRegex(t *testing.T) {
+	tests := []struct {
+		constraint     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			constraint:     "~1.2",
+			shouldMatch:    []string{"1.2", "1.2.0", "1.9.0", "v1.5.3", "1.99"},
+			shouldNotMatch: []string{"2.0.0", "1.1.9", "1.3.0-beta1", "2.0.0-dev"},
+		},
+		{
+			constraint:     "~1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.2.9", "1.2.30.1"},
+			shouldNotMatch: []string{"1.2.2", "1.3.0", "1.3"},
+		},
+		{
+			constraint:     "^1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.9.0", "v1.2.4"},
+			shouldNotMatch: []string{"1.2.2", "2.0.0", "2.0.0-RC1"},
+		},
+		{
+			constraint:     "^0.3",
+			shouldMatch:    []string{"0.3", "0.3.0", "0.3.9"},
+			shouldNotMatch: []string{"0.2.9", "0.4.0", "1.0.0"},
+		},
+		{
+			constraint:     "^0.0.3",
+			shouldMatch:    []string{"0.0.3", "0.0.3.1"},
+			shouldNotMatch: []string{"0.0.2", "0.0.4", "0.1.0"},
+		},
+		{
+			constraint:     "1.2.*",
+			shouldMatch:    []string{"1.2", "1.2.0", "1.2.15", "1.2.3.4"},
+			shouldNotMatch: []string{"1.1", "1.3.0", "1.2.0-beta1"},
+		},
+		{
+			constraint:     "1.x",
+			shouldMatch:    []string{"1.0.0", "1.9.9"},
+			shouldNotMatch: []string{"0.9", "2.0"},
+		},
+		{
+			constraint:     "^1.2@beta",
+			shouldMatch:    []string{"1.2.0", "1.2.0-beta1", "1.3.0-beta1", "1.5.0-RC2", "1.9"},
+			shouldNotMatch: []string{"1.3.0-alpha1", "1.3.0-dev", "2.0.0-beta1", "1.1.0"},
+		},
+		{
+			constraint:     "@dev",
+			shouldMatch:    []string{"0.1", "1.0.0-dev", "2.0.0-alpha", "3.0.0-p1"},
+			shouldNotMatch: []string{"dev-main", "1.0.0-foo"},
+		},
+		{
+			constraint:     "*",
+			shouldMatch:    []string{"0", "1.0.0", "1.0.0-stable", "1.0.0-patch1"},
+			shouldNotMatch: []string{"1.0.0-beta", "1.0.0-dev"},
+		},
+		{
+			constraint:     "dev-main",
+			shouldMatch:    []string{"dev-main"},
+			shouldNotMatch: []string{"dev-master", "1.0.0", "dev-main2"},
+		},
+		{
+			constraint:     "^1.0 || 2.x-dev",
+			shouldMatch:    []string{"1.5.0", "2.x-dev"},
+			shouldNotMatch: []string{"2.0.0", "2.1.x-dev"},
+		},
+		{
+			constraint:     "^1.2@beta || dev-main",
+			shouldMatch:    []string{"1.3.0-beta2", "dev-main"},
+			shouldNotMatch: []string{"1.3.0-alpha1", "1.3.0-dev"},
+		},
+		{
+			constraint:     ">=1.0 <1.1 | >=1.2",
+			shouldMatch:    []string{"1.0.0", "1.0.5", "1.2.0", "3.0"},
+			shouldNotMatch: []string{"0.9", "1.1.0", "1.1.9"},
+		},
+		{
+			constraint:     ">= 1.0, < 2.0",
+			shouldMatch:    []string{"1.0.0", "1.9.9"},
+			shouldNotMatch: []string{"2.0.0", "2.0.0-beta1", "0.9"},
+		},
+		{
+			constraint:     "1.0 - 2.0",
+			shouldMatch:    []string{"1.0.0", "2.0.0", "2.0.9"},
+			shouldNotMatch: []string{"0.9", "2.1.0"},
+		},
+		{
+			constraint:     "1.0.0 - 2.1.0",
+			shouldMatch:    []string{"1.0.0", "2.1.0"},
+			shouldNotMatch: []string{"2.1.1", "2.2"},
+		},
+		{
+			constraint:     "!=1.5",
+			shouldMatch:    []string{"1.4", "1.5.1", "1.6"},
+			shouldNotMatch: []string{"1.5", "1.5.0", "1.5.0.0"},
+		},
+		{
+			constraint:     ">=1.0-beta2",
+			shouldMatch:    []string{"1.0.0-beta2", "1.0.0-beta.3", "1.0.0-RC1", "1.0.0", "1.1-beta1"},
+			shouldNotMatch: []string{"1.0.0-beta1", "1.0.0-alpha3", "1.0.0-dev", "0.9"},
+		},
+		{
+			constraint:     "1.0.0",
+			shouldMatch:    []string{"1.0.0", "1.0", "v1.0.0", "1.0.0.0"},
+			shouldNotMatch: []string{"1.0.1", "1.0.0-RC1"},
+		},
+		{
+			constraint:     "<2.0",
+			shouldMatch:    []string{"1.9", "0.0.1"},
+			shouldNotMatch: []string{"2.0", "2.0.0-beta1", "1.9.0-beta1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.constraint, Options{Ecosystem: ECOSYSTEM_COMPOSER})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
+
+// TestComposerSuffixOrderPatterns compares the suffix patterns of composerSuffixOrder
+// with its compare method over a corpus of stability suffixes.
+func TestComposerSuffixOrderPatterns(t *testing.T) {
+	corpus := []string{
+		"", "-dev", "-alpha", "-alpha1", "-a2", "-beta", "-b0", "-beta1", "-beta.10", "-RC",
+		"-RC1", "-rc2", "-stable", "-patch", "-p1", "-pl2",
+	}
+	bounds := []string{"", "-dev", "-beta", "-beta1", "-RC2", "-patch1"}
+
+	for _, minimum := range []int{COMPOSER_RANK_DEV, COMPOSER_RANK_BETA} {
+		order := composerSuffixOrder{minimum: minimum}
+		check := func(name, pattern string, ok bool, want func(string) bool) {
+			re := regexp.MustCompile(NEVER_MATCH_PATTERN)
+			if ok {
+				re = regexp.MustCompile(REGEX_START + pattern + REGEX_END)
+			}
+			for _, suffix := range corpus {
+				expected := want(suffix) && parseComposerSuffix(suffix).rank >= minimum
+				if re.MatchString(suffix) != expected {
+					t.Errorf("%s: pattern %q matching %q: expected %v", name, pattern, suffix, expected)
+				}
+			}
+		}
+
+		check("any", order.any(), true, func(string) bool { return true })
+		for _, lo := range bounds {
+			for _, inclusive := range []bool{true, false} {
+				pattern, ok := order.atLeast(lo, inclusive)
+				check("atLeast "+lo, pattern, ok, func(s string) bool {
+					c := order.compare(s, lo)
+					return c > 0 || (c == 0 && inclusive)
+				})
+
+				pattern, ok = order.atMost(lo, inclusive)
+				check("atMost "+lo, pattern, ok, func(s string) bool {
+					c := order.compare(s, lo)
+					return c < 0 || (c == 0 && inclusive)
+				})
+
+				for _, hi := range bounds {
+					pattern, ok = order.between(lo, inclusive, hi, !inclusive)
+					check("between "+lo+" "+hi, pattern, ok, func(s string) bool {
+						cl, ch := order.compare(s, lo), order.compare(s, hi)
+						return (cl > 0 || (cl == 0 && inclusive)) && (ch < 0 || (ch == 0 && !inclusive))
+					})
+				}
+			}
+		}
+	}
+}
@@ -8,10 +8,16 @@
 //   - Go modules (v1.2.3, pseudo-versions)
 //   - C# NuGet (1.2.3.4567, pre-release patterns)
 //   - Python (1.2.3, ~=1.2.3 compatible release)
-//   - Ruby (~>1.2.3 pessimistic operator)
+//   - Ruby (~>1.2.3 pessimistic operator, and full requirement lists in Ruby mode)
+//   - PHP Composer (~1.2, 1.2.*, ^1.2@beta || dev-main in Composer mode)
+//   - Rust Cargo (1.2.3 meaning ^1.2.3, ~1, >=1.2, <1.5 in Cargo mode)
+//   - Debian packages (>= 1:2.30-1ubuntu2, << 3.0 with dpkg ordering in Debian mode)
+//   - RPM packages (>= 1:2.30-1.el8 with rpmvercmp ordering in RPM mode)
+//   - Calendar versions (>=2024.10, <2025 for formats like YYYY.MM.MICRO in CalVer mode)
 //
 // The main entry points are VersionToRegex for converting version constraints
-// to compiled regular expressions, and VersionMatches for direct version
+// to compiled regular expressions, VersionToRegexWithOptions for ecosystem-specific
+// modes and other conversion options, and VersionMatches for direct version
 // matching without exposing the regex details.
 //
 // Example usage:
@@ -33,7 +39,6 @@ package convert
 import (
 	"fmt"
 	"regexp"
-	"strconv"
 	"strings"
 )
 
@@ -89,20 +94,46 @@ import (
 //	matches := regex.MatchString("1.2.5") // true
 //	matches = regex.MatchString("2.0.0")  // false
 func VersionToRegex(versionStr string) (*regexp.Regexp, error) {
-	// Parse the version constraint
-	constraint, err := parseVersionConstraint(versionStr)
+	return VersionToRegexWithOptions(versionStr, Options{})
+}
+
+// VersionToRegexWithOptions converts a version constraint string to a compiled regular
+// expression, using opts to adjust the conversion.
+//
+// It accepts the same constraint formats as VersionToRegex. The zero Options value
+// gives the same result as VersionToRegex.
+//
+// Parameters:
+//   - versionStr: The version constraint string to convert
+//   - opts: Conversion options, such as the Maven snapshot policy
+//
+// Returns:
+//   - *regexp.Regexp: Compiled regular expression matching the constraint
+//   - error: Error if the constraint cannot be parsed or converted
+//
+// Example:
+//
+//	// Releases between 1.0 and 2.0, without snapshots
+//	regex, err := VersionToRegexWithOptions("[1.0,2.0)", Options{MavenSnapshots: SNAPSHOTS_EXCLUDE})
+//	if err != nil {
+//		return err
+//	}
+//
+//	matches := regex.MatchString("1.5.0")          // true
+//	matches = regex.MatchString("1.5.0-SNAPSHOT") // false
+func VersionToRegexWithOptions(versionStr string, opts Options) (*regexp.Regexp, error) {
+	// Convert to the shortest equivalent pattern
+	pattern, err := VersionToPattern(versionStr, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse version constraint: %w", err)
+		return nil, err
 	}
 
-	// Convert to regex pattern
-	pattern, err := constraintToRegex(constraint)
+	// Surround it with the boundary and compile
+	bounded, err := boundedPattern(pattern, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to convert to regex: %w", err)
+		return nil, err
 	}
-
-	// Compile the regex
-	regex, err := regexp.Compile(pattern)
+	regex, err := regexp.Compile(bounded)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compile regex: %w", err)
 	}
@@ -197,6 +228,70 @@ func MustVersionToRegex(versionStr string) *regexp.Regexp {
 	return regex
 }
 
+// ParseConstraint parses a version constraint string into its operator and version,
+// using the syntax of opts.Ecosystem.
+//
+// Constraints of the default ecosystem are split into an operator such as OP_CARET
+// and a version. Other ecosystems validate the whole constraint and return an
+// ecosystem-specific operator such as OP_RUBY_REQUIREMENT, with the whole constraint
+// as the version.
+//
+// Parameters:
+//   - versionStr: The version constraint string to parse
+//   - opts: Conversion options selecting the ecosystem
+//
+// Returns:
+//   - *VersionConstraint: Parsed constraint with operator and version fields
+//   - error: Error if the ecosystem is unknown or the constraint is invalid
+//
+// Example:
+//
+//	constraint, err := ParseConstraint("^1.2.3", Options{})
+//	// constraint.Operator: "^", constraint.Version: "1.2.3"
+func ParseConstraint(versionStr string, opts Options) (*VersionConstraint, error) {
+	return parseConstraint(versionStr, opts)
+}
+
+// parseConstraint parses a version constraint string using the syntax of opts.Ecosystem.
+//
+// In ECOSYSTEM_AUTO and ECOSYSTEM_NUGET modes the format is detected by
+// parseVersionConstraint. Other ecosystems have their own parser, which validates the whole constraint and returns
+// an ecosystem-specific operator such as OP_RUBY_REQUIREMENT.
+//
+// Parameters:
+//   - versionStr: Raw version constraint string from user input
+//   - opts: Conversion options selecting the ecosystem
+//
+// Returns:
+//   - *VersionConstraint: Parsed constraint with operator and version fields
+//   - error: Error if the ecosystem is unknown or the constraint is invalid
+func parseConstraint(versionStr string, opts Options) (*VersionConstraint, error) {
+	switch opts.Ecosystem {
+	case ECOSYSTEM_AUTO, ECOSYSTEM_NUGET:
+		return parseVersionConstraint(versionStr)
+	case ECOSYSTEM_RUBY:
+		return parseRubyRequirement(versionStr)
+	case ECOSYSTEM_COMPOSER:
+		return parseComposerConstraint(versionStr)
+	case ECOSYSTEM_CARGO:
+		return parseCargoRequirement(versionStr)
+	case ECOSYSTEM_DEBIAN:
+		return parseDebianRelation(versionStr)
+	case ECOSYSTEM_RPM:
+		return parseRPMRequirement(versionStr)
+	case ECOSYSTEM_CALVER:
+		return parseCalVerConstraint(versionStr, opts)
+	case ECOSYSTEM_ADVISORY:
+		return parseAdvisoryRange(versionStr)
+	case ECOSYSTEM_NPM:
+		return parseNpmRange(versionStr)
+	case ECOSYSTEM_PYPI:
+		return parsePEP440Specifiers(versionStr)
+	default:
+		return nil, fmt.Errorf("unsupported ecosystem: %s", opts.Ecosystem)
+	}
+}
+
 // parseVersionConstraint parses a version constraint string into operator and version components.
 //
 // This function is the first stage of version constraint processing. It analyzes the input
@@ -204,9 +299,10 @@ func MustVersionToRegex(versionStr string) *regexp.Regexp {
 //
 // The parsing follows this precedence:
 // 1. Maven-style ranges with brackets: [1.0,2.0), (1.0,2.0]
-// 2. Multi-character operators: >=, <=, !=, ==, ~>, ~=
-// 3. Single-character operators: >, <, =, ^, ~
-// 4. No operator: defaults to exact match (==)
+// 2. Comparator lists of security advisories: >= 1.0.0, < 1.2.5 or <0.3.1 || >=1.0.0 <1.0.4
+// 3. Multi-character operators: >=, <=, !=, ==, ~>, ~=
+// 4. Single-character operators: >, <, =, ^, ~
+// 5. No operator: defaults to exact match (==)
 //
 // The function handles whitespace normalization and operator precedence to ensure
 // correct parsing of complex version constraints.
@@ -220,7 +316,8 @@ func MustVersionToRegex(versionStr string) *regexp.Regexp {
 //
 // Examples:
 //   - "^1.2.3" → VersionConstraint{Operator: "^", Version: "1.2.3"}
-//   - "[1.0,2.0)" → VersionConstraint{Operator: "maven-range", Version: "1.0,2.0"}
+//   - "[1.0,2.0)" → VersionConstraint{Operator: "maven-range", Version: "[1.0,2.0)"}
+//   - ">= 1.0.0, < 1.2.5" → VersionConstraint{Operator: "advisory-range", Version: ">=1.0.0, <1.2.5"}
 //   - "1.2.3" → VersionConstraint{Operator: "==", Version: "1.2.3"}
 func parseVersionConstraint(versionStr string) (*VersionConstraint, error) {
 	versionStr = strings.TrimSpace(versionStr)
@@ -230,6 +327,11 @@ func parseVersionConstraint(versionStr string) (*VersionConstraint, error) {
 		return parseMavenRange(versionStr)
 	}
 
+	// Lists of comparators, as written by security advisories
+	if isAdvisoryRange(versionStr) {
+		return parseAdvisoryRange(versionStr)
+	}
+
 	// Handle common operators - order matters for correct parsing
 	operators := []string{OP_GREATER_EQUAL, OP_LESS_EQUAL, OP_NOT_EQUAL, OP_EQUAL_EQUAL, OP_PESSIMISTIC, OP_COMPATIBLE, OP_GREATER, OP_LESS, OP_EQUAL, OP_CARET, OP_TILDE}
 
@@ -260,6 +362,9 @@ func parseVersionConstraint(versionStr string) (*VersionConstraint, error) {
 //   - Comparison operators: greaterThanEqualRegex, lessThanRegex, etc.
 //   - NPM ranges: caretRangeRegex, tildeRangeRegex
 //   - Other ecosystem ranges: compatibleReleaseRegex, mavenRangeRegex
+//   - Ecosystem requirement lists: rubyRequirementRegex, composerConstraintRegex,
+//     cargoRequirementRegex, debianRelationRegex, rpmRequirementRegex,
+//     calverConstraintRegex, advisoryRangeRegex, npmRangeRegex, pep440SpecifiersRegex
 //
 // Each regex generator implements the specific semantic rules for that constraint type,
 // handling version part comparison, pre-release identifiers, and build metadata according
@@ -267,6 +372,7 @@ func parseVersionConstraint(versionStr string) (*VersionConstraint, error) {
 //
 // Parameters:
 //   - constraint: Parsed version constraint with operator and version
+//   - opts: Conversion options passed on to the ecosystem-specific generators
 //
 // Returns:
 //   - string: Regular expression pattern string
@@ -274,30 +380,48 @@ func parseVersionConstraint(versionStr string) (*VersionConstraint, error) {
 //
 // The generated patterns are designed to be compiled into Go regexp.Regexp objects
 // for efficient version matching operations.
-func constraintToRegex(constraint *VersionConstraint) (string, error) {
+func constraintToRegex(constraint *VersionConstraint, opts Options) (string, error) {
 	version := constraint.Version
 
 	switch constraint.Operator {
 	case OP_EQUAL_EQUAL, OP_EQUAL:
-		return exactMatchRegex(version), nil
+		return exactMatchRegex(version, opts), nil
 	case OP_GREATER_EQUAL:
-		return greaterThanEqualRegex(version)
+		return greaterThanEqualRegex(version, opts)
 	case OP_LESS_EQUAL:
-		return lessThanEqualRegex(version)
+		return lessThanEqualRegex(version, opts)
 	case OP_GREATER:
-		return greaterThanRegex(version)
+		return greaterThanRegex(version, opts)
 	case OP_LESS:
-		return lessThanRegex(version)
+		return lessThanRegex(version, opts)
 	case OP_NOT_EQUAL:
-		return notEqualRegex(version)
+		return notEqualRegex(version, opts)
 	case OP_CARET: // NPM caret range
-		return caretRangeRegex(version)
+		return caretRangeRegex(version, opts)
 	case OP_TILDE, OP_PESSIMISTIC: // NPM tilde range / Ruby pessimistic operator
-		return tildeRangeRegex(version)
+		return tildeRangeRegex(version, opts)
 	case OP_COMPATIBLE: // Python compatible release
-		return compatibleReleaseRegex(version)
+		return compatibleReleaseRegex(version, opts)
 	case OP_MAVEN_RANGE: // Maven version ranges
-		return mavenRangeRegex(version)
+		return mavenRangeRegex(version, opts)
+	case OP_RUBY_REQUIREMENT: // RubyGems requirement lists
+		return rubyRequirementRegex(version, opts)
+	case OP_COMPOSER_CONSTRAINT: // PHP Composer constraints
+		return composerConstraintRegex(version, opts)
+	case OP_CARGO_REQUIREMENT: // Rust Cargo requirements
+		return cargoRequirementRegex(version, opts)
+	case OP_DEBIAN_RELATION: // Debian version relations
+		return debianRelationRegex(version)
+	case OP_RPM_REQUIREMENT: // RPM version requirements
+		return rpmRequirementRegex(version)
+	case OP_CALVER_CONSTRAINT: // Calendar version constraints
+		return calverConstraintRegex(version, opts)
+	case OP_ADVISORY_RANGE: // Security advisory version ranges
+		return advisoryRangeRegex(version, opts)
+	case OP_NPM_RANGE: // npm version ranges
+		return npmRangeRegex(version, opts)
+	case OP_PEP440_SPECIFIERS: // PEP 440 version specifiers
+		return pep440SpecifiersRegex(version, opts)
 	default:
 		return "", fmt.Errorf("unsupported operator: %s", constraint.Operator)
 	}
@@ -312,7 +436,8 @@ func constraintToRegex(constraint *VersionConstraint) (string, error) {
 // The function handles several version formats:
 //   - Wildcard versions (1.*, 2.1.*): Delegates to wildcardToRegex
 //   - Go module versions (v1.2.3): Delegates to goModuleVersionRegex
-//   - C# NuGet versions (1.2.3.4567, pre-release): Delegates to csharpVersionRegex
+//   - C# NuGet versions (1.2.3.4567, or any version in ECOSYSTEM_NUGET mode): Delegates
+//     to csharpVersionRegex
 //   - Standard semantic versions: Processes directly with pre-release and build metadata
 //
 // For standard semantic versions, the function:
@@ -321,8 +446,13 @@ func constraintToRegex(constraint *VersionConstraint) (string, error) {
 //   - Handles build metadata (1.2.3+build.1)
 //   - Allows optional pre-release/build suffixes when not explicitly specified
 //
+// Numeric components follow opts.LeadingZeros, so 1.2.3 only matches 01.02.3 when
+// leading zeros compare by value. A Maven snapshot policy other than the default
+// makes the version a Maven version, of which only snapshots or releases match.
+//
 // Parameters:
 //   - version: Version string to create exact match pattern for
+//   - opts: Conversion options, of which Ecosystem, LeadingZeros and MavenSnapshots apply
 //
 // Returns:
 //   - string: Regex pattern for exact version matching
@@ -331,10 +461,18 @@ func constraintToRegex(constraint *VersionConstraint) (string, error) {
 //   - exactMatchRegex("1.2.3") → pattern matching 1.2.3 with optional pre-release
 //   - exactMatchRegex("1.*") → pattern matching any 1.x.x version
 //   - exactMatchRegex("v1.2.3") → Go module pattern for v1.2.3
-func exactMatchRegex(version string) string {
+func exactMatchRegex(version string, opts Options) string {
+	// Handle Maven soft requirements under a snapshot policy, and -SNAPSHOT versions,
+	// which their timestamped builds compare equal to
+	if opts.MavenSnapshots != SNAPSHOTS_INCLUDE || mavenSnapshotQualifierRegex.MatchString(version) {
+		if pattern, ok := mavenExactRegex(version, opts); ok {
+			return pattern
+		}
+	}
+
 	// Handle wildcards and partial versions
 	if strings.Contains(version, "*") {
-		return wildcardToRegex(version)
+		return wildcardToRegex(version, opts)
 	}
 
 	// Handle Go module versions (with v prefix)
@@ -342,9 +480,9 @@ func exactMatchRegex(version string) string {
 		return goModuleVersionRegex(version)
 	}
 
-	// Handle C# 4-part versions
-	if isCSharpVersion(version) {
-		return csharpVersionRegex(version)
+	// Handle C# 4-part versions, and every NuGet version in NuGet mode
+	if isNuGetExact(version, opts) {
+		return csharpVersionRegex(version, opts)
 	}
 
 	// Split version into parts and pre-release/build metadata
@@ -368,7 +506,11 @@ func exactMatchRegex(version string) string {
 		if i > 0 {
 			pattern += `\.`
 		}
-		pattern += regexp.QuoteMeta(part)
+		if isDigits(part) {
+			pattern += opts.LeadingZeros.Equal(part)
+		} else {
+			pattern += regexp.QuoteMeta(part)
+		}
 	}
 
 	// Add pre-release pattern
@@ -391,20 +533,22 @@ func exactMatchRegex(version string) string {
 
 // wildcardToRegex converts wildcard patterns to regex
 // Result: ^1\.\d+\.\d+(?:-[a-zA-Z0-9\-\.]+)?(?:\+[a-zA-Z0-9\-\.]+)?$ (for "1.*")
-func wildcardToRegex(version string) string {
+func wildcardToRegex(version string, opts Options) string {
 	parts := strings.Split(version, ".")
-	patternParts := convertWildcardParts(parts)
-	patternParts = padToSemanticVersion(patternParts, parts)
+	patternParts := convertWildcardParts(parts, opts.LeadingZeros)
+	patternParts = padToSemanticVersion(patternParts, parts, opts.LeadingZeros)
 
 	return REGEX_START + strings.Join(patternParts, VERSION_DOT) + VERSION_SUFFIX_PATTERN + REGEX_END
 }
 
 // convertWildcardParts converts each version part, replacing "*" with digit matcher
-func convertWildcardParts(parts []string) []string {
+func convertWildcardParts(parts []string, zeros LeadingZeroPolicy) []string {
 	result := make([]string, len(parts))
 	for i, part := range parts {
 		if part == "*" {
-			result[i] = VERSION_DIGITS
+			result[i] = zeros.Digits()
+		} else if isDigits(part) {
+			result[i] = zeros.Equal(part)
 		} else {
 			result[i] = regexp.QuoteMeta(part)
 		}
@@ -414,7 +558,7 @@ func convertWildcardParts(parts []string) []string {
 
 // padToSemanticVersion ensures we have 3 version parts (major.minor.patch)
 // by appending digit matchers if the last part is a wildcard
-func padToSemanticVersion(patternParts, originalParts []string) []string {
+func padToSemanticVersion(patternParts, originalParts []string, zeros LeadingZeroPolicy) []string {
 	if len(originalParts) == 0 {
 		return patternParts
 	}
@@ -425,117 +569,71 @@ func padToSemanticVersion(patternParts, originalParts []string) []string {
 	}
 
 	for len(patternParts) < 3 {
-		patternParts = append(patternParts, VERSION_DIGITS)
+		patternParts = append(patternParts, zeros.Digits())
 	}
 	return patternParts
 }
 
 // greaterThanEqualRegex creates a regex for >= version matching
-func greaterThanEqualRegex(version string) (string, error) {
-	major, minor, patch, err := parseVersionParts(version)
-	if err != nil {
-		return "", err
-	}
-
-	var patterns []string
-
-	// Versions with major > target major
-	patterns = append(patterns, NumGreaterOrEqual(major+1)+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS)
-
-	// Versions with major = target and minor > target minor
-	patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+NumGreaterOrEqual(minor+1)+VERSION_DOT+VERSION_DIGITS)
-
-	// Versions with major = target, minor = target, and patch >= target patch
-	patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+fmt.Sprintf("%d", minor)+VERSION_DOT+NumGreaterOrEqual(patch))
-
-	pattern := REGEX_START + "(?:" + strings.Join(patterns, REGEX_OR) + ")" + VERSION_SUFFIX_PATTERN + REGEX_END
-	return pattern, nil
+func greaterThanEqualRegex(version string, opts Options) (string, error) {
+	return comparisonRegex(version, opts, func(bound []string) versionRange {
+		return versionRange{lower: &rangeBound{parts: bound, inclusive: true}}
+	})
 }
 
 // lessThanEqualRegex creates a regex for <= version matching
-func lessThanEqualRegex(version string) (string, error) {
-	major, minor, patch, err := parseVersionParts(version)
-	if err != nil {
-		return "", err
-	}
-
-	var patterns []string
-
-	// Versions with major < target major
-	if major > 0 {
-		patterns = append(patterns, NumLessOrEqual(major-1)+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS)
-	}
-
-	// Versions with major = target and minor < target minor
-	if minor > 0 {
-		patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+NumLessOrEqual(minor-1)+VERSION_DOT+VERSION_DIGITS)
-	}
-
-	// Versions with major = target, minor = target, and patch <= target patch
-	patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+fmt.Sprintf("%d", minor)+VERSION_DOT+NumLessOrEqual(patch))
-
-	pattern := REGEX_START + "(?:" + strings.Join(patterns, REGEX_OR) + ")" + VERSION_SUFFIX_PATTERN + REGEX_END
-	return pattern, nil
+func lessThanEqualRegex(version string, opts Options) (string, error) {
+	return comparisonRegex(version, opts, func(bound []string) versionRange {
+		return versionRange{upper: &rangeBound{parts: bound, inclusive: true}}
+	})
 }
 
 // greaterThanRegex creates a regex for > version matching
-func greaterThanRegex(version string) (string, error) {
-	major, minor, patch, err := parseVersionParts(version)
-	if err != nil {
-		return "", err
-	}
-
-	var patterns []string
-
-	// Versions with major > target major
-	patterns = append(patterns, NumGreaterOrEqual(major+1)+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS)
-
-	// Versions with major = target and minor > target minor
-	patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+NumGreaterOrEqual(minor+1)+VERSION_DOT+VERSION_DIGITS)
-
-	// Versions with major = target, minor = target, and patch > target patch
-	patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+fmt.Sprintf("%d", minor)+VERSION_DOT+NumGreaterOrEqual(patch+1))
-
-	pattern := REGEX_START + "(?:" + strings.Join(patterns, REGEX_OR) + ")" + VERSION_SUFFIX_PATTERN + REGEX_END
-	return pattern, nil
+func greaterThanRegex(version string, opts Options) (string, error) {
+	return comparisonRegex(version, opts, func(bound []string) versionRange {
+		return versionRange{lower: &rangeBound{parts: bound}}
+	})
 }
 
 // lessThanRegex creates a regex for < version matching
-func lessThanRegex(version string) (string, error) {
-	major, minor, patch, err := parseVersionParts(version)
+func lessThanRegex(version string, opts Options) (string, error) {
+	return comparisonRegex(version, opts, func(bound []string) versionRange {
+		return versionRange{upper: &rangeBound{parts: bound}}
+	})
+}
+
+// comparisonRegex creates a regex for a comparison operator, whose range is built
+// from the numeric components of version.
+//
+// Versions may have any number of components. With the default LENGTH_PAD policy,
+// missing components compare as zero, so >=1.2.3 matches 1.2.3.4 and 1.3; with
+// LENGTH_STRICT, only versions with as many components as the constraint match.
+// Pre-release and build suffixes are allowed but not ordered, so >=1.2.3 matches
+// 1.2.3-alpha while >1.2.3 does not.
+// Result: ^(?:(?:\d{2,}|[2-9])(?:\.\d+)*...|1(?:...))$ matching 1.2.3.4, 1.3, 2.0.0-rc.1 but not 1.2.2 (for ">=1.2.3")
+func comparisonRegex(version string, opts Options, rangeFor func(bound []string) versionRange) (string, error) {
+	parts, err := parseVersionComponents(version)
 	if err != nil {
 		return "", err
 	}
 
-	var patterns []string
-
-	// Versions with major < target major
-	if major > 0 {
-		patterns = append(patterns, NumLessOrEqual(major-1)+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS)
-	}
-
-	// Versions with major = target and minor < target minor
-	if minor > 0 {
-		patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+NumLessOrEqual(minor-1)+VERSION_DOT+VERSION_DIGITS)
-	}
-
-	// Versions with major = target, minor = target, and patch < target patch
-	if patch > 0 {
-		patterns = append(patterns, fmt.Sprintf("%d", major)+VERSION_DOT+fmt.Sprintf("%d", minor)+VERSION_DOT+NumLessOrEqual(patch-1))
+	builder := seqBuilder{order: unorderedSuffixOrder{}, zeros: opts.LeadingZeros}
+	if opts.VersionLength == LENGTH_STRICT {
+		builder.fixed = len(parts)
 	}
 
-	if len(patterns) == 0 {
-		return EMPTY_MATCH_PATTERN, nil // No versions match (empty regex)
+	pattern, ok := builder.rangePattern(rangeFor(parts))
+	if !ok {
+		// Only < can exclude every version, as in <0.0.0
+		return NEVER_MATCH_PATTERN, nil
 	}
-
-	pattern := REGEX_START + "(?:" + strings.Join(patterns, REGEX_OR) + ")" + VERSION_SUFFIX_PATTERN + REGEX_END
-	return pattern, nil
+	return REGEX_START + pattern + REGEX_END, nil
 }
 
 // notEqualRegex creates a regex for != version matching
-func notEqualRegex(version string) (string, error) {
+func notEqualRegex(version string, opts Options) (string, error) {
 	// This is complex with regex alone - we'll use negative lookahead
-	exactPattern := exactMatchRegex(version)
+	exactPattern := exactMatchRegex(version, opts)
 
 	// Remove ^ and $ from exact pattern for use in negative lookahead
 	exactCore := strings.TrimPrefix(strings.TrimSuffix(exactPattern, REGEX_END), REGEX_START)
@@ -570,21 +668,22 @@ func notEqualRegex(version string) (string, error) {
 // Examples:
 //   - caretRangeRegex("1.2.3") → matches 1.2.3, 1.2.4, 1.3.0 but not 2.0.0
 //   - caretRangeRegex("0.2.3") → matches 0.2.3, 0.2.4 but not 0.3.0
-func caretRangeRegex(version string) (string, error) {
+func caretRangeRegex(version string, opts Options) (string, error) {
 	major, minor, _, err := parseVersionParts(version)
 	if err != nil {
 		return "", err
 	}
 
-	// ^1.2.3 := >=1.2.3 <2.0.0 (compatible within same major version)
-	pattern := fmt.Sprintf(CARET_RANGE_TEMPLATE, major)
+	// ^1.2.3 := >=1.2.3 <2.0.0 (compatible within same major version), as in CARET_RANGE_TEMPLATE
+	zeros := opts.LeadingZeros
+	parts := []string{zeros.Equal(major), zeros.Digits(), zeros.Digits()}
 
 	// Special case: ^0.y.z is treated as 0.y.z exactly (since 0.x.x is considered unstable)
-	if major == 0 {
-		pattern = fmt.Sprintf(CARET_RANGE_ZERO_MAJOR_TEMPLATE, minor)
+	if major == "0" {
+		parts[1] = zeros.Equal(minor)
 	}
 
-	return pattern, nil
+	return REGEX_START + strings.Join(parts, VERSION_DOT) + VERSION_SUFFIX_PATTERN + REGEX_END, nil
 }
 
 // tildeRangeRegex creates a regex for NPM tilde range (~1.2.3).
@@ -612,22 +711,23 @@ func caretRangeRegex(version string) (string, error) {
 // Examples:
 //   - tildeRangeRegex("1.2.3") → matches 1.2.3, 1.2.4, 1.2.10 but not 1.3.0
 //   - tildeRangeRegex("2.1.0") → matches 2.1.0, 2.1.5 but not 2.2.0
-func tildeRangeRegex(version string) (string, error) {
+func tildeRangeRegex(version string, opts Options) (string, error) {
 	major, minor, _, err := parseVersionParts(version)
 	if err != nil {
 		return "", err
 	}
 
-	// ~1.2.3 := >=1.2.3 <1.3.0 (compatible within same minor version)
-	pattern := fmt.Sprintf(TILDE_RANGE_TEMPLATE, major, minor)
+	// ~1.2.3 := >=1.2.3 <1.3.0 (compatible within same minor version), as in TILDE_RANGE_TEMPLATE
+	zeros := opts.LeadingZeros
+	parts := []string{zeros.Equal(major), zeros.Equal(minor), zeros.Digits()}
 
-	return pattern, nil
+	return REGEX_START + strings.Join(parts, VERSION_DOT) + VERSION_SUFFIX_PATTERN + REGEX_END, nil
 }
 
 // compatibleReleaseRegex creates a regex for Python compatible release (~=1.2.3)
-func compatibleReleaseRegex(version string) (string, error) {
+func compatibleReleaseRegex(version string, opts Options) (string, error) {
 	// ~=1.2.3 is equivalent to >=1.2.3, ==1.2.*
-	return tildeRangeRegex(version)
+	return tildeRangeRegex(version, opts)
 }
 
 // parseVersionParts extracts major, minor, and patch version numbers from a version string.
@@ -646,23 +746,40 @@ func compatibleReleaseRegex(version string) (string, error) {
 // 1. Remove pre-release identifiers (-alpha, -beta.1, etc.)
 // 2. Remove build metadata (+build.1, +20210101, etc.)
 // 3. Split remaining version on '.' delimiter
-// 4. Parse each component as integer with validation
+// 4. Validate each component as a decimal string, which may exceed int
 //
 // Parameters:
 //   - version: Version string to parse (e.g., "1.2.3-alpha+build.1")
 //
 // Returns:
-//   - major: Major version number (0 if not specified)
-//   - minor: Minor version number (0 if not specified)
-//   - patch: Patch version number (0 if not specified)
+//   - major: Major version number ("0" if not specified)
+//   - minor: Minor version number ("0" if not specified)
+//   - patch: Patch version number ("0" if not specified)
 //   - err: Error if any version component is invalid or non-numeric
 //
 // Examples:
-//   - parseVersionParts("1.2.3") → (1, 2, 3, nil)
-//   - parseVersionParts("2.0.0-beta.1") → (2, 0, 0, nil)
-//   - parseVersionParts("1.5") → (1, 5, 0, nil)
-//   - parseVersionParts("3") → (3, 0, 0, nil)
-func parseVersionParts(version string) (major, minor, patch int, err error) {
+//   - parseVersionParts("1.2.3") → ("1", "2", "3", nil)
+//   - parseVersionParts("2.0.0-beta.1") → ("2", "0", "0", nil)
+//   - parseVersionParts("1.5") → ("1", "5", "0", nil)
+//   - parseVersionParts("20240115103045000000.1") → ("20240115103045000000", "1", "0", nil)
+func parseVersionParts(version string) (major, minor, patch string, err error) {
+	parts, err := parseVersionComponents(version)
+	if err != nil {
+		return "", "", "", err
+	}
+	return componentAt(parts, 0), componentAt(parts, 1), componentAt(parts, 2), nil
+}
+
+// parseVersionComponents extracts every numeric component of a version string,
+// ignoring pre-release identifiers and build metadata like parseVersionParts.
+// Components are decimal strings without leading zeros, so they may exceed int.
+//
+// Examples:
+//   - parseVersionComponents("1.2.3.4") → [1 2 3 4]
+//   - parseVersionComponents("120.0.6099.109-beta") → [120 0 6099 109]
+//   - parseVersionComponents("1.0.20240115103045000000") → [1 0 20240115103045000000]
+//   - parseVersionComponents("1.x") → error
+func parseVersionComponents(version string) ([]string, error) {
 	// Remove pre-release and build metadata for parsing
 	cleanVersion := version
 	if idx := strings.Index(version, "-"); idx != -1 {
@@ -672,25 +789,27 @@ func parseVersionParts(version string) (major, minor, patch int, err error) {
 		cleanVersion = cleanVersion[:idx]
 	}
 
-	parts := strings.Split(cleanVersion, ".")
-
-	if len(parts) >= 1 {
-		if major, err = strconv.Atoi(parts[0]); err != nil {
-			return 0, 0, 0, fmt.Errorf("invalid major version: %s", parts[0])
-		}
-	}
-
-	if len(parts) >= 2 {
-		if minor, err = strconv.Atoi(parts[1]); err != nil {
-			return 0, 0, 0, fmt.Errorf("invalid minor version: %s", parts[1])
+	fields := strings.Split(cleanVersion, ".")
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		if !isDigits(field) {
+			return nil, fmt.Errorf("invalid %s version: %s", componentName(i), field)
 		}
+		parts[i] = trimDecimal(field)
 	}
+	return parts, nil
+}
 
-	if len(parts) >= 3 {
-		if patch, err = strconv.Atoi(parts[2]); err != nil {
-			return 0, 0, 0, fmt.Errorf("invalid patch version: %s", parts[2])
-		}
+// componentName names version component i in error messages.
+func componentName(i int) string {
+	switch i {
+	case 0:
+		return "major"
+	case 1:
+		return "minor"
+	case 2:
+		return "patch"
+	default:
+		return fmt.Sprintf("component %d of the", i+1)
 	}
-
-	return major, minor, patch, nil
 }
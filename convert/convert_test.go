@@ -3,6 +3,7 @@ package convert
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -11,6 +12,7 @@ func TestVersionToRegex(t *testing.T) {
 	tests := []struct {
 		name           string
 		constraint     string
+		opts           Options
 		shouldMatch    []string
 		shouldNotMatch []string
 	}{
@@ -128,6 +130,24 @@ func TestVersionToRegex(t *testing.T) {
 			shouldMatch:    []string{"123.456.789", "123.456.790", "123.456.999"},
 			shouldNotMatch: []string{"123.457.0", "124.0.0"},
 		},
+		{
+			name:           "caret range - components beyond int64",
+			constraint:     "^20240115103045000000.1.0",
+			shouldMatch:    []string{"20240115103045000000.1.0", "20240115103045000000.0.7", "20240115103045000000.99.1-rc.1"},
+			shouldNotMatch: []string{"20240115103045000001.0.0", "2024011510304500000.1.0", "9223372036854775807.1.0"},
+		},
+		{
+			name:           "caret range - zero major with a minor beyond int64",
+			constraint:     "^0.20240115103045000000.1",
+			shouldMatch:    []string{"0.20240115103045000000.1", "0.20240115103045000000.9"},
+			shouldNotMatch: []string{"0.20240115103045000001.0", "1.20240115103045000000.1"},
+		},
+		{
+			name:           "tilde range - components beyond int64",
+			constraint:     "~20240115103045000000.1",
+			shouldMatch:    []string{"20240115103045000000.1.0", "20240115103045000000.1.99"},
+			shouldNotMatch: []string{"20240115103045000000.2.0", "20240115103045000001.1.0"},
+		},
 		{
 			name:           "tilde range with ~>",
 			constraint:     "~>1.2.3",
@@ -290,13 +310,40 @@ func TestVersionToRegex(t *testing.T) {
 			shouldMatch:    []string{"1.0.0-alpha"},
 			shouldNotMatch: []string{"1.0.0-beta", "1.0.0"},
 		},
+		{
+			name:           "nuget pre-release",
+			constraint:     "1.0.0-alpha",
+			opts:           Options{Ecosystem: ECOSYSTEM_NUGET},
+			shouldMatch:    []string{"1.0.0-alpha", "1.0.0-ALPHA", "1.0-alpha", "1.0.0.0-Alpha", "1.0.0-alpha+build"},
+			shouldNotMatch: []string{"1.0.0-beta", "1.0.0", "1.0.0.1-alpha"},
+		},
+		{
+			name:           "nuget trailing zeros",
+			constraint:     "1.0",
+			opts:           Options{Ecosystem: ECOSYSTEM_NUGET},
+			shouldMatch:    []string{"1", "1.0", "1.0.0", "1.0.0.0", "1.0.0-dev.5"},
+			shouldNotMatch: []string{"1.0.1", "1.0.0.1", "1.0.0.0.0", "10.0"},
+		},
+		{
+			name:           "nuget legacy label",
+			constraint:     "1.0.0-beta001",
+			opts:           Options{Ecosystem: ECOSYSTEM_NUGET},
+			shouldMatch:    []string{"1.0.0-beta001", "1.0-BETA001", "1.0.0.0-beta001"},
+			shouldNotMatch: []string{"1.0.0-beta002", "1.0.0"},
+		},
+		{
+			name:           "semver pre-release",
+			constraint:     "1.2.3-alpha",
+			shouldMatch:    []string{"1.2.3-alpha", "1.2.3-alpha+build"},
+			shouldNotMatch: []string{"1.2.3.0-alpha", "01.2.3-alpha", "1.2.3-ALPHA", "1.2.3-beta", "1.2.3"},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			regex, err := VersionToRegex(tt.constraint)
+			regex, err := VersionToRegexWithOptions(tt.constraint, tt.opts)
 			if err != nil {
-				t.Fatalf("VersionToRegex(%q) failed: %v", tt.constraint, err)
+				t.Fatalf("VersionToRegexWithOptions(%q) failed: %v", tt.constraint, err)
 			}
 
 			for _, version := range tt.shouldMatch {
@@ -351,6 +398,36 @@ func TestParseVersionConstraint(t *testing.T) {
 	}
 }
 
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		input    string
+		opts     Options
+		operator string
+		version  string
+	}{
+		{" ^1.2.3 ", Options{}, OP_CARET, "1.2.3"},
+		{"[1.0,2.0)", Options{}, OP_MAVEN_RANGE, "[1.0,2.0)"},
+		{"~> 2.1, >= 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}, OP_RUBY_REQUIREMENT, "~> 2.1, >= 2.1.3"},
+		{"1.2.3", Options{Ecosystem: ECOSYSTEM_CARGO}, OP_CARGO_REQUIREMENT, "^1.2.3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.input, tt.opts)
+			if err != nil {
+				t.Fatalf("ParseConstraint(%q) failed: %v", tt.input, err)
+			}
+			if constraint.Operator != tt.operator || constraint.Version != tt.version {
+				t.Errorf("ParseConstraint(%q) = %q %q, expected %q %q", tt.input, constraint.Operator, constraint.Version, tt.operator, tt.version)
+			}
+		})
+	}
+
+	if _, err := ParseConstraint("1.2.3", Options{Ecosystem: Ecosystem("conda")}); err == nil {
+		t.Error("ParseConstraint should return an error for an unknown ecosystem")
+	}
+}
+
 func TestExactMatchRegexPreReleaseAndBuildMeta(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -371,7 +448,7 @@ func TestExactMatchRegexPreReleaseAndBuildMeta(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pattern := exactMatchRegex(tt.version)
+			pattern := exactMatchRegex(tt.version, Options{})
 			regex, err := regexp.Compile(pattern)
 			if err != nil {
 				t.Fatalf("Failed to compile pattern %q: %v", pattern, err)
@@ -412,7 +489,7 @@ func TestWildcardToRegex(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pattern := wildcardToRegex(tt.version)
+			pattern := wildcardToRegex(tt.version, Options{})
 			regex, err := regexp.Compile(pattern)
 			if err != nil {
 				t.Fatalf("Failed to compile pattern %q: %v", pattern, err)
@@ -435,7 +512,7 @@ func TestWildcardToRegex(t *testing.T) {
 func TestPadToSemanticVersionEmptyParts(t *testing.T) {
 	// Test padToSemanticVersion with empty originalParts slice
 	// This covers line 420: return patternParts when len(originalParts) == 0
-	result := padToSemanticVersion([]string{}, []string{})
+	result := padToSemanticVersion([]string{}, []string{}, LEADING_ZEROS_NUMERIC)
 	if len(result) != 0 {
 		t.Errorf("Expected empty result for empty input, got %v", result)
 	}
@@ -445,25 +522,25 @@ func TestComparisonRegexInvalidVersion(t *testing.T) {
 	invalidVersion := "invalid.version.x"
 
 	// Test greaterThanEqualRegex error path (line 438)
-	_, err := greaterThanEqualRegex(invalidVersion)
+	_, err := greaterThanEqualRegex(invalidVersion, Options{})
 	if err == nil {
 		t.Error("greaterThanEqualRegex: expected error for invalid version")
 	}
 
 	// Test lessThanEqualRegex error path (line 458)
-	_, err = lessThanEqualRegex(invalidVersion)
+	_, err = lessThanEqualRegex(invalidVersion, Options{})
 	if err == nil {
 		t.Error("lessThanEqualRegex: expected error for invalid version")
 	}
 
 	// Test greaterThanRegex error path (line 488)
-	_, err = greaterThanRegex(invalidVersion)
+	_, err = greaterThanRegex(invalidVersion, Options{})
 	if err == nil {
 		t.Error("greaterThanRegex: expected error for invalid version")
 	}
 
 	// Test lessThanRegex error path (line 501)
-	_, err = lessThanRegex(invalidVersion)
+	_, err = lessThanRegex(invalidVersion, Options{})
 	if err == nil {
 		t.Error("lessThanRegex: expected error for invalid version")
 	}
@@ -471,7 +548,7 @@ func TestComparisonRegexInvalidVersion(t *testing.T) {
 
 func TestGreaterThanEqualMajorVersionOnly(t *testing.T) {
 	// Test >=X.0.0 which uses GREATER_EQUAL_MAJOR_TEMPLATE (line 448)
-	pattern, err := greaterThanEqualRegex("2.0.0")
+	pattern, err := greaterThanEqualRegex("2.0.0", Options{})
 	if err != nil {
 		t.Fatalf("greaterThanEqualRegex: unexpected error: %v", err)
 	}
@@ -580,13 +657,24 @@ func TestMultiDigitVersionBoundaries(t *testing.T) {
 }
 
 func TestLessThanRegexZeroVersion(t *testing.T) {
-	// Test lessThanRegex with 0.0.0 - covers EMPTY_MATCH_PATTERN return (line 522)
-	pattern, err := lessThanRegex("0.0.0")
+	// Test lessThanRegex with 0.0.0 - covers the NEVER_MATCH_PATTERN return
+	pattern, err := lessThanRegex("0.0.0", Options{})
 	if err != nil {
 		t.Fatalf("lessThanRegex: unexpected error: %v", err)
 	}
-	if pattern != EMPTY_MATCH_PATTERN {
-		t.Errorf("Expected EMPTY_MATCH_PATTERN for <0.0.0, got %q", pattern)
+	if pattern != NEVER_MATCH_PATTERN {
+		t.Errorf("Expected NEVER_MATCH_PATTERN for <0.0.0, got %q", pattern)
+	}
+
+	// The pattern must compile with Go's RE2 engine
+	regex, err := VersionToRegex("<0.0.0")
+	if err != nil {
+		t.Fatalf("VersionToRegex(<0.0.0) returned unexpected error: %v", err)
+	}
+	for _, version := range []string{"0.0.0", "0.0.0-alpha", "0", ""} {
+		if regex.MatchString(version) {
+			t.Errorf("pattern %q should not match %q but does", regex.String(), version)
+		}
 	}
 }
 
@@ -596,7 +684,7 @@ func TestConstraintToRegexUnsupportedOperator(t *testing.T) {
 		Operator: "??",
 		Version:  "1.2.3",
 	}
-	_, err := constraintToRegex(constraint)
+	_, err := constraintToRegex(constraint, Options{})
 	if err == nil {
 		t.Error("Expected error for unsupported operator, got nil")
 	}
@@ -609,13 +697,13 @@ func TestCaretAndTildeRegexInvalidVersion(t *testing.T) {
 	invalidVersion := "invalid.x.y"
 
 	// Test caretRangeRegex error path (line 569)
-	_, err := caretRangeRegex(invalidVersion)
+	_, err := caretRangeRegex(invalidVersion, Options{})
 	if err == nil {
 		t.Error("caretRangeRegex: expected error for invalid version")
 	}
 
 	// Test tildeRangeRegex error path (line 611)
-	_, err = tildeRangeRegex(invalidVersion)
+	_, err = tildeRangeRegex(invalidVersion, Options{})
 	if err == nil {
 		t.Error("tildeRangeRegex: expected error for invalid version")
 	}
@@ -624,20 +712,22 @@ func TestCaretAndTildeRegexInvalidVersion(t *testing.T) {
 func TestParseVersionParts(t *testing.T) {
 	tests := []struct {
 		version string
-		major   int
-		minor   int
-		patch   int
+		major   string
+		minor   string
+		patch   string
 		wantErr bool
 	}{
-		{"1.2.3", 1, 2, 3, false},
-		{"1.2.3-alpha", 1, 2, 3, false},
-		{"1.2.3+build", 1, 2, 3, false},
-		{"1.2.3-alpha+build", 1, 2, 3, false},
-		{"1.2", 1, 2, 0, false},
-		{"1", 1, 0, 0, false},
-		{"invalid", 0, 0, 0, true},
-		{"1.invalid.3", 0, 0, 0, true},
-		{"1.2.invalid", 0, 0, 0, true}, // invalid patch version
+		{"1.2.3", "1", "2", "3", false},
+		{"1.2.3-alpha", "1", "2", "3", false},
+		{"1.2.3+build", "1", "2", "3", false},
+		{"1.2.3-alpha+build", "1", "2", "3", false},
+		{"1.2", "1", "2", "0", false},
+		{"1", "1", "0", "0", false},
+		{"01.002.3", "1", "2", "3", false},
+		{"20240115103045000000.1", "20240115103045000000", "1", "0", false}, // beyond int64
+		{"invalid", "", "", "", true},
+		{"1.invalid.3", "", "", "", true},
+		{"1.2.invalid", "", "", "", true}, // invalid patch version
 	}
 
 	for _, tt := range tests {
@@ -656,15 +746,325 @@ func TestParseVersionParts(t *testing.T) {
 			}
 
 			if major != tt.major {
-				t.Errorf("Expected major %d, got %d", tt.major, major)
+				t.Errorf("Expected major %q, got %q", tt.major, major)
 			}
 
 			if minor != tt.minor {
-				t.Errorf("Expected minor %d, got %d", tt.minor, minor)
+				t.Errorf("Expected minor %q, got %q", tt.minor, minor)
 			}
 
 			if patch != tt.patch {
-				t.Errorf("Expected patch %d, got %d", tt.patch, patch)
+				t.Errorf("Expected patch %q, got %q", tt.patch, patch)
+			}
+		})
+	}
+}
+
+func TestParseVersionComponents(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []string
+		wantErr bool
+	}{
+		{"1.2.3", []string{"1", "2", "3"}, false},
+		{"1.2.3.4", []string{"1", "2", "3", "4"}, false},
+		{"120.0.6099.109", []string{"120", "0", "6099", "109"}, false},
+		{"1.2.3.4-beta+build", []string{"1", "2", "3", "4"}, false},
+		{"01.002.0", []string{"1", "2", "0"}, false},
+		{"1.0.20240115103045000000", []string{"1", "0", "20240115103045000000"}, false},
+		{"7", []string{"7"}, false},
+		{"1.2.3.x", nil, true},
+		{"1..2", nil, true},
+		{"", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := parseVersionComponents(tt.version)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected error for version %q, but got none", tt.version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVersionComponents(%q) failed: %v", tt.version, err)
+			}
+			if strings.Join(got, ".") != strings.Join(tt.want, ".") {
+				t.Errorf("parseVersionComponents(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComparisonArbitraryLength(t *testing.T) {
+	tests := []struct {
+		name           string
+		constraint     string
+		policy         LengthPolicy
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			name:           "three parts accept longer versions",
+			constraint:     ">=1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.2.3.4", "1.2.3.0", "1.3", "2", "1.2.3-beta"},
+			shouldNotMatch: []string{"1.2.2.9", "1.2", "1"},
+		},
+		{
+			name:           ".NET assembly version",
+			constraint:     ">=1.2.3.4",
+			shouldMatch:    []string{"1.2.3.4", "1.2.3.10", "1.2.4", "1.2.4.0", "2.0.0.0"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3.3", "1.2.3.0"},
+		},
+		{
+			name:           "Chrome version upper bound",
+			constraint:     "<120.0.6099.109",
+			shouldMatch:    []string{"119.0.6045.199", "120.0.6099.108", "120.0.6099", "99.0.0.0"},
+			shouldNotMatch: []string{"120.0.6099.109", "120.0.6099.110", "120.0.6100.0", "121.0.0.0"},
+		},
+		{
+			name:           "greater excludes zero padded equal versions",
+			constraint:     ">1.2",
+			shouldMatch:    []string{"1.2.0.1", "1.2.1", "1.3"},
+			shouldNotMatch: []string{"1.2", "1.2.0", "1.2.0.0", "1.1.9.9"},
+		},
+		{
+			name:           "less or equal with trailing zeros",
+			constraint:     "<=1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.2.3.0", "1.2.2.99", "1.2"},
+			shouldNotMatch: []string{"1.2.3.1", "1.2.4"},
+		},
+		{
+			name:           "timestamp components beyond int64",
+			constraint:     ">=1.0.20240115103045000000",
+			shouldMatch:    []string{"1.0.20240115103045000000", "1.0.20240115103045000001", "1.0.100000000000000000000", "1.1"},
+			shouldNotMatch: []string{"1.0.20240115103044999999", "1.0.9223372036854775807", "0.9"},
+		},
+		{
+			name:           "timestamp upper bound",
+			constraint:     "<20240115103045000000",
+			shouldMatch:    []string{"20240115103044999999.9", "9223372036854775807", "1.2.3"},
+			shouldNotMatch: []string{"20240115103045000000", "20240115103045000000.1", "30000000000000000000"},
+		},
+		{
+			name:           "strict length",
+			constraint:     ">=1.2.3",
+			policy:         LENGTH_STRICT,
+			shouldMatch:    []string{"1.2.3", "1.3.0", "10.0.0-rc.1"},
+			shouldNotMatch: []string{"1.3", "1.2.3.4", "2", "1.2.2"},
+		},
+		{
+			name:           "strict length with four parts",
+			constraint:     "<1.2.3.4",
+			policy:         LENGTH_STRICT,
+			shouldMatch:    []string{"1.2.3.3", "0.9.9.9", "1.2.2.10"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3.4", "1.2.3.3.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.constraint, Options{VersionLength: tt.policy})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) failed: %v", tt.constraint, err)
+			}
+			for _, v := range tt.shouldMatch {
+				if !regex.MatchString(v) {
+					t.Errorf("%q should match %q", tt.constraint, v)
+				}
+			}
+			for _, v := range tt.shouldNotMatch {
+				if regex.MatchString(v) {
+					t.Errorf("%q should NOT match %q", tt.constraint, v)
+				}
+			}
+		})
+	}
+}
+
+func TestComparisonOrderingMatchesComponents(t *testing.T) {
+	// Every version of up to four components from {0, 1, 9, 10}
+	var corpus [][]int
+	var build func(prefix []int)
+	build = func(prefix []int) {
+		if len(prefix) > 0 {
+			corpus = append(corpus, append([]int(nil), prefix...))
+		}
+		if len(prefix) == 4 {
+			return
+		}
+		for _, n := range []int{0, 1, 9, 10} {
+			build(append(prefix, n))
+		}
+	}
+	build(nil)
+
+	join := func(parts []int) string {
+		s := make([]string, len(parts))
+		for i, p := range parts {
+			s[i] = strconv.Itoa(p)
+		}
+		return strings.Join(s, ".")
+	}
+	operators := map[string]func(c int) bool{
+		">=": func(c int) bool { return c >= 0 },
+		">":  func(c int) bool { return c > 0 },
+		"<=": func(c int) bool { return c <= 0 },
+		"<":  func(c int) bool { return c < 0 },
+	}
+
+	for _, bound := range []string{"1.0.9", "1.9.0.10", "0.10", "10.0.0.1"} {
+		boundParts, _ := parseVersionComponents(bound)
+		for op, accepts := range operators {
+			for _, policy := range []LengthPolicy{LENGTH_PAD, LENGTH_STRICT} {
+				regex, err := VersionToRegexWithOptions(op+bound, Options{VersionLength: policy})
+				if err != nil {
+					t.Fatalf("VersionToRegexWithOptions(%q) failed: %v", op+bound, err)
+				}
+				for _, parts := range corpus {
+					want := accepts(compareParts(decimalParts(parts), boundParts))
+					if policy == LENGTH_STRICT && len(parts) != len(boundParts) {
+						want = false
+					}
+					if got := regex.MatchString(join(parts)); got != want {
+						t.Errorf("%s%s (policy %d) on %s: got %v, want %v", op, bound, policy, join(parts), got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestLeadingZeroPolicyConstraints(t *testing.T) {
+	tests := []struct {
+		name           string
+		constraint     string
+		opts           Options
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			name:           "numeric comparison",
+			constraint:     ">=1.15",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.15", "01.015", "1.0100", "001.20.03"},
+			shouldNotMatch: []string{"1.009", "01.14", "0.99"},
+		},
+		{
+			name:           "rejected comparison",
+			constraint:     ">=1.15",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_REJECT},
+			shouldMatch:    []string{"1.15", "1.100", "2.0.0", "1.20.0"},
+			shouldNotMatch: []string{"01.15", "1.015", "1.009", "1.20.03", "1.15.00"},
+		},
+		{
+			name:           "numeric exact match",
+			constraint:     "1.2.3",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.2.3", "01.002.3", "1.2.03-rc.1"},
+			shouldNotMatch: []string{"1.2.30", "1.20.3", "11.2.3"},
+		},
+		{
+			name:           "rejected exact match",
+			constraint:     "1.2.3",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_REJECT},
+			shouldMatch:    []string{"1.2.3", "1.2.3-rc.1"},
+			shouldNotMatch: []string{"01.002.3", "1.2.03"},
+		},
+		{
+			name:           "default exact match",
+			constraint:     "1.2.3",
+			shouldMatch:    []string{"1.2.3", "1.2.3-rc.1"},
+			shouldNotMatch: []string{"01.2.3", "1.02.3", "1.2.03"},
+		},
+		{
+			name:           "default Cargo requirement",
+			constraint:     "1.2.3",
+			opts:           Options{Ecosystem: ECOSYSTEM_CARGO},
+			shouldMatch:    []string{"1.2.3", "1.9.0"},
+			shouldNotMatch: []string{"01.2.3", "1.09.0"},
+		},
+		{
+			name:           "rejected caret range",
+			constraint:     "^1.2.3",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_REJECT},
+			shouldMatch:    []string{"1.2.3", "1.10.0", "1.0.10"},
+			shouldNotMatch: []string{"01.2.3", "1.02.3", "1.2.03"},
+		},
+		{
+			name:           "numeric tilde range",
+			constraint:     "~1.2.3",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.2.3", "01.02.9", "1.2.010"},
+			shouldNotMatch: []string{"1.3.0", "1.20.3"},
+		},
+		{
+			name:           "rejected NuGet version",
+			constraint:     "2.1.0-beta001",
+			opts:           Options{Ecosystem: ECOSYSTEM_NUGET},
+			shouldMatch:    []string{"2.1.0-beta001", "2.1.0.0-BETA001"},
+			shouldNotMatch: []string{"02.1.0-beta001", "2.01-beta001", "2.1.00-beta001"},
+		},
+		{
+			name:           "numeric NuGet version",
+			constraint:     "1.2.3.4567",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.2.3.4567", "01.002.3.04567"},
+			shouldNotMatch: []string{"1.2.3.4568", "1.2.3"},
+		},
+		{
+			name:           "rejected wildcard",
+			constraint:     "1.*",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_REJECT},
+			shouldMatch:    []string{"1.0.0", "1.20.3"},
+			shouldNotMatch: []string{"01.0.0", "1.020.3"},
+		},
+		{
+			name:           "numeric Maven range",
+			constraint:     "[1.0,2.0)",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.0", "01.5", "1.010-SNAPSHOT"},
+			shouldNotMatch: []string{"02.0", "2.00"},
+		},
+		{
+			name:           "rejected Cargo requirement",
+			constraint:     "^1.2.3",
+			opts:           Options{Ecosystem: ECOSYSTEM_CARGO, LeadingZeros: LEADING_ZEROS_REJECT},
+			shouldMatch:    []string{"1.2.3", "1.9.0"},
+			shouldNotMatch: []string{"1.09.0", "01.2.3"},
+		},
+		{
+			name:           "numeric Composer constraint",
+			constraint:     "~1.2",
+			opts:           Options{Ecosystem: ECOSYSTEM_COMPOSER, LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.2", "v01.09.0"},
+			shouldNotMatch: []string{"1.01", "02.0"},
+		},
+		{
+			name:           "rejected RubyGems requirement",
+			constraint:     "~> 2.1",
+			opts:           Options{Ecosystem: ECOSYSTEM_RUBY, LeadingZeros: LEADING_ZEROS_REJECT},
+			shouldMatch:    []string{"2.1", "2.10.0"},
+			shouldNotMatch: []string{"02.1", "2.01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) failed: %v", tt.constraint, err)
+			}
+			for _, v := range tt.shouldMatch {
+				if !regex.MatchString(v) {
+					t.Errorf("%q should match %q", tt.constraint, v)
+				}
+			}
+			for _, v := range tt.shouldNotMatch {
+				if regex.MatchString(v) {
+					t.Errorf("%q should NOT match %q", tt.constraint, v)
+				}
 			}
 		})
 	}
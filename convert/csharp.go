@@ -4,103 +4,161 @@
 package convert
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 )
 
-// isCSharpVersion checks if a version follows C# NuGet versioning.
-//
-// C# NuGet supports two main version formats:
-// 1. 4-part versions: major.minor.patch.build (e.g., 1.2.3.4567)
-// 2. Semantic versions with pre-release identifiers (e.g., 1.0.0-alpha, 1.0.0-beta001)
+// NUGET_MAX_PARTS is the maximum number of numeric components in a NuGet version
+// (major.minor.patch.revision).
+const NUGET_MAX_PARTS = 4
+
+// nugetVersionRegex recognizes the versions NuGet reads: up to NUGET_MAX_PARTS numeric
+// components, with an optional SemVer 2.0 or legacy pre-release label and build metadata.
+var nugetVersionRegex = regexp.MustCompile(`^\d+(?:\.\d+){0,3}(?:-` + SEMVER_IDENTIFIERS + `)?(?:\+` + SEMVER_IDENTIFIERS + `)?$`)
+
+// isCSharpVersion checks if a version can only be a C# NuGet version.
 //
-// This function returns true if the version string matches either format.
+// SemVer versions have 3 numeric components, so a 4-part version
+// major.minor.patch.revision (e.g., 1.2.3.4567) is read as a NuGet version whatever
+// its label. Only the numeric part of the version is counted, so a dotted pre-release
+// label such as 1.2.3-dev.5 does not make a 3-part version look like a 4-part one.
+// Versions with fewer components are only NuGet versions in ECOSYSTEM_NUGET mode.
 //
 // Examples:
 //   - isCSharpVersion("1.2.3.4567") returns true (4-part version)
-//   - isCSharpVersion("1.0.0-alpha") returns true (pre-release)
-//   - isCSharpVersion("1.0.0-beta001") returns true (pre-release)
+//   - isCSharpVersion("1.2.3.4-nightly") returns true (4-part version with any label)
+//   - isCSharpVersion("1.0.0-beta001") returns false (3-part version)
 //   - isCSharpVersion("1.2.3") returns false (standard 3-part semantic version)
 func isCSharpVersion(version string) bool {
 	// C# can have 4 parts: major.minor.patch.build (like 1.2.3.4567)
-	parts := strings.Split(version, ".")
-	return len(parts) == 4 || containsCSharpPreRelease(version)
+	mainVersion, _, _ := splitNuGetVersion(version)
+	parts := strings.Split(mainVersion, ".")
+	return len(parts) == NUGET_MAX_PARTS
 }
 
-// containsCSharpPreRelease checks for C# pre-release patterns.
-//
-// C# NuGet packages commonly use specific pre-release identifiers:
-// - alpha: Early development versions (e.g., 1.0.0-alpha, 1.0.0-alpha.1)
-// - beta: Beta versions with possible build numbers (e.g., 1.0.0-beta001, 1.0.0-beta.2)
-// - rc: Release candidate versions (e.g., 1.0.0-rc.1)
-// - preview: Preview versions for .NET (e.g., 1.0.0-preview)
-//
-// Returns true if the version contains any of these C#-specific pre-release patterns.
+// isNuGetExact reports whether an exact version is matched with NuGet's normalization:
+// 4-part versions always, and every NuGet version in ECOSYSTEM_NUGET mode.
 //
 // Examples:
-//   - containsCSharpPreRelease("1.0.0-alpha") returns true
-//   - containsCSharpPreRelease("1.0.0-beta001") returns true
-//   - containsCSharpPreRelease("1.0.0-rc.1") returns true
-//   - containsCSharpPreRelease("1.0.0-preview") returns true
-//   - containsCSharpPreRelease("1.0.0-dev") returns false
-func containsCSharpPreRelease(version string) bool {
-	// C# pre-release: 1.0.0-alpha, 1.0.0-beta001, 1.0.0-rc.1
-	return strings.Contains(version, "-alpha") ||
-		strings.Contains(version, "-beta") ||
-		strings.Contains(version, "-rc") ||
-		strings.Contains(version, "-preview")
+//   - isNuGetExact("1.0.0-alpha", Options{Ecosystem: ECOSYSTEM_NUGET}) returns true
+//   - isNuGetExact("1.0.0-alpha", Options{}) returns false (SemVer pre-release)
+//   - isNuGetExact("1.*", Options{Ecosystem: ECOSYSTEM_NUGET}) returns false (wildcard)
+func isNuGetExact(version string, opts Options) bool {
+	if opts.Ecosystem == ECOSYSTEM_NUGET {
+		return nugetVersionRegex.MatchString(version)
+	}
+	return isCSharpVersion(version)
 }
 
 // csharpVersionRegex creates a regex for C# NuGet versions.
 //
 // This function generates a regular expression pattern that matches C# NuGet version formats.
-// It handles both the main version parts and optional pre-release suffixes.
+// It follows NuGet's normalization rules, so the pattern matches every spelling that
+// NuGet considers equal to the given version:
+//   - Missing trailing components are zero: 1.0 == 1.0.0 == 1.0.0.0
+//   - Leading zeros are ignored: 01.002.3 == 1.2.3, under LEADING_ZEROS_NUMERIC; the
+//     default LEADING_ZEROS_REJECT only matches components without them
+//   - Pre-release labels are compared case-insensitively: 1.0.0-Beta == 1.0.0-beta
+//   - Build metadata is ignored: 1.0.0+abc == 1.0.0, unless the version pins it
 //
-// The regex pattern includes:
-// - Exact matching for specified version parts (major.minor.patch[.build])
-// - Optional pre-release suffix matching for common C# patterns
-// - Proper escaping of special regex characters in version strings
-//
-// For versions with pre-release suffixes, the pattern matches the exact suffix.
-// For versions without pre-release suffixes, the pattern allows optional C# pre-release patterns.
+// For versions with a pre-release label, the pattern matches that label (any SemVer 2.0
+// label or legacy NuGet label such as beta001). For versions without a label, the pattern
+// allows any optional SemVer 2.0 pre-release label.
 //
 // Parameters:
 //   - version: The C# version string to create a regex for
+//   - opts: Conversion options, whose LeadingZeros policy applies to the components
 //
 // Returns:
 //   - A regex pattern string that matches the specified version and compatible variations
 //
 // Examples:
-//   - csharpVersionRegex("1.2.3.4567") generates pattern for exact 4-part version
-//   - csharpVersionRegex("1.0.0-alpha") generates pattern for exact pre-release version
-//   - csharpVersionRegex("1.2.3") generates pattern allowing optional C# pre-release suffixes
-func csharpVersionRegex(version string) string {
-	// C# versions can be: 1.2.3.4567, 1.0.0-alpha, 1.0.0-beta001, 1.0.0-rc.1
-	pattern := REGEX_START
+//   - csharpVersionRegex("1.2.3.4567", opts) generates pattern for exact 4-part version
+//   - csharpVersionRegex("1.0.0-alpha", opts) generates pattern for 1.0-alpha, 1.0.0.0-ALPHA, etc.
+//   - csharpVersionRegex("1.2.3.4", opts) generates pattern allowing optional labels like -nightly
+func csharpVersionRegex(version string, opts Options) string {
+	// C# versions can be: 1.2.3.4567, 1.0.0-alpha, 1.0.0-beta001, 1.2.3.4-rc.1
+	mainVersion, preRelease, buildMeta := splitNuGetVersion(version)
+	pattern := REGEX_START + nugetNumericPattern(strings.Split(mainVersion, "."), opts.LeadingZeros)
 
-	// Handle pre-release
-	mainVersion := version
-	preRelease := ""
-	if idx := strings.Index(version, "-"); idx != -1 {
-		preRelease = version[idx:]
-		mainVersion = version[:idx]
+	// Add pre-release pattern
+	if preRelease != "" {
+		pattern += "-(?i:" + regexp.QuoteMeta(preRelease) + ")"
+	} else {
+		// Allow any SemVer 2.0 pre-release label
+		pattern += SEMVER_PRE_RELEASE_PATTERN
 	}
 
-	parts := strings.Split(mainVersion, ".")
-	for i, part := range parts {
+	// Build metadata never takes part in NuGet version equality, but a pinned build
+	// selects that exact artifact, as with other exact versions
+	if buildMeta != "" {
+		return pattern + `\+` + regexp.QuoteMeta(buildMeta) + REGEX_END
+	}
+	return pattern + SEMVER_BUILD_META_PATTERN + REGEX_END
+}
+
+// splitNuGetVersion splits a NuGet version into its numeric part, its pre-release
+// label (without the leading dash) and its build metadata (without the leading plus).
+func splitNuGetVersion(version string) (mainVersion, preRelease, buildMeta string) {
+	mainVersion = version
+	if idx := strings.Index(mainVersion, "+"); idx != -1 {
+		buildMeta = mainVersion[idx+1:]
+		mainVersion = mainVersion[:idx]
+	}
+	if idx := strings.Index(mainVersion, "-"); idx != -1 {
+		preRelease = mainVersion[idx+1:]
+		mainVersion = mainVersion[:idx]
+	}
+	return mainVersion, preRelease, buildMeta
+}
+
+// nugetNumericPattern builds the pattern for the numeric components of a NuGet version.
+// Trailing zero components are optional up to NUGET_MAX_PARTS components in total.
+// Result: 1(?:\.0){0,3} (for "1.0.0"), or 0*1(?:\.0*0){0,3} under LEADING_ZEROS_NUMERIC
+func nugetNumericPattern(parts []string, zeros LeadingZeroPolicy) string {
+	significant := len(parts)
+	for significant > 1 && isZeroComponent(parts[significant-1]) {
+		significant--
+	}
+
+	pattern := ""
+	for i := 0; i < significant; i++ {
 		if i > 0 {
 			pattern += VERSION_DOT
 		}
-		pattern += regexp.QuoteMeta(part)
+		pattern += nugetComponentPattern(parts[i], zeros)
 	}
 
-	// Add pre-release pattern
-	if preRelease != "" {
-		pattern += regexp.QuoteMeta(preRelease)
-	} else {
-		// Allow common C# pre-release patterns
-		pattern += `(?:-(?:alpha|beta|rc|preview)(?:\d+)?(?:\.\d+)?)?`
+	if padding := NUGET_MAX_PARTS - significant; padding > 0 {
+		pattern += fmt.Sprintf(`(?:%s%s){0,%d}`, VERSION_DOT, zeros.Equal("0"), padding)
+	}
+	return pattern
+}
+
+// nugetComponentPattern matches a numeric NuGet component under the leading zero policy.
+// Non-numeric components are matched literally.
+func nugetComponentPattern(part string, zeros LeadingZeroPolicy) string {
+	if !isDigits(part) {
+		return regexp.QuoteMeta(part)
 	}
+	return zeros.Equal(part)
+}
+
+// isZeroComponent reports whether a version component is a (possibly zero-padded) zero.
+func isZeroComponent(part string) bool {
+	return isDigits(part) && strings.Trim(part, "0") == ""
+}
 
-	return pattern + REGEX_END
+// isDigits reports whether s is a non-empty string of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
 }
@@ -2,15 +2,22 @@
 // This file contains unit tests for C# version detection and regex generation.
 package convert
 
-import "testing"
+import (
+	"regexp"
+	"testing"
+)
 
 // TestCSharpVersion tests the isCSharpVersion function with various version formats.
 //
 // This test verifies that the function correctly identifies:
-// - 4-part versions (major.minor.patch.build) as C# versions
-// - Versions with C#-specific pre-release identifiers (alpha, beta, rc, preview) as C# versions
-// - Standard 3-part semantic versions as non-C# versions
-// - Invalid or empty version strings as non-C# versions
+//   - 4-part versions (major.minor.patch.build) as C# versions, whatever their label
+//   - 3-part versions as non-C# versions, whatever their label, since only the NuGet
+//     ecosystem reads them as NuGet versions
+//
+// isCSharpVersion routes exact versions of the default ecosystem, so the pre-release
+// labels alpha, beta, rc and preview no longer make a C# version: they are SemVer
+// labels too, and 1.0.0-alpha must keep SemVer equality unless ECOSYSTEM_NUGET is set.
+//   - Invalid or empty version strings as non-C# versions
 func TestCSharpVersion(t *testing.T) {
 	tests := []struct {
 		version  string
@@ -19,14 +26,21 @@ func TestCSharpVersion(t *testing.T) {
 		// 4-part versions (major.minor.patch.build) - C# format
 		{"1.2.3.4567", true},
 
-		// C# pre-release patterns
-		{"1.0.0-alpha", true},   // Alpha release
-		{"1.0.0-beta001", true}, // Beta with build number
-		{"1.0.0-rc.1", true},    // Release candidate
-		{"1.0.0-preview", true}, // .NET preview release
+		// 4-part versions with arbitrary SemVer 2.0 labels
+		{"1.2.3.4-nightly", true},
+		{"1.2.3.4-dev.5", true},
 
 		// Standard semantic versions - not C# specific
-		{"1.2.3", false}, // Standard 3-part version
+		{"1.2.3", false},           // Standard 3-part version
+		{"1.0.0-alpha", false},     // SemVer pre-release
+		{"1.0.0-beta001", false},   // Legacy label without a 4th component
+		{"1.0.0-preview", false},   // .NET preview release
+		{"8.0.0-preview.7", false}, // Dotted .NET preview release
+		{"1.0.0-rc.1", false},      // Dotted SemVer pre-release
+		{"1.0.0-dev.5", false},     // Dotted label does not add a numeric part
+		{"1.2.3-nightly", false},   // Any other SemVer 2.0 label
+		{"1.2.3-alpha..1", false},  // Empty identifier is not a SemVer label
+		{"1.x-beta2", false},       // Wildcards are not NuGet versions
 
 		// Invalid versions
 		{"", false}, // Empty string
@@ -41,3 +55,91 @@ func TestCSharpVersion(t *testing.T) {
 		})
 	}
 }
+
+// TestCSharpVersionRegex tests that csharpVersionRegex follows NuGet normalization rules.
+//
+// This test verifies that the generated pattern:
+// - Treats missing trailing components as zero (1.0 == 1.0.0 == 1.0.0.0)
+// - Ignores leading zeros in numeric components under LEADING_ZEROS_NUMERIC only
+// - Compares pre-release labels case-insensitively
+// - Accepts arbitrary SemVer 2.0 and legacy NuGet labels for unsuffixed versions
+// - Ignores build metadata
+func TestCSharpVersionRegex(t *testing.T) {
+	tests := []struct {
+		version        string
+		opts           Options
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			version:        "1.0.0.0",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.0", "1.0.0", "1.0.0.0", "1", "01.00.0", "1.0.0-dev.5", "1.0.0-nightly", "1.0-beta001", "1.0.0+sha.abc"},
+			shouldNotMatch: []string{"1.0.0.1", "1.0.1", "10.0", "1.0.0.0.0", "1.0.0-", "1.0.0-alpha..1"},
+		},
+		{
+			version:        "1.0.0.0",
+			shouldMatch:    []string{"1.0", "1.0.0", "1.0.0.0", "1", "1.0.0-dev.5"},
+			shouldNotMatch: []string{"01.0", "1.00.0", "1.0.0.00", "1.0.0.1"},
+		},
+		{
+			version:        "1.2.3.4567",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"1.2.3.4567", "01.02.03.04567", "1.2.3.4567-rc.1"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3.4568", "1.2.3.45670"},
+		},
+		{
+			version:        "1.2.3.4567",
+			shouldMatch:    []string{"1.2.3.4567", "1.2.3.4567-rc.1"},
+			shouldNotMatch: []string{"01.02.03.04567", "1.2.3.04567", "1.2.3"},
+		},
+		{
+			version:        "1.0.0-alpha",
+			shouldMatch:    []string{"1.0.0-alpha", "1.0-alpha", "1.0.0.0-ALPHA", "1.0.0-Alpha+build.7"},
+			shouldNotMatch: []string{"1.0.0", "1.0.0-beta", "1.0.0-alpha.1", "1.0.1-alpha"},
+		},
+		{
+			version:        "2.1.0-beta001",
+			opts:           Options{LeadingZeros: LEADING_ZEROS_NUMERIC},
+			shouldMatch:    []string{"2.1.0-beta001", "2.1-BETA001", "02.1.0.0-beta001"},
+			shouldNotMatch: []string{"2.1.0-beta002", "2.1.0-beta01", "2.1.0"},
+		},
+		{
+			version:        "1.0.0-dev.5",
+			shouldMatch:    []string{"1.0.0-dev.5", "1.0-DEV.5", "1.0.0.0-dev.5+sha.abc"},
+			shouldNotMatch: []string{"1.0.0-dev.6", "1.0.0-dev", "1.0.0"},
+		},
+		{
+			version:        "1.2.3-nightly",
+			shouldMatch:    []string{"1.2.3-nightly", "1.2.3.0-Nightly"},
+			shouldNotMatch: []string{"01.2.3-nightly", "1.2.3-nightly.1", "1.2.4-nightly"},
+		},
+		{
+			version:        "1.2.3.4-nightly.20240115",
+			shouldMatch:    []string{"1.2.3.4-nightly.20240115", "1.2.3.4-Nightly.20240115"},
+			shouldNotMatch: []string{"1.2.3.4", "1.2.3.4-nightly.20240116"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			pattern := csharpVersionRegex(tt.version, tt.opts)
+			regex, err := regexp.Compile(pattern)
+			if err != nil {
+				t.Fatalf("csharpVersionRegex(%q) returned invalid regex pattern %q: %v", tt.version, pattern, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("csharpVersionRegex(%q) pattern %q should match %q but doesn't", tt.version, pattern, match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("csharpVersionRegex(%q) pattern %q should not match %q but does", tt.version, pattern, noMatch)
+				}
+			}
+		})
+	}
+}
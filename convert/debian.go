@@ -0,0 +1,299 @@
+// Package convert provides Debian package version handling functionality.
+// This file contains functions specific to Debian versions and relations, following
+// dpkg's version comparison (verrevcmp):
+//   - versions are written [epoch:]upstream_version[-debian_revision], where the
+//     revision starts after the last hyphen and a missing epoch is 0
+//   - upstream versions and revisions are compared in alternating runs of non-digits
+//     and digits, where digit runs compare numerically
+//   - in non-digit runs, ~ sorts before anything (even the end of the version), then
+//     letters, then the other characters: 1.0~rc1 < 1.0 < 1.0a < 1.0+dfsg
+//   - relations are <<, <=, =, >=, >> (and the obsolete < and > meaning <= and >=),
+//     with a comma between required relations and | between alternatives
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Debian version field characters, in ASCII order
+const (
+	// debianUpstreamChars lists the non-digit characters of upstream versions that are
+	// not followed by a revision
+	debianUpstreamChars = "+.ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz~"
+	// debianUpstreamHyphenChars lists the non-digit characters of upstream versions
+	// followed by a revision, which may contain hyphens
+	debianUpstreamHyphenChars = "+-.ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz~"
+	// debianRevisionChars lists the non-digit characters of revisions
+	debianRevisionChars = debianUpstreamChars
+)
+
+// debianVersionRegex splits a Debian version into epoch, upstream version and revision.
+var debianVersionRegex = regexp.MustCompile(`^(?:(\d+):)?(\d[A-Za-z0-9.+~-]*?)(?:-([A-Za-z0-9.+~]+))?$`)
+
+// debianOperatorRegex splits a relation into operator and version, with optional
+// parentheses as in package dependency fields.
+var debianOperatorRegex = regexp.MustCompile(`^\(?\s*(<<|<=|>=|>>|=|<|>)?\s*([^\s()]+)\s*\)?$`)
+
+// debianScheme describes dpkg version comparison.
+var debianScheme = distroScheme{
+	versionOrder: dpkgTokenOrder{chars: debianUpstreamChars},
+	hyphenOrder:  dpkgTokenOrder{chars: debianUpstreamHyphenChars},
+	releaseOrder: dpkgTokenOrder{chars: debianRevisionChars},
+}
+
+// parseDebianRelation parses Debian version relations such as ">= 1:2.30-1ubuntu2"
+// or "(>= 1.0), (<< 2.0) | (= 3.0)".
+//
+// The whole relation list is validated, and the returned constraint holds it with
+// surrounding whitespace removed.
+func parseDebianRelation(versionStr string) (*VersionConstraint, error) {
+	versionStr = strings.TrimSpace(versionStr)
+	if _, err := parseDebianRelations(versionStr); err != nil {
+		return nil, err
+	}
+
+	return &VersionConstraint{
+		Operator: OP_DEBIAN_RELATION,
+		Version:  versionStr,
+	}, nil
+}
+
+// debianRelationRegex creates a regex matching the Debian versions allowed by a list
+// of relations.
+// Result: ^(?:(?:0+:)?(?:...)|0*(?:[2-9]|...):...)$ matching 1:2.30-1ubuntu3 and 2:1.0 but not 2.31-1 (for ">= 1:2.30-1ubuntu2")
+func debianRelationRegex(relations string) (string, error) {
+	ranges, err := parseDebianRelations(relations)
+	if err != nil {
+		return "", err
+	}
+
+	pattern, ok := debianScheme.rangesPattern(ranges)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + REGEX_END, nil
+}
+
+// parseDebianRelations returns the version ranges allowed by a list of relations,
+// where commas separate required relations and | separates alternatives.
+func parseDebianRelations(relations string) ([]distroRange, error) {
+	if strings.TrimSpace(relations) == "" {
+		return nil, fmt.Errorf("empty Debian version relation")
+	}
+
+	ranges := []distroRange{{}}
+	for _, clause := range strings.Split(relations, ",") {
+		var alternatives []distroRange
+		for _, relation := range strings.Split(clause, "|") {
+			r, err := parseDebianSingleRelation(strings.TrimSpace(relation))
+			if err != nil {
+				return nil, err
+			}
+			alternatives = append(alternatives, r)
+		}
+		ranges = debianScheme.intersect(ranges, alternatives)
+	}
+	return ranges, nil
+}
+
+// parseDebianSingleRelation parses one relation such as ">> 1.0" or "(= 2:1.0-1)".
+// A version without operator means =.
+func parseDebianSingleRelation(relation string) (distroRange, error) {
+	matches := debianOperatorRegex.FindStringSubmatch(relation)
+	if matches == nil {
+		return distroRange{}, fmt.Errorf("invalid Debian version relation: %q", relation)
+	}
+	version, err := parseDebianVersion(matches[2])
+	if err != nil {
+		return distroRange{}, err
+	}
+
+	inclusive := &distroBound{distroVersion: version, inclusive: true}
+	exclusive := &distroBound{distroVersion: version}
+	switch matches[1] {
+	case "<<":
+		return distroRange{upper: exclusive}, nil
+	case "<=", "<":
+		return distroRange{upper: inclusive}, nil
+	case ">=", ">":
+		return distroRange{lower: inclusive}, nil
+	case ">>":
+		return distroRange{lower: exclusive}, nil
+	default:
+		return distroRange{lower: inclusive, upper: inclusive}, nil
+	}
+}
+
+// parseDebianVersion parses a Debian version such as "1:2.30-1ubuntu2".
+func parseDebianVersion(version string) (distroVersion, error) {
+	matches := debianVersionRegex.FindStringSubmatch(version)
+	if matches == nil || (matches[3] == "" && strings.Contains(matches[2], "-")) {
+		// A hyphen always starts a revision, which cannot be empty
+		return distroVersion{}, fmt.Errorf("invalid Debian version: %s", version)
+	}
+
+	epoch := 0
+	if matches[1] != "" {
+		var err error
+		if epoch, err = strconv.Atoi(matches[1]); err != nil {
+			return distroVersion{}, fmt.Errorf("invalid Debian epoch in %s: %w", version, err)
+		}
+	}
+	return distroVersion{
+		epoch:      epoch,
+		version:    matches[2],
+		release:    matches[3],
+		hasRelease: matches[3] != "",
+	}, nil
+}
+
+// compareDebianVersions orders two Debian versions like dpkg --compare-versions.
+func compareDebianVersions(a, b string) (int, error) {
+	va, err := parseDebianVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseDebianVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return debianScheme.compare(va, vb), nil
+}
+
+// dpkgTokenOrder implements distroTokenOrder for dpkg. Fields are split into single
+// non-digit characters and runs of digits, and a missing token compares like the
+// number 0, which every non-digit character except ~ sorts after.
+type dpkgTokenOrder struct {
+	// chars lists the non-digit characters allowed in the field, in ASCII order
+	chars string
+}
+
+// tokenize splits a field into non-digit characters and numbers.
+func (o dpkgTokenOrder) tokenize(field string) []distroToken {
+	var tokens []distroToken
+	for i := 0; i < len(field); {
+		if !isDigit(field[i]) {
+			tokens = append(tokens, distroToken{kind: distroTokenChar, text: field[i : i+1]})
+			i++
+			continue
+		}
+		j := i
+		for j < len(field) && isDigit(field[j]) {
+			j++
+		}
+		tokens = append(tokens, distroToken{kind: distroTokenNumber, value: trimDecimal(field[i:j])})
+		i = j
+	}
+	return tokens
+}
+
+// compare orders two tokens. Numbers and the end marker only compare by value among
+// themselves, as dpkg compares a non-digit character with the end of a non-digit run.
+func (o dpkgTokenOrder) compare(a, b distroToken) int {
+	if c := compareInts(dpkgWeight(a), dpkgWeight(b)); c != 0 {
+		return c
+	}
+	return compareDecimals(a.value, b.value)
+}
+
+// dpkgWeight returns the weight of a non-digit character in dpkg's order, and 0 for
+// numbers and the end marker.
+func dpkgWeight(t distroToken) int {
+	if t.kind != distroTokenChar {
+		return 0
+	}
+	switch c := t.text[0]; {
+	case c == '~':
+		return -1
+	case c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z':
+		return int(c)
+	default:
+		return int(c) + 256
+	}
+}
+
+// between matches the characters and numbers strictly between lo and hi. A number
+// cannot directly follow another number.
+func (o dpkgTokenOrder) between(lo, hi *distroToken, prev distroTokenKind) []distroTokenPattern {
+	var patterns []distroTokenPattern
+
+	chars := ""
+	for i := 0; i < len(o.chars); i++ {
+		t := distroToken{kind: distroTokenChar, text: o.chars[i : i+1]}
+		if (lo == nil || o.compare(*lo, t) < 0) && (hi == nil || o.compare(t, *hi) < 0) {
+			chars += t.text
+		}
+	}
+	if chars != "" {
+		patterns = append(patterns, distroTokenPattern{pattern: distroTokenClass(chars), kind: distroTokenChar})
+	}
+
+	if prev == distroTokenNumber {
+		return patterns
+	}
+	// An empty to means no upper limit
+	from, to := "0", ""
+	if lo != nil {
+		switch weight := dpkgWeight(*lo); {
+		case weight > 0:
+			return patterns
+		case weight == 0:
+			from = incrementDecimal(lo.value)
+		}
+	}
+	if hi != nil {
+		switch weight := dpkgWeight(*hi); {
+		case weight < 0:
+			return patterns
+		case weight == 0:
+			if hi.value == "0" {
+				return patterns
+			}
+			to = decrementDecimal(hi.value)
+		}
+	}
+	if to == "" || compareDecimals(from, to) <= 0 {
+		patterns = append(patterns, distroTokenPattern{pattern: paddedDecimalRange(from, to), kind: distroTokenNumber})
+	}
+	return patterns
+}
+
+// equal matches the tokens equal to t. The end marker equals a number 0.
+func (o dpkgTokenOrder) equal(t distroToken, prev distroTokenKind) []distroTokenPattern {
+	switch {
+	case t.kind == distroTokenChar && !strings.Contains(o.chars, t.text):
+		return nil
+	case t.kind == distroTokenChar:
+		return []distroTokenPattern{{pattern: regexp.QuoteMeta(t.text), kind: distroTokenChar}}
+	case prev == distroTokenNumber:
+		return nil
+	default:
+		return []distroTokenPattern{{pattern: paddedDecimalRange(t.value, t.value), kind: distroTokenNumber}}
+	}
+}
+
+// tail matches any rest of a field. After a number, it starts with a non-digit.
+func (o dpkgTokenOrder) tail(prev distroTokenKind) string {
+	any := distroTokenClass(distroCharsUnion(o.chars, distroDigits))
+	switch prev {
+	case distroTokenStart:
+		return any + "+"
+	case distroTokenNumber:
+		return "(?:" + distroTokenClass(o.chars) + any + "*)?"
+	default:
+		return any + "*"
+	}
+}
+
+// end matches the end of a field, which needs no separator.
+func (o dpkgTokenOrder) end(prev distroTokenKind) string {
+	return ""
+}
+
+// isDigit reports whether c is an ASCII digit.
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
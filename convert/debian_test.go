@@ -0,0 +1,115 @@
+// Package convert provides tests for Debian package version handling functionality.
+// This file contains unit tests for dpkg version ordering and relation regexes.
+package convert
+
+import (
+	"regexp"
+	"testing"
+)
+
+// debianCorpus lists Debian versions used to check relation regexes against
+// compareDebianVersions.
+var debianCorpus = []string{
+	"0", "00", "0.9", "1", "1.0", "1.0.0", "1.00", "1.0~rc1", "1.0~~", "1.0~", "1.0a", "1.0A",
+	"1.0+dfsg", "1.0.1", "1.0-0", "1.0-1", "1.0-1ubuntu1", "1.0-1~bpo1", "1.0-1+b1", "1.0-2",
+	"1.0-10", "1.0-1-1", "1.0a-1", "1.1", "1.10", "1.9", "2", "2.0~beta", "2.30", "2.30-1",
+	"2.30-1ubuntu2", "2.30-1ubuntu10", "2.30-1ubuntu2.1", "2.30-2", "2.30-1ubuntu1", "2.31-1",
+	"2.30~rc-1", "1:0.1", "1:2.30", "1:2.30-1ubuntu1", "1:2.30-1ubuntu2", "1:2.30-1ubuntu3",
+	"1:2.30-1ubuntu2~18.04", "1:2.30-1ubuntu2+esm1", "01:2.30-1ubuntu2", "1:2.31", "2:1.0", "0:1.0",
+	"10:0", "1.0.a", "1.0-a", "1:1.0-1-1", "0~20240115103045000000", "0~20240115103045000001",
+	"0~020240115103045000000", "0~9223372036854775808", "1.0+20240115103045000000",
+}
+
+// TestCompareDebianVersions tests dpkg ordering on known pairs.
+func TestCompareDebianVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0~rc1", "1.0", -1},
+		{"1.0~~", "1.0~", -1},
+		{"1.0", "1.0a", -1},
+		{"1.0a", "1.0+dfsg", -1},
+		{"1.0", "1.0-0", 0},
+		{"1.00", "1.0", 0},
+		{"1.0-1ubuntu1", "1.0-1", 1},
+		{"1:0.1", "2.30", 1},
+		{"0:1.0", "1.0", 0},
+		{"2.30-1ubuntu10", "2.30-1ubuntu2", 1},
+		{"1.0-1-1", "1.0-2", 1},
+		{"1.0.0", "1.0", 1},
+		{"0~20240115103045000001", "0~20240115103045000000", 1},
+		{"0~020240115103045000000", "0~20240115103045000000", 0},
+		{"0~9223372036854775808", "0~20240115103045000000", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			c, err := compareDebianVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("compareDebianVersions(%q, %q) returned unexpected error: %v", tt.a, tt.b, err)
+			}
+			if c != tt.expected {
+				t.Errorf("compareDebianVersions(%q, %q) = %d, expected %d", tt.a, tt.b, c, tt.expected)
+			}
+		})
+	}
+}
+
+// TestDebianRelationRegex compares relation regexes with compareDebianVersions over
+// debianCorpus.
+func TestDebianRelationRegex(t *testing.T) {
+	bounds := []string{"1.0", "1.0~rc1", "1.0-1", "1.0-0", "1.0a", "2.30-1ubuntu2", "1:2.30-1ubuntu2", "1:2.30", "0.9", "1.0-1-1", "2", "0~20240115103045000000"}
+	operators := map[string]func(int) bool{
+		"<<": func(c int) bool { return c < 0 },
+		"<=": func(c int) bool { return c <= 0 },
+		"=":  func(c int) bool { return c == 0 },
+		">=": func(c int) bool { return c >= 0 },
+		">>": func(c int) bool { return c > 0 },
+	}
+
+	check := func(t *testing.T, relation string, want func(string) bool) {
+		regex, err := VersionToRegexWithOptions(relation, Options{Ecosystem: ECOSYSTEM_DEBIAN})
+		if err != nil {
+			t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", relation, err)
+		}
+		for _, version := range debianCorpus {
+			if expected := want(version); regex.MatchString(version) != expected {
+				t.Errorf("%s: pattern %q matching %q: expected %v", relation, regex.String(), version, expected)
+			}
+		}
+	}
+	satisfies := func(version, operator, bound string) bool {
+		c, err := compareDebianVersions(version, bound)
+		if err != nil {
+			t.Fatalf("compareDebianVersions(%q, %q) returned unexpected error: %v", version, bound, err)
+		}
+		return operators[operator](c)
+	}
+
+	for operator := range operators {
+		for _, bound := range bounds {
+			check(t, operator+" "+bound, func(v string) bool { return satisfies(v, operator, bound) })
+		}
+	}
+	for _, lo := range bounds {
+		for _, hi := range bounds {
+			relation := "(>= " + lo + "), (<< " + hi + ")"
+			check(t, relation, func(v string) bool { return satisfies(v, ">=", lo) && satisfies(v, "<<", hi) })
+		}
+	}
+	check(t, "<< 1.0 | >> 2", func(v string) bool { return satisfies(v, "<<", "1.0") || satisfies(v, ">>", "2") })
+	check(t, "1.0-1", func(v string) bool { return satisfies(v, "=", "1.0-1") })
+}
+
+// TestDebianRelationErrors tests rejection of invalid relations.
+func TestDebianRelationErrors(t *testing.T) {
+	for _, relation := range []string{"", ">= ", "=> 1.0", ">= a1.0", ">= 1.0_1", ">= 1.0,", ">= x:1.0"} {
+		if _, err := VersionToRegexWithOptions(relation, Options{Ecosystem: ECOSYSTEM_DEBIAN}); err == nil {
+			t.Errorf("VersionToRegexWithOptions(%q) expected error but got none", relation)
+		}
+	}
+	if regexp.MustCompile(NEVER_MATCH_PATTERN).MatchString("1.0") {
+		t.Errorf("NEVER_MATCH_PATTERN should not match")
+	}
+}
@@ -0,0 +1,535 @@
+// Package convert provides regex dialect output for version constraints.
+// This file contains the printer that renders generated patterns in the syntax of
+// other regex engines, such as PCRE, JavaScript, POSIX ERE, .NET and Python, for
+// configuration files, web servers and databases that do not run Go's RE2.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// Dialect selects the regex syntax a pattern is written in.
+type Dialect string
+
+// Supported regex dialects
+const (
+	// DIALECT_GO is Go's RE2 syntax, as used by VersionToRegex (default)
+	DIALECT_GO Dialect = "go"
+	// DIALECT_PCRE is Perl-compatible syntax, for PCRE2, PHP preg, nginx and grep -P
+	DIALECT_PCRE Dialect = "pcre"
+	// DIALECT_ECMASCRIPT is JavaScript RegExp syntax, without flags
+	DIALECT_ECMASCRIPT Dialect = "ecmascript"
+	// DIALECT_POSIX_ERE is POSIX extended syntax, for grep -E, awk and find -regex,
+	// with bracket expressions instead of \d and plain groups instead of (?:
+	DIALECT_POSIX_ERE Dialect = "posix-ere"
+	// DIALECT_DOTNET is .NET System.Text.RegularExpressions syntax
+	DIALECT_DOTNET Dialect = "dotnet"
+	// DIALECT_PYTHON is the syntax of Python's re module
+	DIALECT_PYTHON Dialect = "python"
+)
+
+// DIALECTS lists the supported dialects, in the order they are documented.
+var DIALECTS = []Dialect{DIALECT_GO, DIALECT_PCRE, DIALECT_ECMASCRIPT, DIALECT_POSIX_ERE, DIALECT_DOTNET, DIALECT_PYTHON}
+
+// Pattern converts a version constraint string to a regex pattern written in the
+// syntax of dialect.
+//
+// The pattern matches the same versions as the regex of VersionToRegexWithOptions,
+// with the boundary, tag and capture options of opts. Anchors are written so that
+// they only match at the very ends of the input: $ in PCRE and Python also matches
+// before a final newline, so \z and \Z are used there.
+//
+// Parameters:
+//   - versionStr: The version constraint string to convert
+//   - dialect: The regex syntax to write the pattern in
+//   - opts: Conversion options, such as the ecosystem or the boundary
+//
+// Returns:
+//   - string: Pattern in the syntax of dialect
+//   - error: Error if the constraint cannot be converted, or the pattern cannot be
+//     written in dialect (e.g. word boundaries in POSIX ERE)
+//
+// Example:
+//
+//	pattern, err := Pattern("^1.2.3", DIALECT_POSIX_ERE, Options{})
+//	// pattern: ^1\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?$
+func Pattern(versionStr string, dialect Dialect, opts Options) (string, error) {
+	pattern, err := VersionToPattern(versionStr, opts)
+	if err != nil {
+		return "", err
+	}
+	bounded, err := boundedPattern(pattern, opts)
+	if err != nil {
+		return "", err
+	}
+	return TranslatePattern(bounded, dialect)
+}
+
+// TranslatePattern rewrites a Go regex pattern, such as one returned by
+// VersionToPattern, in the syntax of dialect.
+//
+// Case-insensitive parts become (?i:...) groups where the dialect has them, and
+// character classes listing both cases otherwise.
+//
+// Parameters:
+//   - pattern: A pattern in Go's RE2 syntax
+//   - dialect: The regex syntax to write the pattern in
+//
+// Returns:
+//   - string: Equivalent pattern in the syntax of dialect
+//   - error: Error if the pattern does not parse or uses features the dialect lacks
+func TranslatePattern(pattern string, dialect Dialect) (string, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse regex: %w", err)
+	}
+
+	switch dialect {
+	case DIALECT_GO:
+		return pattern, nil
+	case DIALECT_PCRE, DIALECT_ECMASCRIPT, DIALECT_POSIX_ERE, DIALECT_DOTNET, DIALECT_PYTHON:
+	default:
+		return "", fmt.Errorf("unsupported regex dialect: %s", dialect)
+	}
+
+	p := &dialectPrinter{dialect: dialect, names: make(map[string]int)}
+	translated, err := p.print(re)
+	if err != nil {
+		return "", err
+	}
+	if p.duplicateNames() {
+		switch dialect {
+		case DIALECT_PCRE:
+			// PCRE only accepts duplicate group names with the J option
+			translated = "(?J)" + translated
+		case DIALECT_ECMASCRIPT, DIALECT_PYTHON:
+			return "", fmt.Errorf("%s does not support duplicate group names, which capture groups need here", dialect)
+		}
+	}
+	return translated, nil
+}
+
+// ParseDialect returns the dialect with the given name, such as "pcre" or "python".
+func ParseDialect(name string) (Dialect, error) {
+	for _, dialect := range DIALECTS {
+		if strings.EqualFold(name, string(dialect)) {
+			return dialect, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported regex dialect: %s", name)
+}
+
+// dialectPrinter prints parsed patterns in the syntax of a dialect.
+type dialectPrinter struct {
+	dialect Dialect
+	names   map[string]int // number of groups by name
+	fold    bool           // inside a (?i:...) group
+}
+
+// duplicateNames reports whether a group name was printed more than once.
+func (p *dialectPrinter) duplicateNames() bool {
+	for _, count := range p.names {
+		if count > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// posix reports whether the dialect is POSIX ERE, which lacks escapes such as \d
+// and non-capturing, lazy or lookaround constructs.
+func (p *dialectPrinter) posix() bool {
+	return p.dialect == DIALECT_POSIX_ERE
+}
+
+// group wraps s in a group that does not capture where the dialect has one.
+func (p *dialectPrinter) group(s string) string {
+	if p.posix() {
+		return "(" + s + ")"
+	}
+	return "(?:" + s + ")"
+}
+
+// print prints re in the syntax of the dialect.
+func (p *dialectPrinter) print(re *syntax.Regexp) (string, error) {
+	if p.opensFold(re) {
+		p.fold = true
+		printed, err := p.print(re)
+		p.fold = false
+		return "(?i:" + printed + ")", err
+	}
+
+	switch re.Op {
+	case syntax.OpNoMatch:
+		if p.posix() {
+			// Nothing can come before the start of the text
+			return "(.^)", nil
+		}
+		return "(?!)", nil
+	case syntax.OpEmptyMatch:
+		return "", nil
+	case syntax.OpLiteral:
+		return p.literal(re)
+	case syntax.OpCharClass:
+		return p.class(re.Rune)
+	case syntax.OpAnyCharNotNL:
+		if p.posix() {
+			return ".", nil
+		}
+		return `[^\n]`, nil
+	case syntax.OpAnyChar:
+		if p.posix() {
+			return ".", nil
+		}
+		return `[\s\S]`, nil
+	case syntax.OpBeginText:
+		return "^", nil
+	case syntax.OpEndText:
+		switch p.dialect {
+		case DIALECT_PCRE, DIALECT_DOTNET:
+			return `\z`, nil
+		case DIALECT_PYTHON:
+			return `\Z`, nil
+		default:
+			return "$", nil
+		}
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		if p.posix() {
+			return "", fmt.Errorf("%s has no word boundaries; use delimiters instead", p.dialect)
+		}
+		if re.Op == syntax.OpNoWordBoundary {
+			return `\B`, nil
+		}
+		return `\b`, nil
+	case syntax.OpCapture:
+		return p.capture(re)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		return p.repeat(re)
+	case syntax.OpConcat:
+		var s strings.Builder
+		for _, sub := range re.Sub {
+			printed, err := p.print(sub)
+			if err != nil {
+				return "", err
+			}
+			if sub.Op == syntax.OpAlternate && optionalAlternative(sub) == nil && !p.opensFold(sub) {
+				printed = p.group(printed)
+			}
+			s.WriteString(printed)
+		}
+		return s.String(), nil
+	case syntax.OpAlternate:
+		if optional := optionalAlternative(re); optional != nil {
+			return p.repeat(optional)
+		}
+		alternatives := make([]string, len(re.Sub))
+		for i, sub := range re.Sub {
+			printed, err := p.print(sub)
+			if err != nil {
+				return "", err
+			}
+			alternatives[i] = printed
+		}
+		return strings.Join(alternatives, REGEX_OR), nil
+	default:
+		return "", fmt.Errorf("%s cannot express %s", p.dialect, re)
+	}
+}
+
+// capture prints a capturing group, named in the syntax of the dialect.
+func (p *dialectPrinter) capture(re *syntax.Regexp) (string, error) {
+	body, err := p.print(re.Sub[0])
+	if err != nil {
+		return "", err
+	}
+	if re.Name == "" {
+		return "(" + body + ")", nil
+	}
+
+	p.names[re.Name]++
+	switch p.dialect {
+	case DIALECT_POSIX_ERE:
+		return "", fmt.Errorf("%s has no named groups", p.dialect)
+	case DIALECT_PYTHON:
+		return "(?P<" + re.Name + ">" + body + ")", nil
+	default:
+		return "(?<" + re.Name + ">" + body + ")", nil
+	}
+}
+
+// repeat prints a repetition, grouping its body when it is not a single unit.
+func (p *dialectPrinter) repeat(re *syntax.Regexp) (string, error) {
+	sub := re.Sub[0]
+	body, err := p.print(sub)
+	if err != nil {
+		return "", err
+	}
+	if !isAtom(sub) && !p.opensFold(sub) {
+		body = p.group(body)
+	}
+
+	var quantifier string
+	switch re.Op {
+	case syntax.OpStar:
+		quantifier = "*"
+	case syntax.OpPlus:
+		quantifier = "+"
+	case syntax.OpQuest:
+		quantifier = "?"
+	default:
+		switch {
+		case re.Min == re.Max:
+			quantifier = fmt.Sprintf("{%d}", re.Min)
+		case re.Max < 0:
+			quantifier = fmt.Sprintf("{%d,}", re.Min)
+		default:
+			quantifier = fmt.Sprintf("{%d,%d}", re.Min, re.Max)
+		}
+	}
+
+	// POSIX has no lazy quantifiers, which only change submatches, not matches
+	if re.Flags&syntax.NonGreedy != 0 && !p.posix() {
+		quantifier += "?"
+	}
+	return body + quantifier, nil
+}
+
+// foldsInline reports whether the dialect has (?i:...) groups.
+func (p *dialectPrinter) foldsInline() bool {
+	switch p.dialect {
+	case DIALECT_PCRE, DIALECT_DOTNET, DIALECT_PYTHON:
+		return true
+	default:
+		return false
+	}
+}
+
+// opensFold reports whether re is printed in a (?i:...) group of its own.
+func (p *dialectPrinter) opensFold(re *syntax.Regexp) bool {
+	return p.foldsInline() && !p.fold && needsFold(re) && canFold(re)
+}
+
+// literal prints literal text, case-insensitively if re has the FoldCase flag.
+func (p *dialectPrinter) literal(re *syntax.Regexp) (string, error) {
+	fold := re.Flags&syntax.FoldCase != 0 && hasLetter(re.Rune)
+
+	var s strings.Builder
+	for _, r := range re.Rune {
+		if fold && !p.fold && hasLetter([]rune{r}) {
+			// Spell out the case variants of each letter
+			class, err := p.class(asciiFold(r))
+			if err != nil {
+				return "", err
+			}
+			s.WriteString(class)
+			continue
+		}
+		if p.fold {
+			r = unicode.ToLower(r)
+		}
+		escaped, err := p.literalRune(r)
+		if err != nil {
+			return "", err
+		}
+		s.WriteString(escaped)
+	}
+	return s.String(), nil
+}
+
+// asciiFold returns the class of the case variants of r, leaving out the variants
+// outside ASCII that Go adds to s and k (ſ, K), which other engines rarely fold.
+func asciiFold(r rune) []rune {
+	variants := []rune{r}
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < utf8RuneSelf || r >= utf8RuneSelf {
+			variants = append(variants, f)
+		}
+	}
+	var ranges []rune
+	for _, v := range variants {
+		ranges = append(ranges, v, v)
+	}
+	return sortedRanges(ranges)
+}
+
+// literalRune prints a rune outside character classes.
+func (p *dialectPrinter) literalRune(r rune) (string, error) {
+	switch {
+	case p.dialect == DIALECT_ECMASCRIPT && r == '/':
+		// Slashes end JavaScript regex literals
+		return `\/`, nil
+	case r < utf8RuneSelf && unicode.IsPrint(r):
+		return regexp.QuoteMeta(string(r)), nil
+	default:
+		return p.escapeRune(r)
+	}
+}
+
+// escapeRune prints a rune that is not printable ASCII.
+func (p *dialectPrinter) escapeRune(r rune) (string, error) {
+	switch {
+	case p.posix():
+		// POSIX has no escapes; the rune is written as it is
+		return string(r), nil
+	case p.dialect == DIALECT_PCRE:
+		return fmt.Sprintf(`\x{%x}`, r), nil
+	case r <= 0xFF:
+		return fmt.Sprintf(`\x%02x`, r), nil
+	case r <= 0xFFFF:
+		return fmt.Sprintf(`\u%04x`, r), nil
+	case p.dialect == DIALECT_PYTHON:
+		return fmt.Sprintf(`\U%08x`, r), nil
+	default:
+		return "", fmt.Errorf("%s cannot write %U outside a character class", p.dialect, r)
+	}
+}
+
+// class prints a character class given as sorted rune ranges.
+func (p *dialectPrinter) class(ranges []rune) (string, error) {
+	if p.fold {
+		// Case variants are left to the (?i:...) group
+		if base, ok := foldBase(ranges); ok {
+			ranges = base
+		}
+	}
+	if len(ranges) == 0 {
+		return p.print(&syntax.Regexp{Op: syntax.OpNoMatch})
+	}
+	if len(ranges) == 2 && ranges[0] == ranges[1] {
+		return p.literalRune(ranges[0])
+	}
+
+	// Classes reaching the last rune, such as [^0-9], are printed negated
+	negated := ranges[len(ranges)-1] == unicode.MaxRune
+	if negated {
+		ranges = complementRanges(ranges)
+	} else {
+		ranges = withoutFoldExtras(ranges)
+	}
+
+	var body string
+	var err error
+	if p.posix() {
+		body = p.posixClassBody(ranges)
+	} else {
+		if !negated && len(ranges) == 2 && ranges[0] == '0' && ranges[1] == '9' && p.asciiDigits() {
+			return `\d`, nil
+		}
+		if body, err = p.classBody(ranges); err != nil {
+			return "", err
+		}
+	}
+
+	if negated {
+		return "[^" + body + "]", nil
+	}
+	return "[" + body + "]", nil
+}
+
+// asciiDigits reports whether \d only matches ASCII digits in the dialect; in .NET
+// and Python it matches every Unicode decimal digit.
+func (p *dialectPrinter) asciiDigits() bool {
+	return p.dialect == DIALECT_PCRE || p.dialect == DIALECT_ECMASCRIPT
+}
+
+// withoutFoldExtras removes the non-ASCII case variants of s and k from a class that
+// contains their ASCII forms, as Go's case folding adds them to classes such as
+// (?i:[a-z]).
+func withoutFoldExtras(ranges []rune) []rune {
+	for _, extra := range []struct{ variant, ascii rune }{{'ſ', 's'}, {'K', 'k'}} {
+		if inRanges(ranges, extra.ascii) {
+			ranges, _ = withoutRune(ranges, extra.variant)
+		}
+	}
+	return ranges
+}
+
+// inRanges reports whether r is in one of the ranges.
+func inRanges(ranges []rune, r rune) bool {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		if ranges[i] <= r && r <= ranges[i+1] {
+			return true
+		}
+	}
+	return false
+}
+
+// classBody prints the ranges of a class without brackets, escaping the characters
+// that are special inside classes.
+func (p *dialectPrinter) classBody(ranges []rune) (string, error) {
+	var s strings.Builder
+	for i := 0; i < len(ranges); i += 2 {
+		lo, err := p.classRune(ranges[i])
+		if err != nil {
+			return "", err
+		}
+		s.WriteString(lo)
+		if ranges[i+1] == ranges[i] {
+			continue
+		}
+		hi, err := p.classRune(ranges[i+1])
+		if err != nil {
+			return "", err
+		}
+		if ranges[i+1] > ranges[i]+1 {
+			s.WriteString("-")
+		}
+		s.WriteString(hi)
+	}
+	return s.String(), nil
+}
+
+// classRune prints a rune inside a character class.
+func (p *dialectPrinter) classRune(r rune) (string, error) {
+	switch {
+	case strings.ContainsRune(`\]-[^`, r):
+		return `\` + string(r), nil
+	case r < utf8RuneSelf && unicode.IsPrint(r):
+		return string(r), nil
+	case r > 0xFFFF && p.dialect != DIALECT_PCRE && p.dialect != DIALECT_PYTHON:
+		return "", fmt.Errorf("%s cannot write %U in a character class", p.dialect, r)
+	default:
+		return p.escapeRune(r)
+	}
+}
+
+// posixClassBody prints the ranges of a bracket expression. POSIX has no escapes in
+// brackets, so ] comes first and - last, and ^ is never first.
+func (p *dialectPrinter) posixClassBody(ranges []rune) string {
+	var first, last string
+	if rest, ok := withoutRune(ranges, ']'); ok {
+		first, ranges = "]", rest
+	}
+	if rest, ok := withoutRune(ranges, '-'); ok {
+		last, ranges = "-", rest
+	}
+	caret := false
+	if rest, ok := withoutRune(ranges, '^'); ok {
+		caret, ranges = true, rest
+	}
+
+	var s strings.Builder
+	s.WriteString(first)
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		s.WriteRune(lo)
+		if hi > lo+1 {
+			s.WriteString("-")
+		}
+		if hi > lo {
+			s.WriteRune(hi)
+		}
+	}
+	if caret {
+		s.WriteString("^")
+	}
+	if s.Len() == 0 && last == "" {
+		// A class of only ^ cannot be written with ^ first
+		return `^]`
+	}
+	return s.String() + last
+}
@@ -0,0 +1,198 @@
+// Package convert provides tests for regex dialect output.
+// This file checks the syntax written for each dialect, and that the patterns still
+// match the same versions when read back by Go's regexp package.
+package convert
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestPattern tests the patterns written for each dialect.
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		constraint string
+		dialect    Dialect
+		opts       Options
+		expected   string
+	}{
+		{"~1.2", DIALECT_GO, Options{}, `^1\.2\.(?:0|[1-9]\d*)(?:-[-.\dA-Za-z]+)?(?:\+[-.\dA-Za-z]+)?$`},
+		{"~1.2", DIALECT_PCRE, Options{}, `^1\.2\.(?:0|[1-9]\d*)(?:-[\-.0-9A-Za-z]+)?(?:\+[\-.0-9A-Za-z]+)?\z`},
+		{"~1.2", DIALECT_ECMASCRIPT, Options{}, `^1\.2\.(?:0|[1-9]\d*)(?:-[\-.0-9A-Za-z]+)?(?:\+[\-.0-9A-Za-z]+)?$`},
+		{"~1.2", DIALECT_POSIX_ERE, Options{}, `^1\.2\.(0|[1-9][0-9]*)(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?$`},
+		{"~1.2", DIALECT_DOTNET, Options{}, `^1\.2\.(?:0|[1-9][0-9]*)(?:-[\-.0-9A-Za-z]+)?(?:\+[\-.0-9A-Za-z]+)?\z`},
+		{"~1.2", DIALECT_PYTHON, Options{}, `^1\.2\.(?:0|[1-9][0-9]*)(?:-[\-.0-9A-Za-z]+)?(?:\+[\-.0-9A-Za-z]+)?\Z`},
+		{"1.2.3", DIALECT_ECMASCRIPT, Options{Prefix: "refs/tags/v"}, `^refs\/tags\/v1\.2\.3(?:-[\-.0-9A-Za-z]+)?(?:\+[\-.0-9A-Za-z]+)?$`},
+		{"1.2.3", DIALECT_POSIX_ERE, Options{Boundary: BOUNDARY_NONE}, `1\.2\.3(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?`},
+		{"1.2.3", DIALECT_PCRE, Options{Boundary: BOUNDARY_WORD}, `\b1\.2\.3(?:-[\-.0-9A-Za-z]+)?(?:\+[\-.0-9A-Za-z]+)?\b`},
+		{"1.2.3", DIALECT_PYTHON, Options{CaptureGroups: true}, `^(?P<major>1)\.(?P<minor>2)\.(?P<patch>3)(?:-(?P<prerelease>[\-.0-9A-Za-z]+))?(?:\+(?P<build>[\-.0-9A-Za-z]+))?\Z`},
+		{"1.2.3", DIALECT_DOTNET, Options{CaptureGroups: true}, `^(?<major>1)\.(?<minor>2)\.(?<patch>3)(?:-(?<prerelease>[\-.0-9A-Za-z]+))?(?:\+(?<build>[\-.0-9A-Za-z]+))?\z`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect)+" "+tt.constraint, func(t *testing.T) {
+			pattern, err := Pattern(tt.constraint, tt.dialect, tt.opts)
+			if err != nil {
+				t.Fatalf("Pattern(%q, %s) returned unexpected error: %v", tt.constraint, tt.dialect, err)
+			}
+			if pattern != tt.expected {
+				t.Errorf("Pattern(%q, %s) = %q, expected %q", tt.constraint, tt.dialect, pattern, tt.expected)
+			}
+		})
+	}
+}
+
+// goEquivalent reads a pattern written for dialect back as a Go regex.
+func goEquivalent(pattern string, dialect Dialect) (*regexp.Regexp, error) {
+	pattern = strings.ReplaceAll(pattern, "(?!)", NEVER_MATCH)
+	switch dialect {
+	case DIALECT_POSIX_ERE:
+		return regexp.CompilePOSIX(pattern)
+	case DIALECT_PCRE:
+		pattern = strings.TrimPrefix(pattern, "(?J)")
+	case DIALECT_PYTHON:
+		pattern = strings.ReplaceAll(pattern, `\Z`, `\z`)
+	}
+	return regexp.Compile(pattern)
+}
+
+// TestPatternEquivalence tests that the patterns of every dialect match the same
+// versions as the Go regex.
+func TestPatternEquivalence(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+	}{
+		{">=1.2.3", Options{}},
+		{"<=1.2.3.4", Options{}},
+		{"^1.2.3", Options{}},
+		{"1.2.*", Options{}},
+		{"[1.0,2.0)", Options{}},
+		{"(,1.0],[1.2,)", Options{}},
+		{"[1.0-alpha-1,1.0]", Options{MavenSnapshots: SNAPSHOTS_EXCLUDE}},
+		{"~> 2.1, >= 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}},
+		{"^1.2@beta || dev-main", Options{Ecosystem: ECOSYSTEM_COMPOSER}},
+		{">= 1:2.30-1ubuntu2", Options{Ecosystem: ECOSYSTEM_DEBIAN}},
+		{">=2024.10, <2025", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM.MICRO"}},
+		{"~1.2", Options{Prefix: "[v]", Suffix: "^{}"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+
+			for _, dialect := range DIALECTS {
+				pattern, err := Pattern(tt.constraint, dialect, tt.opts)
+				if err != nil {
+					t.Fatalf("Pattern(%q, %s) returned unexpected error: %v", tt.constraint, dialect, err)
+				}
+				translated, err := goEquivalent(pattern, dialect)
+				if err != nil {
+					t.Fatalf("%s pattern %q does not compile in Go: %v", dialect, pattern, err)
+				}
+
+				for _, version := range minimizeCorpus {
+					for _, candidate := range []string{version, strings.ToUpper(version), "[v]" + version + "^{}"} {
+						if regex.MatchString(candidate) != translated.MatchString(candidate) {
+							t.Errorf("%s pattern %q and %q disagree on %q", dialect, pattern, regex, candidate)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestTranslatePattern tests the translation of constructs that generated regexes
+// rarely contain.
+func TestTranslatePattern(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		dialect  Dialect
+		expected string
+	}{
+		{`[\]\-^a]`, DIALECT_POSIX_ERE, `[]a^-]`},
+		{`[\]\-^a]`, DIALECT_PCRE, `[\-\]\^a]`},
+		{`[^0-9]x`, DIALECT_POSIX_ERE, `[^0-9]x`},
+		{`[^0-9]x`, DIALECT_DOTNET, `[^0-9]x`},
+		{`[^0-9]x`, DIALECT_ECMASCRIPT, `[^0-9]x`},
+		{`a.b`, DIALECT_ECMASCRIPT, `a[^\n]b`},
+		{`(?s:a.b)`, DIALECT_PYTHON, `a[\s\S]b`},
+		{`\d+?x`, DIALECT_PCRE, `\d+?x`},
+		{`\d+?x`, DIALECT_POSIX_ERE, `[0-9]+x`},
+		{`a\x{e9}\t`, DIALECT_PCRE, `a\x{e9}\x{9}`},
+		{`a\x{e9}\t`, DIALECT_ECMASCRIPT, `a\xe9\x09`},
+		{`a\x{e9}\x{20ac}`, DIALECT_PYTHON, `a\xe9\u20ac`},
+		{`(?i:[a-z]+)`, DIALECT_DOTNET, `[A-Za-z]+`},
+		{`-(?i:snapshot)`, DIALECT_PCRE, `(?i:-snapshot)`},
+		{`-(?i:snapshot)`, DIALECT_ECMASCRIPT, `-[Ss][Nn][Aa][Pp][Ss][Hh][Oo][Tt]`},
+		{`(?i:rc|cr)\d+`, DIALECT_PYTHON, `(?i:(?:rc|cr)[0-9]+)`},
+		{`(?i:rc|cr)\d+`, DIALECT_POSIX_ERE, `([Rr][Cc]|[Cc][Rr])[0-9]+`},
+		{`(?i:a(?:lpha)?)*`, DIALECT_PCRE, `(?i:(?:a(?:lpha)?)*)`},
+		{NEVER_MATCH, DIALECT_ECMASCRIPT, `(?!)`},
+		{NEVER_MATCH, DIALECT_POSIX_ERE, `(.^)`},
+		{`(?P<major>\d+)`, DIALECT_GO, `(?P<major>\d+)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect)+" "+tt.pattern, func(t *testing.T) {
+			translated, err := TranslatePattern(tt.pattern, tt.dialect)
+			if err != nil {
+				t.Fatalf("TranslatePattern(%q, %s) returned unexpected error: %v", tt.pattern, tt.dialect, err)
+			}
+			if translated != tt.expected {
+				t.Errorf("TranslatePattern(%q, %s) = %q, expected %q", tt.pattern, tt.dialect, translated, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPatternErrors tests the patterns that a dialect cannot express.
+func TestPatternErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		dialect    Dialect
+		opts       Options
+	}{
+		{"unknown dialect", "1.2.3", Dialect("vim"), Options{}},
+		{"word boundary in POSIX", "1.2.3", DIALECT_POSIX_ERE, Options{Boundary: BOUNDARY_WORD}},
+		{"named groups in POSIX", "1.2.3", DIALECT_POSIX_ERE, Options{CaptureGroups: true}},
+		{"duplicate names in JavaScript", ">=1.2.3", DIALECT_ECMASCRIPT, Options{CaptureGroups: true}},
+		{"duplicate names in Python", ">=1.2.3", DIALECT_PYTHON, Options{CaptureGroups: true}},
+		{"invalid constraint", ">=abc", DIALECT_PCRE, Options{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if pattern, err := Pattern(tt.constraint, tt.dialect, tt.opts); err == nil {
+				t.Errorf("Pattern(%q, %s) = %q, expected an error", tt.constraint, tt.dialect, pattern)
+			}
+		})
+	}
+
+	// PCRE and .NET accept the same name in several alternatives
+	pattern, err := Pattern(">=1.2.3", DIALECT_PCRE, Options{CaptureGroups: true})
+	if err != nil || !strings.HasPrefix(pattern, "(?J)^") {
+		t.Errorf("Pattern with duplicate names in PCRE = %q, %v, expected the J option", pattern, err)
+	}
+	if _, err := Pattern(">=1.2.3", DIALECT_DOTNET, Options{CaptureGroups: true}); err != nil {
+		t.Errorf("Pattern with duplicate names in .NET returned unexpected error: %v", err)
+	}
+}
+
+// TestParseDialect tests dialect names.
+func TestParseDialect(t *testing.T) {
+	for _, dialect := range DIALECTS {
+		if parsed, err := ParseDialect(strings.ToUpper(string(dialect))); err != nil || parsed != dialect {
+			t.Errorf("ParseDialect(%q) = %q, %v", strings.ToUpper(string(dialect)), parsed, err)
+		}
+	}
+	if _, err := ParseDialect("perl6"); err == nil {
+		t.Error("ParseDialect should return an error for an unknown dialect")
+	}
+}
@@ -0,0 +1,509 @@
+// Package convert provides Linux distribution package version handling functionality.
+// This file contains the machinery shared by the Debian (dpkg) and RPM version
+// orderings.
+//
+// Both write versions as [epoch:]version[-release] and compare them field by field:
+// the epoch as a number (0 when missing), then the version, then the release. The
+// version and release fields are split into tokens and compared token by token,
+// where a missing token is an end marker with its own place in the ordering. The
+// two orderings only differ in how fields are split into tokens and how tokens
+// are ordered, which is supplied by a distroTokenOrder.
+package convert
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Character sets of package version fields, in ASCII order
+const (
+	distroDigits  = "0123456789"
+	distroLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// distroTokenKind classifies the tokens of a package version field.
+type distroTokenKind int
+
+// Token kinds of package version fields
+const (
+	// distroTokenStart is the kind "before" the first token of a field
+	distroTokenStart distroTokenKind = iota
+	// distroTokenEnd marks the end of a field
+	distroTokenEnd
+	// distroTokenChar is a single non-digit character (dpkg)
+	distroTokenChar
+	// distroTokenNumber is a run of digits
+	distroTokenNumber
+	// distroTokenAlpha is a run of letters (rpm)
+	distroTokenAlpha
+	// distroTokenTilde is a ~ (rpm)
+	distroTokenTilde
+	// distroTokenCaret is a ^ (rpm)
+	distroTokenCaret
+)
+
+// distroToken is one token of a package version field.
+type distroToken struct {
+	kind distroTokenKind
+	// text holds the character or letters of the token
+	text string
+	// value holds the value of a number as a decimal string without leading zeros,
+	// so that digit runs such as timestamps may exceed int
+	value string
+}
+
+// distroEndToken is the token found after the end of a field.
+var distroEndToken = distroToken{kind: distroTokenEnd, value: "0"}
+
+// distroTokenPattern is a pattern matching single tokens of one kind.
+type distroTokenPattern struct {
+	pattern string
+	kind    distroTokenKind
+}
+
+// distroTokenOrder describes how an ecosystem splits package version fields into
+// tokens, orders them and spells them.
+//
+// Patterns depend on the kind of the previous token, since some tokens cannot
+// follow each other without a separator (two numbers would be a single number).
+type distroTokenOrder interface {
+	// tokenize splits a field into tokens
+	tokenize(field string) []distroToken
+	// compare orders two tokens, returning -1, 0 or 1
+	compare(a, b distroToken) int
+	// between matches the single tokens strictly between lo and hi, where nil
+	// bounds are unbounded
+	between(lo, hi *distroToken, prev distroTokenKind) []distroTokenPattern
+	// equal matches the single tokens equal to t, including the tokens equal to
+	// the end marker when t is distroEndToken
+	equal(t distroToken, prev distroTokenKind) []distroTokenPattern
+	// tail matches any rest of a field after a token of kind prev, including none
+	// (but at least one token after distroTokenStart)
+	tail(prev distroTokenKind) string
+	// end matches the end of a field after a token of kind prev
+	end(prev distroTokenKind) string
+}
+
+// distroTokenAt returns token i of a field, or the end marker after its last token.
+func distroTokenAt(tokens []distroToken, i int) distroToken {
+	if i < len(tokens) {
+		return tokens[i]
+	}
+	return distroEndToken
+}
+
+// compareDistroFields orders two fields token by token.
+func compareDistroFields(order distroTokenOrder, a, b string) int {
+	ta, tb := order.tokenize(a), order.tokenize(b)
+	for i := 0; i < len(ta) || i < len(tb); i++ {
+		if c := order.compare(distroTokenAt(ta, i), distroTokenAt(tb, i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// compareDistroEnd compares a field that ends before token i with the tokens of
+// bound from i on.
+func compareDistroEnd(order distroTokenOrder, bound []distroToken, i int) int {
+	for ; i < len(bound); i++ {
+		if c := order.compare(distroEndToken, bound[i]); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// distroFieldBounds holds the inclusive bounds of a field, as token lists.
+type distroFieldBounds struct {
+	lower, upper       []distroToken
+	hasLower, hasUpper bool
+}
+
+// distroFieldAlternative is a pattern matching field values within distroFieldBounds,
+// which may be equal to the lower or upper bound.
+type distroFieldAlternative struct {
+	pattern    string
+	equalLower bool
+	equalUpper bool
+}
+
+// distroFieldRange returns alternatives for the tokens from index i on of the field
+// values between the bounds, knowing that the tokens before i equal those of every
+// bound and that the previous token has kind prev.
+func distroFieldRange(order distroTokenOrder, b distroFieldBounds, i int, prev distroTokenKind) []distroFieldAlternative {
+	if !b.hasLower && !b.hasUpper {
+		return []distroFieldAlternative{{pattern: order.tail(prev)}}
+	}
+
+	var alternatives []distroFieldAlternative
+
+	// The field ends here (fields are never empty)
+	if i > 0 {
+		cl, cu := 1, -1
+		if b.hasLower {
+			cl = compareDistroEnd(order, b.lower, i)
+		}
+		if b.hasUpper {
+			cu = compareDistroEnd(order, b.upper, i)
+		}
+		if cl >= 0 && cu <= 0 {
+			alternatives = append(alternatives, distroFieldAlternative{
+				pattern:    order.end(prev),
+				equalLower: b.hasLower && cl == 0,
+				equalUpper: b.hasUpper && cu == 0,
+			})
+		}
+	}
+
+	var lo, hi *distroToken
+	if b.hasLower {
+		token := distroTokenAt(b.lower, i)
+		lo = &token
+	}
+	if b.hasUpper {
+		token := distroTokenAt(b.upper, i)
+		hi = &token
+	}
+	if lo != nil && hi != nil && order.compare(*lo, *hi) > 0 {
+		return alternatives
+	}
+
+	// The next token is strictly between the bounds, so the rest is free
+	for _, token := range order.between(lo, hi, prev) {
+		alternatives = append(alternatives, distroFieldAlternative{pattern: token.pattern + order.tail(token.kind)})
+	}
+
+	// The next token equals a bound, which decides the rest
+	next := func(t distroToken, bounds distroFieldBounds) {
+		for _, token := range order.equal(t, prev) {
+			for _, rest := range distroFieldRange(order, bounds, i+1, token.kind) {
+				alternatives = append(alternatives, distroFieldAlternative{
+					pattern:    token.pattern + rest.pattern,
+					equalLower: rest.equalLower,
+					equalUpper: rest.equalUpper,
+				})
+			}
+		}
+	}
+	switch {
+	case lo != nil && hi != nil && order.compare(*lo, *hi) == 0:
+		next(*lo, b)
+	default:
+		if lo != nil {
+			next(*lo, distroFieldBounds{lower: b.lower, hasLower: true})
+		}
+		if hi != nil {
+			next(*hi, distroFieldBounds{upper: b.upper, hasUpper: true})
+		}
+	}
+	return alternatives
+}
+
+// distroVersion is a parsed package version.
+type distroVersion struct {
+	epoch      int
+	version    string
+	release    string
+	hasRelease bool
+}
+
+// distroBound is one end of a distroRange.
+type distroBound struct {
+	distroVersion
+	inclusive bool
+}
+
+// distroRange is a contiguous interval of package versions. A nil bound is unbounded.
+type distroRange struct {
+	lower, upper *distroBound
+}
+
+// distroScheme describes the version format of a package manager.
+type distroScheme struct {
+	// versionOrder orders the version field
+	versionOrder distroTokenOrder
+	// hyphenOrder orders version fields followed by a release when they may then
+	// contain hyphens (dpkg), or is nil when they never do (rpm)
+	hyphenOrder distroTokenOrder
+	// releaseOrder orders the release field
+	releaseOrder distroTokenOrder
+	// optionalRelease compares releases only when both versions have one (rpm);
+	// otherwise a missing release is an empty one (dpkg)
+	optionalRelease bool
+}
+
+// compare orders two package versions.
+func (s distroScheme) compare(a, b distroVersion) int {
+	if a.epoch != b.epoch {
+		return compareInts(a.epoch, b.epoch)
+	}
+	if c := compareDistroFields(s.versionOrder, a.version, b.version); c != 0 {
+		return c
+	}
+	if s.optionalRelease && (!a.hasRelease || !b.hasRelease) {
+		return 0
+	}
+	return compareDistroFields(s.releaseOrder, a.release, b.release)
+}
+
+// compareBounds orders two bounds in the given direction: 1 when a is the tighter
+// lower bound, or for direction -1, the tighter upper bound.
+//
+// With optional releases, a bound without release covers every release of its
+// version, so it is the tighter one when exclusive and the looser one otherwise.
+func (s distroScheme) compareBounds(a, b *distroBound, direction int) int {
+	if c := s.compare(a.distroVersion, b.distroVersion); c != 0 {
+		return c * direction
+	}
+	if s.optionalRelease && a.hasRelease != b.hasRelease {
+		release := 1
+		if a.hasRelease {
+			release = -1
+		}
+		if !a.inclusive || !b.inclusive {
+			exclusiveWithout := (!a.hasRelease && !a.inclusive) || (!b.hasRelease && !b.inclusive)
+			if exclusiveWithout {
+				return release
+			}
+		}
+		return -release
+	}
+	switch {
+	case a.inclusive == b.inclusive:
+		return 0
+	case a.inclusive:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// intersect returns the ranges of versions inside both a and b.
+func (s distroScheme) intersect(a, b []distroRange) []distroRange {
+	var result []distroRange
+	for _, ra := range a {
+		for _, rb := range b {
+			r := distroRange{lower: ra.lower, upper: ra.upper}
+			if rb.lower != nil && (r.lower == nil || s.compareBounds(rb.lower, r.lower, 1) > 0) {
+				r.lower = rb.lower
+			}
+			if rb.upper != nil && (r.upper == nil || s.compareBounds(rb.upper, r.upper, -1) > 0) {
+				r.upper = rb.upper
+			}
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// rangesPattern returns the un-anchored pattern matching any of the given ranges.
+// It returns false when the ranges are all empty.
+func (s distroScheme) rangesPattern(ranges []distroRange) (string, bool) {
+	var alternatives []string
+	for _, r := range ranges {
+		alternatives = append(alternatives, s.rangePattern(r)...)
+	}
+	return groupAlternatives(alternatives)
+}
+
+// rangePattern returns alternatives matching the versions inside r.
+func (s distroScheme) rangePattern(r distroRange) []string {
+	if r.lower != nil && r.upper != nil {
+		// With optional releases, versions without release may still fit between
+		// bounds that only differ by release, so only the versions are compared
+		lower, upper := r.lower.distroVersion, r.upper.distroVersion
+		if s.optionalRelease {
+			lower.hasRelease, upper.hasRelease = false, false
+		}
+		c := s.compare(lower, upper)
+		if c > 0 || (c == 0 && !s.optionalRelease && !(r.lower.inclusive && r.upper.inclusive)) {
+			return nil
+		}
+	}
+
+	lo, hi := 0, -1
+	if r.lower != nil {
+		lo = r.lower.epoch
+	}
+	if r.upper != nil {
+		hi = r.upper.epoch
+	}
+
+	var alternatives []string
+	add := func(epochLo, epochHi int, lower, upper *distroBound) {
+		if epochHi >= 0 && epochLo > epochHi {
+			return
+		}
+		epoch := paddedNumRange(epochLo, epochHi) + ":"
+		if epochLo == 0 {
+			epoch = "(?:" + epoch + ")?"
+		}
+		if rest, ok := groupAlternatives(s.afterEpoch(lower, upper)); ok {
+			alternatives = append(alternatives, epoch+rest)
+		}
+	}
+
+	switch {
+	case r.lower != nil && r.upper != nil && lo == hi:
+		add(lo, lo, r.lower, r.upper)
+	default:
+		if r.lower != nil {
+			add(lo, lo, r.lower, nil)
+		}
+		// Epochs strictly between the bounds allow any version
+		from, to := lo, hi
+		if r.lower != nil {
+			from++
+		}
+		if r.upper != nil {
+			to--
+			if to < 0 {
+				to, from = 0, 1
+			}
+		}
+		add(from, to, nil, nil)
+		if r.upper != nil {
+			add(hi, hi, nil, r.upper)
+		}
+	}
+	return alternatives
+}
+
+// afterEpoch returns alternatives for the version and release fields of versions
+// between lower and upper (nil for unbounded), whose epochs are equal.
+func (s distroScheme) afterEpoch(lower, upper *distroBound) []string {
+	bounds := distroFieldBounds{hasLower: lower != nil, hasUpper: upper != nil}
+	if lower != nil {
+		bounds.lower = s.versionOrder.tokenize(lower.version)
+	}
+	if upper != nil {
+		bounds.upper = s.versionOrder.tokenize(upper.version)
+	}
+
+	var alternatives []string
+	versions := func(order distroTokenOrder, withRelease, withoutRelease bool) {
+		for _, version := range distroFieldRange(order, bounds, 0, distroTokenStart) {
+			lo, hi := lower, upper
+			if !version.equalLower {
+				lo = nil
+			}
+			if !version.equalUpper {
+				hi = nil
+			}
+			if rest, ok := groupAlternatives(s.releasePart(lo, hi, withRelease, withoutRelease)); ok {
+				alternatives = append(alternatives, version.pattern+rest)
+			}
+		}
+	}
+
+	if s.hyphenOrder != nil {
+		// A version followed by a release ends at the last hyphen
+		versions(s.hyphenOrder, true, false)
+		versions(s.versionOrder, false, true)
+	} else {
+		versions(s.versionOrder, true, true)
+	}
+	return alternatives
+}
+
+// releasePart returns alternatives for the release of versions between lower and
+// upper (nil when the version field differs from the bound), whose epochs and version
+// fields are equal to those of the non-nil bounds.
+func (s distroScheme) releasePart(lower, upper *distroBound, withRelease, withoutRelease bool) []string {
+	// Without a release on either side, the versions are equal
+	if s.optionalRelease {
+		if lower != nil && !lower.hasRelease {
+			if !lower.inclusive {
+				return nil
+			}
+			lower = nil
+		}
+		if upper != nil && !upper.hasRelease {
+			if !upper.inclusive {
+				return nil
+			}
+			upper = nil
+		}
+	}
+
+	var alternatives []string
+	if withoutRelease {
+		ok := true
+		for direction, bound := range map[int]*distroBound{1: lower, -1: upper} {
+			switch {
+			case bound == nil:
+			case s.optionalRelease:
+				ok = ok && bound.inclusive
+			default:
+				c := compareDistroFields(s.releaseOrder, "", bound.release) * direction
+				ok = ok && (c > 0 || (c == 0 && bound.inclusive))
+			}
+		}
+		if ok {
+			alternatives = append(alternatives, "")
+		}
+	}
+
+	if withRelease {
+		bounds := distroFieldBounds{hasLower: lower != nil, hasUpper: upper != nil}
+		if lower != nil {
+			bounds.lower = s.releaseOrder.tokenize(lower.release)
+		}
+		if upper != nil {
+			bounds.upper = s.releaseOrder.tokenize(upper.release)
+		}
+		for _, release := range distroFieldRange(s.releaseOrder, bounds, 0, distroTokenStart) {
+			if (release.equalLower && !lower.inclusive) || (release.equalUpper && !upper.inclusive) {
+				continue
+			}
+			alternatives = append(alternatives, "-"+release.pattern)
+		}
+	}
+	return alternatives
+}
+
+// distroTokenClass renders a list of characters, sorted in ASCII order, as a
+// character class with regex meta-characters escaped. It returns NEVER_MATCH for
+// an empty list.
+func distroTokenClass(chars string) string {
+	switch len(chars) {
+	case 0:
+		return NEVER_MATCH
+	case 1:
+		return regexp.QuoteMeta(chars)
+	}
+
+	var class strings.Builder
+	for i := 0; i < len(chars); {
+		j := i
+		for j+1 < len(chars) && chars[j+1] == chars[j]+1 && isAlphanumeric(chars[j+1]) == isAlphanumeric(chars[i]) {
+			j++
+		}
+		if j-i >= 2 && isAlphanumeric(chars[i]) {
+			class.WriteString(chars[i:i+1] + "-" + chars[j:j+1])
+		} else {
+			for k := i; k <= j; k++ {
+				if strings.IndexByte(`-^]\`, chars[k]) >= 0 {
+					class.WriteByte('\\')
+				}
+				class.WriteByte(chars[k])
+			}
+		}
+		i = j + 1
+	}
+	return "[" + class.String() + "]"
+}
+
+// distroCharsUnion merges two lists of characters into one, sorted in ASCII order.
+func distroCharsUnion(a, b string) string {
+	chars := []byte(a + b)
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	return string(chars)
+}
+
+// isAlphanumeric reports whether c is an ASCII letter or digit.
+func isAlphanumeric(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
@@ -0,0 +1,392 @@
+// Package convert provides plain-language descriptions of version constraints.
+// This file contains Explain, which describes the versions a constraint matches the
+// way the generated regex reads them, for command-line output and documentation.
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain describes in plain words the versions that the regex of a constraint matches.
+//
+// The description follows the regex rather than the specification of the ecosystem
+// where the two differ, so ^1.2.3 reads "any 1.x.x version" like its regex. Ranges of
+// the other ecosystems are listed as their bounds, joined by "or".
+//
+// Parameters:
+//   - versionStr: The version constraint string to describe
+//   - opts: Conversion options, such as the ecosystem and the tag affixes
+//
+// Returns:
+//   - string: Description of the matched versions
+//   - error: Error if the constraint cannot be parsed
+//
+// Examples:
+//
+//	Explain(">=1.2", Options{})
+//	// any version at least 1.2 (missing components count as 0), whatever its pre-release or build
+//
+//	Explain("[1.0,2.0)", Options{})
+//	// versions at least 1.0 and below 2.0
+func Explain(versionStr string, opts Options) (string, error) {
+	prefix, suffix, err := tagAffixes(opts)
+	if err != nil {
+		return "", err
+	}
+	constraint, err := parseConstraint(versionStr, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse version constraint: %w", err)
+	}
+
+	description, err := explainConstraint(constraint, opts)
+	if err != nil {
+		return "", err
+	}
+	if prefix != "" || suffix != "" {
+		description += ", written as " + prefix + "<version>" + suffix
+	}
+	return description, nil
+}
+
+// explainConstraint describes a parsed constraint.
+func explainConstraint(constraint *VersionConstraint, opts Options) (string, error) {
+	version := constraint.Version
+
+	switch constraint.Operator {
+	case OP_EQUAL_EQUAL, OP_EQUAL:
+		return explainExact(version, opts), nil
+	case OP_NOT_EQUAL:
+		if !strings.ContainsAny(version, "*-+") && !isNuGetExact(version, opts) {
+			return "any version except " + version + " and its pre-releases and builds", nil
+		}
+		return "any version except " + explainExact(version, opts), nil
+	case OP_GREATER_EQUAL:
+		return explainComparison(version, "at least", opts)
+	case OP_GREATER:
+		return explainComparison(version, "above", opts)
+	case OP_LESS_EQUAL:
+		return explainComparison(version, "at most", opts)
+	case OP_LESS:
+		return explainComparison(version, "below", opts)
+	case OP_CARET:
+		major, minor, _, err := parseVersionParts(version)
+		if err != nil {
+			return "", err
+		}
+		if major == "0" {
+			return fmt.Sprintf("any 0.%s.x version, whatever its pre-release or build", minor), nil
+		}
+		return fmt.Sprintf("any %s.x.x version, whatever its pre-release or build", major), nil
+	case OP_TILDE, OP_PESSIMISTIC, OP_COMPATIBLE:
+		major, minor, _, err := parseVersionParts(version)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("any %s.%s.x version, whatever its pre-release or build", major, minor), nil
+	case OP_MAVEN_RANGE:
+		ranges, err := parseMavenRangeSet(version)
+		if err != nil {
+			return "", err
+		}
+		return explainRanges(ranges, mavenQualifierOrder{snapshots: opts.MavenSnapshots}, rangeBoundText), nil
+	case OP_RUBY_REQUIREMENT:
+		return explainRubyRequirement(version)
+	case OP_COMPOSER_CONSTRAINT:
+		return explainComposerConstraint(version)
+	case OP_CARGO_REQUIREMENT:
+		return explainCargoRequirement(version)
+	case OP_DEBIAN_RELATION:
+		ranges, err := parseDebianRelations(version)
+		if err != nil {
+			return "", err
+		}
+		return explainDistroRanges(ranges, debianScheme), nil
+	case OP_RPM_REQUIREMENT:
+		ranges, err := parseRPMRequirements(version)
+		if err != nil {
+			return "", err
+		}
+		return explainDistroRanges(ranges, rpmScheme), nil
+	case OP_CALVER_CONSTRAINT:
+		return explainCalVerConstraint(version, opts)
+	case OP_NPM_RANGE:
+		return explainNpmRange(version)
+	case OP_PEP440_SPECIFIERS:
+		return explainPEP440Specifiers(version)
+	case OP_ADVISORY_RANGE:
+		ranges, err := advisoryRanges(version)
+		if err != nil {
+			return "", err
+		}
+		return explainRanges(ranges, semverPrereleaseOrder{}, rangeBoundText), nil
+	default:
+		return "", fmt.Errorf("unsupported operator: %s", constraint.Operator)
+	}
+}
+
+// explainExact describes the versions matched by an exact version, which may be a
+// wildcard, a Go pseudo-version or a NuGet version.
+func explainExact(version string, opts Options) string {
+	if strings.Contains(version, "*") {
+		parts := strings.Split(version, ".")
+		if parts[len(parts)-1] == "*" {
+			for len(parts) < 3 {
+				parts = append(parts, "*")
+			}
+		}
+		if strings.Trim(strings.Join(parts, ""), "*") == "" {
+			return "any version"
+		}
+		return "any " + strings.ReplaceAll(strings.Join(parts, "."), "*", "x") + " version, whatever its pre-release or build"
+	}
+
+	if isGoModuleVersion(version) && strings.Count(version, "-") >= 2 {
+		return "Go pseudo-version " + version
+	}
+	if isNuGetExact(version, opts) {
+		mainVersion, preRelease, buildMeta := splitNuGetVersion(version)
+		build := ", whatever its build"
+		if buildMeta != "" {
+			build = " and build " + buildMeta
+		}
+		if preRelease == "" {
+			return "NuGet version " + mainVersion + " (trailing zero components optional), with any pre-release label" + build
+		}
+		return "NuGet version " + mainVersion + " (trailing zero components optional) with pre-release " + preRelease + " in any case" + build
+	}
+
+	switch {
+	case strings.Contains(version, "+"):
+		return "version " + version
+	case strings.Contains(version, "-"):
+		return "version " + version + ", with any build metadata"
+	default:
+		return "version " + version + ", with any pre-release label or build metadata"
+	}
+}
+
+// explainComparison describes the versions matched by a comparison operator, given
+// the words that describe the relation to its version.
+func explainComparison(version, relation string, opts Options) (string, error) {
+	parts, err := parseVersionComponents(version)
+	if err != nil {
+		return "", err
+	}
+
+	if relation == "below" && strings.Trim(strings.Join(parts, ""), "0") == "" {
+		return "no version", nil
+	}
+	if opts.VersionLength == LENGTH_STRICT {
+		return fmt.Sprintf("any version of %d components %s %s, whatever its pre-release or build", len(parts), relation, version), nil
+	}
+	return fmt.Sprintf("any version %s %s (missing components count as 0), whatever its pre-release or build", relation, version), nil
+}
+
+// explainRubyRequirement describes the versions allowed by a RubyGems requirement list.
+func explainRubyRequirement(list string) (string, error) {
+	requirements, err := parseRubyRequirementList(list)
+	if err != nil {
+		return "", err
+	}
+
+	order := rubyPrereleaseOrder{}
+	ranges := []versionRange{{}}
+	for _, requirement := range requirements {
+		requirementRanges, err := rubyRequirementRanges(requirement)
+		if err != nil {
+			return "", err
+		}
+		ranges = intersectRanges(ranges, requirementRanges, order)
+	}
+	return explainRanges(ranges, order, rubyBoundText), nil
+}
+
+// explainComposerConstraint describes the versions and branches allowed by a Composer
+// constraint.
+func explainComposerConstraint(constraint string) (string, error) {
+	parsed, err := parseComposerConstraintSet(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	minimum := parsed.stability
+	if parsed.flag != -1 {
+		minimum = parsed.flag
+	}
+	order := composerSuffixOrder{minimum: minimum}
+
+	var alternatives []string
+	if hasVersions(parsed.ranges, order) {
+		// Version spans start and end at -dev, the least stable suffix, which reads
+		// as the plain version
+		description := explainRanges(parsed.ranges, order, func(b *rangeBound) string {
+			return strings.Join(b.parts, ".") + strings.TrimSuffix(b.suffix, "-dev")
+		})
+		if minimum < COMPOSER_RANK_STABLE {
+			description += ", down to " + composerSuffixLabels[minimum][0] + " stability"
+		}
+		alternatives = append(alternatives, description)
+	}
+	for _, branch := range parsed.branches {
+		alternatives = append(alternatives, "branch "+branch)
+	}
+	if len(alternatives) == 0 {
+		return "no version", nil
+	}
+	return strings.Join(alternatives, " or "), nil
+}
+
+// explainCargoRequirement describes the versions allowed by a Cargo requirement.
+func explainCargoRequirement(requirement string) (string, error) {
+	comparators, err := parseCargoComparators(requirement)
+	if err != nil {
+		return "", err
+	}
+	return explainSemverComparators(comparators), nil
+}
+
+// explainSemverComparators describes the versions allowed by every comparator of a
+// Cargo requirement or of an npm comparator set.
+func explainSemverComparators(comparators []cargoComparator) string {
+	order := semverPrereleaseOrder{}
+	ranges := []versionRange{{}}
+	var prereleases []string
+	for _, comparator := range comparators {
+		ranges = intersectRanges(ranges, []versionRange{comparator.versionRange()}, order)
+		if comparator.prerelease != "" {
+			prereleases = append(prereleases, strings.Join(comparator.parts, "."))
+		}
+	}
+
+	description := explainRanges(ranges, order, rangeBoundText)
+	if description == "no version" {
+		return description
+	}
+	if len(prereleases) == 0 {
+		return description + ", without pre-releases"
+	}
+	return description + ", with pre-releases only of " + strings.Join(uniqueStrings(prereleases), " or ")
+}
+
+// explainCalVerConstraint describes the versions allowed by a CalVer constraint, with
+// bounds spelled in the format of opts.CalVerFormat.
+func explainCalVerConstraint(constraint string, opts Options) (string, error) {
+	format, err := parseCalVerFormat(opts.CalVerFormat)
+	if err != nil {
+		return "", err
+	}
+	ranges, err := format.parseConstraint(constraint)
+	if err != nil {
+		return "", err
+	}
+
+	return explainRanges(ranges, semverPrereleaseOrder{releasesOnly: true}, func(b *rangeBound) string {
+		var s strings.Builder
+		for i, value := range calverValues(b.parts) {
+			fmt.Fprintf(&s, "%s%0*d", format.separators[i], format.components[i].width, value)
+		}
+		return s.String()
+	}), nil
+}
+
+// hasVersions reports whether any of the ranges contains a version.
+func hasVersions(ranges []versionRange, order suffixOrder) bool {
+	_, ok := seqBuilder{order: order}.rangesPattern(ranges)
+	return ok
+}
+
+// explainRanges lists the non-empty ranges, joined by "or", with bounds printed by text.
+func explainRanges(ranges []versionRange, order suffixOrder, text func(*rangeBound) string) string {
+	var alternatives []string
+	for _, r := range ranges {
+		if !hasVersions([]versionRange{r}, order) {
+			continue
+		}
+
+		switch {
+		case r.lower == nil && r.upper == nil:
+			return "any version"
+		case r.lower != nil && r.upper != nil && r.lower.inclusive && r.upper.inclusive && compareBounds(r.lower, r.upper, order) == 0:
+			alternatives = append(alternatives, "equal to "+text(r.lower))
+		default:
+			var conditions []string
+			if r.lower != nil {
+				conditions = append(conditions, explainBound(r.lower, "at least", "above", text))
+			}
+			if r.upper != nil {
+				conditions = append(conditions, explainBound(r.upper, "at most", "below", text))
+			}
+			alternatives = append(alternatives, strings.Join(conditions, " and "))
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return "no version"
+	}
+	return "versions " + strings.Join(alternatives, " or ")
+}
+
+// explainBound describes one end of a range.
+func explainBound(b *rangeBound, inclusive, exclusive string, text func(*rangeBound) string) string {
+	if b.inclusive {
+		return inclusive + " " + text(b)
+	}
+	return exclusive + " " + text(b)
+}
+
+// rangeBoundText prints a range bound as its components followed by its suffix.
+func rangeBoundText(b *rangeBound) string {
+	return strings.Join(b.parts, ".") + b.suffix
+}
+
+// explainDistroRanges lists the non-empty package version ranges, joined by "or".
+func explainDistroRanges(ranges []distroRange, scheme distroScheme) string {
+	var alternatives []string
+	for _, r := range ranges {
+		if len(scheme.rangePattern(r)) == 0 {
+			continue
+		}
+
+		switch {
+		case r.lower == nil && r.upper == nil:
+			return "any version"
+		case r.lower != nil && r.upper != nil && r.lower.inclusive && r.upper.inclusive && scheme.compare(r.lower.distroVersion, r.upper.distroVersion) == 0:
+			alternatives = append(alternatives, "equal to "+distroVersionText(r.lower.distroVersion))
+		default:
+			var conditions []string
+			if r.lower != nil {
+				conditions = append(conditions, explainDistroBound(r.lower, "at least", "above"))
+			}
+			if r.upper != nil {
+				conditions = append(conditions, explainDistroBound(r.upper, "at most", "below"))
+			}
+			alternatives = append(alternatives, strings.Join(conditions, " and "))
+		}
+	}
+
+	if len(alternatives) == 0 {
+		return "no version"
+	}
+	return "versions " + strings.Join(alternatives, " or ")
+}
+
+// explainDistroBound describes one end of a package version range.
+func explainDistroBound(b *distroBound, inclusive, exclusive string) string {
+	if b.inclusive {
+		return inclusive + " " + distroVersionText(b.distroVersion)
+	}
+	return exclusive + " " + distroVersionText(b.distroVersion)
+}
+
+// distroVersionText prints a package version as [epoch:]version[-release].
+func distroVersionText(v distroVersion) string {
+	text := v.version
+	if v.epoch != 0 {
+		text = fmt.Sprintf("%d:%s", v.epoch, text)
+	}
+	if v.hasRelease {
+		text += "-" + v.release
+	}
+	return text
+}
@@ -0,0 +1,84 @@
+// Package convert provides tests for constraint descriptions.
+// This file checks the plain-language descriptions of constraints of every ecosystem.
+package convert
+
+import (
+	"testing"
+)
+
+// TestExplain tests the descriptions of constraints.
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+		expected   string
+	}{
+		{"1.2.3", Options{}, "version 1.2.3, with any pre-release label or build metadata"},
+		{"1.2.3-dev.5", Options{}, "version 1.2.3-dev.5, with any build metadata"},
+		{"1.2.3-beta2+build.123", Options{Ecosystem: ECOSYSTEM_NUGET}, "NuGet version 1.2.3 (trailing zero components optional) with pre-release beta2 in any case and build build.123"},
+		{"1.0.0-beta001", Options{Ecosystem: ECOSYSTEM_NUGET}, "NuGet version 1.0.0 (trailing zero components optional) with pre-release beta001 in any case, whatever its build"},
+		{"1.0.0-beta001", Options{}, "version 1.0.0-beta001, with any build metadata"},
+		{"1.*", Options{}, "any 1.x.x version, whatever its pre-release or build"},
+		{"1.*.0", Options{}, "any 1.x.0 version, whatever its pre-release or build"},
+		{"*", Options{}, "any version"},
+		{"v0.0.0-20210101000000-abcdef123456", Options{}, "Go pseudo-version v0.0.0-20210101000000-abcdef123456"},
+		{"1.2.3.4", Options{}, "NuGet version 1.2.3.4 (trailing zero components optional), with any pre-release label, whatever its build"},
+		{">=1.2", Options{}, "any version at least 1.2 (missing components count as 0), whatever its pre-release or build"},
+		{">1.2.3", Options{VersionLength: LENGTH_STRICT}, "any version of 3 components above 1.2.3, whatever its pre-release or build"},
+		{"<0.0", Options{}, "no version"},
+		{"!=1.2.3", Options{}, "any version except 1.2.3 and its pre-releases and builds"},
+		{"^1.2.3", Options{}, "any 1.x.x version, whatever its pre-release or build"},
+		{"^0.2.3", Options{}, "any 0.2.x version, whatever its pre-release or build"},
+		{"~=2.1", Options{}, "any 2.1.x version, whatever its pre-release or build"},
+		{"~1.2", Options{Prefix: "mylib-", Suffix: ".tgz"}, "any 1.2.x version, whatever its pre-release or build, written as mylib-<version>.tgz"},
+		{"[1.0,2.0)", Options{}, "versions at least 1.0 and below 2.0"},
+		{"(,1.0],[1.2,)", Options{}, "versions at most 1.0 or at least 1.2"},
+		{"[1.0]", Options{}, "versions equal to 1.0"},
+		{"~> 2.1, >= 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}, "versions at least 2.1.3 and below 3"},
+		{"^1.2@beta || dev-main", Options{Ecosystem: ECOSYSTEM_COMPOSER}, "versions at least 1.2 and below 2, down to beta stability or branch dev-main"},
+		{">=1.2.0-alpha, <1.5", Options{Ecosystem: ECOSYSTEM_CARGO}, "versions at least 1.2.0-alpha and below 1.5, with pre-releases only of 1.2.0"},
+		{"1.2", Options{Ecosystem: ECOSYSTEM_CARGO}, "versions at least 1.2 and below 2, without pre-releases"},
+		{">= 1:2.30-1ubuntu2, << 1:3.0", Options{Ecosystem: ECOSYSTEM_DEBIAN}, "versions at least 1:2.30-1ubuntu2 and below 1:3.0"},
+		{">= 1:2.30-1ubuntu2, << 3.0", Options{Ecosystem: ECOSYSTEM_DEBIAN}, "no version"},
+		{"= 1.0-1", Options{Ecosystem: ECOSYSTEM_RPM}, "versions equal to 1.0-1"},
+		{">=2024.10, <2025", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.0M"}, "versions at least 2024.10 and below 2025.01"},
+		{"^1.2.3-beta.1 || 3.x", Options{Ecosystem: ECOSYSTEM_NPM}, "versions at least 1.2.3-beta.1 and below 2, with pre-releases only of 1.2.3 or versions at least 3 and below 4, without pre-releases"},
+		{"~=1.4.2, !=1.4.5", Options{Ecosystem: ECOSYSTEM_PYPI}, "versions at least 1.4.2 and below 1.4.5 or above 1.4.5 and below 1.5.dev0"},
+		{">1!2.0", Options{Ecosystem: ECOSYSTEM_PYPI}, "versions above 1!2.0 and its post-releases"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			description, err := Explain(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("Explain(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			if description != tt.expected {
+				t.Errorf("Explain(%q) = %q, expected %q", tt.constraint, description, tt.expected)
+			}
+		})
+	}
+}
+
+// TestExplainErrors tests the constraints that cannot be described.
+func TestExplainErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		constraint string
+		opts       Options
+	}{
+		{"invalid version", ">=abc", Options{}},
+		{"invalid Maven range", "[1.0,2.0", Options{}},
+		{"unknown ecosystem", "1.2.3", Options{Ecosystem: Ecosystem("conda")}},
+		{"missing CalVer format", ">=2024.10", Options{Ecosystem: ECOSYSTEM_CALVER}},
+		{"invalid tag template", "1.2.3", Options{TagTemplate: "release"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if description, err := Explain(tt.constraint, tt.opts); err == nil {
+				t.Errorf("Explain(%q) = %q, expected an error", tt.constraint, description)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Package convert provides filesystem pattern output for version constraints.
+// This file contains the patterns for GNU find -regex, which matches whole paths, and
+// the approximation of generated regexes by shell globs with brace expansion, for
+// artifact names such as dist/mylib-1.2.3.tgz.
+package convert
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strings"
+	"unicode"
+)
+
+// FIND_PATH_PREFIX matches the directories before a file name in find -regex, which
+// matches whole paths such as ./dist/mylib-1.2.3.tgz.
+const FIND_PATH_PREFIX = ".*/"
+
+// GLOB_MAX_LENGTH bounds the length of the brace expansions of a glob; longer
+// alternations are approximated by *.
+const GLOB_MAX_LENGTH = 256
+
+// GlobPattern is a shell glob approximating the versions of a constraint.
+type GlobPattern struct {
+	// Pattern is the glob, with {a,b} brace expansions as in bash
+	Pattern string
+	// Exact reports whether the glob matches exactly the names the regex matches;
+	// otherwise it over-approximates them, and matched names should be checked
+	// against the regex
+	Exact bool
+}
+
+// FindPattern converts a version constraint string to a pattern for GNU find, used
+// with -regextype posix-extended -regex.
+//
+// find matches the pattern against whole paths, so it starts with .*/ and matches the
+// file names made of the prefix, version and suffix of opts. The boundary options
+// are ignored. The pattern is also valid for bash [[ =~ ]] tests, which search
+// strings unless the pattern is anchored.
+//
+// Parameters:
+//   - versionStr: The version constraint string to convert
+//   - opts: Conversion options, such as the prefix and suffix of file names
+//
+// Returns:
+//   - string: POSIX ERE pattern matching whole paths
+//   - error: Error if the constraint cannot be converted
+//
+// Example:
+//
+//	pattern, err := FindPattern("~1.2", Options{Prefix: "mylib-", Suffix: ".tgz"})
+//	// find dist -regextype posix-extended -regex "$pattern"
+func FindPattern(versionStr string, opts Options) (string, error) {
+	opts.Boundary = BOUNDARY_NONE
+	pattern, err := Pattern(versionStr, DIALECT_POSIX_ERE, opts)
+	if err != nil {
+		return "", err
+	}
+	return FIND_PATH_PREFIX + pattern, nil
+}
+
+// Glob approximates the versions of a constraint by a shell glob with brace
+// expansions, such as mylib-1.2.[0-9]*.tgz.
+//
+// Globs have no repetition, so repeated parts such as \d+ become * and the glob
+// over-approximates the regex; GlobPattern.Exact tells whether it does. Optional
+// parts and alternatives become brace expansions, which bash and zsh expand into
+// several globs before matching files. With LEADING_ZEROS_NUMERIC, the leading
+// zeros accepted in numeric components become * too.
+//
+// Parameters:
+//   - versionStr: The version constraint string to convert
+//   - opts: Conversion options, such as the prefix and suffix of file names
+//
+// Returns:
+//   - GlobPattern: The glob and whether it is exact
+//   - error: Error if the constraint cannot be converted, or matches no version
+//
+// Example:
+//
+//	glob, err := Glob("1.2.3", Options{Prefix: "mylib-", Suffix: ".tgz"})
+//	// glob.Pattern: mylib-1.2.3{-[-.0-9A-Za-z]*,}{+[-.0-9A-Za-z]*,}.tgz, glob.Exact: false
+func Glob(versionStr string, opts Options) (GlobPattern, error) {
+	pattern, err := VersionToPattern(versionStr, opts)
+	if err != nil {
+		return GlobPattern{}, err
+	}
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return GlobPattern{}, fmt.Errorf("failed to compile regex: %w", err)
+	}
+	if re.Op == syntax.OpNoMatch {
+		return GlobPattern{}, fmt.Errorf("constraint %s matches no version", versionStr)
+	}
+
+	g := &globPrinter{exact: true}
+	glob := g.print(re)
+	return GlobPattern{Pattern: glob, Exact: g.exact}, nil
+}
+
+// globPrinter prints parsed patterns as globs, recording whether they are exact.
+type globPrinter struct {
+	exact bool
+}
+
+// approximate returns the glob matching every string, which over-approximates re.
+func (g *globPrinter) approximate() string {
+	g.exact = false
+	return "*"
+}
+
+// print prints re as a glob.
+func (g *globPrinter) print(re *syntax.Regexp) string {
+	switch re.Op {
+	case syntax.OpEmptyMatch, syntax.OpBeginText, syntax.OpEndText, syntax.OpNoMatch:
+		// Globs match whole names; alternatives that match nothing are left out
+		// by print for alternations
+		return ""
+	case syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		g.exact = false
+		return ""
+	case syntax.OpLiteral:
+		var s strings.Builder
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 && hasLetter([]rune{r}) {
+				s.WriteString(globClass(asciiFold(r)))
+			} else {
+				s.WriteString(globLiteral(r))
+			}
+		}
+		return s.String()
+	case syntax.OpCharClass:
+		return globClass(re.Rune)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return "?"
+	case syntax.OpCapture:
+		return g.print(re.Sub[0])
+	case syntax.OpConcat:
+		pieces := make([]string, len(re.Sub))
+		for i, sub := range re.Sub {
+			pieces[i] = g.print(sub)
+		}
+		return joinGlobs(pieces)
+	case syntax.OpAlternate:
+		var alternatives []string
+		for _, sub := range re.Sub {
+			if sub.Op != syntax.OpNoMatch {
+				alternatives = append(alternatives, g.print(sub))
+			}
+		}
+		return g.braces(alternatives)
+	case syntax.OpQuest:
+		return g.braces([]string{g.print(re.Sub[0]), ""})
+	case syntax.OpStar:
+		return g.approximate()
+	case syntax.OpPlus:
+		return joinGlobs([]string{g.print(re.Sub[0]), g.approximate()})
+	case syntax.OpRepeat:
+		return g.repeat(re)
+	default:
+		return g.approximate()
+	}
+}
+
+// repeat prints a counted repetition, spelling out each count it allows.
+func (g *globPrinter) repeat(re *syntax.Regexp) string {
+	sub := g.print(re.Sub[0])
+	required := joinGlobs(repeatedGlob(sub, re.Min))
+	if re.Max < 0 {
+		return joinGlobs([]string{required, g.approximate()})
+	}
+
+	counts := make([]string, 0, re.Max-re.Min+1)
+	for n := re.Min; n <= re.Max; n++ {
+		counts = append(counts, joinGlobs(repeatedGlob(sub, n-re.Min)))
+	}
+	return joinGlobs([]string{required, g.braces(counts)})
+}
+
+// repeatedGlob returns n copies of glob.
+func repeatedGlob(glob string, n int) []string {
+	copies := make([]string, n)
+	for i := range copies {
+		copies[i] = glob
+	}
+	return copies
+}
+
+// braces prints a brace expansion of alternatives, or * when it would be too long.
+func (g *globPrinter) braces(alternatives []string) string {
+	alternatives = uniqueStrings(alternatives)
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	glob := "{" + strings.Join(alternatives, ",") + "}"
+	if len(glob) > GLOB_MAX_LENGTH {
+		return g.approximate()
+	}
+	return glob
+}
+
+// uniqueStrings returns values without repeats, in their first order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+	return unique
+}
+
+// joinGlobs concatenates globs, merging adjacent stars: ** means any directories in
+// some shells.
+func joinGlobs(globs []string) string {
+	var s strings.Builder
+	for _, glob := range globs {
+		if strings.HasPrefix(glob, "*") && strings.HasSuffix(s.String(), "*") && !strings.HasSuffix(s.String(), `\*`) {
+			glob = glob[1:]
+		}
+		s.WriteString(glob)
+	}
+	return s.String()
+}
+
+// globLiteral escapes the characters that are special in globs and brace expansions.
+func globLiteral(r rune) string {
+	if strings.ContainsRune(`*?[]{},\`, r) {
+		return `\` + string(r)
+	}
+	return string(r)
+}
+
+// globClass prints a character class as a glob bracket expression, in which ] comes
+// first, - last, and ! and ^ are never first.
+func globClass(ranges []rune) string {
+	negated := len(ranges) > 0 && ranges[len(ranges)-1] == unicode.MaxRune
+	if negated {
+		ranges = complementRanges(ranges)
+	} else {
+		ranges = withoutFoldExtras(ranges)
+	}
+	if !negated && len(ranges) == 2 && ranges[0] == ranges[1] {
+		return globLiteral(ranges[0])
+	}
+
+	var last string
+	if rest, ok := withoutRune(ranges, '!'); ok {
+		last, ranges = "!", rest
+	}
+	body := (&dialectPrinter{dialect: DIALECT_POSIX_ERE}).posixClassBody(ranges)
+	if last != "" {
+		if strings.HasSuffix(body, "-") {
+			body = strings.TrimSuffix(body, "-") + last + "-"
+		} else {
+			body += last
+		}
+	}
+
+	if negated {
+		return "[!" + body + "]"
+	}
+	return "[" + body + "]"
+}
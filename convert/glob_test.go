@@ -0,0 +1,190 @@
+// Package convert provides tests for filesystem pattern output.
+// This file checks find -regex patterns and the globs approximating generated regexes.
+package convert
+
+import (
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"testing"
+)
+
+// globRegexp compiles a glob with brace expansions into the equivalent Go regex.
+func globRegexp(glob string) (*regexp.Regexp, error) {
+	var s strings.Builder
+	s.WriteString("^")
+	depth := 0
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; {
+		case c == '\\':
+			i++
+			s.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		case c == '*':
+			s.WriteString(".*")
+		case c == '?':
+			s.WriteString(".")
+		case c == '[':
+			end := i + 1
+			if end < len(glob) && glob[end] == '!' {
+				end++
+			}
+			if end < len(glob) && glob[end] == ']' {
+				end++
+			}
+			end += strings.IndexByte(glob[end:], ']')
+			body := glob[i+1 : end]
+			if strings.HasPrefix(body, "!") {
+				body = "^" + body[1:]
+			}
+			s.WriteString("[" + strings.ReplaceAll(body, `\`, `\\`) + "]")
+			i = end
+		case c == '{':
+			depth++
+			s.WriteString("(?:")
+		case c == '}' && depth > 0:
+			depth--
+			s.WriteString(")")
+		case c == ',' && depth > 0:
+			s.WriteString("|")
+		default:
+			s.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	s.WriteString("$")
+	return regexp.Compile(s.String())
+}
+
+// TestGlob tests the globs approximating generated regexes.
+func TestGlob(t *testing.T) {
+	file := Options{Prefix: "mylib-", Suffix: ".tgz"}
+
+	tests := []struct {
+		constraint string
+		opts       Options
+		expected   string
+		exact      bool
+	}{
+		{"1.2.3", file, `mylib-1.2.3{-[.0-9A-Za-z-]*,}{+[.0-9A-Za-z-]*,}.tgz`, false},
+		{"~1.2", file, `mylib-1.2.{0,[1-9]*}{-[.0-9A-Za-z-]*,}{+[.0-9A-Za-z-]*,}.tgz`, false},
+		{"~1.2", Options{LeadingZeros: LEADING_ZEROS_NUMERIC}, `*1.*2.[0-9]*{-[.0-9A-Za-z-]*,}{+[.0-9A-Za-z-]*,}`, false},
+		{"=1.2.3-alpha", Options{Ecosystem: ECOSYSTEM_CARGO}, `1.2.3-alpha{+[0-9A-Za-z-]*,}`, false},
+		{"[1.0]", Options{Prefix: "lib-", Suffix: ".jar"}, ``, false},
+		{"1.2.3", Options{Ecosystem: ECOSYSTEM_CARGO, Prefix: "crate{x}-"}, ``, false},
+		{">=2024.10, <2025", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.0M"}, `2024.1[0-2]{{[+._-],}[A-Za-z]*,}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			glob, err := Glob(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("Glob(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			if tt.expected != "" && (glob.Pattern != tt.expected || glob.Exact != tt.exact) {
+				t.Errorf("Glob(%q) = %q, exact %v, expected %q, exact %v", tt.constraint, glob.Pattern, glob.Exact, tt.expected, tt.exact)
+			}
+
+			regex, err := VersionToRegexWithOptions(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			globRegex, err := globRegexp(glob.Pattern)
+			if err != nil {
+				t.Fatalf("glob %q cannot be read back: %v", glob.Pattern, err)
+			}
+
+			prefix, suffix, _ := tagAffixes(tt.opts)
+			for _, version := range minimizeCorpus {
+				for _, candidate := range []string{version, prefix + version + suffix, prefix + strings.ToUpper(version) + suffix} {
+					matches, globMatches := regex.MatchString(candidate), globRegex.MatchString(candidate)
+					if matches && !globMatches {
+						t.Errorf("glob %q should match %q like %q", glob.Pattern, candidate, regex)
+					}
+					if glob.Exact && globMatches && !matches {
+						t.Errorf("exact glob %q should not match %q like %q", glob.Pattern, candidate, regex)
+					}
+				}
+			}
+		})
+	}
+}
+
+// TestGlobPrinter tests the globs of patterns, and whether they are exact.
+func TestGlobPrinter(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+		exact    bool
+	}{
+		{`^1\.2\.(?:3|45)(?:-rc)?$`, `1.2.{3,45}{-rc,}`, true},
+		{`v\d{2}`, `v[0-9][0-9]`, true},
+		{`v\d{1,3}`, `v[0-9]{,[0-9],[0-9][0-9]}`, true},
+		{`v\d{2,}`, `v[0-9][0-9]*`, false},
+		{`(?i:rc)\d+`, `[Rr][Cc][0-9]*`, false},
+		{`a.*b`, `a*b`, false},
+		{`a+b*`, `a*`, false},
+		{`\bv1\b`, `v1`, false},
+		{`x\d{1,60}`, `x[0-9]*`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := syntax.Parse(tt.pattern, syntax.Perl)
+			if err != nil {
+				t.Fatalf("pattern %q does not parse: %v", tt.pattern, err)
+			}
+			g := &globPrinter{exact: true}
+			if got := g.print(re); got != tt.expected || g.exact != tt.exact {
+				t.Errorf("glob of %q = %q, exact %v, expected %q, exact %v", tt.pattern, got, g.exact, tt.expected, tt.exact)
+			}
+		})
+	}
+}
+
+// TestGlobLiterals tests the escaping of characters special in globs.
+func TestGlobLiterals(t *testing.T) {
+	tests := []struct {
+		ranges   []rune
+		expected string
+	}{
+		{[]rune{'*', '*'}, `\*`},
+		{[]rune{',', ','}, `\,`},
+		{[]rune{'a', 'a'}, `a`},
+		{[]rune{'!', '!', '-', '.'}, `[.!-]`},
+		{[]rune{']', ']', 'a', 'b'}, `[]ab]`},
+		{[]rune{0, '/', ':', 0x10FFFF}, `[!0-9]`},
+	}
+
+	for _, tt := range tests {
+		if got := globClass(tt.ranges); got != tt.expected {
+			t.Errorf("globClass(%q) = %q, expected %q", tt.ranges, got, tt.expected)
+		}
+	}
+}
+
+// TestFindPattern tests patterns for GNU find, which match whole paths.
+func TestFindPattern(t *testing.T) {
+	pattern, err := FindPattern("~1.2", Options{Prefix: "mylib-", Suffix: ".tgz", Boundary: BOUNDARY_WORD})
+	if err != nil {
+		t.Fatalf("FindPattern returned unexpected error: %v", err)
+	}
+	expected := `.*/mylib-1\.2\.(0|[1-9][0-9]*)(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?\.tgz`
+	if pattern != expected {
+		t.Errorf("FindPattern = %q, expected %q", pattern, expected)
+	}
+
+	regex, err := regexp.CompilePOSIX("^" + pattern + "$")
+	if err != nil {
+		t.Fatalf("pattern %q does not compile: %v", pattern, err)
+	}
+	for path, matches := range map[string]bool{
+		"./dist/mylib-1.2.3.tgz":         true,
+		"dist/nested/mylib-1.2.0-rc.tgz": true,
+		"./dist/mylib-1.3.0.tgz":         false,
+		"./dist/mylib-1.2.3.tgz.asc":     false,
+		"mylib-1.2.3.tgz":                false,
+	} {
+		if regex.MatchString(path) != matches {
+			t.Errorf("pattern %q should match %q: %v", pattern, path, matches)
+		}
+	}
+}
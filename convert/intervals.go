@@ -0,0 +1,415 @@
+// Package convert provides version interval conversion functionality.
+// This file contains the conversion of explicit version intervals, such as the
+// affected ranges of security advisories, to patterns. Each interval is built with
+// the range generator and version ordering of the ecosystem chosen in Options, and
+// the intervals are joined as alternatives, so that unions can be expressed even in
+// ecosystems whose constraint syntax has no alternatives.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// semverBoundRegex parses a SemVer-like version into its components, pre-release
+// label and build metadata: v1.2.3-rc.1+build.5.
+var semverBoundRegex = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:-(` + SEMVER_IDENTIFIER + `(?:\.` + SEMVER_IDENTIFIER + `)*))?(?:\+` + SEMVER_IDENTIFIER + `(?:\.` + SEMVER_IDENTIFIER + `)*)?$`)
+
+// VersionInterval is a range of versions between two optional bounds, written in
+// the version syntax of an ecosystem.
+type VersionInterval struct {
+	// Lower is the smallest version of the interval, or empty when it is unbounded below
+	Lower string `json:"lower,omitempty"`
+	// LowerInclusive tells whether Lower itself is in the interval
+	LowerInclusive bool `json:"lower_inclusive,omitempty"`
+	// Upper is the largest version of the interval, or empty when it is unbounded above
+	Upper string `json:"upper,omitempty"`
+	// UpperInclusive tells whether Upper itself is in the interval
+	UpperInclusive bool `json:"upper_inclusive,omitempty"`
+}
+
+// String writes the interval as comparisons, such as ">=1.0.0, <1.2.5", or "*" when
+// it is unbounded.
+func (v VersionInterval) String() string {
+	lower, upper := "", ""
+	if v.Lower != "" {
+		lower = OP_GREATER + v.Lower
+		if v.LowerInclusive {
+			lower = OP_GREATER_EQUAL + v.Lower
+		}
+	}
+	if v.Upper != "" {
+		upper = OP_LESS + v.Upper
+		if v.UpperInclusive {
+			upper = OP_LESS_EQUAL + v.Upper
+		}
+	}
+	switch {
+	case lower != "" && upper != "":
+		return lower + ", " + upper
+	case lower != "" || upper != "":
+		return lower + upper
+	default:
+		return "*"
+	}
+}
+
+// IntervalsToPattern converts version intervals to the un-anchored pattern of the
+// versions inside any of them, for embedding in larger regular expressions.
+//
+// Bounds are parsed and ordered as the ecosystem of opts does, every pre-release and
+// stability being matched:
+//   - ECOSYSTEM_AUTO, ECOSYSTEM_ADVISORY: SemVer versions with any number of
+//     components, an optional v prefix and build metadata, pre-releases sorting
+//     before their release
+//   - ECOSYSTEM_CARGO: SemVer versions with exactly three components
+//   - ECOSYSTEM_RUBY: gem versions
+//   - ECOSYSTEM_PYPI: PEP 440 versions, a local version such as 1.0+ubuntu1 coming
+//     right after its public version
+//   - ECOSYSTEM_COMPOSER: Composer versions with an optional v prefix
+//   - ECOSYSTEM_DEBIAN, ECOSYSTEM_RPM: package versions with epoch and release
+//   - ECOSYSTEM_CALVER: versions of the format in opts.CalVerFormat
+//
+// As with VersionToPattern, the prefix, suffix and tag template of opts are part of
+// the pattern while the Boundary is ignored. Capture groups are not supported.
+//
+// Parameters:
+//   - intervals: The intervals to match; none matches no version
+//   - opts: Conversion options, such as the ecosystem
+//
+// Returns:
+//   - string: Un-anchored pattern matching the versions inside any interval
+//   - error: Error if a bound is not a version of the ecosystem
+//
+// Example:
+//
+//	// Versions affected by an advisory fixed in 1.2.5 and 2.0.1
+//	pattern, err := IntervalsToPattern([]VersionInterval{
+//		{Lower: "1.0.0", LowerInclusive: true, Upper: "1.2.5"},
+//		{Lower: "2.0.0", LowerInclusive: true, Upper: "2.0.1"},
+//	}, Options{})
+func IntervalsToPattern(intervals []VersionInterval, opts Options) (string, error) {
+	if opts.CaptureGroups {
+		return "", fmt.Errorf("capture groups are not supported for version intervals")
+	}
+	prefix, suffix, err := tagAffixes(opts)
+	if err != nil {
+		return "", err
+	}
+
+	pattern, err := intervalsRegex(intervals, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert version intervals: %w", err)
+	}
+
+	// Remove the anchors of the pattern
+	core, err := unanchoredRegexp(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to compile regex: %w", err)
+	}
+
+	pattern = embeddablePattern(MinimizePattern(printRegexp(core, false)))
+	return regexp.QuoteMeta(prefix) + pattern + regexp.QuoteMeta(suffix), nil
+}
+
+// IntervalsPattern converts version intervals to a pattern in the syntax of dialect,
+// surrounded by the boundary of opts, as Pattern does for constraints.
+//
+// Example:
+//
+//	pattern, _ := IntervalsPattern([]VersionInterval{{Upper: "0.3.1"}}, DIALECT_PCRE, Options{})
+func IntervalsPattern(intervals []VersionInterval, dialect Dialect, opts Options) (string, error) {
+	pattern, err := IntervalsToPattern(intervals, opts)
+	if err != nil {
+		return "", err
+	}
+	bounded, err := boundedPattern(pattern, opts)
+	if err != nil {
+		return "", err
+	}
+	return TranslatePattern(bounded, dialect)
+}
+
+// IntervalsToRegex converts version intervals to a compiled regex anchored with the
+// boundary of opts.
+func IntervalsToRegex(intervals []VersionInterval, opts Options) (*regexp.Regexp, error) {
+	pattern, err := IntervalsPattern(intervals, DIALECT_GO, opts)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+// CompareVersions orders two versions as the ecosystem of opts does for the bounds of
+// intervals, returning -1, 0 or 1, for example to sort the events of advisories.
+//
+// Parameters:
+//   - a, b: The versions, in the syntax of the ecosystem
+//   - opts: Conversion options selecting the ecosystem and, for CalVer, the format
+//
+// Returns:
+//   - int: -1 when a sorts before b, 0 when they are equal and 1 otherwise
+//   - error: Error if the ecosystem has no intervals or a version cannot be parsed
+//
+// Example:
+//
+//	CompareVersions("1.0rc1", "1.0.dev2", Options{Ecosystem: ECOSYSTEM_PYPI}) // 1
+func CompareVersions(a, b string, opts Options) (int, error) {
+	switch opts.Ecosystem {
+	case ECOSYSTEM_DEBIAN, ECOSYSTEM_RPM:
+		parse, scheme := parseDebianVersion, debianScheme
+		if opts.Ecosystem == ECOSYSTEM_RPM {
+			parse, scheme = parseRPMVersion, rpmScheme
+		}
+		x, err := parse(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := parse(b)
+		if err != nil {
+			return 0, err
+		}
+		return scheme.compare(x, y), nil
+	case ECOSYSTEM_CALVER:
+		format, err := parseCalVerFormat(opts.CalVerFormat)
+		if err != nil {
+			return 0, err
+		}
+		x, err := format.parseVersion(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := format.parseVersion(b)
+		if err != nil {
+			return 0, err
+		}
+		return compareParts(decimalParts(x), decimalParts(y)), nil
+	}
+
+	var parse func(string) (*rangeBound, error)
+	var order suffixOrder
+	switch opts.Ecosystem {
+	case ECOSYSTEM_AUTO, ECOSYSTEM_ADVISORY, ECOSYSTEM_CARGO:
+		parse, order = parseSemverBound, semverPrereleaseOrder{}
+	case ECOSYSTEM_RUBY:
+		parse, order = parseRubyBound, rubyPrereleaseOrder{}
+	case ECOSYSTEM_PYPI:
+		parse, order = parsePEP440Bound, pep440SuffixOrder{}
+	case ECOSYSTEM_COMPOSER:
+		parse, order = (&composerConstraint{}).parseBound, composerSuffixOrder{minimum: COMPOSER_RANK_DEV}
+	default:
+		return 0, fmt.Errorf("unsupported ecosystem: %s", opts.Ecosystem)
+	}
+	return compareParsedBounds(a, b, parse, order)
+}
+
+// CompareMavenVersions orders two Maven versions as ComparableVersion does,
+// returning -1, 0 or 1.
+//
+// Example:
+//
+//	CompareMavenVersions("1.0-rc1", "1.0.Final") // -1
+func CompareMavenVersions(a, b string) (int, error) {
+	parse := func(version string) (*rangeBound, error) {
+		return parseMavenBound(version, true)
+	}
+	return compareParsedBounds(a, b, parse, mavenQualifierOrder{})
+}
+
+// compareParsedBounds orders two versions parsed into inclusive bounds by parse.
+func compareParsedBounds(a, b string, parse func(string) (*rangeBound, error), order suffixOrder) (int, error) {
+	x, err := parse(a)
+	if err != nil {
+		return 0, err
+	}
+	y, err := parse(b)
+	if err != nil {
+		return 0, err
+	}
+	return compareBounds(x, y, order), nil
+}
+
+// intervalsRegex creates an anchored regex matching the versions inside any of the
+// intervals, with the range generator of the ecosystem of opts.
+// Result: ^v?(?:1\.(?:...)|2\.0\.0(?:-...)?)(?:\+...)?$ matching 1.1.0, 2.0.0-rc.1 but not 1.2.5 (for [1.0.0, 1.2.5) and [2.0.0, 2.0.1))
+func intervalsRegex(intervals []VersionInterval, opts Options) (string, error) {
+	var prefix, pattern, suffix string
+	var ok bool
+	switch opts.Ecosystem {
+	case ECOSYSTEM_AUTO, ECOSYSTEM_ADVISORY:
+		ranges, err := intervalRanges(intervals, parseSemverBound, semverPrereleaseOrder{})
+		if err != nil {
+			return "", err
+		}
+		return semverRangesRegex(ranges, opts), nil
+	case ECOSYSTEM_CARGO:
+		order := semverPrereleaseOrder{}
+		ranges, err := intervalRanges(intervals, parseSemverBound, order)
+		if err != nil {
+			return "", err
+		}
+		pattern, ok = seqBuilder{fixed: 3, order: order, zeros: opts.LeadingZeros}.rangesPattern(ranges)
+		suffix = SEMVER_BUILD_META_PATTERN
+	case ECOSYSTEM_RUBY:
+		order := rubyPrereleaseOrder{}
+		ranges, err := intervalRanges(intervals, parseRubyBound, order)
+		if err != nil {
+			return "", err
+		}
+		pattern, ok = seqBuilder{order: order, zeros: opts.LeadingZeros}.rangesPattern(ranges)
+	case ECOSYSTEM_PYPI:
+		return pep440IntervalsRegex(intervals, opts)
+	case ECOSYSTEM_COMPOSER:
+		order := composerSuffixOrder{minimum: COMPOSER_RANK_DEV}
+		constraint := &composerConstraint{}
+		ranges, err := intervalRanges(intervals, constraint.parseBound, order)
+		if err != nil {
+			return "", err
+		}
+		builder := seqBuilder{order: order, zeros: opts.LeadingZeros}
+		pattern, ok = builder.rangesPattern(ranges)
+		prefix = "v?"
+	case ECOSYSTEM_DEBIAN:
+		ranges, err := intervalDistroRanges(intervals, parseDebianVersion)
+		if err != nil {
+			return "", err
+		}
+		pattern, ok = debianScheme.rangesPattern(ranges)
+	case ECOSYSTEM_RPM:
+		ranges, err := intervalDistroRanges(intervals, parseRPMVersion)
+		if err != nil {
+			return "", err
+		}
+		pattern, ok = rpmScheme.rangesPattern(ranges)
+	case ECOSYSTEM_CALVER:
+		format, err := parseCalVerFormat(opts.CalVerFormat)
+		if err != nil {
+			return "", err
+		}
+		var alternatives []string
+		for _, interval := range intervals {
+			lower, upper, ok, err := format.intervalValues(interval)
+			if err != nil {
+				return "", err
+			}
+			if !ok {
+				continue
+			}
+			alternatives = append(alternatives, format.rangePattern(0, lower, upper)...)
+		}
+		pattern, ok = groupAlternatives(alternatives)
+		suffix = CALVER_MODIFIER_PATTERN
+	default:
+		return "", fmt.Errorf("unsupported ecosystem: %s", opts.Ecosystem)
+	}
+
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + prefix + pattern + suffix + REGEX_END, nil
+}
+
+// intervalRanges converts intervals to version ranges, parsing their bounds with parse.
+// Intervals that no version can lie inside, such as [1.2, 1.2), are left out.
+func intervalRanges(intervals []VersionInterval, parse func(string) (*rangeBound, error), order suffixOrder) ([]versionRange, error) {
+	ranges := make([]versionRange, 0, len(intervals))
+	for _, interval := range intervals {
+		var r versionRange
+		if interval.Lower != "" {
+			bound, err := parse(interval.Lower)
+			if err != nil {
+				return nil, err
+			}
+			bound.inclusive = interval.LowerInclusive
+			r.lower = bound
+		}
+		if interval.Upper != "" {
+			bound, err := parse(interval.Upper)
+			if err != nil {
+				return nil, err
+			}
+			bound.inclusive = interval.UpperInclusive
+			r.upper = bound
+		}
+		if !isEmptyRange(r, order) {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges, nil
+}
+
+// intervalDistroRanges converts intervals to package version ranges, parsing their
+// bounds with parse.
+func intervalDistroRanges(intervals []VersionInterval, parse func(string) (distroVersion, error)) ([]distroRange, error) {
+	ranges := make([]distroRange, 0, len(intervals))
+	for _, interval := range intervals {
+		var r distroRange
+		if interval.Lower != "" {
+			version, err := parse(interval.Lower)
+			if err != nil {
+				return nil, err
+			}
+			r.lower = &distroBound{distroVersion: version, inclusive: interval.LowerInclusive}
+		}
+		if interval.Upper != "" {
+			version, err := parse(interval.Upper)
+			if err != nil {
+				return nil, err
+			}
+			r.upper = &distroBound{distroVersion: version, inclusive: interval.UpperInclusive}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// parseSemverBound parses a SemVer-like version such as v1.2.3-rc.1+build into an
+// inclusive range bound, ignoring its build metadata.
+func parseSemverBound(version string) (*rangeBound, error) {
+	matches := semverBoundRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid version: %s", version)
+	}
+	parts, ok := parseDecimalParts(matches[1])
+	if !ok {
+		return nil, fmt.Errorf("invalid version: %s", version)
+	}
+	return &rangeBound{parts: parts, suffix: prereleaseSuffix(matches[2]), inclusive: true}, nil
+}
+
+// intervalValues returns the component values of the bounds of an interval, with an
+// inclusive lower bound and an exclusive upper bound as rangePattern expects. Nil
+// values are unbounded, and false is returned when the interval is empty.
+//
+// Partial versions stand for every version starting with them, so [2024.10, 2025)
+// becomes [2024.10.0, 2025.1.0) for YYYY.MM.MICRO.
+func (f calverFormat) intervalValues(interval VersionInterval) ([]int, []int, bool, error) {
+	var lower, upper []int
+	if interval.Lower != "" {
+		parts, err := f.parseVersion(interval.Lower)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if interval.LowerInclusive {
+			lower = f.lowest(parts)
+		} else if lower = f.next(parts); lower == nil {
+			// Nothing comes after the last version
+			return nil, nil, false, nil
+		}
+	}
+	if interval.Upper != "" {
+		parts, err := f.parseVersion(interval.Upper)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !interval.UpperInclusive {
+			upper = f.lowest(parts)
+		} else {
+			upper = f.next(parts)
+		}
+	}
+	if lower != nil && upper != nil && compareParts(decimalParts(lower), decimalParts(upper)) >= 0 {
+		return nil, nil, false, nil
+	}
+	return lower, upper, true, nil
+}
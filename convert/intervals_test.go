@@ -0,0 +1,215 @@
+// Package convert provides tests for version interval conversion functionality.
+// This file checks the patterns of explicit version intervals in every ecosystem.
+package convert
+
+import (
+	"testing"
+)
+
+// TestIntervalsToRegex tests that intervals match the versions between their bounds.
+func TestIntervalsToRegex(t *testing.T) {
+	tests := []struct {
+		name           string
+		intervals      []VersionInterval
+		opts           Options
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			"semver union",
+			[]VersionInterval{{Lower: "1.0.0", LowerInclusive: true, Upper: "1.2.5"}, {Lower: "2.0.0", LowerInclusive: true, Upper: "2.0.1"}},
+			Options{},
+			[]string{"1.0.0", "1.2.4", "1.2.5-rc.1", "v1.1.0", "1.2.4+build.7", "2.0.0", "2.0.1-beta"},
+			[]string{"0.9.9", "1.0.0-rc.1", "1.2.5", "2.0.1", "3.0.0"},
+		},
+		{
+			"unbounded below",
+			[]VersionInterval{{Upper: "0.3.1"}},
+			Options{},
+			[]string{"0.3.0", "0.0.0-20200101120000-abcdef123456", "0.1"},
+			[]string{"0.3.1", "1.0.0"},
+		},
+		{
+			"unbounded",
+			[]VersionInterval{{}},
+			Options{},
+			[]string{"0.0.1", "12.4.0-rc.1"},
+			[]string{"latest"},
+		},
+		{
+			"inclusive upper bound",
+			[]VersionInterval{{Lower: "1.0.0", Upper: "1.2.5", UpperInclusive: true}},
+			Options{Ecosystem: ECOSYSTEM_CARGO},
+			[]string{"1.0.1", "1.2.5", "1.2.5-alpha"},
+			[]string{"1.0.0", "1.2.6", "1.1"},
+		},
+		{
+			"gem versions",
+			[]VersionInterval{{Lower: "1.0", LowerInclusive: true, Upper: "1.2.5"}},
+			Options{Ecosystem: ECOSYSTEM_RUBY},
+			[]string{"1.0.0", "1.2.4", "1.2.5.rc1"},
+			[]string{"0.9", "1.0.a", "1.2.5"},
+		},
+		{
+			"PEP 440 versions",
+			[]VersionInterval{{Lower: "1.0", LowerInclusive: true, Upper: "1.2.5"}, {Lower: "2!1.0", Upper: "2!1.0.post2", UpperInclusive: true}},
+			Options{Ecosystem: ECOSYSTEM_PYPI},
+			[]string{"1.0", "1.0+local.1", "1.2.5rc1", "1.2.5.dev0", "1.2.4.post1", "2!1.0.post1", "2!1.0.post2"},
+			[]string{"1.0rc1", "1.0.dev0", "1.2.5", "1.2.5+local", "2!1.0", "2!1.0.post2+local", "1.2.5.post1"},
+		},
+		{
+			"composer stabilities",
+			[]VersionInterval{{Lower: "1.0", LowerInclusive: true, Upper: "1.2.5"}},
+			Options{Ecosystem: ECOSYSTEM_COMPOSER},
+			[]string{"1.0.0", "v1.1.0", "1.2.5-beta1"},
+			[]string{"1.0.0-RC1", "1.2.5"},
+		},
+		{
+			"debian versions",
+			[]VersionInterval{{Lower: "0", LowerInclusive: true, Upper: "1.2-3"}},
+			Options{Ecosystem: ECOSYSTEM_DEBIAN},
+			[]string{"1.2-2", "1.2~rc1-5", "0.9"},
+			[]string{"1.2-3", "1:1.0-1"},
+		},
+		{
+			"rpm versions",
+			[]VersionInterval{{Upper: "1.2-3.el8", UpperInclusive: true}},
+			Options{Ecosystem: ECOSYSTEM_RPM},
+			[]string{"1.2-3.el8", "1.1-9.el8"},
+			[]string{"1.2-4.el8", "1.3-1.el8"},
+		},
+		{
+			"calver partial bounds",
+			[]VersionInterval{{Lower: "2024.10", LowerInclusive: true, Upper: "2025.2.1"}},
+			Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM.MICRO"},
+			[]string{"2024.10.0", "2024.12.3", "2025.2.0"},
+			[]string{"2024.9.3", "2025.2.1"},
+		},
+		{
+			"empty intervals",
+			[]VersionInterval{{Lower: "1.2", LowerInclusive: true, Upper: "1.2"}},
+			Options{},
+			nil,
+			[]string{"1.2", "1.2.0"},
+		},
+		{
+			"no intervals",
+			nil,
+			Options{},
+			nil,
+			[]string{"1.0.0", ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := IntervalsToRegex(tt.intervals, tt.opts)
+			if err != nil {
+				t.Fatalf("IntervalsToRegex(%v) returned unexpected error: %v", tt.intervals, err)
+			}
+			for _, s := range tt.shouldMatch {
+				if !regex.MatchString(s) {
+					t.Errorf("regex %q should match %q", regex, s)
+				}
+			}
+			for _, s := range tt.shouldNotMatch {
+				if regex.MatchString(s) {
+					t.Errorf("regex %q should not match %q", regex, s)
+				}
+			}
+		})
+	}
+}
+
+// TestIntervalsPatternDialect tests that interval patterns are translated like constraint patterns.
+func TestIntervalsPatternDialect(t *testing.T) {
+	pattern, err := IntervalsPattern([]VersionInterval{{Upper: "0.3.1"}}, DIALECT_POSIX_ERE, Options{Boundary: BOUNDARY_WORD})
+	if err == nil {
+		t.Errorf("IntervalsPattern with a word boundary should fail for POSIX ERE, got %q", pattern)
+	}
+	if _, err := IntervalsPattern([]VersionInterval{{Upper: "0.3.1"}}, DIALECT_PCRE, Options{}); err != nil {
+		t.Errorf("IntervalsPattern returned unexpected error: %v", err)
+	}
+}
+
+// TestIntervalsErrors tests the intervals that cannot be converted.
+func TestIntervalsErrors(t *testing.T) {
+	tests := []struct {
+		intervals []VersionInterval
+		opts      Options
+	}{
+		{[]VersionInterval{{Lower: "abc"}}, Options{}},
+		{[]VersionInterval{{Upper: "1.2.3.4.5"}}, Options{Ecosystem: ECOSYSTEM_COMPOSER}},
+		{[]VersionInterval{{Upper: "1.0"}}, Options{CaptureGroups: true}},
+		{[]VersionInterval{{Upper: "2024.13"}}, Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM"}},
+		{[]VersionInterval{{Upper: "1.0"}}, Options{Ecosystem: "unknown"}},
+		{[]VersionInterval{{Upper: "1.0+local"}}, Options{Ecosystem: ECOSYSTEM_PYPI}},
+	}
+
+	for _, tt := range tests {
+		if _, err := IntervalsToPattern(tt.intervals, tt.opts); err == nil {
+			t.Errorf("IntervalsToPattern(%v, %+v) should return an error", tt.intervals, tt.opts)
+		}
+	}
+}
+
+// TestVersionIntervalString tests the comparisons written for intervals.
+func TestVersionIntervalString(t *testing.T) {
+	tests := []struct {
+		interval VersionInterval
+		expected string
+	}{
+		{VersionInterval{Lower: "1.0.0", LowerInclusive: true, Upper: "1.2.5"}, ">=1.0.0, <1.2.5"},
+		{VersionInterval{Upper: "0.3.1", UpperInclusive: true}, "<=0.3.1"},
+		{VersionInterval{Lower: "2.0"}, ">2.0"},
+		{VersionInterval{}, "*"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.interval.String(); got != tt.expected {
+			t.Errorf("String() = %q, expected %q", got, tt.expected)
+		}
+	}
+}
+
+// TestCompareVersions tests that versions are ordered as their ecosystem does.
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		opts     Options
+		expected int
+	}{
+		{"1.2.5-rc.1", "1.2.5", Options{}, -1},
+		{"v1.10.0", "1.9.0", Options{}, 1},
+		{"1.0", "1.0.0", Options{Ecosystem: ECOSYSTEM_CARGO}, 0},
+		{"1.0.a", "1.0", Options{Ecosystem: ECOSYSTEM_RUBY}, -1},
+		{"1.0.dev1", "1.0a1", Options{Ecosystem: ECOSYSTEM_PYPI}, -1},
+		{"1.0.post1", "1.0", Options{Ecosystem: ECOSYSTEM_PYPI}, 1},
+		{"1!0.1", "2.0", Options{Ecosystem: ECOSYSTEM_PYPI}, 1},
+		{"1.0-beta1", "1.0-RC1", Options{Ecosystem: ECOSYSTEM_COMPOSER}, -1},
+		{"1.0~rc1", "1.0", Options{Ecosystem: ECOSYSTEM_DEBIAN}, -1},
+		{"1.0-2.el9", "1.0-10.el9", Options{Ecosystem: ECOSYSTEM_RPM}, -1},
+		{"2024.10.1", "2024.9.3", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM.MICRO"}, 1},
+	}
+
+	for _, tt := range tests {
+		got, err := CompareVersions(tt.a, tt.b, tt.opts)
+		if err != nil {
+			t.Errorf("CompareVersions(%q, %q, %+v) returned unexpected error: %v", tt.a, tt.b, tt.opts, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("CompareVersions(%q, %q, %+v) = %d, expected %d", tt.a, tt.b, tt.opts, got, tt.expected)
+		}
+	}
+
+	if got, err := CompareMavenVersions("2.0-beta9", "2.0"); err != nil || got != -1 {
+		t.Errorf("CompareMavenVersions(2.0-beta9, 2.0) = %d, %v, expected -1", got, err)
+	}
+
+	for _, opts := range []Options{{}, {Ecosystem: ECOSYSTEM_PYPI}, {Ecosystem: "unknown"}} {
+		if _, err := CompareVersions("1.0", "not a version", opts); err == nil {
+			t.Errorf("CompareVersions(1.0, not a version, %+v) should return an error", opts)
+		}
+	}
+}
@@ -1,12 +1,119 @@
+// Package convert provides Maven version handling functionality.
+// This file contains functions specific to Maven version ranges, which follow the
+// ordering rules of Maven's ComparableVersion: any number of numeric components,
+// trailing zeros ignored (1.0 == 1.0.0), and qualifiers ordered as
+// alpha < beta < milestone < rc < snapshot < "" (release) < sp.
+// Timestamped snapshot builds (1.2.3-20240115.103045-7) compare equal to their
+// -SNAPSHOT base.
+//
+// ComparableVersion orders any list of qualifiers, such as 2.0.0-M1-jdk8 or
+// 1.0-beta-1-SNAPSHOT. Only the first qualifier takes part in the ordering here:
+// the ones after it are matched but ignored, so 2.0.0-M1-jdk8 sorts with 2.0.0-M1
+// and 2.0.0-rc.1-jre with 2.0.0-rc.1, instead of matching no range at all.
 package convert
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+// Maven qualifier ranks, in ComparableVersion order
+const (
+	MAVEN_RANK_ALPHA = iota
+	MAVEN_RANK_BETA
+	MAVEN_RANK_MILESTONE
+	MAVEN_RANK_RC
+	MAVEN_RANK_SNAPSHOT
+	MAVEN_RANK_RELEASE
+	MAVEN_RANK_SP
+	// MAVEN_RANK_OTHER covers qualifiers Maven does not know (e.g., -jre, -android),
+	// which sort after all known qualifiers
+	MAVEN_RANK_OTHER
+	// MAVEN_RANK_NUMBER covers bare numeric qualifiers (e.g., 1.0-1), which sort after
+	// every word qualifier but before the next release (1.0 < 1.0-1 < 1.0.1)
+	MAVEN_RANK_NUMBER
+)
+
+// mavenQualifierLabels lists the spellings of each known qualifier rank.
+// The single-letter aliases a, b and m only apply when directly followed by a number.
+var mavenQualifierLabels = map[int][]string{
+	MAVEN_RANK_ALPHA:     {"alpha"},
+	MAVEN_RANK_BETA:      {"beta"},
+	MAVEN_RANK_MILESTONE: {"milestone"},
+	MAVEN_RANK_RC:        {"rc", "cr"},
+	MAVEN_RANK_SNAPSHOT:  {"snapshot"},
+	MAVEN_RANK_RELEASE:   {"ga", "final", "release"},
+	MAVEN_RANK_SP:        {"sp"},
+}
+
+// mavenQualifierAliases maps the single-letter qualifier aliases to their rank.
+var mavenQualifierAliases = map[string]int{
+	"a": MAVEN_RANK_ALPHA,
+	"b": MAVEN_RANK_BETA,
+	"m": MAVEN_RANK_MILESTONE,
+}
+
+// MAVEN_TIMESTAMP_PATTERN matches the unique version suffix of a timestamped snapshot build
+// Format: -yyyyMMdd.HHmmss-buildNumber (e.g., -20240115.103045-7)
+const MAVEN_TIMESTAMP_PATTERN = `-\d{8}\.\d{6}-\d+`
+
+// MAVEN_SNAPSHOT_PATTERN matches a -SNAPSHOT qualifier or a snapshot build timestamp
+const MAVEN_SNAPSHOT_PATTERN = `(?:-(?i:snapshot)|` + MAVEN_TIMESTAMP_PATTERN + `)`
+
+// MAVEN_QUALIFIER_TAIL_PATTERN matches the qualifiers that follow the first one, which
+// do not take part in the ordering
+// Format: -jdk8, -jre, -SNAPSHOT, -20240115.103045-7, etc.
+const MAVEN_QUALIFIER_TAIL_PATTERN = `(?:[.-][A-Za-z]+(?:[.-]?\d+)?)*(?:` + MAVEN_TIMESTAMP_PATTERN + `)?`
+
+// mavenReleaseTailPattern matches the qualifiers that follow the first one in a
+// version that is not a snapshot: the list may not end with -SNAPSHOT or a timestamp
+// Format: -jdk8, -SNAPSHOT-jre, etc.
+var mavenReleaseTailPattern = `(?:(?:[.-][A-Za-z]+(?:[.-]?\d+)?)*[.-](?:(?i:` + wordComplementPattern(mavenQualifierLabels[MAVEN_RANK_SNAPSHOT]) + `)(?:[.-]?\d+)?|[A-Za-z]+[.-]?\d+))?`
+
+// MAVEN_SNAPSHOT_TAIL_PATTERN matches the qualifiers that follow the first one in a
+// snapshot version: the list ends with -SNAPSHOT or a timestamp
+// Format: -SNAPSHOT, -jdk8-SNAPSHOT, -20240115.103045-7, etc.
+const MAVEN_SNAPSHOT_TAIL_PATTERN = `(?:[.-][A-Za-z]+(?:[.-]?\d+)?)*(?:[.-](?i:snapshot)|` + MAVEN_TIMESTAMP_PATTERN + `)`
+
+// MAVEN_QUALIFIER_PATTERN matches any optional Maven qualifier list, with or without numbers
+// Format: -alpha-1, .Final, -M2, -SNAPSHOT, -20240115.103045-7, -jre, -1, -M1-jdk8, etc.
+const MAVEN_QUALIFIER_PATTERN = `(?:(?:[.-]?[A-Za-z]+(?:[.-]?\d+)?|-\d+)` + MAVEN_QUALIFIER_TAIL_PATTERN + `|` + MAVEN_TIMESTAMP_PATTERN + `)?`
+
+// mavenVersionRegex splits a Maven version into numeric components and qualifier.
+var mavenVersionRegex = regexp.MustCompile(`^(\d+(?:\.\d+)*)(.*)$`)
+
+// mavenQualifierRegex parses a qualifier list such as -alpha-1, .Final, -M2, -1 or
+// -rc.1-jre into its first qualifier, as a label and number or a bare number, and
+// the tail of the list.
+var mavenQualifierRegex = regexp.MustCompile(`(?i)^(?:[.-]?([a-z]+)(?:[.-]?(\d+))?|-(\d+))(` + MAVEN_QUALIFIER_TAIL_PATTERN + `)$`)
+
+// mavenTimestampRegex recognizes the qualifier of a timestamped snapshot build.
+var mavenTimestampRegex = regexp.MustCompile(`^` + MAVEN_TIMESTAMP_PATTERN + `$`)
+
+// mavenSnapshotRegex recognizes the qualifier lists of snapshots, which end with
+// SNAPSHOT or a build timestamp: -SNAPSHOT, -RC2-SNAPSHOT, .BUILD-SNAPSHOT or
+// -RC2-20240115.103045-7.
+var mavenSnapshotRegex = regexp.MustCompile(`(?i)(?:^[.-]?|[.-])snapshot$|` + MAVEN_TIMESTAMP_PATTERN + `$`)
+
+// mavenSnapshotQualifierRegex splits the qualifier list of a snapshot into the
+// qualifiers before its -SNAPSHOT or build timestamp, which are equal: 1.2.3-RC2-SNAPSHOT
+// is deployed as 1.2.3-RC2-20240115.103045-7.
+var mavenSnapshotQualifierRegex = regexp.MustCompile(`^(.*?)` + MAVEN_SNAPSHOT_PATTERN + `$`)
+
+// mavenQualifier is the parsed form of the text that follows a Maven version's
+// numeric components. Only its first qualifier is kept.
+type mavenQualifier struct {
+	rank   int
+	label  string // lowercase label, only kept for MAVEN_RANK_OTHER
+	number int    // qualifier number (alpha-2 → 2), 0 when absent
+}
+
 // parseMavenRange parses Maven-style version ranges like [1.0,2.0), (1.0,2.0], etc.
+//
+// The returned constraint keeps the brackets, since they decide whether each bound
+// is inclusive. Several ranges may be combined with commas, e.g. (,1.0],[1.2,).
 func parseMavenRange(versionStr string) (*VersionConstraint, error) {
 	// Maven ranges: [1.0,2.0), (1.0,2.0], [1.0,2.0], (1.0,2.0)
 	// [ = inclusive lower bound, ( = exclusive lower bound
@@ -23,77 +130,400 @@ func parseMavenRange(versionStr string) (*VersionConstraint, error) {
 		return nil, fmt.Errorf("invalid Maven range brackets: %s", versionStr)
 	}
 
-	// Extract the version range content
-	rangeContent := versionStr[1 : len(versionStr)-1]
-
 	return &VersionConstraint{
-		Operator: "maven-range",
-		Version:  rangeContent,
+		Operator: OP_MAVEN_RANGE,
+		Version:  versionStr,
 	}, nil
 }
 
-// mavenRangeRegex creates a regex for Maven version ranges
-func mavenRangeRegex(rangeStr string) (string, error) {
-	lowerBound, upperBound, err := parseMavenRangeBounds(rangeStr)
+// mavenRangeRegex creates a regex for Maven version ranges.
+// opts.MavenSnapshots decides whether snapshot versions are matched: a version whose
+// qualifier list ends with SNAPSHOT or a timestamp, such as 1.1.0-RC2-SNAPSHOT, is a
+// snapshot wherever it sorts.
+// Result: ^1(?:...)$ matching 1.0, 1.5.2, 1.9-SNAPSHOT, 1.0.Final but not 2.0 (for [1.0,2.0))
+func mavenRangeRegex(rangeStr string, opts Options) (string, error) {
+	ranges, err := parseMavenRangeSet(rangeStr)
 	if err != nil {
 		return "", err
 	}
 
+	builder := seqBuilder{order: mavenQualifierOrder{snapshots: opts.MavenSnapshots}, zeros: opts.LeadingZeros}
+	pattern, ok := builder.rangesPattern(ranges)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + REGEX_END, nil
+}
+
+// mavenExactRegex creates a regex for an exact Maven version, such as a soft
+// requirement, under a snapshot policy other than SNAPSHOTS_INCLUDE, or for a -SNAPSHOT
+// version under any policy. It returns false when version is not a Maven version.
+//
+// The policy keeps the snapshots, or the other versions, among those matched by the
+// version without a policy: 1.2.3 matches any qualifier, so with SNAPSHOTS_ONLY it
+// matches 1.2.3-SNAPSHOT, 1.2.3-RC2-SNAPSHOT and 1.2.3-20240115.103045-7, while a
+// version with a qualifier only matches itself, if the policy allows it. A snapshot
+// also matches the snapshots that compare equal to it, so 1.2.3-SNAPSHOT matches
+// 1.2.3-20240115.103045-7 and the other timestamped builds of 1.2.3.
+// Result: ^1\.2\.3(?:(?:[.-]?[A-Za-z]+...)...|...)$ (for "1.2.3" with SNAPSHOTS_ONLY)
+func mavenExactRegex(version string, opts Options) (string, bool) {
+	matches := mavenVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return "", false
+	}
+	qualifier := matches[2]
+	if _, err := parseMavenQualifier(qualifier); err != nil {
+		return "", false
+	}
+
+	parts := strings.Split(matches[1], ".")
+	for i, part := range parts {
+		parts[i] = opts.LeadingZeros.Equal(part)
+	}
+	core := strings.Join(parts, VERSION_DOT)
+
+	snapshot := mavenSnapshotRegex.MatchString(qualifier)
+	switch {
+	case qualifier == "":
+		return REGEX_START + core + mavenQualifierOrder{snapshots: opts.MavenSnapshots}.any() + REGEX_END, true
+	case snapshot && opts.MavenSnapshots == SNAPSHOTS_EXCLUDE, !snapshot && opts.MavenSnapshots == SNAPSHOTS_ONLY:
+		return NEVER_MATCH_PATTERN, true
+	default:
+		if base := mavenSnapshotQualifierRegex.FindStringSubmatch(qualifier); base != nil {
+			return REGEX_START + core + regexp.QuoteMeta(base[1]) + MAVEN_SNAPSHOT_PATTERN + REGEX_END, true
+		}
+		return REGEX_START + core + regexp.QuoteMeta(qualifier) + REGEX_END, true
+	}
+}
+
+// parseMavenRangeSet parses one or more comma-separated Maven ranges
+// such as [1.0,2.0) or (,1.0],[1.2,) into version ranges.
+func parseMavenRangeSet(rangeStr string) ([]versionRange, error) {
+	rangeStr = strings.TrimSpace(rangeStr)
+	var ranges []versionRange
+	for rangeStr != "" {
+		if rangeStr[0] != '[' && rangeStr[0] != '(' {
+			return nil, fmt.Errorf("invalid Maven range format: %s", rangeStr)
+		}
+		end := strings.IndexAny(rangeStr, "])")
+		if end == -1 {
+			return nil, fmt.Errorf("invalid Maven range format: %s", rangeStr)
+		}
+
+		r, err := parseMavenRangeBounds(rangeStr[:end+1])
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+
+		rangeStr = strings.TrimSpace(rangeStr[end+1:])
+		if rangeStr != "" {
+			if rangeStr[0] != ',' {
+				return nil, fmt.Errorf("invalid Maven range format: %s", rangeStr)
+			}
+			rangeStr = strings.TrimSpace(rangeStr[1:])
+		}
+	}
+	return ranges, nil
+}
+
+// parseMavenRangeBounds extracts and validates the lower and upper bounds from a single
+// bracketed Maven range. [1.0] pins an exact version. A range whose lower bound is above
+// its upper bound, or equal to it with an exclusive end, such as [2.0,1.0] or [1.0,1.0),
+// is rejected as Maven does, rather than matching nothing.
+func parseMavenRangeBounds(rangeStr string) (versionRange, error) {
+	lowerInclusive := rangeStr[0] == '['
+	upperInclusive := rangeStr[len(rangeStr)-1] == ']'
+	content := rangeStr[1 : len(rangeStr)-1]
+
+	parts := strings.Split(content, ",")
+	switch len(parts) {
+	case 1:
+		if !lowerInclusive || !upperInclusive || strings.TrimSpace(content) == "" {
+			return versionRange{}, fmt.Errorf("invalid Maven range format: %s", rangeStr)
+		}
+		bound, err := parseMavenBound(content, true)
+		if err != nil {
+			return versionRange{}, err
+		}
+		return versionRange{lower: bound, upper: bound}, nil
+	case 2:
+	default:
+		return versionRange{}, fmt.Errorf("invalid Maven range format: %s", rangeStr)
+	}
+
+	var r versionRange
+	var err error
+	if lower := strings.TrimSpace(parts[0]); lower != "" {
+		if r.lower, err = parseMavenBound(lower, lowerInclusive); err != nil {
+			return versionRange{}, err
+		}
+	}
+	if upper := strings.TrimSpace(parts[1]); upper != "" {
+		if r.upper, err = parseMavenBound(upper, upperInclusive); err != nil {
+			return versionRange{}, err
+		}
+	}
+
+	if r.lower != nil && r.upper != nil {
+		c := compareBounds(r.lower, r.upper, mavenQualifierOrder{})
+		if c > 0 || (c == 0 && (!lowerInclusive || !upperInclusive)) {
+			return versionRange{}, fmt.Errorf("invalid Maven range, lower bound above upper bound: %s", rangeStr)
+		}
+	}
+	return r, nil
+}
+
+// parseMavenBound parses a Maven version such as 1.0, 2.5.1-SNAPSHOT or 3.0-M2 into a range bound.
+func parseMavenBound(version string, inclusive bool) (*rangeBound, error) {
+	version = strings.TrimSpace(version)
+	matches := mavenVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid Maven version: %s", version)
+	}
+	if _, err := parseMavenQualifier(matches[2]); err != nil {
+		return nil, err
+	}
+
+	parts, ok := parseDecimalParts(matches[1])
+	if !ok {
+		return nil, fmt.Errorf("invalid Maven version: %s", version)
+	}
+
+	return &rangeBound{parts: parts, suffix: matches[2], inclusive: inclusive}, nil
+}
+
+// parseMavenQualifier parses the qualifier that follows a Maven version's numeric components.
+// An empty qualifier is a release, and a build timestamp is a snapshot. Qualifiers
+// after the first one are validated but not kept.
+func parseMavenQualifier(qualifier string) (mavenQualifier, error) {
+	if qualifier == "" {
+		return mavenQualifier{rank: MAVEN_RANK_RELEASE}, nil
+	}
+	if mavenTimestampRegex.MatchString(qualifier) {
+		return mavenQualifier{rank: MAVEN_RANK_SNAPSHOT}, nil
+	}
+
+	matches := mavenQualifierRegex.FindStringSubmatch(qualifier)
+	if matches == nil {
+		return mavenQualifier{}, fmt.Errorf("invalid Maven qualifier: %s", qualifier)
+	}
+
+	if matches[3] != "" {
+		number, err := strconv.Atoi(matches[3])
+		if err != nil {
+			return mavenQualifier{}, fmt.Errorf("invalid Maven qualifier: %s", qualifier)
+		}
+		return mavenQualifier{rank: MAVEN_RANK_NUMBER, number: number}, nil
+	}
+
+	label := strings.ToLower(matches[1])
+	number := 0
+	if matches[2] != "" {
+		var err error
+		if number, err = strconv.Atoi(matches[2]); err != nil {
+			return mavenQualifier{}, fmt.Errorf("invalid Maven qualifier: %s", qualifier)
+		}
+	}
+
+	if rank, ok := mavenQualifierAliases[label]; ok && matches[2] != "" {
+		return mavenQualifier{rank: rank, number: number}, nil
+	}
+	for rank, labels := range mavenQualifierLabels {
+		for _, known := range labels {
+			if label == known {
+				return mavenQualifier{rank: rank, number: number}, nil
+			}
+		}
+	}
+	return mavenQualifier{rank: MAVEN_RANK_OTHER, label: label, number: number}, nil
+}
+
+// mavenQualifierOrder implements suffixOrder for Maven qualifiers.
+type mavenQualifierOrder struct {
+	// snapshots decides whether snapshot qualifiers may match
+	snapshots SnapshotPolicy
+}
+
+// compare orders two Maven qualifiers by rank, label and number.
+func (o mavenQualifierOrder) compare(a, b string) int {
+	qa, _ := parseMavenQualifier(a)
+	qb, _ := parseMavenQualifier(b)
 	switch {
-	case lowerBound != "" && upperBound != "":
-		return mavenBothBoundsPattern(lowerBound, upperBound), nil
-	case lowerBound != "":
-		return mavenLowerBoundPattern(lowerBound), nil
-	case upperBound != "":
-		return mavenUpperBoundPattern(upperBound), nil
+	case qa.rank != qb.rank:
+		return compareInts(qa.rank, qb.rank)
+	case qa.label != qb.label:
+		return strings.Compare(qa.label, qb.label)
 	default:
-		return EXACT_VERSION_TEMPLATE, nil
+		return compareInts(qa.number, qb.number)
 	}
 }
 
-// parseMavenRangeBounds extracts and validates the lower and upper bounds from a Maven range string
-func parseMavenRangeBounds(rangeStr string) (lowerBound, upperBound string, err error) {
-	parts := strings.Split(rangeStr, ",")
-	if len(parts) != 2 {
-		return "", "", fmt.Errorf("invalid Maven range format: %s", rangeStr)
+// any matches every supported Maven qualifier allowed by the snapshot policy.
+// Result: (?:(?:[.-]?[A-Za-z]+(?:[.-]?\d+)?|-\d+)(?:...)*...|-\d{8}\.\d{6}-\d+)?
+func (o mavenQualifierOrder) any() string {
+	switch o.snapshots {
+	case SNAPSHOTS_EXCLUDE:
+		return `(?:(?:[.-]?(?i:` + wordComplementPattern(mavenQualifierLabels[MAVEN_RANK_SNAPSHOT]) + `)(?:[.-]?\d+)?|-\d+)` + mavenReleaseTailPattern + `)?`
+	case SNAPSHOTS_ONLY:
+		return `(?:(?:[.-]?[A-Za-z]+(?:[.-]?\d+)?|-\d+)` + MAVEN_SNAPSHOT_TAIL_PATTERN + `|` + MAVEN_SNAPSHOT_PATTERN + `)`
+	default:
+		return MAVEN_QUALIFIER_PATTERN
 	}
-	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]), nil
 }
 
-// extractMajorVersion extracts the major version number from a version string
-func extractMajorVersion(version string) int {
-	parts := strings.Split(version, ".")
-	major, _ := strconv.Atoi(parts[0])
-	return major
+// tail matches the qualifiers that may follow the first one under the snapshot policy.
+func (o mavenQualifierOrder) tail() string {
+	switch o.snapshots {
+	case SNAPSHOTS_EXCLUDE:
+		return mavenReleaseTailPattern
+	case SNAPSHOTS_ONLY:
+		return MAVEN_SNAPSHOT_TAIL_PATTERN
+	default:
+		return MAVEN_QUALIFIER_TAIL_PATTERN
+	}
 }
 
-// mavenBothBoundsPattern creates a regex pattern when both bounds are specified
-// Result (same major): ^1\.\d+\.\d+(?:-[a-zA-Z0-9\-\.]+)?(?:\+[a-zA-Z0-9\-\.]+)?$ (for [1.0,1.9])
-// Result (diff major): ^[1-3]\.\d+\.\d+(?:-[a-zA-Z0-9\-\.]+)?(?:\+[a-zA-Z0-9\-\.]+)?$ (for [1.0,3.0])
-func mavenBothBoundsPattern(lowerBound, upperBound string) string {
-	lowerMajor := extractMajorVersion(lowerBound)
-	upperMajor := extractMajorVersion(upperBound)
+// atLeast matches qualifiers ordered after s (or equal to it when inclusive).
+func (o mavenQualifierOrder) atLeast(s string, inclusive bool) (string, bool) {
+	q, _ := parseMavenQualifier(s)
+	alternatives := []string{o.sameRank(q, q.number, inclusive, -1, true)}
+	for rank := q.rank + 1; rank <= MAVEN_RANK_NUMBER; rank++ {
+		alternatives = append(alternatives, o.rankPattern(mavenQualifier{rank: rank}, 0, -1))
+	}
+	return groupQualifiers(alternatives)
+}
 
-	if lowerMajor == upperMajor {
-		return fmt.Sprintf(REGEX_START+"%d"+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS+VERSION_SUFFIX_PATTERN+REGEX_END, lowerMajor)
+// atMost matches qualifiers ordered before s (or equal to it when inclusive).
+func (o mavenQualifierOrder) atMost(s string, inclusive bool) (string, bool) {
+	q, _ := parseMavenQualifier(s)
+	var alternatives []string
+	for rank := MAVEN_RANK_ALPHA; rank < q.rank; rank++ {
+		alternatives = append(alternatives, o.rankPattern(mavenQualifier{rank: rank}, 0, -1))
 	}
-	return fmt.Sprintf(REGEX_START+"[%d-%d]"+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS+VERSION_SUFFIX_PATTERN+REGEX_END, lowerMajor, upperMajor)
+	alternatives = append(alternatives, o.sameRank(q, 0, true, q.number, inclusive))
+	return groupQualifiers(alternatives)
 }
 
-// mavenLowerBoundPattern creates a regex pattern when only lower bound is specified
-// Result: ^(?:[2-9]|\d{2,})\.\d+\.\d+(?:-[a-zA-Z0-9\-\.]+)?(?:\+[a-zA-Z0-9\-\.]+)?$ (for [2.0,))
-func mavenLowerBoundPattern(lowerBound string) string {
-	lowerMajor := extractMajorVersion(lowerBound)
-	return fmt.Sprintf(REGEX_START+`(?:[%d-9]|\d{2,})`+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS+VERSION_SUFFIX_PATTERN+REGEX_END, lowerMajor)
+// between matches qualifiers ordered between lo and hi.
+func (o mavenQualifierOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	qlo, _ := parseMavenQualifier(lo)
+	qhi, _ := parseMavenQualifier(hi)
+	if qlo.rank == qhi.rank && qlo.label == qhi.label {
+		return groupQualifiers([]string{o.sameRank(qlo, qlo.number, loInclusive, qhi.number, hiInclusive)})
+	}
+
+	alternatives := []string{o.sameRank(qlo, qlo.number, loInclusive, -1, true)}
+	for rank := qlo.rank + 1; rank < qhi.rank; rank++ {
+		alternatives = append(alternatives, o.rankPattern(mavenQualifier{rank: rank}, 0, -1))
+	}
+	if qhi.rank > qlo.rank {
+		alternatives = append(alternatives, o.sameRank(qhi, 0, true, qhi.number, hiInclusive))
+	}
+	return groupQualifiers(alternatives)
+}
+
+// sameRank matches qualifiers with q's rank (and label) whose number lies between
+// lo and hi; hi < 0 means unbounded. It returns NEVER_MATCH when no number fits.
+func (o mavenQualifierOrder) sameRank(q mavenQualifier, lo int, loInclusive bool, hi int, hiInclusive bool) string {
+	if !loInclusive {
+		lo++
+	}
+	if hi >= 0 && !hiInclusive {
+		if hi == 0 {
+			return NEVER_MATCH
+		}
+		hi--
+	}
+	if hi >= 0 && lo > hi {
+		return NEVER_MATCH
+	}
+	return o.rankPattern(q, lo, hi)
 }
 
-// mavenUpperBoundPattern creates a regex pattern when only upper bound is specified
-// Result (major > 0): ^[0-3]\.\d+\.\d+(?:-[a-zA-Z0-9\-\.]+)?(?:\+[a-zA-Z0-9\-\.]+)?$ (for (,3.0])
-// Result (major = 0): ^0\.\d+\.\d+(?:-[a-zA-Z0-9\-\.]+)?(?:\+[a-zA-Z0-9\-\.]+)?$ (for (,0.9])
-func mavenUpperBoundPattern(upperBound string) string {
-	upperMajor := extractMajorVersion(upperBound)
-	if upperMajor > 0 {
-		return fmt.Sprintf(REGEX_START+"[0-%d]"+VERSION_DOT+VERSION_DIGITS+VERSION_DOT+VERSION_DIGITS+VERSION_SUFFIX_PATTERN+REGEX_END, upperMajor)
+// rankPattern matches qualifier lists whose first qualifier has q's rank and a number
+// between lo and hi (hi < 0 means unbounded). A missing qualifier number counts as 0.
+// The snapshot policy decides how the list may end, and excludes the snapshot rank.
+func (o mavenQualifierOrder) rankPattern(q mavenQualifier, lo, hi int) string {
+	switch q.rank {
+	case MAVEN_RANK_RELEASE:
+		if lo > 0 {
+			return NEVER_MATCH
+		}
+		release := `[.-](?i:` + strings.Join(mavenQualifierLabels[MAVEN_RANK_RELEASE], REGEX_OR) + `)` + o.tail()
+		if o.snapshots == SNAPSHOTS_ONLY {
+			// A plain release is never a snapshot
+			return release
+		}
+		return `(?:` + release + `)?`
+	case MAVEN_RANK_SNAPSHOT:
+		if lo > 0 || o.snapshots == SNAPSHOTS_EXCLUDE {
+			return NEVER_MATCH
+		}
+		return MAVEN_SNAPSHOT_PATTERN
+	}
+
+	number := NumGreaterOrEqual(lo)
+	if hi >= 0 {
+		number = NumRange(lo, hi)
+	}
+	if q.rank == MAVEN_RANK_NUMBER {
+		return `-` + number + o.tail()
+	}
+	optionalNumber := `(?:[.-]?` + number + `)?`
+	if lo > 0 {
+		optionalNumber = `[.-]?` + number
+	}
+	optionalNumber += o.tail()
+
+	if q.rank == MAVEN_RANK_OTHER {
+		if q.label != "" {
+			return `[.-](?i:` + regexp.QuoteMeta(q.label) + `)` + optionalNumber
+		}
+		var known []string
+		for _, labels := range mavenQualifierLabels {
+			known = append(known, labels...)
+		}
+		for alias := range mavenQualifierAliases {
+			known = append(known, alias)
+		}
+		return `[.-](?i:` + wordComplementPattern(known) + `)` + optionalNumber
+	}
+
+	alternatives := []string{`(?i:` + strings.Join(mavenQualifierLabels[q.rank], REGEX_OR) + `)` + optionalNumber}
+	for alias, rank := range mavenQualifierAliases {
+		if rank == q.rank {
+			alternatives = append(alternatives, `(?i:`+alias+`)`+number+o.tail())
+		}
+	}
+	return `[.-]?` + joinPatterns(alternatives)
+}
+
+// groupQualifiers joins qualifier alternatives, dropping those that cannot match.
+func groupQualifiers(alternatives []string) (string, bool) {
+	var patterns []string
+	for _, alternative := range alternatives {
+		if alternative != NEVER_MATCH {
+			patterns = append(patterns, alternative)
+		}
+	}
+	if len(patterns) == 0 {
+		return "", false
+	}
+	if len(patterns) == 1 {
+		return patterns[0], true
+	}
+	return "(?:" + strings.Join(patterns, REGEX_OR) + ")", true
+}
+
+// compareInts returns -1, 0 or 1 depending on how a compares to b.
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
 	}
-	return REGEX_START + "0" + VERSION_DOT + VERSION_DIGITS + VERSION_DOT + VERSION_DIGITS + VERSION_SUFFIX_PATTERN + REGEX_END
 }
@@ -11,10 +11,11 @@ func TestParseMavenRange(t *testing.T) {
 		wantErr  bool
 		expected string
 	}{
-		{"[1.0,2.0]", false, "1.0,2.0"},
-		{"(1.0,2.0)", false, "1.0,2.0"},
-		{"[1.0,)", false, "1.0,"},
-		{"(,2.0]", false, ",2.0"},
+		{"[1.0,2.0]", false, "[1.0,2.0]"},
+		{"(1.0,2.0)", false, "(1.0,2.0)"},
+		{"[1.0,)", false, "[1.0,)"},
+		{"(,2.0]", false, "(,2.0]"},
+		{"(,1.0],[1.2,)", false, "(,1.0],[1.2,)"},
 		{"invalid", true, ""},
 		{"[1.0", true, ""},
 		{"[]", true, ""}, // Test length < 3 case (line 16)
@@ -49,12 +50,12 @@ func TestParseMavenRange(t *testing.T) {
 // TestMavenRangeRegex tests the mavenRangeRegex function with various Maven version ranges.
 //
 // This test specifically targets the regex generation logic including:
-// - Invalid range formats (tests line 40)
-// - Same major version ranges (tests lines 55-62)
-// - Different major version ranges (tests lines 59-67)
-// - Lower bound only ranges (tests lines 69-78)
-// - Upper bound only ranges (tests lines 79-89)
-// - Edge cases and fallback patterns
+// - Invalid range formats
+// - Inclusive and exclusive bounds
+// - Lower bound only and upper bound only ranges
+// - Any number of numeric components, with trailing zeros ignored
+// - ComparableVersion qualifier ordering (alpha < beta < milestone < rc < snapshot < release < sp)
+// - Exact versions and unions of ranges
 func TestMavenRangeRegex(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -64,107 +65,301 @@ func TestMavenRangeRegex(t *testing.T) {
 		shouldNotMatch []string
 	}{
 		{
-			name:     "invalid range format - no comma",
-			rangeStr: "1.0",
+			name:     "invalid range format - no brackets",
+			rangeStr: "1.0,2.0",
 			wantErr:  true,
 		},
 		{
 			name:     "invalid range format - too many commas",
-			rangeStr: "1.0,2.0,3.0",
+			rangeStr: "[1.0,2.0,3.0]",
 			wantErr:  true,
 		},
+		{
+			name:     "invalid range format - exclusive exact version",
+			rangeStr: "(1.0)",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid range format - missing separator between ranges",
+			rangeStr: "[1.0,2.0)[3.0,)",
+			wantErr:  true,
+		},
+		{
+			name:     "invalid version",
+			rangeStr: "[1.0,two]",
+			wantErr:  true,
+		},
+		{
+			name:     "inverted range",
+			rangeStr: "[2.0,1.0]",
+			wantErr:  true,
+		},
+		{
+			name:     "inverted range after trailing zeros and qualifiers",
+			rangeStr: "[1.0.0,1-rc1]",
+			wantErr:  true,
+		},
+		{
+			name:     "empty range with an exclusive end",
+			rangeStr: "[1.0,1.0)",
+			wantErr:  true,
+		},
+		{
+			name:           "single version written as a range",
+			rangeStr:       "[1.0,1.0.0]",
+			shouldMatch:    []string{"1.0", "1"},
+			shouldNotMatch: []string{"1.0.1", "0.9"},
+		},
+		{
+			name:           "timestamp components beyond int64",
+			rangeStr:       "[1.20240115103045000000,1.20240115103045000009)",
+			shouldMatch:    []string{"1.20240115103045000000", "1.20240115103045000008.9", "1.20240115103045000001"},
+			shouldNotMatch: []string{"1.20240115103044999999", "1.20240115103045000009", "1.9223372036854775807"},
+		},
 		{
 			name:     "same major version range",
-			rangeStr: "1.0,1.9",
+			rangeStr: "[1.0,1.9]",
 			wantErr:  false,
 			shouldMatch: []string{
+				"1",
+				"1.0",
 				"1.0.0",
 				"1.5.2",
-				"1.9.9",
-				"1.0.0-alpha",
-				"1.2.3+build",
+				"1.9",
+				"1.9.0.0",
+				"1.0.Final",
+				"1.5-SNAPSHOT",
+				"1.2.3.RELEASE",
 			},
 			shouldNotMatch: []string{
 				"0.9.9",
+				"1.0-alpha-1",
+				"1.9.1",
+				"1.9-sp1",
 				"2.0.0",
 			},
 		},
 		{
-			name:     "different major version range",
-			rangeStr: "1.0,3.0",
+			name:     "exclusive upper bound",
+			rangeStr: "[1.0,2.0)",
 			wantErr:  false,
 			shouldMatch: []string{
-				"1.0.0",
+				"1.0",
+				"1.5.2",
+				"1.9.9.9",
+				"1.9-SNAPSHOT",
+				"2.0-alpha-1",
+				"2.0-M2",
+				"2.0-rc1",
+				"2.0-SNAPSHOT",
+			},
+			shouldNotMatch: []string{
+				"0.9",
+				"2.0",
+				"2.0.0",
+				"2.0.Final",
+				"2.0-sp1",
+				"2.0.1",
+			},
+		},
+		{
+			name:     "exclusive lower bound",
+			rangeStr: "(1.0,3.0]",
+			wantErr:  false,
+			shouldMatch: []string{
+				"1.0-sp1",
+				"1.0-jre",
+				"1.0.1",
 				"2.5.2",
-				"3.0.0",
-				"1.0.0-alpha",
-				"2.2.3+build",
+				"3.0",
+				"3.0.GA",
 			},
 			shouldNotMatch: []string{
-				"0.9.9",
-				"4.0.0",
+				"1.0",
+				"1.0.0",
+				"1.0.Final",
+				"1.0-rc1",
+				"3.0-sp1",
+				"4.0",
 			},
 		},
 		{
 			name:     "lower bound only",
-			rangeStr: "2.0,",
+			rangeStr: "[2.0,)",
 			wantErr:  false,
 			shouldMatch: []string{
+				"2",
 				"2.0.0",
 				"3.0.0",
 				"10.5.2",
 				"999.0.0",
-				"2.0.0-alpha",
+				"2.0.Final",
+				"2.0-sp2",
 			},
 			shouldNotMatch: []string{
 				"1.9.9",
 				"0.5.0",
+				"2.0-alpha",
+				"2.0-SNAPSHOT",
 			},
 		},
 		{
 			name:     "upper bound only - positive major",
-			rangeStr: ",3.0",
+			rangeStr: "(,3.0]",
 			wantErr:  false,
 			shouldMatch: []string{
 				"0.0.0",
 				"1.0.0",
 				"2.5.2",
 				"3.0.0",
-				"1.0.0-alpha",
+				"3.0-beta-2",
 			},
 			shouldNotMatch: []string{
+				"3.0.1",
+				"3.0-sp1",
 				"4.0.0",
 				"10.0.0",
 			},
 		},
 		{
 			name:     "upper bound only - zero major",
-			rangeStr: ",0.5",
+			rangeStr: "(,0.5)",
 			wantErr:  false,
 			shouldMatch: []string{
-				"0.0.0",
+				"0",
 				"0.1.0",
+				"0.4.9",
+				"0.5-SNAPSHOT",
+			},
+			shouldNotMatch: []string{
+				"0.5",
 				"0.5.0",
-				"0.0.1-alpha",
+				"1.0.0",
+			},
+		},
+		{
+			name:     "qualifier bounds",
+			rangeStr: "[1.0-alpha-2,1.0-rc)",
+			wantErr:  false,
+			shouldMatch: []string{
+				"1.0-alpha-2",
+				"1.0-alpha10",
+				"1.0-a3",
+				"1.0-beta",
+				"1.0-b1",
+				"1.0-M1",
+				"1.0-milestone-2",
 			},
 			shouldNotMatch: []string{
+				"1.0-alpha-1",
+				"1.0-alpha",
+				"1.0-rc",
+				"1.0-cr1",
+				"1.0-SNAPSHOT",
+				"1.0",
+			},
+		},
+		{
+			name:     "snapshot bound",
+			rangeStr: "[2.5.1-SNAPSHOT,2.5.1]",
+			wantErr:  false,
+			shouldMatch: []string{
+				"2.5.1-SNAPSHOT",
+				"2.5.1",
+				"2.5.1.Final",
+			},
+			shouldNotMatch: []string{
+				"2.5.1-rc1",
+				"2.5.0",
+				"2.5.2-SNAPSHOT",
+			},
+		},
+		{
+			name:     "exact version",
+			rangeStr: "[1.0]",
+			wantErr:  false,
+			shouldMatch: []string{
+				"1",
+				"1.0",
 				"1.0.0",
-				"2.0.0",
+				"1.0.RELEASE",
+			},
+			shouldNotMatch: []string{
+				"1.0.1",
+				"1.0-SNAPSHOT",
+				"1.0-sp1",
+			},
+		},
+		{
+			name:     "union of ranges",
+			rangeStr: "(,1.0],[1.2,)",
+			wantErr:  false,
+			shouldMatch: []string{
+				"0.9",
+				"1.0",
+				"1.2",
+				"1.2.0.1",
+				"5.0",
+			},
+			shouldNotMatch: []string{
+				"1.0.1",
+				"1.1",
+				"1.1.9",
+				"1.2-rc1",
+			},
+		},
+		{
+			name:     "qualifier lists sort by their first qualifier",
+			rangeStr: "[0.1,)",
+			wantErr:  false,
+			shouldMatch: []string{
+				"1.0-1",
+				"2.0.0-M1-jdk8",
+				"2.0.0-rc.1-jre",
+				"1.0-beta-1-SNAPSHOT",
+				"1.0.0-RC2-SNAPSHOT",
+				"1.1.0.BUILD-SNAPSHOT",
+				"1.1.0-RC2-20240115.103045-7",
+			},
+			shouldNotMatch: []string{
+				"0.1-alpha-1-jdk8",
+				"0.1-SNAPSHOT",
+				"1.0--1",
+				"1.0-rc-",
 			},
 		},
 		{
-			name:     "both bounds empty - fallback",
-			rangeStr: " , ",
+			name:     "bare numeric qualifiers",
+			rangeStr: "(1.0,1.0.1)",
+			wantErr:  false,
+			shouldMatch: []string{
+				"1.0-1",
+				"1.0-12-jre",
+				"1.0-sp1",
+				"1.0-jre",
+			},
+			shouldNotMatch: []string{
+				"1.0",
+				"1.0.1",
+				"1.0-M1-jdk8",
+			},
+		},
+		{
+			name:     "empty range",
+			rangeStr: "(2.0,1.0)",
+			wantErr:  true,
+		},
+		{
+			name:     "unbounded range",
+			rangeStr: "(,)",
 			wantErr:  false,
 			shouldMatch: []string{
 				"1.0.0",
-				"2.5.2",
-				"10.0.0",
-				"1.0.0-alpha",
-				"2.2.3+build",
+				"2.5",
+				"1.0-alpha-1",
+				"1.0.Final",
 			},
 			shouldNotMatch: []string{
-				"1.0", // Not semantic version format
 				"invalid",
 			},
 		},
@@ -172,7 +367,7 @@ func TestMavenRangeRegex(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			regexPattern, err := mavenRangeRegex(tt.rangeStr)
+			regexPattern, err := mavenRangeRegex(tt.rangeStr, Options{})
 
 			if tt.wantErr {
 				if err == nil {
@@ -211,3 +406,157 @@ func TestMavenRangeRegex(t *testing.T) {
 		})
 	}
 }
+
+// TestMavenQualifierOrder tests that Maven qualifiers are ordered like ComparableVersion.
+func TestMavenQualifierOrder(t *testing.T) {
+	ordered := []string{"-alpha-1", "-a2", "-beta", "-b1", "-M1", "-milestone-2", "-rc1", "-CR2", "-SNAPSHOT", "", "-sp1", "-jre", "-1", "-2"}
+	order := mavenQualifierOrder{}
+	for i := 1; i < len(ordered); i++ {
+		if c := order.compare(ordered[i-1], ordered[i]); c >= 0 {
+			t.Errorf("compare(%q, %q) = %d, expected -1", ordered[i-1], ordered[i], c)
+		}
+	}
+
+	equal := [][2]string{{"", ".Final"}, {"", "-ga"}, {".RELEASE", "-final"}, {"-rc1", "-cr-1"}, {"-a1", "-alpha.1"}, {"-SNAPSHOT", "-20240115.103045-7"}, {"-M1", "-M1-jdk8"}, {"-rc.1", "-rc.1-jre"}, {"-beta-1", "-beta-1-SNAPSHOT"}}
+	for _, pair := range equal {
+		if c := order.compare(pair[0], pair[1]); c != 0 {
+			t.Errorf("compare(%q, %q) = %d, expected 0", pair[0], pair[1], c)
+		}
+	}
+}
+
+// TestMavenSnapshotPolicy tests how the snapshot policy and timestamped snapshot
+// builds affect Maven range matching.
+func TestMavenSnapshotPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		rangeStr       string
+		policy         SnapshotPolicy
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			name:           "include - timestamped builds equal their snapshot base",
+			rangeStr:       "[1.0,2.0)",
+			policy:         SNAPSHOTS_INCLUDE,
+			shouldMatch:    []string{"1.5", "1.5-SNAPSHOT", "1.5-20240115.103045-7", "2.0-20240115.103045-1"},
+			shouldNotMatch: []string{"2.0", "0.9-20240115.103045-7", "1.5-2024.1-7"},
+		},
+		{
+			name:           "include - timestamped bound",
+			rangeStr:       "[1.2.3-20240115.103045-7]",
+			policy:         SNAPSHOTS_INCLUDE,
+			shouldMatch:    []string{"1.2.3-SNAPSHOT", "1.2.3-20240116.080000-8"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3-rc1"},
+		},
+		{
+			name:           "exclude",
+			rangeStr:       "[1.0,2.0)",
+			policy:         SNAPSHOTS_EXCLUDE,
+			shouldMatch:    []string{"1.0", "1.5.2", "1.5.Final", "1.5-rc1", "1.5-jre", "2.0-beta-1"},
+			shouldNotMatch: []string{"1.5-SNAPSHOT", "1.5-snapshot", "1.5-20240115.103045-7", "2.0-SNAPSHOT", "2.0"},
+		},
+		{
+			name:           "exclude - unbounded",
+			rangeStr:       "(,)",
+			policy:         SNAPSHOTS_EXCLUDE,
+			shouldMatch:    []string{"1.0", "3.2.1-sp1", "1.0-snapshots"},
+			shouldNotMatch: []string{"1.0-SNAPSHOT", "3.2.1-20240115.103045-7"},
+		},
+		{
+			name:           "only - stale snapshots",
+			rangeStr:       "(,1.2.0)",
+			policy:         SNAPSHOTS_ONLY,
+			shouldMatch:    []string{"1.0-SNAPSHOT", "1.1.9-20240115.103045-7", "1.2.0-SNAPSHOT", "1.2-20240115.103045-7"},
+			shouldNotMatch: []string{"1.0", "1.1.9", "1.1-rc1", "1.2.1-SNAPSHOT", "1.3-20240115.103045-7"},
+		},
+		{
+			name:           "only - snapshots with qualifier lists",
+			rangeStr:       "[1.0,2.0)",
+			policy:         SNAPSHOTS_ONLY,
+			shouldMatch:    []string{"1.1.0-RC2-SNAPSHOT", "1.1.0.BUILD-SNAPSHOT", "1.1.0-RC2-20240115.103045-7", "1.1-beta-1-SNAPSHOT", "1.1-jdk8-SNAPSHOT", "2.0.0-RC2-SNAPSHOT"},
+			shouldNotMatch: []string{"1.1.0-RC2", "1.1.0.BUILD", "1.1.0-RC2-SNAPSHOT1", "1.0-beta-1-SNAPSHOT", "2.0.1-RC2-SNAPSHOT"},
+		},
+		{
+			name:           "exclude - snapshots with qualifier lists",
+			rangeStr:       "[1.0,2.0)",
+			policy:         SNAPSHOTS_EXCLUDE,
+			shouldMatch:    []string{"1.1.0-RC2", "1.1.0.BUILD", "1.1.0-RC2-SNAPSHOT1", "1.1.0-M1-jdk8", "1.0-1"},
+			shouldNotMatch: []string{"1.1.0-RC2-SNAPSHOT", "1.1.0.BUILD-SNAPSHOT", "1.1.0-RC2-20240115.103045-7", "1.1-beta-1-SNAPSHOT", "1.0-1-SNAPSHOT"},
+		},
+		{
+			name:           "only - soft requirement",
+			rangeStr:       "1.2.3",
+			policy:         SNAPSHOTS_ONLY,
+			shouldMatch:    []string{"1.2.3-SNAPSHOT", "1.2.3-RC2-SNAPSHOT", "1.2.3-20240115.103045-7", "1.2.3.BUILD-SNAPSHOT"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3-RC2", "1.2.4-SNAPSHOT"},
+		},
+		{
+			name:           "exclude - soft requirement",
+			rangeStr:       "1.2.3",
+			policy:         SNAPSHOTS_EXCLUDE,
+			shouldMatch:    []string{"1.2.3", "1.2.3-RC2", "1.2.3.Final"},
+			shouldNotMatch: []string{"1.2.3-SNAPSHOT", "1.2.3-RC2-SNAPSHOT", "1.2.3-20240115.103045-7"},
+		},
+		{
+			name:           "exclude - exact snapshot",
+			rangeStr:       "1.2.3-RC2-SNAPSHOT",
+			policy:         SNAPSHOTS_EXCLUDE,
+			shouldNotMatch: []string{"1.2.3-RC2-SNAPSHOT", "1.2.3-RC2", "1.2.3"},
+		},
+		{
+			name:           "only - exact snapshot",
+			rangeStr:       "1.2.3-RC2-SNAPSHOT",
+			policy:         SNAPSHOTS_ONLY,
+			shouldMatch:    []string{"1.2.3-RC2-SNAPSHOT", "1.2.3-RC2-20240115.103045-7"},
+			shouldNotMatch: []string{"1.2.3-RC2", "1.2.3-SNAPSHOT", "1.2.3-20240115.103045-7"},
+		},
+		{
+			name:           "include - exact snapshot matches its timestamped builds",
+			rangeStr:       "1.2.3-SNAPSHOT",
+			policy:         SNAPSHOTS_INCLUDE,
+			shouldMatch:    []string{"1.2.3-SNAPSHOT", "1.2.3-snapshot", "1.2.3-20240115.103045-7"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3-RC2-20240115.103045-7", "1.2.4-20240115.103045-7", "1.2.3-2024.1-7"},
+		},
+		{
+			name:           "include - exact timestamped build",
+			rangeStr:       "1.2.3-20240115.103045-7",
+			policy:         SNAPSHOTS_INCLUDE,
+			shouldMatch:    []string{"1.2.3-SNAPSHOT", "1.2.3-20240116.080000-8"},
+			shouldNotMatch: []string{"1.2.3", "1.2.3-rc1"},
+		},
+		{
+			name:           "only - exact release",
+			rangeStr:       "1.2.3-RC2",
+			policy:         SNAPSHOTS_ONLY,
+			shouldNotMatch: []string{"1.2.3-RC2", "1.2.3-RC2-SNAPSHOT"},
+		},
+		{
+			name:           "only - snapshot-only bound",
+			rangeStr:       "[1.0,1.0-sp)",
+			policy:         SNAPSHOTS_ONLY,
+			shouldNotMatch: []string{"1.0", "1.0-SNAPSHOT", "0.9-SNAPSHOT"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.rangeStr, Options{MavenSnapshots: tt.policy})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.rangeStr, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,819 @@
+// Package convert provides regex minimization functionality.
+// This file contains the optimization pass applied to generated patterns: it factors
+// common prefixes and suffixes out of alternations, merges single characters into
+// character classes and prints the result with as few groups and as short quantifiers
+// as possible, while matching exactly the same strings.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MinimizePattern returns a pattern equivalent to pattern that is as short as the
+// optimization pass can make it.
+//
+// The pattern is parsed with Go's regexp/syntax, which factors common prefixes out
+// of alternations and merges single characters into classes. Common suffixes are
+// then factored as well, repetitions of the same expression are folded into one
+// quantifier (X(?:X*)? becomes X+ and (?:|\d|\d\d+) becomes \d*), and the result
+// is printed with groups only where precedence requires them, \d for digits and the
+// shortest quantifier form (\d\d+ rather than \d{2,}). Quantifiers are printed
+// greedy, as laziness never changes which strings match. Case-insensitive parts are
+// printed inside a single (?i:...) group. Rounds are repeated as long as they make
+// the pattern shorter, since each one exposes new alternations to factor.
+//
+// The original pattern is returned when it cannot be parsed or is already shorter.
+//
+// Example:
+//
+//	MinimizePattern(`^(?:1\.2\.3|1\.2\.4|1\.2\.5)$`) → `^1\.2\.[3-5]$`
+func MinimizePattern(pattern string) string {
+	best := pattern
+	for {
+		// Factoring prefixes first or suffixes first can each lead to the shorter result
+		next := best
+		for _, prefixesFirst := range []bool{true, false} {
+			re, err := syntax.Parse(best, syntax.Perl)
+			if err != nil {
+				return best
+			}
+			candidate := printRegexp(foldRepeats(factorAlternations(re, prefixesFirst)), false)
+			if _, err := regexp.Compile(candidate); err == nil && len(candidate) < len(next) {
+				next = candidate
+			}
+		}
+		if next == best {
+			return best
+		}
+		best = next
+	}
+}
+
+// factorAlternations rewrites alternations whose adjacent alternatives start or end
+// with the same subexpressions, so that A(?:\.\d+)*|B(?:\.\d+)* becomes
+// (?:A|B)(?:\.\d+)* and 0*1|0*2x becomes 0*(?:1|2x). Prefixes are only factored when
+// prefixesFirst is set. The order of the alternatives is kept, and a run is only
+// factored when it gets shorter.
+func factorAlternations(re *syntax.Regexp, prefixesFirst bool) *syntax.Regexp {
+	for i, sub := range re.Sub {
+		re.Sub[i] = factorAlternations(sub, prefixesFirst)
+	}
+	if re.Op != syntax.OpAlternate {
+		return re
+	}
+
+	alternatives := re.Sub
+	if prefixesFirst {
+		alternatives = factorRuns(alternatives, commonPrefix, factorPrefixRun)
+	}
+	alternatives = factorRuns(alternatives, commonSuffix, factorSuffixRun)
+	if len(alternatives) == 1 {
+		return alternatives[0]
+	}
+	re.Sub = alternatives
+	return re
+}
+
+// foldRepeats rewrites repetitions of the same expression into a single quantifier:
+// alternatives such as (?:)|\d|\d\d+ become \d*, nested quantifiers such as (?:\d+)?
+// become \d*, and pieces of a concatenation that repeat a neighbouring quantified
+// expression are moved into its quantifier, so that \.\d+(?:\.\d+)* becomes (?:\.\d+)+.
+func foldRepeats(re *syntax.Regexp) *syntax.Regexp {
+	for i, sub := range re.Sub {
+		re.Sub[i] = foldRepeats(sub)
+	}
+
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		atom, min, max := repeatOf(re)
+		return repeatNode(atom, min, max)
+	case syntax.OpAlternate:
+		alternatives := mergeRepeatAlternatives(re.Sub)
+		if len(alternatives) == 1 {
+			return alternatives[0]
+		}
+		re.Sub = alternatives
+		return re
+	case syntax.OpConcat:
+		return concatOf(mergeLiterals(foldConcatRepeats(splitLiterals(re.Sub))))
+	default:
+		return re
+	}
+}
+
+// repeatOf returns re as a repetition of atom between min and max times (-1 for
+// unbounded). The empty match has a nil atom, and any other expression is its own
+// atom repeated once. Nested quantifiers are combined when every count between
+// them can be reached, e.g. (?:\d+)? is \d{0,}.
+func repeatOf(re *syntax.Regexp) (*syntax.Regexp, int, int) {
+	min, max := 1, 1
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return nil, 0, 0
+	case syntax.OpStar:
+		min, max = 0, -1
+	case syntax.OpPlus:
+		min, max = 1, -1
+	case syntax.OpQuest:
+		min, max = 0, 1
+	case syntax.OpRepeat:
+		min, max = re.Min, re.Max
+	case syntax.OpConcat:
+		// A concatenation of repetitions of one atom repeats it as often as all of them
+		var atom *syntax.Regexp
+		min, max = 0, 0
+		for _, piece := range re.Sub {
+			pieceAtom, pieceMin, pieceMax := repeatOf(piece)
+			if pieceAtom == nil || (atom != nil && !atom.Equal(pieceAtom)) || hasCapture(pieceAtom) {
+				return re, 1, 1
+			}
+			atom, min, max = pieceAtom, min+pieceMin, addCounts(max, pieceMax)
+		}
+		return atom, min, max
+	default:
+		return re, 1, 1
+	}
+
+	atom, innerMin, innerMax := repeatOf(re.Sub[0])
+	switch {
+	case atom == nil:
+		return nil, 0, 0
+	case innerMin == 1 && innerMax == 1:
+		return atom, min, max
+	case innerMin <= 1 && innerMax != 0 && (max == -1 || max == 1) && !hasCapture(atom):
+		// (X{0,n})? is X{0,n}, (X{1,n})* is X*, (X{1,n})+ is X+
+		if max == -1 {
+			innerMax = -1
+		}
+		return atom, min * innerMin, innerMax
+	default:
+		return re, 1, 1
+	}
+}
+
+// addCounts adds two repetition counts, where -1 is unbounded.
+func addCounts(a, b int) int {
+	if a == -1 || b == -1 {
+		return -1
+	}
+	return a + b
+}
+
+// repeatNode returns the expression repeating atom between min and max times.
+func repeatNode(atom *syntax.Regexp, min, max int) *syntax.Regexp {
+	switch {
+	case atom == nil || max == 0:
+		return &syntax.Regexp{Op: syntax.OpEmptyMatch}
+	case min == 1 && max == 1:
+		return atom
+	case min == 0 && max == -1:
+		return &syntax.Regexp{Op: syntax.OpStar, Sub: []*syntax.Regexp{atom}}
+	case min == 1 && max == -1:
+		return &syntax.Regexp{Op: syntax.OpPlus, Sub: []*syntax.Regexp{atom}}
+	case min == 0 && max == 1:
+		return &syntax.Regexp{Op: syntax.OpQuest, Sub: []*syntax.Regexp{atom}}
+	default:
+		return &syntax.Regexp{Op: syntax.OpRepeat, Min: min, Max: max, Sub: []*syntax.Regexp{atom}}
+	}
+}
+
+// mergeRepeatAlternatives merges alternatives that repeat the same atom with counts
+// that together form a single range, such as (?:)|\d|\d\d+ into \d*. The empty
+// match merges with any atom repeated at most once, so that x|(?:) becomes x?.
+func mergeRepeatAlternatives(alternatives []*syntax.Regexp) []*syntax.Regexp {
+	type repetition struct {
+		atom     *syntax.Regexp
+		min, max int
+	}
+	repetitions := make([]repetition, len(alternatives))
+	for i, alternative := range alternatives {
+		atom, min, max := repeatOf(alternative)
+		repetitions[i] = repetition{atom, min, max}
+	}
+
+	merged := make([]bool, len(alternatives))
+	for changed := true; changed; {
+		changed = false
+		for i := range repetitions {
+			for j := i + 1; j < len(repetitions) && !merged[i]; j++ {
+				a, b := &repetitions[i], repetitions[j]
+				sameAtom := a.atom == nil || b.atom == nil || a.atom.Equal(b.atom)
+				adjacent := (a.max == -1 || b.min <= a.max+1) && (b.max == -1 || a.min <= b.max+1)
+				if merged[j] || !sameAtom || !adjacent || (a.atom != nil && hasCapture(a.atom)) || (b.atom != nil && hasCapture(b.atom)) {
+					continue
+				}
+				if a.atom == nil {
+					a.atom = b.atom
+				}
+				a.min = min(a.min, b.min)
+				if a.max != -1 {
+					a.max = max(a.max, b.max)
+					if b.max == -1 {
+						a.max = -1
+					}
+				}
+				merged[j], changed = true, true
+			}
+		}
+	}
+
+	var result []*syntax.Regexp
+	for i, r := range repetitions {
+		switch {
+		case merged[i]:
+			continue
+		case r.atom != nil && r.atom.Equal(alternatives[i]) && r.min == 1 && r.max == 1:
+			result = append(result, alternatives[i])
+		default:
+			result = append(result, repeatNode(r.atom, r.min, r.max))
+		}
+	}
+	return result
+}
+
+// foldConcatRepeats moves the pieces of a concatenation that repeat the atom of a
+// neighbouring quantified piece into its quantifier, e.g. X(?:X)* into X+.
+func foldConcatRepeats(pieces []*syntax.Regexp) []*syntax.Regexp {
+	pieces = append([]*syntax.Regexp(nil), pieces...)
+	for i := 0; i < len(pieces); i++ {
+		atom, min, max := repeatOf(pieces[i])
+		if atom == nil || (min == 1 && max == 1) || hasCapture(atom) {
+			continue
+		}
+
+		repeated := splitLiterals(concatPieces(atom))
+		k, folded := len(repeated), false
+		for i >= k && piecesEqual(pieces[i-k:i], repeated) {
+			pieces = append(pieces[:i-k], pieces[i:]...)
+			i -= k
+			min, max, folded = min+1, addCounts(max, 1), true
+		}
+		for i+k < len(pieces) && piecesEqual(pieces[i+1:i+1+k], repeated) {
+			pieces = append(pieces[:i+1], pieces[i+1+k:]...)
+			min, max, folded = min+1, addCounts(max, 1), true
+		}
+		if folded {
+			pieces[i] = repeatNode(atom, min, max)
+		}
+	}
+	return pieces
+}
+
+// piecesEqual reports whether two lists of subexpressions are equal.
+func piecesEqual(a, b []*syntax.Regexp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitLiterals splits the literals of a concatenation into one literal per rune.
+func splitLiterals(pieces []*syntax.Regexp) []*syntax.Regexp {
+	var result []*syntax.Regexp
+	for _, piece := range pieces {
+		if piece.Op != syntax.OpLiteral || len(piece.Rune) < 2 {
+			result = append(result, piece)
+			continue
+		}
+		for _, r := range piece.Rune {
+			result = append(result, &syntax.Regexp{Op: syntax.OpLiteral, Flags: piece.Flags, Rune: []rune{r}})
+		}
+	}
+	return result
+}
+
+// mergeLiterals joins adjacent literals of a concatenation that have the same flags.
+func mergeLiterals(pieces []*syntax.Regexp) []*syntax.Regexp {
+	var result []*syntax.Regexp
+	for _, piece := range pieces {
+		if n := len(result); n > 0 && piece.Op == syntax.OpLiteral && result[n-1].Op == syntax.OpLiteral && result[n-1].Flags == piece.Flags {
+			last := *result[n-1]
+			last.Rune = append(append([]rune(nil), last.Rune...), piece.Rune...)
+			result[n-1] = &last
+			continue
+		}
+		result = append(result, piece)
+	}
+	return result
+}
+
+// factorRuns replaces runs of adjacent alternatives sharing common pieces, as counted
+// by common, with the factored expression built by factor, when that is shorter.
+func factorRuns(subs []*syntax.Regexp, common func([]*syntax.Regexp) int, factor func([]*syntax.Regexp, int) *syntax.Regexp) []*syntax.Regexp {
+	var alternatives []*syntax.Regexp
+	for i := 0; i < len(subs); {
+		// Extend the run as long as the alternatives share pieces
+		end, shared := i+1, 0
+		for j := i + 1; j < len(subs); j++ {
+			n := common(subs[i : j+1])
+			if n == 0 {
+				break
+			}
+			// A whole alternation usually needs a group that its factored form does not
+			cost := printedLength(subs[i : j+1])
+			if i == 0 && j == len(subs)-1 {
+				cost += len("(?:)")
+			}
+			if len(printRegexp(factor(subs[i:j+1], n), false)) < cost {
+				end, shared = j+1, n
+			}
+		}
+
+		if shared == 0 {
+			alternatives = append(alternatives, subs[i])
+		} else {
+			alternatives = append(alternatives, factor(subs[i:end], shared))
+		}
+		i = end
+	}
+	return alternatives
+}
+
+// concatPieces returns the subexpressions of re as a concatenation.
+func concatPieces(re *syntax.Regexp) []*syntax.Regexp {
+	if re.Op == syntax.OpConcat {
+		return re.Sub
+	}
+	return []*syntax.Regexp{re}
+}
+
+// commonPrefix returns how many leading subexpressions all alternatives share.
+func commonPrefix(alternatives []*syntax.Regexp) int {
+	first := concatPieces(alternatives[0])
+	for n := 0; ; n++ {
+		for _, alternative := range alternatives {
+			pieces := concatPieces(alternative)
+			if n >= len(pieces) || !pieces[n].Equal(first[n]) {
+				return n
+			}
+		}
+	}
+}
+
+// commonSuffix returns how many trailing subexpressions all alternatives share.
+func commonSuffix(alternatives []*syntax.Regexp) int {
+	first := concatPieces(alternatives[0])
+	for n := 0; ; n++ {
+		for _, alternative := range alternatives {
+			pieces := concatPieces(alternative)
+			if n >= len(pieces) || !pieces[len(pieces)-1-n].Equal(first[len(first)-1-n]) {
+				return n
+			}
+		}
+	}
+}
+
+// factorPrefixRun returns prefix(?:rests) for alternatives that share their first n
+// subexpressions. An alternative made only of the prefix leaves an empty rest.
+func factorPrefixRun(alternatives []*syntax.Regexp, n int) *syntax.Regexp {
+	rests := &syntax.Regexp{Op: syntax.OpAlternate}
+	for _, alternative := range alternatives {
+		rests.Sub = append(rests.Sub, concatOf(concatPieces(alternative)[n:]))
+	}
+
+	pieces := append([]*syntax.Regexp(nil), concatPieces(alternatives[0])[:n]...)
+	return concatOf(append(pieces, rests))
+}
+
+// factorSuffixRun returns (?:prefixes)suffix for alternatives that share their last n
+// subexpressions. An alternative made only of the suffix leaves an empty prefix.
+func factorSuffixRun(alternatives []*syntax.Regexp, n int) *syntax.Regexp {
+	prefixes := &syntax.Regexp{Op: syntax.OpAlternate}
+	for _, alternative := range alternatives {
+		pieces := concatPieces(alternative)
+		prefixes.Sub = append(prefixes.Sub, concatOf(pieces[:len(pieces)-n]))
+	}
+
+	first := concatPieces(alternatives[0])
+	return concatOf(append([]*syntax.Regexp{prefixes}, first[len(first)-n:]...))
+}
+
+// concatOf returns the concatenation of pieces, simplified when there are less than two.
+func concatOf(pieces []*syntax.Regexp) *syntax.Regexp {
+	switch len(pieces) {
+	case 0:
+		return &syntax.Regexp{Op: syntax.OpEmptyMatch}
+	case 1:
+		return pieces[0]
+	default:
+		return &syntax.Regexp{Op: syntax.OpConcat, Sub: append([]*syntax.Regexp(nil), pieces...)}
+	}
+}
+
+// printedLength returns the printed length of alternatives joined by |.
+func printedLength(alternatives []*syntax.Regexp) int {
+	length := len(alternatives) - 1
+	for _, alternative := range alternatives {
+		length += len(printRegexp(alternative, false))
+	}
+	return length
+}
+
+// printRegexp prints a parsed pattern with as few groups as precedence allows. In
+// fold mode, the caller has opened a (?i:...) group.
+func printRegexp(re *syntax.Regexp, fold bool) string {
+	if opensFold(re, fold) {
+		return "(?i:" + printRegexp(re, true) + ")"
+	}
+
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return NEVER_MATCH
+	case syntax.OpEmptyMatch:
+		return "(?:)"
+	case syntax.OpLiteral:
+		return printLiteral(re.Rune, fold)
+	case syntax.OpCharClass:
+		return printCharClass(re.Rune, fold)
+	case syntax.OpAnyCharNotNL:
+		return "."
+	case syntax.OpAnyChar:
+		return "(?s:.)"
+	case syntax.OpBeginLine:
+		return "(?m:^)"
+	case syntax.OpEndLine:
+		return "(?m:$)"
+	case syntax.OpBeginText:
+		return "^"
+	case syntax.OpEndText:
+		if re.Flags&syntax.WasDollar != 0 {
+			return "$"
+		}
+		return `\z`
+	case syntax.OpWordBoundary:
+		return `\b`
+	case syntax.OpNoWordBoundary:
+		return `\B`
+	case syntax.OpCapture:
+		if re.Name != "" {
+			return "(?P<" + re.Name + ">" + printRegexp(re.Sub[0], fold) + ")"
+		}
+		return "(" + printRegexp(re.Sub[0], fold) + ")"
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		return printRepeat(re, fold)
+	case syntax.OpConcat:
+		var s strings.Builder
+		for _, sub := range re.Sub {
+			if sub.Op == syntax.OpAlternate && optionalAlternative(sub) == nil && !opensFold(sub, fold) {
+				s.WriteString("(?:" + printRegexp(sub, fold) + ")")
+			} else if sub.Op != syntax.OpEmptyMatch {
+				s.WriteString(printRegexp(sub, fold))
+			}
+		}
+		if s.Len() == 0 {
+			return "(?:)"
+		}
+		return s.String()
+	case syntax.OpAlternate:
+		if optional := optionalAlternative(re); optional != nil {
+			return printRepeat(optional, fold)
+		}
+		alternatives := make([]string, len(re.Sub))
+		for i, sub := range re.Sub {
+			if sub.Op != syntax.OpEmptyMatch {
+				alternatives[i] = printRegexp(sub, fold)
+			}
+		}
+		return strings.Join(alternatives, REGEX_OR)
+	default:
+		return re.String()
+	}
+}
+
+// optionalAlternative returns x? for x|(?:) and (?:)|x, which match the same
+// strings, or nil for other alternations.
+func optionalAlternative(re *syntax.Regexp) *syntax.Regexp {
+	if len(re.Sub) != 2 {
+		return nil
+	}
+	switch {
+	case re.Sub[1].Op == syntax.OpEmptyMatch && re.Sub[0].Op != syntax.OpEmptyMatch:
+		return &syntax.Regexp{Op: syntax.OpQuest, Sub: re.Sub[:1]}
+	case re.Sub[0].Op == syntax.OpEmptyMatch && re.Sub[1].Op != syntax.OpEmptyMatch:
+		return &syntax.Regexp{Op: syntax.OpQuest, Sub: re.Sub[1:]}
+	default:
+		return nil
+	}
+}
+
+// printRepeat prints a repetition with its shortest quantifier form.
+func printRepeat(re *syntax.Regexp, fold bool) string {
+	sub := re.Sub[0]
+	body := printRegexp(sub, fold)
+	atom := body
+	if !isAtom(sub) && !opensFold(sub, fold) {
+		atom = "(?:" + body + ")"
+	}
+	// A concatenation can be written several times in a row without a group
+	unit := atom
+	if sub.Op == syntax.OpConcat || sub.Op == syntax.OpLiteral {
+		unit = body
+	}
+
+	min, max := re.Min, re.Max
+	switch re.Op {
+	case syntax.OpStar:
+		min, max = 0, -1
+	case syntax.OpPlus:
+		min, max = 1, -1
+	case syntax.OpQuest:
+		min, max = 0, 1
+	}
+
+	var candidates []string
+	switch {
+	case min == 0 && max == -1:
+		candidates = append(candidates, atom+"*")
+	case min == 1 && max == -1:
+		candidates = append(candidates, atom+"+")
+	case min == 0 && max == 1:
+		candidates = append(candidates, atom+"?")
+	case max == -1:
+		candidates = append(candidates, atom+"{"+strconv.Itoa(min)+",}")
+	case min == max:
+		candidates = append(candidates, atom+"{"+strconv.Itoa(min)+"}")
+	default:
+		candidates = append(candidates, atom+"{"+strconv.Itoa(min)+","+strconv.Itoa(max)+"}")
+	}
+
+	// Repeating the atom itself is shorter for small counts, e.g. \d\d+ for \d{2,}.
+	// Captures cannot be repeated without changing the group numbers.
+	if !hasCapture(sub) {
+		switch {
+		case max == -1 && min > 1:
+			candidates = append(candidates, strings.Repeat(unit, min-1)+atom+"+")
+		case min == max && min > 0:
+			candidates = append(candidates, strings.Repeat(unit, min))
+		}
+	}
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if len(candidate) < len(best) {
+			best = candidate
+		}
+	}
+	return best
+}
+
+// opensFold reports whether printRegexp prints re inside its own (?i:...) group,
+// which a quantifier can follow and which needs no other group.
+func opensFold(re *syntax.Regexp, fold bool) bool {
+	return !fold && needsFold(re) && canFold(re)
+}
+
+// isAtom reports whether re prints as a single unit that a quantifier can follow.
+func isAtom(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune) == 1
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL, syntax.OpCapture, syntax.OpNoMatch:
+		return true
+	default:
+		return false
+	}
+}
+
+// hasCapture reports whether re contains a capturing group.
+func hasCapture(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpCapture {
+		return true
+	}
+	for _, sub := range re.Sub {
+		if hasCapture(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// needsFold reports whether re contains a case-insensitive literal with letters,
+// which can only be printed inside a (?i:...) group.
+func needsFold(re *syntax.Regexp) bool {
+	if re.Op == syntax.OpLiteral {
+		return re.Flags&syntax.FoldCase != 0 && hasLetter(re.Rune)
+	}
+	for _, sub := range re.Sub {
+		if needsFold(sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// canFold reports whether re can be printed inside a (?i:...) group: its literals
+// with letters are case-insensitive, and its classes are closed under case folding.
+func canFold(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return re.Flags&syntax.FoldCase != 0 || !hasLetter(re.Rune)
+	case syntax.OpCharClass:
+		_, ok := foldBase(re.Rune)
+		return ok
+	}
+	for _, sub := range re.Sub {
+		if !canFold(sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLetter reports whether runes contains a rune with case variants.
+func hasLetter(runes []rune) bool {
+	for _, r := range runes {
+		if unicode.SimpleFold(r) != r {
+			return true
+		}
+	}
+	return false
+}
+
+// printLiteral prints literal text, lowercased in fold mode.
+func printLiteral(runes []rune, fold bool) string {
+	var s strings.Builder
+	for _, r := range runes {
+		if fold {
+			r = unicode.ToLower(r)
+		}
+		switch {
+		case r < utf8RuneSelf && unicode.IsPrint(r):
+			s.WriteString(regexp.QuoteMeta(string(r)))
+		default:
+			s.WriteString(fmt.Sprintf(`\x{%x}`, r))
+		}
+	}
+	return s.String()
+}
+
+// utf8RuneSelf is the first rune printed as an escape.
+const utf8RuneSelf = 0x80
+
+// printCharClass prints a class given as sorted rune ranges, as a negated class when
+// that is shorter. In fold mode, case variants are left to the (?i:...) group.
+func printCharClass(ranges []rune, fold bool) string {
+	if fold {
+		if base, ok := foldBase(ranges); ok {
+			ranges = base
+		}
+	}
+	if len(ranges) == 0 {
+		return NEVER_MATCH
+	}
+	if len(ranges) == 2 && ranges[0] == ranges[1] {
+		return printLiteral([]rune{ranges[0]}, false)
+	}
+
+	class := classBody(ranges)
+	if class == `\d` {
+		return class
+	}
+	class = "[" + class + "]"
+	if negated := complementRanges(ranges); len(negated) > 0 {
+		if body := classBody(negated); body == `\d` {
+			return `\D`
+		} else if len(body)+3 < len(class) {
+			return "[^" + body + "]"
+		}
+	}
+	return class
+}
+
+// classBody prints the ranges of a class without brackets.
+func classBody(ranges []rune) string {
+	if rest, ok := withoutRune(ranges, '-'); ok {
+		// A leading hyphen needs no escape
+		return "-" + classBody(rest)
+	}
+
+	var s strings.Builder
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		switch {
+		case lo == '0' && hi == '9':
+			s.WriteString(`\d`)
+		case lo == hi:
+			s.WriteString(classRune(lo))
+		case hi == lo+1:
+			s.WriteString(classRune(lo) + classRune(hi))
+		default:
+			s.WriteString(classRune(lo) + "-" + classRune(hi))
+		}
+	}
+	return s.String()
+}
+
+// withoutRune returns ranges without r, and whether ranges contained r.
+func withoutRune(ranges []rune, r rune) ([]rune, bool) {
+	for i := 0; i < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		if r < lo || r > hi {
+			continue
+		}
+		rest := append([]rune(nil), ranges[:i]...)
+		if lo < r {
+			rest = append(rest, lo, r-1)
+		}
+		if r < hi {
+			rest = append(rest, r+1, hi)
+		}
+		return append(rest, ranges[i+2:]...), true
+	}
+	return ranges, false
+}
+
+// classRune prints a rune inside a character class.
+func classRune(r rune) string {
+	switch {
+	case strings.ContainsRune(`\]-[^`, r):
+		return `\` + string(r)
+	case r < utf8RuneSelf && unicode.IsPrint(r):
+		return string(r)
+	default:
+		return fmt.Sprintf(`\x{%x}`, r)
+	}
+}
+
+// complementRanges returns the ranges of the runes outside ranges.
+func complementRanges(ranges []rune) []rune {
+	var complement []rune
+	next := rune(0)
+	for i := 0; i < len(ranges); i += 2 {
+		if ranges[i] > next {
+			complement = append(complement, next, ranges[i]-1)
+		}
+		next = ranges[i+1] + 1
+	}
+	if next <= unicode.MaxRune {
+		complement = append(complement, next, unicode.MaxRune)
+	}
+	return complement
+}
+
+// FOLD_BASE_MAX_RUNES bounds the size of the classes foldBase examines.
+const FOLD_BASE_MAX_RUNES = 1024
+
+// foldBase returns the smallest class whose case folding gives ranges, keeping one
+// rune per set of case variants, preferably an ASCII lowercase letter. It returns
+// false when ranges is not closed under case folding or is too large to examine.
+func foldBase(ranges []rune) ([]rune, bool) {
+	members := map[rune]bool{}
+	for i := 0; i < len(ranges); i += 2 {
+		if len(members)+int(ranges[i+1]-ranges[i]) >= FOLD_BASE_MAX_RUNES {
+			return nil, false
+		}
+		for r := ranges[i]; r <= ranges[i+1]; r++ {
+			members[r] = true
+		}
+	}
+
+	var base []rune
+	for r := range members {
+		representative := r
+		for v := unicode.SimpleFold(r); v != r; v = unicode.SimpleFold(v) {
+			if !members[v] {
+				return nil, false
+			}
+			if foldPreferred(v, representative) {
+				representative = v
+			}
+		}
+		if representative == r {
+			base = append(base, r)
+		}
+	}
+
+	sort.Slice(base, func(i, j int) bool { return base[i] < base[j] })
+	var result []rune
+	for _, r := range base {
+		if n := len(result); n > 0 && result[n-1] == r-1 {
+			result[n-1] = r
+			continue
+		}
+		result = append(result, r, r)
+	}
+	return result, true
+}
+
+// foldPreferred reports whether case variant a represents its set better than b:
+// ASCII runes first, then lowercase ones, then the smallest.
+func foldPreferred(a, b rune) bool {
+	if (a < utf8RuneSelf) != (b < utf8RuneSelf) {
+		return a < utf8RuneSelf
+	}
+	if lowerA, lowerB := unicode.IsLower(a), unicode.IsLower(b); lowerA != lowerB {
+		return lowerA
+	}
+	return a < b
+}
@@ -0,0 +1,159 @@
+// Package convert provides tests for regex minimization functionality.
+// This file checks that minimized patterns are shorter and match exactly the same
+// versions as the patterns they were built from.
+package convert
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestMinimizePattern tests the individual rewrites of the optimization pass.
+func TestMinimizePattern(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		expected string
+	}{
+		{"common prefix", `^(?:1\.2\.3|1\.2\.4|1\.2\.5)$`, `^1\.2\.[3-5]$`},
+		{"common suffix", `^(?:[2-9](?:\.\d+)*|1\.[3-9](?:\.\d+)*)$`, `^(?:[2-9]|1\.[3-9])(?:\.\d+)*$`},
+		{"redundant groups", `^(?:(?:(?:1)))\.(?:2)$`, `^1\.2$`},
+		{"adjacent classes", `^(?:[a-c]|[d-f]|g)$`, `^[a-g]$`},
+		{"short quantifiers", `^\d{2,}\.\d{1}\.\d{0,1}$`, `^\d\d+\.\d\.\d?$`},
+		{"digit class", `^[0-9]+\.[^0-9]$`, `^\d+\.\D$`},
+		{"optional alternative", `^1(?:2|)$`, `^12?$`},
+		{"empty alternative first", `^(?:\d+|\d+\.\d+)$`, `^\d+(?:\.\d+)?$`},
+		{"optional before a digit", `^(?:|1)\d$`, `^1?\d$`},
+		{"nested quantifiers", `^[1-9](?:\d+)?$`, `^[1-9]\d*$`},
+		{"repetition alternatives", `^(?:|\d|\d\d+)$`, `^\d*$`},
+		{"repeated atom", `^\d(?:\d*)?$`, `^\d+$`},
+		{"repeated group", `^\.\d+(?:\.\d+)*$`, `^(?:\.\d+)+$`},
+		{"fixed repetition", `^1(?:\.\d+){2}$`, `^1\.\d+\.\d+$`},
+		{"case-insensitive alternation", `^[A-Z]-(?:(?i:ga)|(?i:final))$`, `^[A-Z]-(?i:ga|final)$`},
+		{"hyphen in class", `^[a-zA-Z0-9\-\.]+$`, `^[-.\dA-Za-z]+$`},
+		{"case-insensitive words", `^1(?:-(?i:alpha)|-(?i:beta))$`, `(?i:^1-(?:alpha|beta)$)`},
+		{"never match", `^` + NEVER_MATCH + `$`, `^` + NEVER_MATCH + `$`},
+		{"unparsable pattern kept", EMPTY_MATCH_PATTERN, EMPTY_MATCH_PATTERN},
+		{"named groups kept", `^(?P<major>\d+)(?:\.\d+|\.\d+\.\d+)$`, `^(?P<major>\d+)(?:\.\d+){1,2}$`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MinimizePattern(tt.pattern); got != tt.expected {
+				t.Errorf("MinimizePattern(%q) = %q, expected %q", tt.pattern, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMinimizeGeneratedPatterns tests the minimized patterns of real constraints.
+func TestMinimizeGeneratedPatterns(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+		expected   string
+	}{
+		{"~1", Options{Ecosystem: ECOSYSTEM_CARGO}, `1(?:\.(?:0|[1-9]\d*)){2}(?:\+[-\dA-Za-z]+(?:\.[-\dA-Za-z]+)*)?`},
+		{"~1", Options{Ecosystem: ECOSYSTEM_CARGO, LeadingZeros: LEADING_ZEROS_NUMERIC}, `0*1\.\d+\.\d+(?:\+[-\dA-Za-z]+(?:\.[-\dA-Za-z]+)*)?`},
+		{">=1.2.3", Options{}, `(?:[1-9]\d+|[2-9]|1\.(?:2\.)?(?:[1-9]\d+|[3-9]))(?:\.(?:0|[1-9]\d*))*(?:-[-.\dA-Za-z]+)?(?:\+[-.\dA-Za-z]+)?`},
+		{"<120.0.6099.109", Options{}, `(?:(?:[1-9]?|1[01])\d(?:\.(?:0|[1-9]\d*))*|120(?:\.0(?:\.(?:(?:\d|[1-9]\d{1,2}|[1-5]\d{3}|60(?:[0-8]\d|9[0-8]))(?:\.(?:0|[1-9]\d*))*|6099(?:\.(?:[1-9]?\d|10[0-8])(?:\.(?:0|[1-9]\d*))*)?))?)?)(?:-[-.\dA-Za-z]+)?(?:\+[-.\dA-Za-z]+)?`},
+		{"~> 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}, `2\.1\.(?:(?:[1-9]\d+|[4-9])(?:\.(?:0|[1-9]\d*))*(?:\.?[A-Za-z]+(?:\.?[\dA-Za-z]+)*)?|3(?:\.0)*(?:\.[1-9]\d*(?:\.(?:0|[1-9]\d*))*(?:\.?[A-Za-z]+(?:\.?[\dA-Za-z]+)*)?)?)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			got, err := VersionToPattern(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToPattern(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			if got != tt.expected {
+				t.Errorf("VersionToPattern(%q) = %q, expected %q", tt.constraint, got, tt.expected)
+			}
+		})
+	}
+}
+
+// minimizeCorpus lists versions of every ecosystem used to check that minimized
+// patterns match the same versions as the original ones.
+var minimizeCorpus = func() []string {
+	corpus := []string{
+		"", "v1.2.3", "1.2.3-alpha", "1.2.3-alpha.1", "1.2.3-rc.1+build.5", "1.2.3+build",
+		"1.0-SNAPSHOT", "1.0-20240115.103045-7", "1.0.Final", "1.0-alpha-1", "1.0-beta2",
+		"1.0-M1", "1.0-rc1", "1.0-sp1", "1.0.GA", "1.0-foo", "2.0-RC1", "1.0.0.rc1", "1.0a",
+		"dev-main", "2.x-dev", "1.0-dev", "1.0.p3", "1.0-patch1", "2024.10.1", "24.01",
+		"2024.10", "1.2.3.4567", "1.0.0-preview", "01.002.3", "1.2.3a",
+	}
+	for _, version := range rangeTestVersions(4, 3) {
+		corpus = append(corpus, formatParts(version))
+	}
+	for _, version := range []string{"9", "10", "11", "119", "120", "121", "6099", "6100"} {
+		corpus = append(corpus, version, "120.0."+version, "120.0.6099."+version, "1."+version+".0")
+	}
+	corpus = append(corpus, debianCorpus...)
+	return append(corpus, rpmCorpus...)
+}()
+
+// TestMinimizePatternEquivalence tests that minimizing the patterns of constraints of
+// every ecosystem keeps the versions they match and never makes them longer.
+func TestMinimizePatternEquivalence(t *testing.T) {
+	tests := []struct {
+		constraint string
+		opts       Options
+	}{
+		{">=1.2.3", Options{}},
+		{">1.2", Options{}},
+		{"<=1.2.3.4", Options{}},
+		{"<120.0.6099.109", Options{}},
+		{">=1.2.3", Options{VersionLength: LENGTH_STRICT}},
+		{"^1.2.3", Options{}},
+		{"~1.2.3", Options{}},
+		{"1.2.*", Options{}},
+		{"v1.2.3", Options{}},
+		{"1.2.3.4567", Options{}},
+		{"[1.0,2.0)", Options{}},
+		{"(,1.0],[1.2,)", Options{}},
+		{"[1.0-alpha-1,1.0]", Options{MavenSnapshots: SNAPSHOTS_EXCLUDE}},
+		{"~> 2.1, >= 2.1.3", Options{Ecosystem: ECOSYSTEM_RUBY}},
+		{"^1.2@beta || dev-main", Options{Ecosystem: ECOSYSTEM_COMPOSER}},
+		{">=1.0 <1.1 || >=1.2", Options{Ecosystem: ECOSYSTEM_COMPOSER}},
+		{"1.2.3", Options{Ecosystem: ECOSYSTEM_CARGO}},
+		{">=1.2.0-alpha, <1.5", Options{Ecosystem: ECOSYSTEM_CARGO}},
+		{">= 1:2.30-1ubuntu2, << 3.0", Options{Ecosystem: ECOSYSTEM_DEBIAN}},
+		{"<< 1.0~rc1 | >= 2.30-1ubuntu2", Options{Ecosystem: ECOSYSTEM_DEBIAN}},
+		{">= 1:2.30-1.el8, < 3.0", Options{Ecosystem: ECOSYSTEM_RPM}},
+		{"> 1.0^git1", Options{Ecosystem: ECOSYSTEM_RPM}},
+		{">=2024.10, <2025", Options{Ecosystem: ECOSYSTEM_CALVER, CalVerFormat: "YYYY.MM.MICRO"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraint, err := parseConstraint(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("parseConstraint(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			pattern, err := constraintToRegex(constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("constraintToRegex(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+
+			minimized := MinimizePattern(pattern)
+			if len(minimized) > len(pattern) {
+				t.Errorf("minimized pattern %q is longer than %q", minimized, pattern)
+			}
+			original := regexp.MustCompile(pattern)
+			optimized, err := regexp.Compile(minimized)
+			if err != nil {
+				t.Fatalf("minimized pattern %q does not compile: %v", minimized, err)
+			}
+
+			for _, version := range minimizeCorpus {
+				for _, candidate := range []string{version, strings.ToUpper(version)} {
+					if original.MatchString(candidate) != optimized.MatchString(candidate) {
+						t.Errorf("%q: original %q and minimized %q disagree on %q", tt.constraint, pattern, minimized, candidate)
+					}
+				}
+			}
+		})
+	}
+}
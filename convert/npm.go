@@ -0,0 +1,152 @@
+// Package convert provides npm version range handling functionality.
+// This file contains functions specific to the ranges of package.json files, as
+// interpreted by node-semver:
+//   - comparator sets separated by || are alternatives, and the comparators of a
+//     set, separated by spaces, must all hold
+//   - a bare version is exact, while a partial version is an x-range: 1.2 and 1.2.x
+//     mean >=1.2.0 <1.3.0
+//   - ^ and ~ follow the Cargo rules: ^0.2.3 means >=0.2.3 <0.3.0 and ~1 means <2.0.0
+//   - hyphen ranges such as 1.2 - 2.3 include their partial upper bound: <2.4.0
+//
+// Versions are SemVer 2.0 versions with exactly three components, and a pre-release
+// only matches when a comparator of the same set names a pre-release of the same
+// major.minor.patch: ^1.2.3-beta.1 matches 1.2.3-beta.2 but not 1.3.0-beta.1.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// npmOperatorSpaceRegex matches the spaces npm allows between an operator and its version.
+var npmOperatorSpaceRegex = regexp.MustCompile(`(>=|<=|>|<|=|\^|~>?)\s+`)
+
+// npmComparatorRegex splits a comparator into operator and version.
+var npmComparatorRegex = regexp.MustCompile(`^(>=|<=|>|<|=|\^|~>?)?=?v?(\S+)$`)
+
+// parseNpmRange parses an npm version range such as "^1.2.3 || >=2.1.0 <3".
+//
+// The returned constraint holds the normalized range, where x-ranges, hyphen ranges
+// and the ~> spelling of ~ become explicit comparators: "1.2.x || 2 - 3" is returned
+// as "=1.2 || >=2 <=3".
+func parseNpmRange(versionStr string) (*VersionConstraint, error) {
+	sets, err := parseNpmComparatorSets(versionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make([]string, len(sets))
+	for i, set := range sets {
+		comparators := make([]string, len(set))
+		for j, comparator := range set {
+			comparators[j] = comparator.String()
+		}
+		normalized[i] = strings.Join(comparators, " ")
+	}
+
+	return &VersionConstraint{
+		Operator: OP_NPM_RANGE,
+		Version:  strings.Join(normalized, " || "),
+	}, nil
+}
+
+// parseNpmComparatorSets splits a range into its comparator sets. An empty set
+// matches every release, like *.
+func parseNpmComparatorSets(versionRange string) ([][]cargoComparator, error) {
+	var sets [][]cargoComparator
+	for _, alternative := range strings.Split(versionRange, "||") {
+		fields := strings.Fields(npmOperatorSpaceRegex.ReplaceAllString(alternative, "$1"))
+		if len(fields) == 3 && fields[1] == "-" {
+			// A hyphen range includes every version of its partial upper bound
+			fields = []string{OP_GREATER_EQUAL + fields[0], OP_LESS_EQUAL + fields[2]}
+		}
+
+		set := []cargoComparator{}
+		for _, field := range fields {
+			comparator, err := parseNpmComparator(field)
+			if err != nil {
+				return nil, err
+			}
+			set = append(set, comparator)
+		}
+		if len(set) == 0 {
+			set = append(set, cargoComparator{operator: OP_EQUAL})
+		}
+		sets = append(sets, set)
+	}
+	return sets, nil
+}
+
+// parseNpmComparator parses one comparator. Wildcards drop the components after
+// them and keep the operator: >=1.2.x is >=1.2, and a bare partial version is an
+// = comparator on its components.
+func parseNpmComparator(field string) (cargoComparator, error) {
+	matches := npmComparatorRegex.FindStringSubmatch(field)
+	if matches == nil {
+		return cargoComparator{}, fmt.Errorf("invalid npm comparator: %q", field)
+	}
+	operator := matches[1]
+	switch operator {
+	case "":
+		operator = OP_EQUAL
+	case "~>":
+		operator = OP_TILDE
+	}
+
+	// Wildcards are only allowed after an operator when the version stays partial
+	comparator, err := parseCargoComparator("", matches[2])
+	if err != nil {
+		return cargoComparator{}, fmt.Errorf("invalid npm version: %s", matches[2])
+	}
+	if len(comparator.parts) == 0 && (operator == OP_LESS || operator == OP_GREATER) {
+		return cargoComparator{}, fmt.Errorf("npm comparator %s matches no version", field)
+	}
+	if len(comparator.parts) == 0 {
+		operator = OP_EQUAL
+	}
+	comparator.operator, comparator.wildcard = operator, false
+	return comparator, nil
+}
+
+// npmRangeRegex creates a regex matching the versions allowed by an npm range, with
+// optional build metadata.
+// Result: ^(?:1\.(?:...)...|2\.0\.0-beta\.1)(?:\+...)?$ matching 1.2.3, 1.9.0, 2.0.0-beta.1 but not 1.3.0-beta (for "^1.2.3 || 2.0.0-beta.1")
+func npmRangeRegex(versionRange string, opts Options) (string, error) {
+	sets, err := parseNpmComparatorSets(versionRange)
+	if err != nil {
+		return "", err
+	}
+
+	var alternatives []string
+	for _, set := range sets {
+		if pattern, ok := semverComparatorsPattern(set, opts); ok {
+			alternatives = append(alternatives, pattern)
+		}
+	}
+
+	pattern, ok := groupAlternatives(alternatives)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + SEMVER_BUILD_META_PATTERN + REGEX_END, nil
+}
+
+// explainNpmRange describes the versions allowed by an npm range.
+func explainNpmRange(versionRange string) (string, error) {
+	sets, err := parseNpmComparatorSets(versionRange)
+	if err != nil {
+		return "", err
+	}
+
+	var alternatives []string
+	for _, set := range sets {
+		if description := explainSemverComparators(set); description != "no version" {
+			alternatives = append(alternatives, description)
+		}
+	}
+	if len(alternatives) == 0 {
+		return "no version", nil
+	}
+	return strings.Join(alternatives, " or "), nil
+}
@@ -0,0 +1,111 @@
+// Package convert provides tests for npm version range handling functionality.
+// This file contains unit tests for range parsing and regex generation.
+package convert
+
+import (
+	"testing"
+)
+
+// TestParseNpmRange tests parsing and normalization of npm ranges.
+func TestParseNpmRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"1.2.3", false, "=1.2.3"},
+		{"^1.2.3-beta.1", false, "^1.2.3-beta.1"},
+		{">= 1.2.3 < 2", false, ">=1.2.3 <2"},
+		{"v1.2.x", false, "=1.2"},
+		{"~>1.2", false, "~1.2"},
+		{"1.2 - 2.3", false, ">=1.2 <=2.3"},
+		{"1.x || >=2.1.0 <3", false, "=1 || >=2.1.0 <3"},
+		{"", false, "*"},
+		{"<*", true, ""},
+		{"1.2.3.4", true, ""},
+		{"latest", true, ""},
+		{"01.2.3", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := parseNpmRange(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseNpmRange(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseNpmRange(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if constraint.Operator != OP_NPM_RANGE {
+				t.Errorf("expected operator %q, got %q", OP_NPM_RANGE, constraint.Operator)
+			}
+			if constraint.Version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, constraint.Version)
+			}
+		})
+	}
+}
+
+// TestNpmRangeRegex tests regex generation for npm ranges.
+func TestNpmRangeRegex(t *testing.T) {
+	tests := []struct {
+		requirement    string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			requirement:    "^1.2.3-beta.1",
+			shouldMatch:    []string{"1.2.3-beta.1", "1.2.3-beta.2", "1.2.3", "1.3.0", "1.9.9+build.1"},
+			shouldNotMatch: []string{"1.2.3-alpha", "1.3.0-beta.1", "2.0.0", "2.0.0-rc.1"},
+		},
+		{
+			requirement:    ">=1.2.3 <2",
+			shouldMatch:    []string{"1.2.3", "1.5.0", "1.99.0"},
+			shouldNotMatch: []string{"1.5.0.1", "1.5", "2.0.0-rc.1", "2.0.0", "1.2.2"},
+		},
+		{
+			requirement:    "1.2 - 2.3",
+			shouldMatch:    []string{"1.2.0", "2.0.0", "2.3.9"},
+			shouldNotMatch: []string{"1.1.9", "2.4.0", "2.3.0-beta"},
+		},
+		{
+			requirement:    "1.x || ~2.1.0",
+			shouldMatch:    []string{"1.0.0", "1.9.3", "2.1.0", "2.1.7"},
+			shouldNotMatch: []string{"0.9.0", "2.0.0", "2.2.0", "1.5.0-rc.1"},
+		},
+		{
+			requirement:    "*",
+			shouldMatch:    []string{"0.0.1", "10.20.30"},
+			shouldNotMatch: []string{"1.0.0-alpha", "1.0"},
+		},
+		{
+			requirement:    ">2.0.0 <2.0.1",
+			shouldMatch:    []string{},
+			shouldNotMatch: []string{"2.0.0", "2.0.1", "2.0.1-alpha"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requirement, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.requirement, Options{Ecosystem: ECOSYSTEM_NPM})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.requirement, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package convert provides conversion options for version constraints.
+// This file defines the Options type that adjusts how constraints are turned
+// into regular expressions, for the cases where one set of defaults does not
+// fit every ecosystem or use case.
+package convert
+
+// SnapshotPolicy decides how Maven snapshot versions are matched.
+type SnapshotPolicy int
+
+// Snapshot policies for Maven ranges
+const (
+	// SNAPSHOTS_INCLUDE matches snapshots wherever they sort inside the range (default)
+	SNAPSHOTS_INCLUDE SnapshotPolicy = iota
+	// SNAPSHOTS_EXCLUDE never matches snapshots, so only releases are selected
+	SNAPSHOTS_EXCLUDE
+	// SNAPSHOTS_ONLY matches nothing but snapshots, e.g. to select stale snapshot builds
+	SNAPSHOTS_ONLY
+)
+
+// LengthPolicy decides how comparison operators treat versions with a different
+// number of numeric components than the constraint version.
+type LengthPolicy int
+
+// Length policies for comparison operators
+const (
+	// LENGTH_PAD compares missing components as zeros, so >=1.2.3 matches 1.2.3.4
+	// and 1.3, and 1.2 equals 1.2.0 (default)
+	LENGTH_PAD LengthPolicy = iota
+	// LENGTH_STRICT only matches versions with as many components as the constraint
+	// version, so >=1.2.3 matches 1.3.0 but neither 1.3 nor 1.2.3.4
+	LENGTH_STRICT
+)
+
+// LeadingZeroPolicy decides how numeric version components written with leading
+// zeros, such as 01.002.3, are matched.
+type LeadingZeroPolicy int
+
+// Leading zero policies for numeric version components
+const (
+	// LEADING_ZEROS_REJECT never matches components with leading zeros, as SemVer
+	// requires, so no constraint matches 01.002.3 while 0 and 1.0.10 are still valid (default)
+	LEADING_ZEROS_REJECT LeadingZeroPolicy = iota
+	// LEADING_ZEROS_NUMERIC matches components with leading zeros by their numeric
+	// value, as Maven and Debian do, so 01.002.3 equals 1.2.3 and 009 is below 15
+	LEADING_ZEROS_NUMERIC
+)
+
+// Boundary decides what must surround the versions matched by a generated regex.
+type Boundary int
+
+// Boundaries of generated regexes
+const (
+	// BOUNDARY_ANCHORED only matches whole strings, from ^ to $ (default)
+	BOUNDARY_ANCHORED Boundary = iota
+	// BOUNDARY_WORD matches versions between word boundaries (\b), such as 1.2.3 in
+	// "released mylib 1.2.3 today". Dots and dashes are boundaries too, so 1.2.3 is
+	// found inside 1.2.3.4 and the -alpine of app-1.2.3-alpine is read as a pre-release;
+	// use BOUNDARY_DELIMITERS for such inputs
+	BOUNDARY_WORD
+	// BOUNDARY_DELIMITERS matches versions preceded by Options.DelimiterBefore and
+	// followed by Options.DelimiterAfter, such as 1.2.3 in "app:1.2.3-alpine"
+	BOUNDARY_DELIMITERS
+	// BOUNDARY_NONE matches versions anywhere in a string, with nothing around them
+	BOUNDARY_NONE
+)
+
+// Ecosystem selects the constraint syntax and version ordering rules of a package manager.
+type Ecosystem string
+
+// Supported ecosystems
+const (
+	// ECOSYSTEM_AUTO detects the constraint format from its syntax (default)
+	ECOSYSTEM_AUTO Ecosystem = ""
+	// ECOSYSTEM_RUBY parses RubyGems requirement lists such as '~> 2.1', '>= 2.1.3'
+	ECOSYSTEM_RUBY Ecosystem = "ruby"
+	// ECOSYSTEM_COMPOSER parses PHP Composer constraints such as ~1.2, ^1.2@beta || dev-main
+	ECOSYSTEM_COMPOSER Ecosystem = "composer"
+	// ECOSYSTEM_CARGO parses Rust Cargo requirements such as 1.2.3 (meaning ^1.2.3), >=1.2, <1.5
+	ECOSYSTEM_CARGO Ecosystem = "cargo"
+	// ECOSYSTEM_DEBIAN parses Debian version relations such as >= 1:2.30-1ubuntu2, << 3.0
+	// and orders versions like dpkg
+	ECOSYSTEM_DEBIAN Ecosystem = "debian"
+	// ECOSYSTEM_RPM parses RPM version requirements such as >= 1:2.30-1.el8, < 3.0
+	// and orders versions like rpmvercmp
+	ECOSYSTEM_RPM Ecosystem = "rpm"
+	// ECOSYSTEM_CALVER parses calendar version constraints such as >=2024.10, <2025
+	// for the format given in Options.CalVerFormat
+	ECOSYSTEM_CALVER Ecosystem = "calver"
+	// ECOSYSTEM_ADVISORY parses the vulnerable version ranges of GitHub Security
+	// Advisories and npm audit reports such as < 0.3.1 || >= 1.0.0, < 1.0.4
+	ECOSYSTEM_ADVISORY Ecosystem = "advisory"
+	// ECOSYSTEM_NPM parses npm ranges such as ^1.2.3, 1.2.x || >=2.0.0 <3, 1.2 - 2.3
+	// and orders pre-releases like node-semver
+	ECOSYSTEM_NPM Ecosystem = "npm"
+	// ECOSYSTEM_PYPI parses PEP 440 version specifiers such as ~=1.4.2, >=1.0, !=1.2.*
+	// and orders versions like pip, with epochs, post-releases and local labels
+	ECOSYSTEM_PYPI Ecosystem = "pypi"
+	// ECOSYSTEM_NUGET parses constraints like ECOSYSTEM_AUTO and matches exact versions
+	// as NuGet does, so 1.0-ALPHA and 1.0.0.0-alpha equal 1.0.0-alpha
+	ECOSYSTEM_NUGET Ecosystem = "nuget"
+)
+
+// Options adjusts how VersionToRegexWithOptions converts a version constraint.
+//
+// The zero value gives the same behavior as VersionToRegex, so callers only
+// need to set the fields they care about.
+//
+// Example:
+//
+//	// Select the snapshot builds older than 1.2.0
+//	opts := Options{MavenSnapshots: SNAPSHOTS_ONLY}
+//	regex, err := VersionToRegexWithOptions("(,1.2.0)", opts)
+type Options struct {
+	// Ecosystem selects ecosystem-specific constraint syntax and ordering rules.
+	// The default, ECOSYSTEM_AUTO, guesses the format from the constraint itself.
+	Ecosystem Ecosystem
+
+	// MavenSnapshots decides whether Maven ranges and exact versions, such as soft
+	// requirements, match snapshot versions.
+	//
+	// Snapshots are versions whose qualifiers end with -SNAPSHOT (1.2.3-SNAPSHOT,
+	// 1.2.3-RC2-SNAPSHOT) and the unique timestamped builds deployed to repositories
+	// (1.2.3-20240115.103045-7), which compare equal to their -SNAPSHOT base.
+	MavenSnapshots SnapshotPolicy
+
+	// VersionLength decides how the comparison operators (>=, >, <=, <) compare
+	// versions with a different number of components, such as 4-part .NET assembly
+	// versions (1.2.3.4) or Chrome versions (120.0.6099.109) against 1.2.3.
+	VersionLength LengthPolicy
+
+	// LeadingZeros decides whether the numeric release components of versions may
+	// have leading zeros (01.002.3), in which case they compare by value.
+	LeadingZeros LeadingZeroPolicy
+
+	// CalVerFormat is the version format used in CalVer mode, such as
+	// "YYYY.MM.MICRO" or "YY.0M". It joins calver.org components (YYYY, YY,
+	// 0Y, MM, 0M, WW, 0W, DD, 0D, MAJOR, MINOR, MICRO) with '.', '-' or '_'.
+	// Versions must follow the format, with zero-padding and date components
+	// within range (month 1-12, week 1-53, day 1-31).
+	CalVerFormat string
+
+	// Boundary decides whether the regex matches whole strings or finds versions
+	// inside larger ones, such as file names, Docker tags or log lines.
+	Boundary Boundary
+
+	// DelimiterBefore and DelimiterAfter are the regexes that must come right
+	// before and after versions with BOUNDARY_DELIMITERS, such as `(?:^|[:@/])`
+	// and `(?:$|[-/])`. Go regexes have no lookaround, so the delimiters are part
+	// of the match; an empty delimiter matches anything.
+	DelimiterBefore string
+	DelimiterAfter  string
+
+	// Prefix and Suffix are literal text around versions, such as "release-" or
+	// "-linux-amd64", for matching tag names rather than bare versions.
+	Prefix string
+	Suffix string
+
+	// TagTemplate is a tag name format such as "{name}@{version}" or "v{version}",
+	// where {version} stands for the versions matching the constraint and {name}
+	// for TagName. The rest of the template is literal text, inside Prefix and Suffix.
+	TagTemplate string
+
+	// TagName replaces the {name} placeholder of TagTemplate, such as a package
+	// name in the tags of a monorepo.
+	TagName string
+
+	// CaptureGroups wraps the components of matched versions in the named groups
+	// major, minor, patch, prerelease and build, so that a match both checks the
+	// constraint and extracts them. Debian, RPM and PyPI versions are not supported.
+	CaptureGroups bool
+}
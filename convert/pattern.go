@@ -0,0 +1,195 @@
+// Package convert provides embeddable pattern output for version constraints.
+// This file contains the functions that return the un-anchored core of a generated
+// regex and surround it with the boundary chosen in Options, so that versions can be
+// found inside larger strings such as file names, Docker tags or log lines.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// Placeholders of tag templates
+const (
+	// TAG_VERSION_PLACEHOLDER stands for the version in a tag template
+	TAG_VERSION_PLACEHOLDER = "{version}"
+	// TAG_NAME_PLACEHOLDER stands for Options.TagName in a tag template
+	TAG_NAME_PLACEHOLDER = "{name}"
+)
+
+// VersionToPattern converts a version constraint string to the un-anchored pattern
+// of the versions it matches, for embedding in larger regular expressions.
+//
+// The pattern has no anchors and no boundary, and is grouped where needed so that it
+// can be concatenated with other patterns as it is. With opts.CaptureGroups, the
+// components of matched versions are captured in the named groups major, minor,
+// patch, prerelease and build. The prefix, suffix and tag template of opts are part of
+// the pattern, while the Boundary and delimiter options are ignored.
+//
+// Parameters:
+//   - versionStr: The version constraint string to convert
+//   - opts: Conversion options, such as the ecosystem or the capture groups
+//
+// Returns:
+//   - string: Un-anchored pattern matching the versions that satisfy the constraint
+//   - error: Error if the constraint cannot be parsed or converted
+//
+// Example:
+//
+//	// Find the archives of mylib 1.x
+//	pattern, err := VersionToPattern("^1.2.0", Options{})
+//	if err != nil {
+//		return err
+//	}
+//
+//	archive := regexp.MustCompile(`^mylib-` + pattern + `\.tar\.gz$`)
+//	matches := archive.MatchString("mylib-1.4.2.tar.gz") // true
+func VersionToPattern(versionStr string, opts Options) (string, error) {
+	prefix, suffix, err := tagAffixes(opts)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse the version constraint
+	constraint, err := parseConstraint(versionStr, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse version constraint: %w", err)
+	}
+
+	// Convert to regex pattern
+	pattern, err := constraintToRegex(constraint, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert to regex: %w", err)
+	}
+
+	// Remove the anchors of the pattern
+	core, err := unanchoredRegexp(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to compile regex: %w", err)
+	}
+
+	if opts.CaptureGroups {
+		if err := checkCaptureGroups(constraint, opts); err != nil {
+			return "", err
+		}
+		if core, err = captureComponents(core); err != nil {
+			return "", fmt.Errorf("failed to capture version components: %w", err)
+		}
+	}
+
+	pattern = embeddablePattern(MinimizePattern(printRegexp(core, false)))
+	return regexp.QuoteMeta(prefix) + pattern + regexp.QuoteMeta(suffix), nil
+}
+
+// tagAffixes returns the literal text before and after versions in the tag names
+// described by opts.
+//
+// Examples:
+//   - Options{Prefix: "release-"} → "release-", ""
+//   - Options{TagTemplate: "{name}@{version}", TagName: "myproj"} → "myproj@", ""
+//   - Options{TagTemplate: "v{version}", Suffix: "-linux-amd64"} → "v", "-linux-amd64"
+func tagAffixes(opts Options) (string, string, error) {
+	if opts.TagTemplate == "" {
+		return opts.Prefix, opts.Suffix, nil
+	}
+
+	if strings.Count(opts.TagTemplate, TAG_VERSION_PLACEHOLDER) != 1 {
+		return "", "", fmt.Errorf("tag template %q must contain %s exactly once", opts.TagTemplate, TAG_VERSION_PLACEHOLDER)
+	}
+	if strings.Contains(opts.TagTemplate, TAG_NAME_PLACEHOLDER) && opts.TagName == "" {
+		return "", "", fmt.Errorf("tag template %q needs a tag name for %s", opts.TagTemplate, TAG_NAME_PLACEHOLDER)
+	}
+
+	before, after, _ := strings.Cut(opts.TagTemplate, TAG_VERSION_PLACEHOLDER)
+	before = strings.ReplaceAll(before, TAG_NAME_PLACEHOLDER, opts.TagName)
+	after = strings.ReplaceAll(after, TAG_NAME_PLACEHOLDER, opts.TagName)
+	return opts.Prefix + before, after + opts.Suffix, nil
+}
+
+// boundedPattern surrounds an un-anchored pattern with the boundary of opts.
+func boundedPattern(pattern string, opts Options) (string, error) {
+	switch opts.Boundary {
+	case BOUNDARY_ANCHORED:
+		return REGEX_START + pattern + REGEX_END, nil
+	case BOUNDARY_WORD:
+		return `\b` + pattern + `\b`, nil
+	case BOUNDARY_DELIMITERS:
+		return delimiterPattern(opts.DelimiterBefore) + pattern + delimiterPattern(opts.DelimiterAfter), nil
+	case BOUNDARY_NONE:
+		return pattern, nil
+	default:
+		return "", fmt.Errorf("unsupported boundary: %d", opts.Boundary)
+	}
+}
+
+// delimiterPattern groups a delimiter so that alternations in it stay apart from the version.
+func delimiterPattern(delimiter string) string {
+	if delimiter == "" {
+		return ""
+	}
+	return "(?:" + delimiter + ")"
+}
+
+// unanchoredRegexp parses a generated pattern and removes the ^ and $ anchors around
+// it, or around each of its alternatives.
+func unanchoredRegexp(pattern string) (*syntax.Regexp, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return unanchored(re), nil
+}
+
+// unanchored returns re without the anchors at its ends.
+func unanchored(re *syntax.Regexp) *syntax.Regexp {
+	switch re.Op {
+	case syntax.OpBeginText, syntax.OpEndText:
+		return &syntax.Regexp{Op: syntax.OpEmptyMatch}
+	case syntax.OpConcat:
+		pieces := re.Sub
+		if len(pieces) > 0 && pieces[0].Op == syntax.OpBeginText {
+			pieces = pieces[1:]
+		}
+		if len(pieces) > 0 && pieces[len(pieces)-1].Op == syntax.OpEndText {
+			pieces = pieces[:len(pieces)-1]
+		}
+		if len(pieces) == 1 {
+			return unanchored(pieces[0])
+		}
+		return concatOf(pieces)
+	case syntax.OpAlternate:
+		alternatives := make([]*syntax.Regexp, len(re.Sub))
+		for i, sub := range re.Sub {
+			alternatives[i] = unanchored(sub)
+		}
+		return &syntax.Regexp{Op: syntax.OpAlternate, Flags: re.Flags, Sub: alternatives}
+	default:
+		return re
+	}
+}
+
+// embeddablePattern groups a top-level alternation, so that the pattern can be
+// concatenated with others.
+func embeddablePattern(pattern string) string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err == nil && re.Op == syntax.OpAlternate {
+		return "(?:" + pattern + ")"
+	}
+	return pattern
+}
+
+// checkCaptureGroups returns an error for the constraints whose versions do not
+// have major, minor and patch components.
+func checkCaptureGroups(constraint *VersionConstraint, opts Options) error {
+	switch constraint.Operator {
+	case OP_DEBIAN_RELATION, OP_RPM_REQUIREMENT, OP_PEP440_SPECIFIERS:
+		return fmt.Errorf("capture groups are not supported for %s versions", opts.Ecosystem)
+	case OP_CALVER_CONSTRAINT:
+		if strings.ContainsAny(opts.CalVerFormat, "-_") {
+			return fmt.Errorf("capture groups are only supported for CalVer formats separated by dots, got %q", opts.CalVerFormat)
+		}
+	}
+	return nil
+}
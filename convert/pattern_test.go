@@ -0,0 +1,186 @@
+// Package convert provides tests for embeddable pattern functionality.
+// This file checks un-anchored patterns and the boundaries that can surround them.
+package convert
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestVersionToPattern tests that un-anchored patterns find versions inside larger strings.
+func TestVersionToPattern(t *testing.T) {
+	tests := []struct {
+		constraint     string
+		opts           Options
+		prefix         string
+		suffix         string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{"^1.2.0", Options{}, `mylib-`, `\.tar\.gz`, []string{"mylib-1.2.0.tar.gz", "mylib-1.9.3.tar.gz"}, []string{"mylib-2.0.0.tar.gz", "mylib-0.9.0.tar.gz"}},
+		{">=1.2.3", Options{}, `app:`, `-alpine`, []string{"app:1.2.3-alpine", "app:10.0.0-alpine"}, []string{"app:1.2.2-alpine", "other:1.2.3-alpine"}},
+		{"1.2.3 || 2.0.0", Options{Ecosystem: ECOSYSTEM_COMPOSER}, `version `, ` started`, []string{"version 1.2.3 started", "version 2.0.0 started"}, []string{"version 1.2.4 started"}},
+		{"[1.0,2.0)", Options{}, `lib-`, `\.jar`, []string{"lib-1.5.jar", "lib-1.5-SNAPSHOT.jar"}, []string{"lib-2.0.jar", "lib-0.9.jar"}},
+		{"~1.2", Options{}, `v`, ``, []string{"v1.2.5"}, []string{"v1.3.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			pattern, err := VersionToPattern(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToPattern(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			regex, err := regexp.Compile(`^` + tt.prefix + pattern + tt.suffix + `$`)
+			if err != nil {
+				t.Fatalf("pattern %q cannot be embedded: %v", pattern, err)
+			}
+			for _, s := range tt.shouldMatch {
+				if !regex.MatchString(s) {
+					t.Errorf("regex %q should match %q", regex, s)
+				}
+			}
+			for _, s := range tt.shouldNotMatch {
+				if regex.MatchString(s) {
+					t.Errorf("regex %q should not match %q", regex, s)
+				}
+			}
+		})
+	}
+}
+
+// TestVersionToPatternErrors tests the constraints that cannot be turned into patterns.
+func TestVersionToPatternErrors(t *testing.T) {
+	for _, constraint := range []string{"[invalid", ">=abc", "!=1.2.3"} {
+		if _, err := VersionToPattern(constraint, Options{}); err == nil {
+			t.Errorf("VersionToPattern(%q) should return an error", constraint)
+		}
+	}
+}
+
+// TestBoundaries tests the boundaries that surround the versions of generated regexes.
+func TestBoundaries(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		input    string
+		expected string
+	}{
+		{"anchored", Options{}, "1.2.5", "1.2.5"},
+		{"anchored rejects text", Options{}, "mylib 1.2.5", ""},
+		{"word", Options{Boundary: BOUNDARY_WORD}, "released mylib 1.2.5 today", "1.2.5"},
+		{"word inside word", Options{Boundary: BOUNDARY_WORD}, "mylib1.2.5", ""},
+		{"word inside 4-part version", Options{Boundary: BOUNDARY_WORD}, "1.2.5.4", "1.2.5"},
+		{"word before dash", Options{Boundary: BOUNDARY_WORD}, "app-1.2.5-alpine", "1.2.5-alpine"},
+		{"delimiters reject 4-part version", Options{Boundary: BOUNDARY_DELIMITERS, DelimiterBefore: `^|\s`, DelimiterAfter: `\s|$`}, "1.2.5.4", ""},
+		{"delimiters", Options{Boundary: BOUNDARY_DELIMITERS, DelimiterBefore: `:`, DelimiterAfter: `@|$`}, "app:1.2.5@sha256", ":1.2.5@"},
+		{"delimiters at end", Options{Boundary: BOUNDARY_DELIMITERS, DelimiterBefore: `:`, DelimiterAfter: `@|$`}, "app:1.2.5-alpine", ":1.2.5-alpine"},
+		{"delimiters not found", Options{Boundary: BOUNDARY_DELIMITERS, DelimiterBefore: `:`, DelimiterAfter: `@|$`}, "app-1.2.5", ""},
+		{"empty delimiters", Options{Boundary: BOUNDARY_DELIMITERS}, "app-1.2.5", "1.2.5"},
+		{"none", Options{Boundary: BOUNDARY_NONE}, "app1.2.5x", "1.2.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions("~1.2", tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions returned unexpected error: %v", err)
+			}
+			if got := regex.FindString(tt.input); got != tt.expected {
+				t.Errorf("regex %q found %q in %q, expected %q", regex, got, tt.input, tt.expected)
+			}
+		})
+	}
+
+	if _, err := VersionToRegexWithOptions("~1.2", Options{Boundary: Boundary(42)}); err == nil {
+		t.Error("VersionToRegexWithOptions should return an error for an unknown boundary")
+	}
+}
+
+// TestTagAffixes tests regexes over tag names with prefixes, suffixes and templates.
+func TestTagAffixes(t *testing.T) {
+	tests := []struct {
+		name           string
+		constraint     string
+		opts           Options
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{"prefix", ">=1.2.3", Options{Prefix: "release-"}, []string{"release-1.2.3", "release-2.0.0"}, []string{"1.2.3", "release-1.2.2", "release1.2.3"}},
+		{"prefix and suffix", "^1.2.3", Options{Prefix: "v", Suffix: "-linux-amd64"}, []string{"v1.2.3-linux-amd64", "v1.9.0-rc1-linux-amd64"}, []string{"v1.2.3", "v2.0.0-linux-amd64", "v1.2.3-linux-arm64"}},
+		{"literal affixes", "~1.2", Options{Prefix: "app.", Suffix: "+x"}, []string{"app.1.2.5+x"}, []string{"appX1.2.5+x", "app.1.2.5x"}},
+		{"template", "~1.2", Options{TagTemplate: "{name}@{version}", TagName: "myproj"}, []string{"myproj@1.2.0", "myproj@1.2.9"}, []string{"other@1.2.0", "myproj@1.3.0", "myproj-1.2.0"}},
+		{"scoped name", "^2.0.0", Options{TagTemplate: "{name}@{version}", TagName: "@scope/pkg"}, []string{"@scope/pkg@2.1.0"}, []string{"@scope/pkg@1.0.0", "@scopeXpkg@2.1.0"}},
+		{"template with prefix and suffix", "1.2.3", Options{TagTemplate: "v{version}", Prefix: "refs/tags/", Suffix: "^{}"}, []string{"refs/tags/v1.2.3^{}"}, []string{"v1.2.3", "refs/tags/v1.2.4^{}"}},
+		{"capture groups", ">=1.0", Options{TagTemplate: "{name}-v{version}", TagName: "core", CaptureGroups: true}, []string{"core-v1.4.0"}, []string{"core-1.4.0", "core-v0.9"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.constraint, tt.opts)
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			for _, s := range tt.shouldMatch {
+				if !regex.MatchString(s) {
+					t.Errorf("regex %q should match %q", regex, s)
+				}
+			}
+			for _, s := range tt.shouldNotMatch {
+				if regex.MatchString(s) {
+					t.Errorf("regex %q should not match %q", regex, s)
+				}
+			}
+		})
+	}
+
+	captured, err := VersionToRegexWithOptions(">=1.0", Options{TagTemplate: "{name}-v{version}", TagName: "core", CaptureGroups: true})
+	if err != nil {
+		t.Fatalf("VersionToRegexWithOptions returned unexpected error: %v", err)
+	}
+	if components, ok := ExtractComponents(captured, "core-v1.4.0"); !ok || components != (VersionComponents{Major: "1", Minor: "4", Patch: "0"}) {
+		t.Errorf("ExtractComponents(%q, %q) = %+v, %v", captured, "core-v1.4.0", components, ok)
+	}
+}
+
+// TestTagAffixesErrors tests invalid tag templates.
+func TestTagAffixesErrors(t *testing.T) {
+	for _, opts := range []Options{
+		{TagTemplate: "release"},
+		{TagTemplate: "{version}-{version}"},
+		{TagTemplate: "{name}@{version}"},
+	} {
+		if _, err := VersionToPattern("1.2.3", opts); err == nil {
+			t.Errorf("VersionToPattern with tag template %q should return an error", opts.TagTemplate)
+		}
+	}
+}
+
+// TestUnanchoredRegexp tests the removal of anchors from generated patterns.
+func TestUnanchoredRegexp(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{`^1\.2\.3$`, `1\.2\.3`},
+		{`^1\.2$|^2\.0$`, `1\.2|2\.0`},
+		{`^(?:1|23)\.0$`, `(?:1|23)\.0`},
+		{`^$`, `(?:)`},
+		{`1\.2`, `1\.2`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := unanchoredRegexp(tt.pattern)
+			if err != nil {
+				t.Fatalf("unanchoredRegexp(%q) returned unexpected error: %v", tt.pattern, err)
+			}
+			if got := printRegexp(re, false); got != tt.expected {
+				t.Errorf("unanchoredRegexp(%q) = %q, expected %q", tt.pattern, got, tt.expected)
+			}
+		})
+	}
+
+	if got := embeddablePattern(`1|23`); got != `(?:1|23)` {
+		t.Errorf("embeddablePattern(%q) = %q, expected %q", `1|23`, got, `(?:1|23)`)
+	}
+}
@@ -0,0 +1,737 @@
+// Package convert provides PEP 440 version handling functionality.
+// This file contains functions specific to the version specifiers of Python
+// packages ('~=1.4.2', '>=1.0, !=1.2.*') and to the ordering rules of PEP 440, as
+// implemented by pip and the packaging library.
+//
+// A PEP 440 version is made of an optional epoch (1!), release segments, and optional
+// pre-release (a1, b2, rc3), post-release (.post1) and development release (.dev1)
+// parts, followed by an optional local label (+ubuntu1). Versions compare by epoch,
+// then release segments ignoring trailing zeros, then 1.0.dev1 < 1.0a1.dev1 < 1.0a1 <
+// 1.0a1.post1 < 1.0b1 < 1.0rc1 < 1.0 < 1.0.post1.dev1 < 1.0.post1.
+//
+// Specifiers follow the matching rules of PEP 440 rather than the plain order:
+//   - local labels are ignored, except that >V never matches a local version of V
+//   - <V never matches a pre-release of V, and >V a post-release of V, unless V is one
+//   - ==1.2.* matches every version whose release starts with 1.2, pre-releases included
+//   - ~=1.4.2 means >=1.4.2, ==1.4.*
+//
+// Pre-releases are matched like other versions, as pip does for installed ones.
+// Versions are matched in their normalized form (1.0a1, 1.0.post1, 1!2.0+local).
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// PEP440_LOCAL_PATTERN matches the normalized local label of a version
+// Format: +ubuntu1, +cpu.2024
+const PEP440_LOCAL_PATTERN = `\+[a-z0-9]+(?:\.[a-z0-9]+)*`
+
+// PEP440_AFTER_RELEASE is the suffix of a bound placed after every pre-release,
+// post-release and development release of its release segments, which no PEP 440
+// version can spell
+const PEP440_AFTER_RELEASE = "~"
+
+// pep440SpecifierRegex splits a version specifier into operator and version.
+var pep440SpecifierRegex = regexp.MustCompile(`^(~=|===|==|!=|<=|>=|<|>)\s*(\S+)$`)
+
+// pep440VersionRegex parses a version in any spelling PEP 440 accepts, such as
+// 1.0, v1!2.0.0-rc.1, 1.0-1 or 1.0.post2.dev3+local.
+var pep440VersionRegex = regexp.MustCompile(`(?i)^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(alpha|beta|preview|pre|a|b|c|rc)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+// pep440SuffixRegex parses a normalized suffix such as rc1.post2.dev3.
+var pep440SuffixRegex = regexp.MustCompile(`^(?:(a|b|rc)(\d+))?(?:\.post(\d+))?(?:\.dev(\d+))?$`)
+
+// Phases of PEP 440 suffixes, in their order
+const (
+	// pep440DevRelease is a development release of a final release, such as 1.0.dev1
+	pep440DevRelease = iota
+	pep440Alpha
+	pep440Beta
+	pep440Candidate
+	// pep440Final is a final release, or one of its post-releases
+	pep440Final
+)
+
+// pep440PhaseLabels holds the normalized label of each phase.
+var pep440PhaseLabels = []string{"", "a", "b", "rc", ""}
+
+// pep440Suffix is the part of a version that follows its release segments.
+type pep440Suffix struct {
+	phase int
+	// pre is the pre-release number, for the alpha, beta and candidate phases
+	pre string
+	// post and dev are the post-release and development release numbers, or empty
+	post string
+	dev  string
+}
+
+// pep440Version is a parsed and normalized PEP 440 version.
+type pep440Version struct {
+	epoch   string
+	release []string
+	suffix  pep440Suffix
+	local   string
+}
+
+// parsePEP440Version parses a version in any spelling PEP 440 accepts.
+func parsePEP440Version(version string) (pep440Version, error) {
+	matches := pep440VersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return pep440Version{}, fmt.Errorf("invalid PEP 440 version: %s", version)
+	}
+
+	v := pep440Version{epoch: "0", local: strings.ToLower(matches[10])}
+	if matches[1] != "" {
+		v.epoch = trimDecimal(matches[1])
+	}
+	for _, part := range strings.Split(matches[2], ".") {
+		v.release = append(v.release, trimDecimal(part))
+	}
+
+	switch strings.ToLower(matches[3]) {
+	case "":
+		v.suffix.phase = pep440Final
+	case "a", "alpha":
+		v.suffix.phase = pep440Alpha
+	case "b", "beta":
+		v.suffix.phase = pep440Beta
+	default:
+		v.suffix.phase = pep440Candidate
+	}
+	if v.suffix.phase != pep440Final {
+		v.suffix.pre = optionalDecimal(matches[4])
+	}
+	if matches[5] != "" || matches[6] != "" {
+		v.suffix.post = optionalDecimal(matches[5] + matches[7])
+	}
+	if matches[8] != "" {
+		v.suffix.dev = optionalDecimal(matches[9])
+		if v.suffix.phase == pep440Final && v.suffix.post == "" {
+			v.suffix.phase = pep440DevRelease
+		}
+	}
+	return v, nil
+}
+
+// optionalDecimal normalizes a number that defaults to 0 when it is omitted.
+func optionalDecimal(n string) string {
+	if n == "" {
+		return "0"
+	}
+	return trimDecimal(n)
+}
+
+// isPrerelease reports whether v is a pre-release or a development release.
+func (v pep440Version) isPrerelease() bool {
+	return v.suffix.dev != "" || (v.suffix.phase != pep440Final && v.suffix.phase != pep440DevRelease)
+}
+
+// bound returns the inclusive range bound of v, whose first component is the epoch.
+func (v pep440Version) bound() *rangeBound {
+	return &rangeBound{parts: append([]string{v.epoch}, v.release...), suffix: v.suffix.String(), inclusive: true}
+}
+
+// releaseBound returns a bound at the release segments of v with the given suffix.
+func (v pep440Version) releaseBound(release []string, suffix string, inclusive bool) *rangeBound {
+	return &rangeBound{parts: append([]string{v.epoch}, release...), suffix: suffix, inclusive: inclusive}
+}
+
+// String formats the suffix in its normalized form, e.g. rc1.post2.dev3.
+func (s pep440Suffix) String() string {
+	text := pep440PhaseLabels[s.phase] + s.pre
+	if s.post != "" {
+		text += ".post" + s.post
+	}
+	if s.dev != "" {
+		text += ".dev" + s.dev
+	}
+	return text
+}
+
+// parsePEP440Suffix parses a normalized suffix.
+func parsePEP440Suffix(s string) pep440Suffix {
+	matches := pep440SuffixRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return pep440Suffix{phase: pep440Final}
+	}
+
+	suffix := pep440Suffix{phase: pep440Final, pre: matches[2], post: matches[3], dev: matches[4]}
+	switch matches[1] {
+	case "a":
+		suffix.phase = pep440Alpha
+	case "b":
+		suffix.phase = pep440Beta
+	case "rc":
+		suffix.phase = pep440Candidate
+	default:
+		if suffix.post == "" && suffix.dev != "" {
+			suffix.phase = pep440DevRelease
+		}
+	}
+	return suffix
+}
+
+// comparePEP440Suffixes orders two suffixes: by phase and pre-release number, then
+// without post-release before post-releases, then development releases before the
+// version without one.
+func comparePEP440Suffixes(a, b pep440Suffix) int {
+	if c := compareInts(a.phase, b.phase); c != 0 {
+		return c
+	}
+	if a.pre != "" && b.pre != "" {
+		if c := compareDecimals(a.pre, b.pre); c != 0 {
+			return c
+		}
+	}
+	if c := compareOptionalDecimals(a.post, b.post, -1); c != 0 {
+		return c
+	}
+	return compareOptionalDecimals(a.dev, b.dev, 1)
+}
+
+// compareOptionalDecimals compares two numbers, where an empty one sorts first when
+// missing is -1 and last when it is 1.
+func compareOptionalDecimals(a, b string, missing int) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return missing
+	case b == "":
+		return -missing
+	default:
+		return compareDecimals(a, b)
+	}
+}
+
+// parsePEP440Specifiers parses comma-separated PEP 440 version specifiers such as
+// '~=1.4.2', '>=1.0, !=1.2.*'.
+//
+// The returned constraint holds the normalized specifiers, e.g. "~=1.4.2, !=1.2.*".
+func parsePEP440Specifiers(versionStr string) (*VersionConstraint, error) {
+	if _, _, err := pep440SpecifierRanges(versionStr); err != nil {
+		return nil, err
+	}
+
+	var specifiers []string
+	for _, specifier := range strings.Split(versionStr, ",") {
+		if specifier = strings.TrimSpace(specifier); specifier != "" {
+			matches := pep440SpecifierRegex.FindStringSubmatch(specifier)
+			specifiers = append(specifiers, matches[1]+matches[2])
+		}
+	}
+
+	return &VersionConstraint{
+		Operator: OP_PEP440_SPECIFIERS,
+		Version:  strings.Join(specifiers, ", "),
+	}, nil
+}
+
+// pep440SpecifierRanges returns the ranges of the public versions, without local
+// label, that satisfy every specifier, and the ranges of the public parts of the
+// local versions that do. Empty specifiers match every version.
+func pep440SpecifierRanges(specifiers string) ([]versionRange, []versionRange, error) {
+	order := pep440SuffixOrder{}
+	public, local := []versionRange{{}}, []versionRange{{}}
+	for _, specifier := range strings.Split(specifiers, ",") {
+		specifier = strings.TrimSpace(specifier)
+		if specifier == "" {
+			continue
+		}
+		matches := pep440SpecifierRegex.FindStringSubmatch(specifier)
+		if matches == nil {
+			return nil, nil, fmt.Errorf("invalid version specifier: %q", specifier)
+		}
+
+		publicRanges, localRanges, err := pep440Ranges(matches[1], matches[2])
+		if err != nil {
+			return nil, nil, err
+		}
+		public = intersectRanges(public, publicRanges, order)
+		local = intersectRanges(local, localRanges, order)
+	}
+	return public, local, nil
+}
+
+// pep440Ranges returns the ranges of public versions and of the public parts of local
+// versions that satisfy a single specifier.
+func pep440Ranges(operator, version string) ([]versionRange, []versionRange, error) {
+	if operator == "===" {
+		return nil, nil, fmt.Errorf("unsupported arbitrary equality: %s%s", operator, version)
+	}
+
+	prefix, isPrefix := strings.CutSuffix(version, ".*")
+	if isPrefix {
+		version = prefix
+	}
+	v, err := parsePEP440Version(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	if v.local != "" {
+		return nil, nil, fmt.Errorf("unsupported local version in specifier: %s%s", operator, version)
+	}
+
+	if isPrefix {
+		// ==1.2.* matches the releases from 1.2.dev0, the first version of 1.2, up to 1.3.dev0
+		if operator != "==" && operator != "!=" {
+			return nil, nil, fmt.Errorf("prefix match requires == or !=: %s%s.*", operator, version)
+		}
+		if v.suffix.phase != pep440Final || v.suffix.post != "" {
+			return nil, nil, fmt.Errorf("prefix match requires release segments: %s.*", version)
+		}
+		lower, upper := v.seriesBounds(v.release)
+		if operator == "==" {
+			ranges := []versionRange{{lower: lower, upper: upper}}
+			return ranges, ranges, nil
+		}
+		lower.inclusive, upper.inclusive = false, true
+		ranges := []versionRange{{upper: lower}, {lower: upper}}
+		return ranges, ranges, nil
+	}
+
+	bound := v.bound()
+	exclusive := *bound
+	exclusive.inclusive = false
+	// Versions whose release segments are greater than those of v
+	laterReleases := []versionRange{{lower: v.releaseBound(v.release, PEP440_AFTER_RELEASE, false)}}
+
+	switch operator {
+	case "==":
+		ranges := []versionRange{{lower: bound, upper: bound}}
+		return ranges, ranges, nil
+	case "!=":
+		ranges := []versionRange{{upper: &exclusive}, {lower: &exclusive}}
+		return ranges, ranges, nil
+	case ">=":
+		ranges := []versionRange{{lower: bound}}
+		return ranges, ranges, nil
+	case "<=":
+		ranges := []versionRange{{upper: bound}}
+		return ranges, ranges, nil
+	case ">":
+		// >V excludes the post-releases of V unless V is one, and every local version of V
+		switch {
+		case v.suffix.post != "":
+			return []versionRange{{lower: &exclusive}}, laterReleases, nil
+		case v.isPrerelease():
+			return nil, nil, fmt.Errorf("unsupported exclusive comparison with a pre-release: %s%s", operator, version)
+		default:
+			return laterReleases, laterReleases, nil
+		}
+	case "<":
+		// <V excludes the pre-releases of V unless V is one
+		switch {
+		case v.isPrerelease():
+			ranges := []versionRange{{upper: &exclusive}}
+			return ranges, ranges, nil
+		case v.suffix.post != "":
+			return nil, nil, fmt.Errorf("unsupported exclusive comparison with a post-release: %s%s", operator, version)
+		default:
+			ranges := []versionRange{{upper: v.releaseBound(v.release, ".dev0", false)}}
+			return ranges, ranges, nil
+		}
+	default: // ~=
+		// ~=1.4.2 means >=1.4.2, ==1.4.*
+		if len(v.release) < 2 {
+			return nil, nil, fmt.Errorf("compatible release requires two release segments: %s%s", operator, version)
+		}
+		_, upper := v.seriesBounds(v.release[:len(v.release)-1])
+		ranges := []versionRange{{lower: bound, upper: upper}}
+		return ranges, ranges, nil
+	}
+}
+
+// seriesBounds returns the bounds of the versions whose release starts with the given
+// segments: from the first development release of the series, included, to the first
+// development release of the next one, excluded.
+func (v pep440Version) seriesBounds(release []string) (*rangeBound, *rangeBound) {
+	next := append([]string(nil), release...)
+	next[len(next)-1] = incrementDecimal(next[len(next)-1])
+	return v.releaseBound(release, ".dev0", true), v.releaseBound(next, ".dev0", false)
+}
+
+// pep440SpecifiersRegex creates a regex matching the normalized versions that satisfy
+// every PEP 440 version specifier.
+// Result: ^(?:(?:0!)?1\.4\.(?:...))(?:\+...)?$ matching 1.4.2, 1.4.9.post1, 1.4.5+local but not 1.5 or 1.4.2rc1 (for "~=1.4.2")
+func pep440SpecifiersRegex(specifiers string, opts Options) (string, error) {
+	public, local, err := pep440SpecifierRanges(specifiers)
+	if err != nil {
+		return "", err
+	}
+	return pep440RangesRegex(public, local, opts), nil
+}
+
+// pep440IntervalsRegex creates a regex matching the normalized versions inside any of
+// the intervals, in the order of PEP 440: a local version such as 1.0+ubuntu1 comes
+// right after its public version, so it is inside an interval when its public version
+// is at or above the lower bound and below the upper bound.
+func pep440IntervalsRegex(intervals []VersionInterval, opts Options) (string, error) {
+	public, err := intervalRanges(intervals, parsePEP440Bound, pep440SuffixOrder{})
+	if err != nil {
+		return "", err
+	}
+
+	local := make([]versionRange, len(public))
+	for i, r := range public {
+		if r.lower != nil {
+			local[i].lower = &rangeBound{parts: r.lower.parts, suffix: r.lower.suffix, inclusive: true}
+		}
+		if r.upper != nil {
+			local[i].upper = &rangeBound{parts: r.upper.parts, suffix: r.upper.suffix, inclusive: false}
+		}
+	}
+	return pep440RangesRegex(public, local, opts), nil
+}
+
+// parsePEP440Bound parses a public PEP 440 version into an inclusive range bound.
+func parsePEP440Bound(version string) (*rangeBound, error) {
+	v, err := parsePEP440Version(version)
+	if err != nil {
+		return nil, err
+	}
+	if v.local != "" {
+		return nil, fmt.Errorf("unsupported local version: %s", version)
+	}
+	return v.bound(), nil
+}
+
+// pep440RangesRegex creates an anchored regex matching the public versions inside the
+// public ranges and the local versions whose public part is inside the local ones.
+func pep440RangesRegex(public, local []versionRange, opts Options) string {
+	var alternatives []string
+	if pattern, ok := pep440RangesPattern(public, opts.LeadingZeros); ok {
+		alternatives = append(alternatives, pattern)
+	}
+	if pattern, ok := pep440RangesPattern(local, opts.LeadingZeros); ok {
+		alternatives = append(alternatives, pattern+PEP440_LOCAL_PATTERN)
+	}
+
+	pattern, ok := groupAlternatives(alternatives)
+	if !ok {
+		return NEVER_MATCH_PATTERN
+	}
+	return REGEX_START + pattern + REGEX_END
+}
+
+// pep440RangesPattern returns the un-anchored pattern of the versions inside any of
+// the ranges, whose bounds start with the epoch.
+func pep440RangesPattern(ranges []versionRange, zeros LeadingZeroPolicy) (string, bool) {
+	order := pep440SuffixOrder{zeros: zeros}
+	var alternatives []string
+	for _, r := range ranges {
+		if isEmptyRange(r, order) {
+			continue
+		}
+		alternatives = append(alternatives, pep440RangePattern(r, order)...)
+	}
+	return groupAlternatives(alternatives)
+}
+
+// pep440RangePattern returns alternatives for the versions inside r, split by epoch:
+// the epoch of each bound followed by the release segments and suffixes allowed by
+// the bound, and the epochs in between followed by any version.
+func pep440RangePattern(r versionRange, order pep440SuffixOrder) []string {
+	builder := seqBuilder{order: order, zeros: order.zeros}
+	release := func(b *rangeBound) *rangeBound {
+		if b == nil {
+			return nil
+		}
+		return &rangeBound{parts: b.parts[1:], suffix: b.suffix, inclusive: b.inclusive}
+	}
+	withEpoch := func(epoch string, r versionRange) []string {
+		if pattern, ok := builder.rangePattern(r); ok {
+			return []string{order.epoch(epoch) + pattern}
+		}
+		return nil
+	}
+
+	if r.lower != nil && r.upper != nil && compareDecimals(r.lower.parts[0], r.upper.parts[0]) == 0 {
+		return withEpoch(r.lower.parts[0], versionRange{lower: release(r.lower), upper: release(r.upper)})
+	}
+
+	var alternatives []string
+	first, last := "0", ""
+	if r.lower != nil {
+		alternatives = append(alternatives, withEpoch(r.lower.parts[0], versionRange{lower: release(r.lower)})...)
+		first = incrementDecimal(r.lower.parts[0])
+	}
+	if r.upper != nil {
+		if r.upper.parts[0] != "0" {
+			last = decrementDecimal(r.upper.parts[0])
+		} else {
+			first = ""
+		}
+	}
+	if epochs, ok := order.epochs(first, last); ok {
+		anyVersion, _ := builder.rangePattern(versionRange{})
+		alternatives = append(alternatives, epochs+anyVersion)
+	}
+	if r.upper != nil {
+		alternatives = append(alternatives, withEpoch(r.upper.parts[0], versionRange{upper: release(r.upper)})...)
+	}
+	return alternatives
+}
+
+// pep440SuffixOrder implements suffixOrder for the normalized suffixes of PEP 440
+// versions: pre-releases, post-releases and development releases.
+type pep440SuffixOrder struct {
+	zeros LeadingZeroPolicy
+}
+
+// pep440Window is one active bound of pep440SuffixOrder.window.
+type pep440Window struct {
+	suffix    pep440Suffix
+	inclusive bool
+}
+
+// epoch matches the given epoch, which may be left out when it is zero.
+func (o pep440SuffixOrder) epoch(epoch string) string {
+	if compareDecimals(epoch, "0") == 0 {
+		return "(?:" + o.zeros.Equal("0") + "!)?"
+	}
+	return o.zeros.Equal(epoch) + "!"
+}
+
+// epochs matches the epochs from first to last (empty for no limit), or reports
+// false when there is none.
+func (o pep440SuffixOrder) epochs(first, last string) (string, bool) {
+	if first == "" || (last != "" && compareDecimals(first, last) > 0) {
+		return "", false
+	}
+	pattern := o.number(first, last)
+	if compareDecimals(first, "0") == 0 {
+		return "(?:" + pattern + "!)?", true
+	}
+	return pattern + "!", true
+}
+
+// number matches the numbers from lo to hi (empty for no limit).
+func (o pep440SuffixOrder) number(lo, hi string) string {
+	if hi == "" {
+		return o.zeros.GreaterOrEqual(lo)
+	}
+	return o.zeros.Range(lo, hi)
+}
+
+// compare orders two suffixes. PEP440_AFTER_RELEASE sorts after every suffix.
+func (o pep440SuffixOrder) compare(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == PEP440_AFTER_RELEASE:
+		return 1
+	case b == PEP440_AFTER_RELEASE:
+		return -1
+	}
+	return comparePEP440Suffixes(parsePEP440Suffix(a), parsePEP440Suffix(b))
+}
+
+// any matches every normalized suffix.
+// Result: (?:(?:a|b|rc)(?:0|[1-9]\d*))?(?:\.post(?:0|[1-9]\d*))?(?:\.dev(?:0|[1-9]\d*))?
+func (o pep440SuffixOrder) any() string {
+	digits := o.zeros.Digits()
+	return `(?:(?:a|b|rc)` + digits + `)?(?:\.post` + digits + `)?(?:\.dev` + digits + `)?`
+}
+
+// atLeast matches suffixes ordered after s (or equal to it when inclusive).
+func (o pep440SuffixOrder) atLeast(s string, inclusive bool) (string, bool) {
+	if s == PEP440_AFTER_RELEASE {
+		return "", false
+	}
+	return groupAlternatives(o.window(&pep440Window{parsePEP440Suffix(s), inclusive}, nil))
+}
+
+// atMost matches suffixes ordered before s (or equal to it when inclusive).
+func (o pep440SuffixOrder) atMost(s string, inclusive bool) (string, bool) {
+	if s == PEP440_AFTER_RELEASE {
+		return o.any(), true
+	}
+	return groupAlternatives(o.window(nil, &pep440Window{parsePEP440Suffix(s), inclusive}))
+}
+
+// between matches suffixes ordered between lo and hi.
+func (o pep440SuffixOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	switch {
+	case lo == PEP440_AFTER_RELEASE:
+		return "", false
+	case hi == PEP440_AFTER_RELEASE:
+		return o.atLeast(lo, loInclusive)
+	}
+	return groupAlternatives(o.window(&pep440Window{parsePEP440Suffix(lo), loInclusive}, &pep440Window{parsePEP440Suffix(hi), hiInclusive}))
+}
+
+// window returns alternatives for the suffixes between the bounds lo and hi (nil
+// means unbounded), phase by phase.
+func (o pep440SuffixOrder) window(lo, hi *pep440Window) []string {
+	var alternatives []string
+	for phase := pep440DevRelease; phase <= pep440Final; phase++ {
+		if (lo != nil && phase < lo.suffix.phase) || (hi != nil && phase > hi.suffix.phase) {
+			continue
+		}
+		// Bounds in other phases no longer restrict this one
+		var phaseLo, phaseHi *pep440Window
+		if lo != nil && lo.suffix.phase == phase {
+			phaseLo = lo
+		}
+		if hi != nil && hi.suffix.phase == phase {
+			phaseHi = hi
+		}
+
+		switch phase {
+		case pep440DevRelease:
+			// 1.0.dev1: a development release number only
+			alternatives = append(alternatives, o.devs(phaseLo, phaseHi, true, false)...)
+		case pep440Final:
+			// 1.0 or 1.0.post1.dev2: a development release needs a post-release
+			alternatives = append(alternatives, o.posts(phaseLo, phaseHi, false)...)
+		default:
+			alternatives = append(alternatives, o.prereleases(phase, phaseLo, phaseHi)...)
+		}
+	}
+	return alternatives
+}
+
+// prereleases returns alternatives for the pre-releases of a phase between lo and hi,
+// which are nil or bounds in that phase.
+func (o pep440SuffixOrder) prereleases(phase int, lo, hi *pep440Window) []string {
+	label := pep440PhaseLabels[phase]
+	if lo != nil && hi != nil && compareDecimals(lo.suffix.pre, hi.suffix.pre) == 0 {
+		return prefixAlternatives(label+o.zeros.Equal(lo.suffix.pre), o.posts(lo, hi, true))
+	}
+
+	var alternatives []string
+	first := "0"
+	if lo != nil {
+		alternatives = append(alternatives, prefixAlternatives(label+o.zeros.Equal(lo.suffix.pre), o.posts(lo, nil, true))...)
+		first = incrementDecimal(lo.suffix.pre)
+	}
+	if hi == nil {
+		alternatives = append(alternatives, label+o.number(first, "")+o.anyPosts())
+	} else {
+		if compareDecimals(first, hi.suffix.pre) < 0 {
+			alternatives = append(alternatives, label+o.number(first, decrementDecimal(hi.suffix.pre))+o.anyPosts())
+		}
+		alternatives = append(alternatives, prefixAlternatives(label+o.zeros.Equal(hi.suffix.pre), o.posts(nil, hi, true))...)
+	}
+	return alternatives
+}
+
+// posts returns alternatives for the post-release and development release parts
+// between lo and hi, whose phase and pre-release number are the current ones.
+// Development releases without post-release are only allowed after a pre-release.
+func (o pep440SuffixOrder) posts(lo, hi *pep440Window, prerelease bool) []string {
+	var alternatives []string
+
+	// Without post-release, which sorts before every post-release
+	if lo == nil || lo.suffix.post == "" {
+		devHi := hi
+		if hi != nil && hi.suffix.post != "" {
+			devHi = nil
+		}
+		alternatives = append(alternatives, o.devs(lo, devHi, prerelease, true)...)
+	}
+	if hi != nil && hi.suffix.post == "" {
+		return alternatives
+	}
+
+	// With a post-release number
+	post := func(n string) string { return `\.post` + o.zeros.Equal(n) }
+	if lo != nil && lo.suffix.post != "" && hi != nil && compareDecimals(lo.suffix.post, hi.suffix.post) == 0 {
+		return append(alternatives, prefixAlternatives(post(lo.suffix.post), o.devs(lo, hi, true, true))...)
+	}
+	first := "0"
+	if lo != nil && lo.suffix.post != "" {
+		alternatives = append(alternatives, prefixAlternatives(post(lo.suffix.post), o.devs(lo, nil, true, true))...)
+		first = incrementDecimal(lo.suffix.post)
+	}
+	anyDev := `(?:\.dev` + o.zeros.Digits() + `)?`
+	if hi == nil {
+		alternatives = append(alternatives, `\.post`+o.number(first, "")+anyDev)
+	} else {
+		if compareDecimals(first, hi.suffix.post) < 0 {
+			alternatives = append(alternatives, `\.post`+o.number(first, decrementDecimal(hi.suffix.post))+anyDev)
+		}
+		alternatives = append(alternatives, prefixAlternatives(post(hi.suffix.post), o.devs(nil, hi, true, true))...)
+	}
+	return alternatives
+}
+
+// devs returns alternatives for the development release parts between lo and hi,
+// whose other parts are the current ones: development release numbers when withDevs
+// is set, and the version without one, which sorts last, when withRelease is set.
+func (o pep440SuffixOrder) devs(lo, hi *pep440Window, withDevs, withRelease bool) []string {
+	var alternatives []string
+
+	if withDevs && (lo == nil || lo.suffix.dev != "") {
+		first, last, ok := "0", "", true
+		if lo != nil {
+			first = lo.suffix.dev
+			if !lo.inclusive {
+				first = incrementDecimal(first)
+			}
+		}
+		if hi != nil && hi.suffix.dev != "" {
+			switch {
+			case hi.inclusive:
+				last = hi.suffix.dev
+			case hi.suffix.dev == "0":
+				ok = false
+			default:
+				last = decrementDecimal(hi.suffix.dev)
+			}
+		}
+		if ok && (last == "" || compareDecimals(first, last) <= 0) {
+			alternatives = append(alternatives, `\.dev`+o.number(first, last))
+		}
+	}
+
+	lowerAllows := lo == nil || lo.suffix.dev != "" || lo.inclusive
+	upperAllows := hi == nil || (hi.suffix.dev == "" && hi.inclusive)
+	if withRelease && lowerAllows && upperAllows {
+		alternatives = append(alternatives, "")
+	}
+	return alternatives
+}
+
+// anyPosts matches any post-release and development release parts of a pre-release.
+func (o pep440SuffixOrder) anyPosts() string {
+	digits := o.zeros.Digits()
+	return `(?:\.post` + digits + `)?(?:\.dev` + digits + `)?`
+}
+
+// prefixAlternatives prepends prefix to a non-empty set of alternatives, grouping them.
+func prefixAlternatives(prefix string, alternatives []string) []string {
+	if pattern, ok := groupAlternatives(alternatives); ok {
+		return []string{prefix + pattern}
+	}
+	return nil
+}
+
+// explainPEP440Specifiers describes the versions allowed by PEP 440 version specifiers.
+func explainPEP440Specifiers(specifiers string) (string, error) {
+	public, _, err := pep440SpecifierRanges(specifiers)
+	if err != nil {
+		return "", err
+	}
+	return explainRanges(public, pep440SuffixOrder{}, pep440BoundText), nil
+}
+
+// pep440BoundText formats a PEP 440 bound, with its epoch when it is not zero.
+func pep440BoundText(b *rangeBound) string {
+	text := strings.Join(b.parts[1:], ".")
+	if b.parts[0] != "0" {
+		text = b.parts[0] + "!" + text
+	}
+	if b.suffix == PEP440_AFTER_RELEASE {
+		return text + " and its post-releases"
+	}
+	return text + b.suffix
+}
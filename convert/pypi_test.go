@@ -0,0 +1,145 @@
+// Package convert provides tests for PEP 440 version handling functionality.
+// This file contains unit tests for specifier parsing and regex generation.
+package convert
+
+import (
+	"testing"
+)
+
+// TestParsePEP440Specifiers tests parsing and normalization of PEP 440 specifiers.
+func TestParsePEP440Specifiers(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{">=1.0", false, ">=1.0"},
+		{"~= 1.4.2, != 1.4.5", false, "~=1.4.2, !=1.4.5"},
+		{"!=1.2.*", false, "!=1.2.*"},
+		{">=1!0.5", false, ">=1!0.5"},
+		{"<2.0rc1", false, "<2.0rc1"},
+		{"", false, ""},
+		{"1.0", true, ""},
+		{"~=1", true, ""},
+		{">=1.2.*", true, ""},
+		{"==1.0rc1.*", true, ""},
+		{"===1.0", true, ""},
+		{"==1.0+local", true, ""},
+		{">1.0rc1", true, ""},
+		{"<1.0.post1", true, ""},
+		{">=1.0,", false, ">=1.0"},
+		{">=banana", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := parsePEP440Specifiers(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parsePEP440Specifiers(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePEP440Specifiers(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if constraint.Operator != OP_PEP440_SPECIFIERS {
+				t.Errorf("expected operator %q, got %q", OP_PEP440_SPECIFIERS, constraint.Operator)
+			}
+			if constraint.Version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, constraint.Version)
+			}
+		})
+	}
+}
+
+// TestPEP440SpecifiersRegex tests regex generation for PEP 440 specifiers.
+func TestPEP440SpecifiersRegex(t *testing.T) {
+	tests := []struct {
+		specifiers     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			specifiers:     ">=1.0",
+			shouldMatch:    []string{"1.0", "1", "1.0.0", "1.0.post1", "1.0+local", "1.1a1", "2.0", "1!0.1"},
+			shouldNotMatch: []string{"0.9", "1.0rc1", "1.0.dev1", "1.0a1.post2"},
+		},
+		{
+			specifiers:     "~=1.4.2",
+			shouldMatch:    []string{"1.4.2", "1.4.2.post1", "1.4.9", "1.4.3a1", "1.4.2+cpu"},
+			shouldNotMatch: []string{"1.4.1", "1.4.2rc1", "1.5", "1.5.dev0", "2.0"},
+		},
+		{
+			specifiers:     "<2.0",
+			shouldMatch:    []string{"1.9", "1.9.post3", "1.9.9+local", "0!1.0"},
+			shouldNotMatch: []string{"2.0", "2.0rc1", "2.0.dev1", "2.0a1.post1", "1!0.1"},
+		},
+		{
+			specifiers:     "<2.0rc1",
+			shouldMatch:    []string{"1.9", "2.0b3", "2.0rc1.dev2", "2.0.dev1"},
+			shouldNotMatch: []string{"2.0rc1", "2.0rc2", "2.0"},
+		},
+		{
+			specifiers:     ">1.0",
+			shouldMatch:    []string{"1.0.1", "1.0.1+local", "1.1a1"},
+			shouldNotMatch: []string{"1.0", "1.0.post1", "1.0+local", "1.0.post1+local"},
+		},
+		{
+			specifiers:     ">1.0.post1",
+			shouldMatch:    []string{"1.0.post2", "1.0.post2.dev1", "1.0.1"},
+			shouldNotMatch: []string{"1.0.post1", "1.0.post1+local", "1.0"},
+		},
+		{
+			specifiers:     ">=1!0.5",
+			shouldMatch:    []string{"1!0.5", "1!0.5.post1", "1!2.0", "2!0.1"},
+			shouldNotMatch: []string{"1!0.4", "0.9", "5.0"},
+		},
+		{
+			specifiers:     "==1.2.*",
+			shouldMatch:    []string{"1.2", "1.2.0.dev0", "1.2rc1", "1.2.5.post1", "1.2.3+local"},
+			shouldNotMatch: []string{"1.1.9", "1.3.dev0", "1.3"},
+		},
+		{
+			specifiers:     ">=1.0, !=1.2.*",
+			shouldMatch:    []string{"1.0", "1.1.9", "1.3.dev0", "1.3"},
+			shouldNotMatch: []string{"0.9", "1.2", "1.2.5", "1.2rc1", "1.2.0.dev0"},
+		},
+		{
+			specifiers:     ">=1.0a2.post1.dev3, <1.0b2",
+			shouldMatch:    []string{"1.0a2.post1.dev3", "1.0a2.post1", "1.0a3.dev1", "1.0b1.post4", "1.0b2.dev0"},
+			shouldNotMatch: []string{"1.0a2", "1.0a2.post1.dev2", "1.0b2", "1.0"},
+		},
+		{
+			specifiers:     "==1.0",
+			shouldMatch:    []string{"1.0", "1.0.0", "1", "1.0+ubuntu.1"},
+			shouldNotMatch: []string{"1.0.post1", "1.0rc1", "1.0.1"},
+		},
+		{
+			specifiers:     ">=2.0, <1.0",
+			shouldMatch:    []string{},
+			shouldNotMatch: []string{"1.0", "2.0", "1.5"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.specifiers, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.specifiers, Options{Ecosystem: ECOSYSTEM_PYPI})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.specifiers, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,407 @@
+// Package convert provides version range handling functionality.
+// This file contains the generic machinery that turns intervals of versions into
+// regex patterns. Versions are split into numeric release components, compared
+// component by component, and a trailing suffix (pre-release label, qualifier, ...)
+// whose ordering is supplied by the ecosystem through a suffixOrder.
+package convert
+
+import (
+	"strconv"
+	"strings"
+)
+
+// rangeBound is one end of a versionRange.
+type rangeBound struct {
+	// parts holds the numeric release components as decimal strings without leading
+	// zeros (e.g., [1 2 3] for 1.2.3), so components may exceed int
+	parts []string
+	// suffix is the ecosystem-specific text that follows the release components
+	suffix string
+	// inclusive reports whether the bound itself belongs to the range
+	inclusive bool
+}
+
+// versionRange is a contiguous interval of versions. A nil bound is unbounded.
+type versionRange struct {
+	lower *rangeBound
+	upper *rangeBound
+}
+
+// suffixOrder describes how an ecosystem orders and spells the suffixes of versions
+// whose release components are equal.
+//
+// Every pattern returned by a suffixOrder must be self-contained (alternations
+// wrapped in a group) so it can be concatenated after the release components.
+// A false ok result means that no suffix satisfies the request.
+type suffixOrder interface {
+	// compare orders two suffixes, returning -1, 0 or 1
+	compare(a, b string) int
+	// any matches every suffix
+	any() string
+	// atLeast matches suffixes ordered after s, and s itself when inclusive
+	atLeast(s string, inclusive bool) (pattern string, ok bool)
+	// atMost matches suffixes ordered before s, and s itself when inclusive
+	atMost(s string, inclusive bool) (pattern string, ok bool)
+	// between matches suffixes ordered between lo and hi
+	between(lo string, loInclusive bool, hi string, hiInclusive bool) (pattern string, ok bool)
+}
+
+// unorderedSuffixOrder implements suffixOrder for versions whose suffixes are allowed
+// but not compared: every pre-release and build suffix is equal to every other one.
+type unorderedSuffixOrder struct{}
+
+// compare treats all suffixes as equal.
+func (o unorderedSuffixOrder) compare(a, b string) int { return 0 }
+
+// any matches an optional pre-release and build metadata suffix.
+func (o unorderedSuffixOrder) any() string { return VERSION_SUFFIX_PATTERN }
+
+// atLeast matches every suffix when inclusive, since all suffixes are equal.
+func (o unorderedSuffixOrder) atLeast(s string, inclusive bool) (string, bool) {
+	return o.any(), inclusive
+}
+
+// atMost matches every suffix when inclusive, since all suffixes are equal.
+func (o unorderedSuffixOrder) atMost(s string, inclusive bool) (string, bool) {
+	return o.any(), inclusive
+}
+
+// between matches every suffix when both bounds are inclusive.
+func (o unorderedSuffixOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	return o.any(), loInclusive && hiInclusive
+}
+
+// seqBuilder generates regex patterns for versionRanges over dot-separated numeric
+// release components.
+//
+// When fixed is 0, versions may have any number of components and missing trailing
+// components compare as zero (1.0 == 1.0.0). Otherwise versions must have exactly
+// fixed components. Release components are matched by value under the zeros policy.
+type seqBuilder struct {
+	fixed int
+	order suffixOrder
+	zeros LeadingZeroPolicy
+}
+
+// rangesPattern returns the un-anchored pattern matching any of the given ranges.
+// It returns false when the ranges are all empty.
+func (b seqBuilder) rangesPattern(ranges []versionRange) (string, bool) {
+	var alternatives []string
+	for _, r := range ranges {
+		if pattern, ok := b.rangePattern(r); ok {
+			alternatives = append(alternatives, pattern)
+		}
+	}
+	return groupAlternatives(alternatives)
+}
+
+// rangePattern returns the un-anchored pattern matching the versions inside r.
+func (b seqBuilder) rangePattern(r versionRange) (string, bool) {
+	switch {
+	case r.lower == nil && r.upper == nil:
+		return b.zeros.Digits() + b.anyTail(1) + b.order.any(), true
+	case r.upper == nil:
+		eq, eqOK := b.order.atLeast(r.lower.suffix, r.lower.inclusive)
+		return groupAlternatives(b.atLeast(b.normalize(r.lower.parts), 0, eq, eqOK))
+	case r.lower == nil:
+		eq, eqOK := b.order.atMost(r.upper.suffix, r.upper.inclusive)
+		return groupAlternatives(b.atMost(b.normalize(r.upper.parts), 0, eq, eqOK))
+	}
+
+	if compareBounds(r.lower, r.upper, b.order) > 0 {
+		return "", false
+	}
+	return groupAlternatives(b.between(b.normalize(r.lower.parts), b.normalize(r.upper.parts), 0, r))
+}
+
+// normalize pads or trims release components to the builder's length rules.
+// Trailing zeros are dropped when versions may have any number of components.
+func (b seqBuilder) normalize(parts []string) []string {
+	if b.fixed == 0 {
+		end := len(parts)
+		for end > 1 && parts[end-1] == "0" {
+			end--
+		}
+		return parts[:end]
+	}
+	normalized := make([]string, b.fixed)
+	for i := range normalized {
+		normalized[i] = componentAt(parts, i)
+	}
+	return normalized
+}
+
+// atLeast returns alternatives for the components from index u on that are
+// greater than parts[u:], followed by eq when they are equal.
+func (b seqBuilder) atLeast(parts []string, u int, eq string, eqOK bool) []string {
+	if u >= len(parts) {
+		if eqOK && eq == b.order.any() {
+			return []string{b.anyTail(u) + eq}
+		}
+		var alternatives []string
+		if eqOK {
+			alternatives = append(alternatives, b.zeroTail(u)+eq)
+		}
+		return append(alternatives, b.nonZeroTails(u)...)
+	}
+	// Zeros are the smallest components, so every component is allowed when any suffix is
+	if eqOK && eq == b.order.any() && compareParts(parts[u:], nil) == 0 {
+		if u == 0 {
+			return []string{b.zeros.Digits() + b.anyTail(1) + eq}
+		}
+		return []string{b.anyTail(u) + eq}
+	}
+
+	value := parts[u]
+	alternatives := []string{b.sep(u) + b.zeros.GreaterOrEqual(incrementDecimal(value)) + b.anyTail(u+1) + b.order.any()}
+	if rest, ok := groupAlternatives(b.atLeast(parts, u+1, eq, eqOK)); ok {
+		alternatives = append(alternatives, b.sep(u)+b.zeros.Equal(value)+rest)
+	}
+	return alternatives
+}
+
+// atMost returns alternatives for the components from index u on that are
+// less than parts[u:], followed by eq when they are equal.
+func (b seqBuilder) atMost(parts []string, u int, eq string, eqOK bool) []string {
+	if u >= len(parts) {
+		if eqOK {
+			return []string{b.zeroTail(u) + eq}
+		}
+		return nil
+	}
+
+	value := parts[u]
+	var alternatives []string
+	if value != "0" {
+		alternatives = append(alternatives, b.sep(u)+b.zeros.LessOrEqual(decrementDecimal(value))+b.anyTail(u+1)+b.order.any())
+	}
+	if rest, ok := groupAlternatives(b.atMost(parts, u+1, eq, eqOK)); ok {
+		alternatives = append(alternatives, b.sep(u)+b.zeros.Equal(value)+rest)
+	}
+	// A version that stops here compares as zeros, which is below the remaining non-zero parts
+	if b.fixed == 0 && u > 0 {
+		alternatives = append(alternatives, b.order.any())
+	}
+	return alternatives
+}
+
+// between returns alternatives for the components from index u on that lie
+// between lower[u:] and upper[u:], where lower[:u] and upper[:u] are equal.
+func (b seqBuilder) between(lower, upper []string, u int, r versionRange) []string {
+	if u >= len(lower) && u >= len(upper) {
+		eq, ok := b.order.between(r.lower.suffix, r.lower.inclusive, r.upper.suffix, r.upper.inclusive)
+		if !ok {
+			return nil
+		}
+		return []string{b.zeroTail(u) + eq}
+	}
+
+	lo, hi := componentAt(lower, u), componentAt(upper, u)
+	var alternatives []string
+	if lo == hi {
+		if rest, ok := groupAlternatives(b.between(lower, upper, u+1, r)); ok {
+			alternatives = append(alternatives, b.sep(u)+b.zeros.Equal(lo)+rest)
+		}
+	} else {
+		eq, eqOK := b.order.atLeast(r.lower.suffix, r.lower.inclusive)
+		if rest, ok := groupAlternatives(b.atLeast(lower, u+1, eq, eqOK)); ok {
+			alternatives = append(alternatives, b.sep(u)+b.zeros.Equal(lo)+rest)
+		}
+		if next, last := incrementDecimal(lo), decrementDecimal(hi); compareDecimals(next, last) <= 0 {
+			alternatives = append(alternatives, b.sep(u)+b.zeros.Range(next, last)+b.anyTail(u+1)+b.order.any())
+		}
+		eq, eqOK = b.order.atMost(r.upper.suffix, r.upper.inclusive)
+		if rest, ok := groupAlternatives(b.atMost(upper, u+1, eq, eqOK)); ok {
+			alternatives = append(alternatives, b.sep(u)+b.zeros.Equal(hi)+rest)
+		}
+	}
+
+	// A version that stops here compares as zeros: it equals the lower bound when
+	// the lower bound has no further non-zero parts, and is below the upper bound
+	if b.fixed == 0 && u > 0 && u >= len(lower) {
+		if eq, ok := b.order.atLeast(r.lower.suffix, r.lower.inclusive); ok {
+			alternatives = append(alternatives, eq)
+		}
+	}
+	return alternatives
+}
+
+// sep returns the separator that precedes component u.
+func (b seqBuilder) sep(u int) string {
+	if u == 0 {
+		return ""
+	}
+	return VERSION_DOT
+}
+
+// anyTail matches any components from index u on.
+// Result: (?:\.\d+)* or (?:\.\d+){2}
+func (b seqBuilder) anyTail(u int) string {
+	return b.repeatTail(VERSION_DOT+b.zeros.Digits(), u)
+}
+
+// zeroTail matches zero components from index u on.
+// Result: (?:\.0*0)* or (?:\.0){2}
+func (b seqBuilder) zeroTail(u int) string {
+	return b.repeatTail(VERSION_DOT+b.zeros.Equal("0"), u)
+}
+
+// repeatTail repeats a component pattern for the components from index u on.
+func (b seqBuilder) repeatTail(component string, u int) string {
+	if b.fixed == 0 {
+		return "(?:" + component + ")*"
+	}
+	switch count := b.fixed - u; {
+	case count <= 0:
+		return ""
+	case count == 1:
+		return component
+	default:
+		return "(?:" + component + "){" + strconv.Itoa(count) + "}"
+	}
+}
+
+// nonZeroTails returns alternatives for components from index u on that contain
+// at least one non-zero component, i.e. that are greater than all zeros.
+func (b seqBuilder) nonZeroTails(u int) []string {
+	nonZero, zero := b.zeros.GreaterOrEqual("1"), b.zeros.Equal("0")
+	if b.fixed == 0 {
+		if u == 0 {
+			return []string{"(?:" + zero + VERSION_DOT + ")*" + nonZero + b.anyTail(1) + b.order.any()}
+		}
+		return []string{b.zeroTail(u) + VERSION_DOT + nonZero + b.anyTail(u) + b.order.any()}
+	}
+
+	var alternatives []string
+	for k := u; k < b.fixed; k++ {
+		zeros := strings.Repeat(b.sep(u)+zero, min(k-u, 1)) + strings.Repeat(VERSION_DOT+zero, max(k-u-1, 0))
+		alternatives = append(alternatives, zeros+b.sep(k)+nonZero+b.anyTail(k+1)+b.order.any())
+	}
+	return alternatives
+}
+
+// intersectRanges returns the ranges of versions that lie inside both a range of a
+// and a range of b. It is used for requirement lists whose entries must all hold.
+func intersectRanges(a, b []versionRange, order suffixOrder) []versionRange {
+	var result []versionRange
+	for _, x := range a {
+		for _, y := range b {
+			r := versionRange{
+				lower: tighterBound(x.lower, y.lower, order, 1),
+				upper: tighterBound(x.upper, y.upper, order, -1),
+			}
+			if !isEmptyRange(r, order) {
+				result = append(result, r)
+			}
+		}
+	}
+	return result
+}
+
+// tighterBound returns the more restrictive of two bounds: the greater one when
+// direction is 1 (lower bounds) and the lesser one when it is -1 (upper bounds).
+// On a tie the exclusive bound wins. A nil bound is unbounded.
+func tighterBound(a, b *rangeBound, order suffixOrder, direction int) *rangeBound {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	switch c := compareBounds(a, b, order) * direction; {
+	case c > 0:
+		return a
+	case c < 0:
+		return b
+	case !a.inclusive:
+		return a
+	default:
+		return b
+	}
+}
+
+// isEmptyRange reports whether no version can lie inside r.
+func isEmptyRange(r versionRange, order suffixOrder) bool {
+	if r.lower == nil || r.upper == nil {
+		return false
+	}
+	c := compareBounds(r.lower, r.upper, order)
+	return c > 0 || (c == 0 && !(r.lower.inclusive && r.upper.inclusive))
+}
+
+// componentAt returns component i of parts, treating missing components as zero.
+func componentAt(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+	return "0"
+}
+
+// compareParts compares two lists of release components, treating missing
+// components as zero.
+func compareParts(a, b []string) int {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		if c := compareDecimals(componentAt(a, i), componentAt(b, i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// decimalParts returns release components as decimal strings, for bounds built
+// from int components.
+func decimalParts(values []int) []string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return parts
+}
+
+// parseDecimalParts splits dot-separated release components into decimal strings
+// without leading zeros. It returns false when a component is not a decimal number.
+func parseDecimalParts(version string) ([]string, bool) {
+	fields := strings.Split(version, ".")
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		if !isDigits(field) {
+			return nil, false
+		}
+		parts[i] = trimDecimal(field)
+	}
+	return parts, true
+}
+
+// compareBounds orders two bounds by release components, then by suffix.
+func compareBounds(a, b *rangeBound, order suffixOrder) int {
+	if c := compareParts(a.parts, b.parts); c != 0 {
+		return c
+	}
+	return order.compare(a.suffix, b.suffix)
+}
+
+// groupAlternatives joins alternatives into a single self-contained pattern.
+// An empty alternative makes the whole group optional. It returns false when
+// there are no alternatives.
+func groupAlternatives(alternatives []string) (string, bool) {
+	var patterns []string
+	optional := false
+	for _, alternative := range alternatives {
+		if alternative == "" {
+			optional = true
+			continue
+		}
+		patterns = append(patterns, alternative)
+	}
+
+	switch {
+	case len(patterns) == 0:
+		return "", optional
+	case optional:
+		return "(?:" + strings.Join(patterns, REGEX_OR) + ")?", true
+	case len(patterns) == 1:
+		return patterns[0], true
+	default:
+		return "(?:" + strings.Join(patterns, REGEX_OR) + ")", true
+	}
+}
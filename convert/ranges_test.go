@@ -0,0 +1,134 @@
+// Package convert provides tests for the generic version range machinery.
+// This file checks seqBuilder patterns against a brute-force comparison of versions.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// plainOrder is a suffixOrder for versions without any suffix.
+type plainOrder struct{}
+
+func (plainOrder) compare(a, b string) int { return 0 }
+func (plainOrder) any() string             { return "" }
+
+func (plainOrder) atLeast(s string, inclusive bool) (string, bool) { return "", inclusive }
+func (plainOrder) atMost(s string, inclusive bool) (string, bool)  { return "", inclusive }
+
+func (plainOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	return "", loInclusive && hiInclusive
+}
+
+// rangeTestVersions returns every version with 1 to maxLen components between 0 and maxValue.
+func rangeTestVersions(maxLen, maxValue int) [][]int {
+	var versions [][]int
+	var build func(prefix []int)
+	build = func(prefix []int) {
+		if len(prefix) > 0 {
+			versions = append(versions, append([]int(nil), prefix...))
+		}
+		if len(prefix) == maxLen {
+			return
+		}
+		for v := 0; v <= maxValue; v++ {
+			build(append(prefix, v))
+		}
+	}
+	build(nil)
+	return versions
+}
+
+// formatParts joins release components with dots.
+func formatParts(parts []int) string {
+	s := make([]string, len(parts))
+	for i, p := range parts {
+		s[i] = strconv.Itoa(p)
+	}
+	return strings.Join(s, ".")
+}
+
+// inRange reports whether a version lies inside r by direct comparison.
+func inRange(parts []int, r versionRange) bool {
+	if r.lower != nil {
+		c := compareParts(decimalParts(parts), r.lower.parts)
+		if c < 0 || (c == 0 && !r.lower.inclusive) {
+			return false
+		}
+	}
+	if r.upper != nil {
+		c := compareParts(decimalParts(parts), r.upper.parts)
+		if c > 0 || (c == 0 && !r.upper.inclusive) {
+			return false
+		}
+	}
+	return true
+}
+
+// TestSeqBuilderRangePattern compares seqBuilder patterns with a brute-force
+// comparison of every version with up to four components between 0 and 3.
+func TestSeqBuilderRangePattern(t *testing.T) {
+	bounds := [][]int{nil, {0}, {1}, {1, 0}, {1, 2}, {0, 0, 1}, {2, 0, 3}, {1, 2, 3}, {3, 3}}
+	versions := rangeTestVersions(4, 3)
+
+	for _, fixed := range []int{0, 3} {
+		builder := seqBuilder{fixed: fixed, order: plainOrder{}}
+		for _, lower := range bounds {
+			for _, upper := range bounds {
+				for _, inclusive := range [][2]bool{{true, true}, {true, false}, {false, true}, {false, false}} {
+					var r versionRange
+					if lower != nil {
+						r.lower = &rangeBound{parts: decimalParts(lower), inclusive: inclusive[0]}
+					}
+					if upper != nil {
+						r.upper = &rangeBound{parts: decimalParts(upper), inclusive: inclusive[1]}
+					}
+
+					name := fmt.Sprintf("fixed=%d %v %v %v", fixed, lower, upper, inclusive)
+					pattern, ok := builder.rangePattern(r)
+					re := regexp.MustCompile(NEVER_MATCH_PATTERN)
+					if ok {
+						var err error
+						if re, err = regexp.Compile(REGEX_START + pattern + REGEX_END); err != nil {
+							t.Fatalf("%s: invalid pattern %q: %v", name, pattern, err)
+						}
+					}
+
+					for _, version := range versions {
+						expected := inRange(version, r) && (fixed == 0 || len(version) == fixed)
+						if re.MatchString(formatParts(version)) != expected {
+							t.Errorf("%s: pattern %q matching %s: expected %v", name, pattern, formatParts(version), expected)
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestGroupAlternatives tests how alternatives are combined into one pattern.
+func TestGroupAlternatives(t *testing.T) {
+	tests := []struct {
+		alternatives []string
+		expected     string
+		ok           bool
+	}{
+		{nil, "", false},
+		{[]string{""}, "", true},
+		{[]string{"a"}, "a", true},
+		{[]string{"a", "b"}, "(?:a|b)", true},
+		{[]string{"a", ""}, "(?:a)?", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.alternatives, ","), func(t *testing.T) {
+			pattern, ok := groupAlternatives(tt.alternatives)
+			if pattern != tt.expected || ok != tt.ok {
+				t.Errorf("groupAlternatives(%q) = %q, %v, expected %q, %v", tt.alternatives, pattern, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ package convert
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -25,6 +26,10 @@ const (
 	// VERSION_DIGITS matches one or more digits in version numbers
 	VERSION_DIGITS = `\d+`
 
+	// CANONICAL_DIGITS matches a number written without leading zeros, as SemVer
+	// requires for numeric components
+	CANONICAL_DIGITS = `(?:0|[1-9]\d*)`
+
 	// VERSION_DOT matches a literal dot separator in version numbers
 	VERSION_DOT = `\.`
 
@@ -44,6 +49,19 @@ const (
 	// SEMANTIC_VERSION_CORE matches the core major.minor.patch pattern
 	// Result: \d+\.\d+\.\d+
 	SEMANTIC_VERSION_CORE = VERSION_DIGITS + VERSION_DOT + VERSION_DIGITS + VERSION_DOT + VERSION_DIGITS
+
+	// SEMVER_IDENTIFIERS matches a dot-separated list of SemVer 2.0 identifiers
+	// Format: alpha, beta.1, dev.5, nightly-2024, etc.
+	SEMVER_IDENTIFIERS = `[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*`
+
+	// SEMVER_PRE_RELEASE_PATTERN matches an optional SemVer 2.0 pre-release label
+	// Unlike PRE_RELEASE_PATTERN it rejects empty identifiers such as -alpha..1
+	// Result: (?:-[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?
+	SEMVER_PRE_RELEASE_PATTERN = `(?:-` + SEMVER_IDENTIFIERS + `)?`
+
+	// SEMVER_BUILD_META_PATTERN matches optional SemVer 2.0 build metadata
+	// Result: (?:\+[0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*)?
+	SEMVER_BUILD_META_PATTERN = `(?:\+` + SEMVER_IDENTIFIERS + `)?`
 )
 
 // Template patterns for common version constraint formats
@@ -96,14 +114,22 @@ const (
 
 	// EMPTY_MATCH_PATTERN matches no versions (used for impossible constraints)
 	EMPTY_MATCH_PATTERN = REGEX_START + `(?!.*)` + REGEX_END
+
+	// NEVER_MATCH matches nothing using an empty character class, which unlike a
+	// negative lookahead is supported by Go's RE2 engine
+	NEVER_MATCH = `[^\x00-\x{10FFFF}]`
+
+	// NEVER_MATCH_PATTERN is the anchored, RE2-compatible counterpart of EMPTY_MATCH_PATTERN
+	NEVER_MATCH_PATTERN = REGEX_START + NEVER_MATCH + REGEX_END
 )
 
 // NumGreaterOrEqual generates a regex pattern that matches integers >= n.
 //
 // This function creates regex patterns for matching version number components
-// that are greater than or equal to a given value. It supports multi-digit
-// numbers up to 10 digits (0 to 9999999999), making it suitable for semantic
-// version comparisons where version parts can be arbitrarily large.
+// that are greater than or equal to a given value. Matched numbers may have any
+// number of digits; use DecimalGreaterOrEqual for bounds that do not fit in an int.
+// Numbers written with leading zeros are compared by value, as with the
+// LEADING_ZEROS_NUMERIC policy: 015 matches n=15 but 009 does not.
 //
 // Algorithm:
 // The function builds multiple alternation patterns to cover all cases:
@@ -111,10 +137,12 @@ const (
 //  2. Numbers with the same digit count but higher leading digits
 //  3. Numbers matching the exact prefix with higher subsequent digits
 //
+// and lets any number of leading zeros precede them.
+//
 // Pattern Construction Strategy:
 //   - For n=0: matches any non-negative integer (\d+)
 //   - For single digits (n=5): matches [5-9] or multi-digit numbers
-//   - For multi-digit (n=15): matches 15-19 (1[5-9]), 20-99 ([2-9]\d), or 100+ (\d{3,})
+//   - For multi-digit (n=15): matches 15-19 (1[5-9]), 20-99 ([2-9]\d), or 100+ ([1-9]\d{2,})
 //
 // Parameters:
 //   - n: The minimum value to match (inclusive). Must be >= 0.
@@ -128,55 +156,45 @@ const (
 //	NumGreaterOrEqual(0)   → `\d+`
 //	    Matches: 0, 1, 10, 100, 9999999999
 //
-//	NumGreaterOrEqual(5)   → `(?:[5-9]|\d{2,})`
-//	    Matches: 5, 6, 7, 8, 9, 10, 100, 1000...
-//	    Does not match: 0, 1, 2, 3, 4
+//	NumGreaterOrEqual(5)   → `0*(?:[1-9]\d+|[5-9])`
+//	    Matches: 5, 6, 7, 8, 9, 10, 100, 1000, 05...
+//	    Does not match: 0, 1, 2, 3, 4, 004
 //
-//	NumGreaterOrEqual(15)  → `(?:1[5-9]|[2-9]\d|\d{3,})`
-//	    Matches: 15, 16, 17, 18, 19, 20, 99, 100, 1000...
-//	    Does not match: 0, 1, 10, 11, 12, 13, 14
+//	NumGreaterOrEqual(15)  → `0*(?:[1-9]\d{2,}|1[5-9]|[2-9]\d)`
+//	    Matches: 15, 16, 17, 18, 19, 20, 99, 100, 1000, 015...
+//	    Does not match: 0, 1, 10, 11, 12, 13, 14, 009
 //
-//	NumGreaterOrEqual(100) → `(?:10[0-9]|1[1-9]\d|[2-9]\d{2}|\d{4,})`
+//	NumGreaterOrEqual(100) → `0*(?:[1-9]\d{3,}|[1-9]\d{2})`
 //	    Matches: 100, 101, 150, 200, 999, 1000...
-//	    Does not match: 0, 1, 50, 99
+//	    Does not match: 0, 1, 50, 99, 099
 func NumGreaterOrEqual(n int) string {
 	if n <= 0 {
 		return VERSION_DIGITS
 	}
+	return DecimalGreaterOrEqual(strconv.Itoa(n))
+}
 
-	s := strconv.Itoa(n)
-	numDigits := len(s)
-	var patterns []string
-
-	// Pattern for numbers with more digits than n (always greater)
-	if numDigits < 10 {
-		patterns = append(patterns, fmt.Sprintf(`\d{%d,}`, numDigits+1))
-	}
-
-	// Build patterns for numbers with the same number of digits as n
-	for i := 0; i < numDigits; i++ {
-		digit := int(s[i] - '0')
-		prefix := s[:i]
-		suffixLen := numDigits - i - 1
-
-		if i == numDigits-1 {
-			// Last digit: match this digit or higher
-			patterns = append(patterns, prefix+digitRangeUp(digit))
-		} else if digit < 9 {
-			// Non-last digit: if higher, any following digits are fine
-			patterns = append(patterns, prefix+digitRangeUp(digit+1)+fmt.Sprintf(`\d{%d}`, suffixLen))
-		}
-	}
-
-	return joinPatterns(patterns)
+// DecimalGreaterOrEqual generates a regex pattern that matches integers >= n, where n
+// is a decimal string of any length, such as the timestamp 20240115103045000000 that
+// does not fit in an int. Leading zeros in n are ignored.
+//
+// It returns the same patterns as NumGreaterOrEqual, and NEVER_MATCH when n is not a
+// decimal string.
+//
+// Examples:
+//
+//	DecimalGreaterOrEqual("15")                   → `0*(?:[1-9]\d{2,}|1[5-9]|[2-9]\d)`
+//	DecimalGreaterOrEqual("20240115103045000000") → `0*(?:[1-9]\d{20,}|...)`
+func DecimalGreaterOrEqual(n string) string {
+	return LEADING_ZEROS_NUMERIC.GreaterOrEqual(n)
 }
 
 // NumLessOrEqual generates a regex pattern that matches integers <= n.
 //
 // This function creates regex patterns for matching version number components
-// that are less than or equal to a given value. It supports multi-digit
-// numbers up to 10 digits (0 to 9999999999), making it suitable for semantic
-// version comparisons where version parts can be arbitrarily large.
+// that are less than or equal to a given value. Use DecimalLessOrEqual for
+// bounds that do not fit in an int. Numbers written with leading zeros are
+// compared by value, as with the LEADING_ZEROS_NUMERIC policy.
 //
 // Algorithm:
 // The function builds multiple alternation patterns to cover all cases:
@@ -184,11 +202,13 @@ func NumGreaterOrEqual(n int) string {
 //  2. Numbers with the same digit count but lower leading digits
 //  3. Numbers matching the exact prefix with lower or equal subsequent digits
 //
+// and lets any number of leading zeros precede them.
+//
 // Pattern Construction Strategy:
 //   - For n<0: returns EMPTY_MATCH_PATTERN (matches nothing)
 //   - For single digits (n=5): matches [0-5]
 //   - For multi-digit (n=15): matches 0-9 (\d), 10-15 (1[0-5])
-//   - For larger numbers: combines patterns for each digit position
+//   - For larger numbers: combines patterns for each digit count
 //
 // Parameters:
 //   - n: The maximum value to match (inclusive). If n < 0, returns a pattern
@@ -200,15 +220,15 @@ func NumGreaterOrEqual(n int) string {
 //
 // Examples:
 //
-//	NumLessOrEqual(9)   → `[0-9]`
-//	    Matches: 0, 1, 2, 3, 4, 5, 6, 7, 8, 9
+//	NumLessOrEqual(9)   → `0*\d`
+//	    Matches: 0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 09
 //	    Does not match: 10, 11, 100...
 //
-//	NumLessOrEqual(15)  → `(?:\d|1[0-5])`
-//	    Matches: 0, 1, 2, ..., 9, 10, 11, 12, 13, 14, 15
+//	NumLessOrEqual(15)  → `0*(?:\d|1[0-5])`
+//	    Matches: 0, 1, 2, ..., 9, 10, 11, 12, 13, 14, 15, 015
 //	    Does not match: 16, 17, 20, 100...
 //
-//	NumLessOrEqual(123) → `(?:\d|\d{2}|1[0-1]\d|12[0-3])`
+//	NumLessOrEqual(123) → `0*(?:\d|[1-9]\d|1(?:[01]\d|2[0-3]))`
 //	    Matches: 0, 1, 9, 10, 99, 100, 110, 119, 120, 121, 122, 123
 //	    Does not match: 124, 125, 130, 200, 1000...
 //
@@ -218,38 +238,458 @@ func NumLessOrEqual(n int) string {
 	if n < 0 {
 		return EMPTY_MATCH_PATTERN
 	}
+	return DecimalLessOrEqual(strconv.Itoa(n))
+}
+
+// DecimalLessOrEqual generates a regex pattern that matches integers <= n, where n is
+// a decimal string of any length. Leading zeros in n are ignored.
+//
+// It returns the same patterns as NumLessOrEqual, and NEVER_MATCH when n is not a
+// decimal string.
+//
+// Examples:
+//
+//	DecimalLessOrEqual("15")                   → `0*(?:\d|1[0-5])`
+//	DecimalLessOrEqual("20240115103045000000") → `0*(?:\d|[1-9]\d|...|20(?:[01]\d{17}|...))`
+func DecimalLessOrEqual(n string) string {
+	return LEADING_ZEROS_NUMERIC.LessOrEqual(n)
+}
+
+// NumRange generates a regex pattern that matches integers between lo and hi (inclusive).
+//
+// The range is split by digit count and each same-length sub-range is split
+// around its leading digit, so every alternative is a fixed-length pattern.
+// Numbers written with leading zeros are compared by value, as with the
+// LEADING_ZEROS_NUMERIC policy.
+//
+// Parameters:
+//   - lo: The minimum value to match (inclusive). Negative values are treated as 0.
+//   - hi: The maximum value to match (inclusive).
+//
+// Returns:
+//   - A regex pattern string that matches any integer in [lo, hi], or NEVER_MATCH
+//     when the range is empty.
+//
+// Examples:
+//
+//	NumRange(3, 7)   → `0*[3-7]`
+//	NumRange(8, 12)  → `0*(?:[89]|1[0-2])`
+//	NumRange(15, 99) → `0*(?:1[5-9]|[2-9]\d)`
+func NumRange(lo, hi int) string {
+	if lo < 0 {
+		lo = 0
+	}
+	if lo > hi {
+		return NEVER_MATCH
+	}
+	return DecimalRange(strconv.Itoa(lo), strconv.Itoa(hi))
+}
+
+// DecimalRange generates a regex pattern that matches integers between lo and hi
+// (inclusive), where lo and hi are decimal strings of any length. Leading zeros in
+// the bounds are ignored.
+//
+// It returns the same patterns as NumRange, and NEVER_MATCH when the range is empty
+// or a bound is not a decimal string.
+//
+// Examples:
+//
+//	DecimalRange("8", "12")                                     → `0*(?:[89]|1[0-2])`
+//	DecimalRange("20240101000000000000", "20241231235959999999") → `0*2024(?:...)`
+func DecimalRange(lo, hi string) string {
+	return LEADING_ZEROS_NUMERIC.Range(lo, hi)
+}
+
+// Digits returns the pattern of a numeric component of any value under the policy.
+//
+// Examples:
+//
+//	LEADING_ZEROS_NUMERIC.Digits() → `\d+`
+//	LEADING_ZEROS_REJECT.Digits()  → `(?:0|[1-9]\d*)`
+func (p LeadingZeroPolicy) Digits() string {
+	if p == LEADING_ZEROS_REJECT {
+		return CANONICAL_DIGITS
+	}
+	return VERSION_DIGITS
+}
+
+// Equal generates a regex pattern that matches the integer n under the policy, where
+// n is a decimal string of any length. It returns NEVER_MATCH when n is not a decimal
+// string.
+//
+// Examples:
+//
+//	LEADING_ZEROS_NUMERIC.Equal("12") → `0*12`
+//	LEADING_ZEROS_REJECT.Equal("012") → `12`
+func (p LeadingZeroPolicy) Equal(n string) string {
+	if !isDigits(n) {
+		return NEVER_MATCH
+	}
+	return p.padded(trimDecimal(n))
+}
+
+// GreaterOrEqual generates a regex pattern that matches integers >= n under the
+// policy, where n is a decimal string of any length. It returns NEVER_MATCH when n
+// is not a decimal string.
+//
+// Examples:
+//
+//	LEADING_ZEROS_NUMERIC.GreaterOrEqual("15") → `0*(?:[1-9]\d{2,}|1[5-9]|[2-9]\d)`
+//	LEADING_ZEROS_REJECT.GreaterOrEqual("15")  → `(?:[1-9]\d{2,}|1[5-9]|[2-9]\d)`
+func (p LeadingZeroPolicy) GreaterOrEqual(n string) string {
+	if !isDigits(n) {
+		return NEVER_MATCH
+	}
+	s := trimDecimal(n)
+	if s == "0" {
+		return p.Digits()
+	}
+
+	// Numbers with more digits than n are always greater
+	longer := `[1-9]\d+`
+	if len(s) > 1 {
+		longer = fmt.Sprintf(`[1-9]\d{%d,}`, len(s))
+	}
+	patterns := []string{longer}
+	patterns = append(patterns, sameLengthRange(s, strings.Repeat("9", len(s)))...)
+	return p.padded(joinPatterns(patterns))
+}
 
-	s := strconv.Itoa(n)
-	numDigits := len(s)
+// LessOrEqual generates a regex pattern that matches integers <= n under the policy,
+// where n is a decimal string of any length. It returns NEVER_MATCH when n is not a
+// decimal string.
+//
+// Examples:
+//
+//	LEADING_ZEROS_NUMERIC.LessOrEqual("15") → `0*(?:\d|1[0-5])`
+//	LEADING_ZEROS_REJECT.LessOrEqual("15")  → `(?:\d|1[0-5])`
+func (p LeadingZeroPolicy) LessOrEqual(n string) string {
+	return p.Range("0", n)
+}
+
+// Range generates a regex pattern that matches integers between lo and hi
+// (inclusive) under the policy, where lo and hi are decimal strings of any length.
+// It returns NEVER_MATCH when the range is empty or a bound is not a decimal string.
+//
+// Examples:
+//
+//	LEADING_ZEROS_NUMERIC.Range("8", "12") → `0*(?:[89]|1[0-2])`
+//	LEADING_ZEROS_REJECT.Range("8", "12")  → `(?:[89]|1[0-2])`
+func (p LeadingZeroPolicy) Range(lo, hi string) string {
+	if !isDigits(lo) || !isDigits(hi) || compareDecimals(lo, hi) > 0 {
+		return NEVER_MATCH
+	}
+
+	// Split the range by digit count, so that only zero has a leading 0
+	loStr, hiStr := trimDecimal(lo), trimDecimal(hi)
 	var patterns []string
+	for length := len(loStr); length <= len(hiStr); length++ {
+		from, to := loStr, hiStr
+		if length > len(loStr) {
+			from = "1" + strings.Repeat("0", length-1)
+		}
+		if length < len(hiStr) {
+			to = strings.Repeat("9", length)
+		}
+		patterns = append(patterns, sameLengthRange(from, to)...)
+	}
 
-	// Pattern for numbers with fewer digits than n (always less)
-	for d := 1; d < numDigits; d++ {
-		if d == 1 {
-			patterns = append(patterns, `\d`)
-		} else {
-			patterns = append(patterns, fmt.Sprintf(`\d{%d}`, d))
+	return p.padded(joinPatterns(patterns))
+}
+
+// padded lets the leading zeros allowed by the policy precede a pattern matching
+// numbers written without leading zeros.
+func (p LeadingZeroPolicy) padded(pattern string) string {
+	if p == LEADING_ZEROS_REJECT {
+		return pattern
+	}
+	return "0*" + pattern
+}
+
+// trimDecimal removes the leading zeros of a decimal string, keeping a single 0 for
+// zero: trimDecimal("007") is "7".
+func trimDecimal(n string) string {
+	trimmed := strings.TrimLeft(n, "0")
+	if trimmed == "" {
+		return "0"
+	}
+	return trimmed
+}
+
+// compareDecimals compares the numeric values of two decimal strings of any length,
+// returning -1, 0 or 1.
+func compareDecimals(a, b string) int {
+	a, b = trimDecimal(a), trimDecimal(b)
+	if len(a) != len(b) {
+		return compareInts(len(a), len(b))
+	}
+	return strings.Compare(a, b)
+}
+
+// incrementDecimal returns n+1 for a decimal string n without leading zeros.
+func incrementDecimal(n string) string {
+	digits := []byte(n)
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] < '9' {
+			digits[i]++
+			return string(digits)
 		}
+		digits[i] = '0'
+	}
+	return "1" + string(digits)
+}
+
+// decrementDecimal returns n-1 for a positive decimal string n without leading zeros.
+func decrementDecimal(n string) string {
+	digits := []byte(n)
+	for i := len(digits) - 1; i >= 0; i-- {
+		if digits[i] > '0' {
+			digits[i]--
+			break
+		}
+		digits[i] = '9'
+	}
+	return trimDecimal(string(digits))
+}
+
+// paddedNumRange generates a regex pattern that matches non-empty digit strings whose
+// numeric value lies between lo and hi (inclusive), ignoring leading zeros, as
+// needed by orderings that compare digit runs numerically (007 == 7) whatever the
+// LeadingZeroPolicy.
+//
+// A negative hi means no upper limit. It returns NEVER_MATCH when the range is empty.
+//
+// Examples:
+//
+//	paddedNumRange(0, 0)   → `0*0`
+//	paddedNumRange(3, 12)  → `0*(?:[3-9]|1[0-2])`
+//	paddedNumRange(15, -1) → `0*(?:[1-9]\d{2,}|1[5-9]|[2-9]\d)`
+func paddedNumRange(lo, hi int) string {
+	if lo < 0 {
+		lo = 0
+	}
+	if hi < 0 {
+		return paddedDecimalRange(strconv.Itoa(lo), "")
 	}
+	return paddedDecimalRange(strconv.Itoa(lo), strconv.Itoa(hi))
+}
 
-	// Build patterns for numbers with the same number of digits as n
-	for i := 0; i < numDigits; i++ {
-		digit := int(s[i] - '0')
-		prefix := s[:i]
-		suffixLen := numDigits - i - 1
+// paddedDecimalRange is paddedNumRange for bounds given as decimal strings of any
+// length. An empty hi means no upper limit.
+func paddedDecimalRange(lo, hi string) string {
+	if hi == "" {
+		return LEADING_ZEROS_NUMERIC.GreaterOrEqual(lo)
+	}
+	return LEADING_ZEROS_NUMERIC.Range(lo, hi)
+}
 
-		if i == numDigits-1 {
-			// Last digit: match this digit or lower
-			patterns = append(patterns, prefix+digitRangeDown(digit))
-		} else if digit > 0 {
-			// Non-last digit: if lower, any following digits are fine
-			patterns = append(patterns, prefix+digitRangeDown(digit-1)+fmt.Sprintf(`\d{%d}`, suffixLen))
+// sameLengthRange returns alternatives matching the numbers between two decimal
+// strings of the same length.
+func sameLengthRange(from, to string) []string {
+	if from == to {
+		return []string{from}
+	}
+	if len(from) == 1 {
+		return []string{digitClass(from[0], to[0])}
+	}
+	if from[0] == to[0] {
+		return []string{from[:1] + joinPatterns(sameLengthRange(from[1:], to[1:]))}
+	}
+
+	rest := len(from) - 1
+	var patterns []string
+	low, high := from[0], to[0]
+	if strings.Trim(from[1:], "0") != "" {
+		patterns = append(patterns, from[:1]+joinPatterns(sameLengthRange(from[1:], strings.Repeat("9", rest))))
+		low++
+	}
+	partialHigh := strings.Trim(to[1:], "9") != ""
+	if partialHigh {
+		high--
+	}
+	if low <= high {
+		patterns = append(patterns, digitClass(low, high)+digitCount(rest))
+	}
+	if partialHigh {
+		patterns = append(patterns, to[:1]+joinPatterns(sameLengthRange(strings.Repeat("0", rest), to[1:])))
+	}
+	return patterns
+}
+
+// digitClass returns a pattern matching a single digit between from and to.
+func digitClass(from, to byte) string {
+	switch {
+	case from == to:
+		return string(from)
+	case from == '0' && to == '9':
+		return `\d`
+	case to == from+1:
+		return "[" + string(from) + string(to) + "]"
+	default:
+		return "[" + string(from) + "-" + string(to) + "]"
+	}
+}
+
+// digitCount returns a pattern matching exactly n digits.
+func digitCount(n int) string {
+	if n == 1 {
+		return `\d`
+	}
+	return fmt.Sprintf(`\d{%d}`, n)
+}
+
+// wordComplementPattern generates a pattern matching lowercase words ([a-z]+)
+// that are not in the given list. It is used to match "any other" qualifier
+// next to qualifiers that have their own ordering rules.
+//
+// Result: (?:[b-z][a-z]*|a(?:[a-z]...)) built from a trie of the excluded words
+func wordComplementPattern(words []string) string {
+	root := &trieNode{children: map[byte]*trieNode{}}
+	for _, word := range words {
+		node := root
+		for i := 0; i < len(word); i++ {
+			child, ok := node.children[word[i]]
+			if !ok {
+				child = &trieNode{children: map[byte]*trieNode{}}
+				node.children[word[i]] = child
+			}
+			node = child
 		}
+		node.end = true
 	}
+	return complementNode(root, true)
+}
+
+// trieNode is a node of the word trie used by wordComplementPattern.
+type trieNode struct {
+	children map[byte]*trieNode
+	end      bool
+}
 
+// complementNode returns the pattern for the words that continue from node
+// without spelling one of the trie's words.
+func complementNode(node *trieNode, root bool) string {
+	var patterns []string
+
+	// Any letter that leaves the trie starts a word that cannot be excluded
+	others := ""
+	for c := byte('a'); c <= 'z'; c++ {
+		if _, ok := node.children[c]; !ok {
+			others += string(c)
+		}
+	}
+	if others != "" {
+		patterns = append(patterns, letterClass(others)+`[a-z]*`)
+	}
+
+	keys := make([]byte, 0, len(node.children))
+	for c := range node.children {
+		keys = append(keys, c)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, c := range keys {
+		patterns = append(patterns, string(c)+complementNode(node.children[c], false))
+	}
+
+	// Stopping inside the trie is fine unless the prefix is itself an excluded word
+	if !root && !node.end {
+		return "(?:" + strings.Join(patterns, REGEX_OR) + ")?"
+	}
 	return joinPatterns(patterns)
 }
 
+// lexPiece describes a set of strings over an alphabet: a literal prefix, then one
+// character of chars (when not empty), then any string when rest is set.
+type lexPiece struct {
+	literal string
+	chars   string
+	rest    bool
+}
+
+// lexAbove returns the strings over alphabet (sorted in ASCII order) that sort after s.
+func lexAbove(s, alphabet string) []lexPiece {
+	var pieces []lexPiece
+	for j := 0; j < len(s); j++ {
+		pieces = append(pieces, lexPiece{literal: s[:j], chars: lexCharsBetween(s[j], 0xff, alphabet), rest: true})
+	}
+	return append(pieces, lexPiece{literal: s, chars: alphabet, rest: true})
+}
+
+// lexBelow returns the strings over alphabet (sorted in ASCII order) that sort before
+// s, including its proper prefixes and the empty string.
+func lexBelow(s, alphabet string) []lexPiece {
+	var pieces []lexPiece
+	for j := 0; j < len(s); j++ {
+		pieces = append(pieces, lexPiece{literal: s[:j]})
+		pieces = append(pieces, lexPiece{literal: s[:j], chars: lexCharsBetween(0, s[j], alphabet), rest: true})
+	}
+	return pieces
+}
+
+// lexBetween returns the strings over alphabet that sort strictly between lo and hi (lo < hi).
+func lexBetween(lo, hi, alphabet string) []lexPiece {
+	k := 0
+	for k < len(lo) && k < len(hi) && lo[k] == hi[k] {
+		k++
+	}
+
+	prefix := lo[:k]
+	var pieces []lexPiece
+	if k == len(lo) {
+		// lo is a prefix of hi: continue below the rest of hi, without stopping at lo
+		for _, piece := range lexBelow(hi[k:], alphabet) {
+			if piece.literal != "" || piece.chars != "" {
+				pieces = append(pieces, lexPiece{literal: prefix + piece.literal, chars: piece.chars, rest: piece.rest})
+			}
+		}
+		return pieces
+	}
+
+	for _, piece := range lexAbove(lo[k+1:], alphabet) {
+		pieces = append(pieces, lexPiece{literal: lo[:k+1] + piece.literal, chars: piece.chars, rest: piece.rest})
+	}
+	if chars := lexCharsBetween(lo[k], hi[k], alphabet); chars != "" {
+		pieces = append(pieces, lexPiece{literal: prefix, chars: chars, rest: true})
+	}
+	for _, piece := range lexBelow(hi[k+1:], alphabet) {
+		pieces = append(pieces, lexPiece{literal: hi[:k+1] + piece.literal, chars: piece.chars, rest: piece.rest})
+	}
+	return pieces
+}
+
+// lexCharsBetween returns the characters of alphabet strictly between lo and hi.
+func lexCharsBetween(lo, hi byte, alphabet string) string {
+	chars := ""
+	for i := 0; i < len(alphabet); i++ {
+		if ch := alphabet[i]; ch > lo && ch < hi {
+			chars += string(ch)
+		}
+	}
+	return chars
+}
+
+// letterClass compresses a sorted list of letters into a character class.
+func letterClass(letters string) string {
+	if len(letters) == 1 {
+		return letters
+	}
+	class := ""
+	for i := 0; i < len(letters); {
+		j := i
+		for j+1 < len(letters) && letters[j+1] == letters[j]+1 {
+			j++
+		}
+		switch {
+		case j-i >= 2:
+			class += string(letters[i]) + "-" + string(letters[j])
+		default:
+			class += letters[i : j+1]
+		}
+		i = j + 1
+	}
+	return "[" + class + "]"
+}
+
 // digitRangeUp returns a pattern matching digits from d to 9.
 func digitRangeUp(d int) string {
 	if d == 9 {
@@ -1,7 +1,10 @@
 package convert
 
 import (
+	"math/big"
 	"regexp"
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -39,8 +42,8 @@ func TestNumGreaterOrEqual(t *testing.T) {
 		{
 			name:           "two digits 15",
 			n:              15,
-			shouldMatch:    []string{"15", "16", "19", "20", "21", "99", "100", "999"},
-			shouldNotMatch: []string{"0", "1", "9", "10", "11", "14"},
+			shouldMatch:    []string{"15", "16", "19", "20", "21", "99", "100", "999", "015", "0100"},
+			shouldNotMatch: []string{"0", "1", "9", "10", "11", "14", "009", "014"},
 		},
 		{
 			name:           "two digits 99",
@@ -502,3 +505,207 @@ func TestExactVersionTemplate(t *testing.T) {
 		}
 	}
 }
+
+// TestNumRange tests NumRange against every number up to 1200 for a set of bounds.
+func TestNumRange(t *testing.T) {
+	bounds := [][2]int{
+		{0, 0}, {0, 9}, {3, 7}, {8, 12}, {15, 99}, {0, 100}, {10, 19}, {99, 101},
+		{123, 456}, {100, 999}, {7, 1000}, {5, 5}, {42, 1105}, {-3, 4},
+	}
+
+	for _, b := range bounds {
+		pattern := NumRange(b[0], b[1])
+		t.Run(pattern, func(t *testing.T) {
+			re, err := regexp.Compile("^" + pattern + "$")
+			if err != nil {
+				t.Fatalf("NumRange(%d, %d) returned invalid pattern %q: %v", b[0], b[1], pattern, err)
+			}
+			for n := 0; n <= 1200; n++ {
+				expected := n >= b[0] && n <= b[1]
+				if re.MatchString(strconv.Itoa(n)) != expected {
+					t.Errorf("NumRange(%d, %d) pattern %q matching %d: expected %v", b[0], b[1], pattern, n, expected)
+				}
+			}
+		})
+	}
+
+	if pattern := NumRange(5, 4); pattern != NEVER_MATCH {
+		t.Errorf("NumRange(5, 4) = %q, expected NEVER_MATCH", pattern)
+	}
+}
+
+// TestLeadingZeroPolicy tests the policy builders against every number up to 1200,
+// written with and without leading zeros.
+func TestLeadingZeroPolicy(t *testing.T) {
+	bounds := [][2]string{{"0", "0"}, {"0", "9"}, {"3", "7"}, {"8", "12"}, {"15", "99"}, {"10", "19"}, {"99", "101"}, {"123", "1105"}, {"007", "010"}}
+	policies := map[LeadingZeroPolicy]string{LEADING_ZEROS_NUMERIC: "numeric", LEADING_ZEROS_REJECT: "reject"}
+
+	for policy, name := range policies {
+		check := func(builder, pattern string, want func(int) bool) {
+			re, err := regexp.Compile("^" + pattern + "$")
+			if err != nil {
+				t.Fatalf("%s %s returned invalid pattern %q: %v", name, builder, pattern, err)
+			}
+			for n := 0; n <= 1200; n++ {
+				for _, padding := range []string{"", "0", "00"} {
+					written := padding + strconv.Itoa(n)
+					expected := want(n) && (padding == "" || policy == LEADING_ZEROS_NUMERIC)
+					if re.MatchString(written) != expected {
+						t.Errorf("%s %s pattern %q matching %s: expected %v", name, builder, pattern, written, expected)
+					}
+				}
+			}
+		}
+
+		check("Digits()", policy.Digits(), func(int) bool { return true })
+		for _, b := range bounds {
+			lo, _ := strconv.Atoi(b[0])
+			hi, _ := strconv.Atoi(b[1])
+			check("Equal("+b[0]+")", policy.Equal(b[0]), func(n int) bool { return n == lo })
+			check("GreaterOrEqual("+b[0]+")", policy.GreaterOrEqual(b[0]), func(n int) bool { return n >= lo })
+			check("LessOrEqual("+b[1]+")", policy.LessOrEqual(b[1]), func(n int) bool { return n <= hi })
+			check("Range("+b[0]+", "+b[1]+")", policy.Range(b[0], b[1]), func(n int) bool { return n >= lo && n <= hi })
+		}
+
+		for _, pattern := range []string{policy.Equal("1.5"), policy.GreaterOrEqual(""), policy.Range("9", "8")} {
+			if pattern != NEVER_MATCH {
+				t.Errorf("%s builder returned %q for an invalid bound, expected NEVER_MATCH", name, pattern)
+			}
+		}
+	}
+}
+
+// TestDecimalBuilders tests the decimal string builders with bounds that do not fit
+// in an int against math/big comparisons of numbers around the bounds.
+func TestDecimalBuilders(t *testing.T) {
+	bounds := []string{
+		"0", "9", "15", "1000", "20240115103045000000", "99999999999999999999",
+		"100000000000000000000", "18446744073709551616", "000123",
+	}
+
+	// Candidates: every bound, its neighbours, and numbers with one digit more or less
+	var candidates []*big.Int
+	for _, bound := range bounds {
+		n, _ := new(big.Int).SetString(bound, 10)
+		for _, delta := range []int64{-11, -1, 0, 1, 10} {
+			if c := new(big.Int).Add(n, big.NewInt(delta)); c.Sign() >= 0 {
+				candidates = append(candidates, c)
+			}
+		}
+		candidates = append(candidates, new(big.Int).Mul(n, big.NewInt(10)), new(big.Int).Div(n, big.NewInt(10)))
+	}
+
+	check := func(name, pattern string, want func(*big.Int) bool) {
+		re, err := regexp.Compile("^" + pattern + "$")
+		if err != nil {
+			t.Fatalf("%s returned invalid pattern %q: %v", name, pattern, err)
+		}
+		for _, c := range candidates {
+			if re.MatchString(c.String()) != want(c) {
+				t.Errorf("%s pattern %q matching %s: expected %v", name, pattern, c, want(c))
+			}
+		}
+	}
+
+	for _, lo := range bounds {
+		low, _ := new(big.Int).SetString(lo, 10)
+		check("DecimalGreaterOrEqual("+lo+")", DecimalGreaterOrEqual(lo), func(c *big.Int) bool { return c.Cmp(low) >= 0 })
+		check("DecimalLessOrEqual("+lo+")", DecimalLessOrEqual(lo), func(c *big.Int) bool { return c.Cmp(low) <= 0 })
+		for _, hi := range bounds {
+			high, _ := new(big.Int).SetString(hi, 10)
+			check("DecimalRange("+lo+", "+hi+")", DecimalRange(lo, hi), func(c *big.Int) bool {
+				return c.Cmp(low) >= 0 && c.Cmp(high) <= 0
+			})
+		}
+	}
+
+	for _, invalid := range []string{"", "1.5", "-3", "x"} {
+		if pattern := DecimalGreaterOrEqual(invalid); pattern != NEVER_MATCH {
+			t.Errorf("DecimalGreaterOrEqual(%q) = %q, expected NEVER_MATCH", invalid, pattern)
+		}
+	}
+}
+
+// TestDecimalBuildersMatchIntBuilders tests that the int builders and the decimal
+// string builders emit the same patterns.
+func TestDecimalBuildersMatchIntBuilders(t *testing.T) {
+	for _, n := range []int{1, 5, 15, 100, 123, 9999999999} {
+		s := strconv.Itoa(n)
+		if got, want := DecimalGreaterOrEqual(s), NumGreaterOrEqual(n); got != want {
+			t.Errorf("DecimalGreaterOrEqual(%s) = %q, NumGreaterOrEqual = %q", s, got, want)
+		}
+		if got, want := DecimalLessOrEqual(s), NumLessOrEqual(n); got != want {
+			t.Errorf("DecimalLessOrEqual(%s) = %q, NumLessOrEqual = %q", s, got, want)
+		}
+		if got, want := DecimalRange("7", s), NumRange(7, n); got != want {
+			t.Errorf("DecimalRange(7, %s) = %q, NumRange = %q", s, got, want)
+		}
+	}
+}
+
+// TestDecimalArithmetic tests the decimal string helpers.
+func TestDecimalArithmetic(t *testing.T) {
+	tests := []struct {
+		n, trimmed, next, previous string
+	}{
+		{"1", "1", "2", "0"},
+		{"009", "9", "10", "8"},
+		{"100", "100", "101", "99"},
+		{"99999999999999999999", "99999999999999999999", "100000000000000000000", "99999999999999999998"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.n, func(t *testing.T) {
+			trimmed := trimDecimal(tt.n)
+			if trimmed != tt.trimmed {
+				t.Errorf("trimDecimal(%q) = %q, expected %q", tt.n, trimmed, tt.trimmed)
+			}
+			if next := incrementDecimal(trimmed); next != tt.next {
+				t.Errorf("incrementDecimal(%q) = %q, expected %q", trimmed, next, tt.next)
+			}
+			if previous := decrementDecimal(trimmed); previous != tt.previous {
+				t.Errorf("decrementDecimal(%q) = %q, expected %q", trimmed, previous, tt.previous)
+			}
+			if c := compareDecimals(tt.n, strings.Repeat("0", 3)+tt.trimmed); c != 0 {
+				t.Errorf("compareDecimals(%q, padded) = %d, expected 0", tt.n, c)
+			}
+		})
+	}
+
+	if trimDecimal("000") != "0" {
+		t.Errorf("trimDecimal(%q) = %q, expected %q", "000", trimDecimal("000"), "0")
+	}
+	if compareDecimals("99", "100") != -1 || compareDecimals("100", "99") != 1 {
+		t.Error("compareDecimals should order numbers of different lengths by value")
+	}
+}
+
+// TestWordComplementPattern tests that the complement pattern rejects exactly the excluded words.
+func TestWordComplementPattern(t *testing.T) {
+	excluded := []string{"a", "alpha", "b", "beta", "rc", "sp"}
+	re := regexp.MustCompile("^" + wordComplementPattern(excluded) + "$")
+
+	for _, word := range excluded {
+		if re.MatchString(word) {
+			t.Errorf("complement pattern should not match excluded word %q", word)
+		}
+	}
+	for _, word := range []string{"al", "alph", "alphas", "ab", "bet", "betas", "r", "rcs", "s", "spx", "jre", "android", "z"} {
+		if !re.MatchString(word) {
+			t.Errorf("complement pattern should match %q", word)
+		}
+	}
+	if re.MatchString("") {
+		t.Error("complement pattern should not match the empty string")
+	}
+}
+
+// TestNeverMatchPattern tests that NEVER_MATCH_PATTERN compiles and matches nothing.
+func TestNeverMatchPattern(t *testing.T) {
+	re := regexp.MustCompile(NEVER_MATCH_PATTERN)
+	for _, s := range []string{"", "1.0.0", "a", "\x00"} {
+		if re.MatchString(s) {
+			t.Errorf("NEVER_MATCH_PATTERN should not match %q", s)
+		}
+	}
+}
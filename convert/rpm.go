@@ -0,0 +1,368 @@
+// Package convert provides RPM package version handling functionality.
+// This file contains functions specific to RPM versions and requirements, following
+// rpm's version comparison (rpmvercmp):
+//   - versions are written [epoch:]version[-release] and a missing epoch is 0
+//   - versions and releases are split into alphabetic and numeric segments, ignoring
+//     the separators between them: 1.0a.2 has the segments 1, 0, a and 2
+//   - numeric segments compare numerically and sort after alphabetic segments, which
+//     compare in ASCII order: 1.0a < 1.0.1
+//   - ~ sorts before anything, even the end of the version, and ^ sorts after the end
+//     of the version but before anything else: 1.0~rc1 < 1.0 < 1.0^git1 < 1.0.1
+//   - a requirement without release matches every release of its version:
+//     = 1.0 matches 1.0-1 and 1.0-2.el8
+//   - requirements separated by commas must all hold
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// rpmSeparator matches one of the separators between segments.
+const rpmSeparator = `[+._]`
+
+// rpmChars lists the characters allowed in versions and releases, in ASCII order.
+const rpmChars = "+.0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ^_abcdefghijklmnopqrstuvwxyz~"
+
+// rpmVersionRegex splits an RPM version into epoch, version and release.
+var rpmVersionRegex = regexp.MustCompile(`^(?:(\d+):)?([A-Za-z0-9._+~^]+)(?:-([A-Za-z0-9._+~^]+))?$`)
+
+// rpmOperatorRegex splits a requirement into operator and version.
+var rpmOperatorRegex = regexp.MustCompile(`^(<=|>=|==|=|<|>)?\s*(\S+)$`)
+
+// rpmScheme describes rpm version comparison.
+var rpmScheme = distroScheme{
+	versionOrder:    rpmTokenOrder{},
+	releaseOrder:    rpmTokenOrder{},
+	optionalRelease: true,
+}
+
+// parseRPMRequirement parses RPM version requirements such as ">= 1:2.30-1.el8" or
+// ">= 1.0, < 2.0".
+//
+// The whole requirement list is validated, and the returned constraint holds it with
+// surrounding whitespace removed.
+func parseRPMRequirement(versionStr string) (*VersionConstraint, error) {
+	versionStr = strings.TrimSpace(versionStr)
+	if _, err := parseRPMRequirements(versionStr); err != nil {
+		return nil, err
+	}
+
+	return &VersionConstraint{
+		Operator: OP_RPM_REQUIREMENT,
+		Version:  versionStr,
+	}, nil
+}
+
+// rpmRequirementRegex creates a regex matching the RPM versions allowed by a list of
+// requirements.
+// Result: ^(?:(?:0+:)?(?:...)|0*(?:[2-9]|...):...)$ matching 1:2.31-1.el8 and 2:1.0 but not 2.31-1 (for ">= 1:2.30-1.el8")
+func rpmRequirementRegex(requirements string) (string, error) {
+	ranges, err := parseRPMRequirements(requirements)
+	if err != nil {
+		return "", err
+	}
+
+	pattern, ok := rpmScheme.rangesPattern(ranges)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + REGEX_END, nil
+}
+
+// parseRPMRequirements returns the version range allowed by a comma-separated list
+// of requirements.
+func parseRPMRequirements(requirements string) ([]distroRange, error) {
+	if strings.TrimSpace(requirements) == "" {
+		return nil, fmt.Errorf("empty RPM version requirement")
+	}
+
+	ranges := []distroRange{{}}
+	for _, requirement := range strings.Split(requirements, ",") {
+		r, err := parseRPMSingleRequirement(strings.TrimSpace(requirement))
+		if err != nil {
+			return nil, err
+		}
+		ranges = rpmScheme.intersect(ranges, []distroRange{r})
+	}
+	return ranges, nil
+}
+
+// parseRPMSingleRequirement parses one requirement such as "> 1.0" or "= 2:1.0-1".
+// A version without operator means =.
+func parseRPMSingleRequirement(requirement string) (distroRange, error) {
+	matches := rpmOperatorRegex.FindStringSubmatch(requirement)
+	if matches == nil {
+		return distroRange{}, fmt.Errorf("invalid RPM version requirement: %q", requirement)
+	}
+	version, err := parseRPMVersion(matches[2])
+	if err != nil {
+		return distroRange{}, err
+	}
+
+	inclusive := &distroBound{distroVersion: version, inclusive: true}
+	exclusive := &distroBound{distroVersion: version}
+	switch matches[1] {
+	case "<":
+		return distroRange{upper: exclusive}, nil
+	case "<=":
+		return distroRange{upper: inclusive}, nil
+	case ">=":
+		return distroRange{lower: inclusive}, nil
+	case ">":
+		return distroRange{lower: exclusive}, nil
+	default:
+		return distroRange{lower: inclusive, upper: inclusive}, nil
+	}
+}
+
+// parseRPMVersion parses an RPM version such as "1:2.30-1.el8".
+func parseRPMVersion(version string) (distroVersion, error) {
+	matches := rpmVersionRegex.FindStringSubmatch(version)
+	if matches == nil || len(rpmTokenOrder{}.tokenize(matches[2])) == 0 ||
+		(matches[3] != "" && len(rpmTokenOrder{}.tokenize(matches[3])) == 0) {
+		return distroVersion{}, fmt.Errorf("invalid RPM version: %s", version)
+	}
+
+	epoch := 0
+	if matches[1] != "" {
+		var err error
+		if epoch, err = strconv.Atoi(matches[1]); err != nil {
+			return distroVersion{}, fmt.Errorf("invalid RPM epoch in %s: %w", version, err)
+		}
+	}
+	return distroVersion{
+		epoch:      epoch,
+		version:    matches[2],
+		release:    matches[3],
+		hasRelease: matches[3] != "",
+	}, nil
+}
+
+// compareRPMVersions orders two RPM versions like rpmdev-vercmp, comparing releases
+// only when both versions have one.
+func compareRPMVersions(a, b string) (int, error) {
+	va, err := parseRPMVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	vb, err := parseRPMVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	return rpmScheme.compare(va, vb), nil
+}
+
+// rpmTokenOrder implements distroTokenOrder for rpmvercmp. Fields are split into ~,
+// ^, runs of letters and runs of digits, and the other characters only separate them.
+type rpmTokenOrder struct{}
+
+// rpmTokenRanks orders the kinds of tokens.
+var rpmTokenRanks = map[distroTokenKind]int{
+	distroTokenTilde:  0,
+	distroTokenEnd:    1,
+	distroTokenCaret:  2,
+	distroTokenAlpha:  3,
+	distroTokenNumber: 4,
+}
+
+// tokenize splits a field into segments, ~ and ^.
+func (o rpmTokenOrder) tokenize(field string) []distroToken {
+	var tokens []distroToken
+	for i := 0; i < len(field); {
+		c := field[i]
+		switch {
+		case c == '~':
+			tokens = append(tokens, distroToken{kind: distroTokenTilde, text: "~"})
+			i++
+		case c == '^':
+			tokens = append(tokens, distroToken{kind: distroTokenCaret, text: "^"})
+			i++
+		case isDigit(c):
+			j := i
+			for j < len(field) && isDigit(field[j]) {
+				j++
+			}
+			tokens = append(tokens, distroToken{kind: distroTokenNumber, value: trimDecimal(field[i:j])})
+			i = j
+		case isAlphanumeric(c):
+			j := i
+			for j < len(field) && isAlphanumeric(field[j]) && !isDigit(field[j]) {
+				j++
+			}
+			tokens = append(tokens, distroToken{kind: distroTokenAlpha, text: field[i:j]})
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
+
+// compare orders two tokens: ~ < end < ^ < letters < numbers.
+func (o rpmTokenOrder) compare(a, b distroToken) int {
+	if c := compareInts(rpmTokenRanks[a.kind], rpmTokenRanks[b.kind]); c != 0 {
+		return c
+	}
+	switch a.kind {
+	case distroTokenAlpha:
+		return strings.Compare(a.text, b.text)
+	case distroTokenNumber:
+		return compareDecimals(a.value, b.value)
+	}
+	return 0
+}
+
+// between matches the tokens strictly between lo and hi, with the separators before them.
+func (o rpmTokenOrder) between(lo, hi *distroToken, prev distroTokenKind) []distroTokenPattern {
+	// inRange reports whether tokens of kind might sort between the bounds, and
+	// returns the bounds of the same kind that limit them
+	inRange := func(kind distroTokenKind) (bool, *distroToken, *distroToken) {
+		rank := rpmTokenRanks[kind]
+		var from, to *distroToken
+		if lo != nil {
+			switch c := compareInts(rpmTokenRanks[lo.kind], rank); {
+			case c > 0:
+				return false, nil, nil
+			case c == 0:
+				from = lo
+			}
+		}
+		if hi != nil {
+			switch c := compareInts(rpmTokenRanks[hi.kind], rank); {
+			case c < 0:
+				return false, nil, nil
+			case c == 0:
+				to = hi
+			}
+		}
+		return true, from, to
+	}
+
+	var patterns []distroTokenPattern
+	for _, kind := range []distroTokenKind{distroTokenTilde, distroTokenCaret} {
+		if ok, from, to := inRange(kind); ok && from == nil && to == nil {
+			patterns = append(patterns, distroTokenPattern{pattern: o.separator(prev, kind) + regexp.QuoteMeta(rpmTokenText(kind)), kind: kind})
+		}
+	}
+
+	if ok, from, to := inRange(distroTokenAlpha); ok {
+		var pieces []lexPiece
+		switch {
+		case from == nil && to == nil:
+			pieces = []lexPiece{{chars: distroLetters, rest: true}}
+		case to == nil:
+			pieces = lexAbove(from.text, distroLetters)
+		case from == nil:
+			pieces = lexBelow(to.text, distroLetters)
+		case from.text < to.text:
+			pieces = lexBetween(from.text, to.text, distroLetters)
+		}
+		if pattern, ok := groupAlternatives(rpmAlphaPatterns(pieces)); ok && pattern != "" {
+			patterns = append(patterns, distroTokenPattern{pattern: o.separator(prev, distroTokenAlpha) + pattern, kind: distroTokenAlpha})
+		}
+	}
+
+	if ok, from, to := inRange(distroTokenNumber); ok {
+		// An empty max means no upper limit
+		min, max := "0", ""
+		if from != nil {
+			min = incrementDecimal(from.value)
+		}
+		if to != nil {
+			if to.value == "0" {
+				return patterns
+			}
+			max = decrementDecimal(to.value)
+		}
+		if max == "" || compareDecimals(min, max) <= 0 {
+			patterns = append(patterns, distroTokenPattern{pattern: o.separator(prev, distroTokenNumber) + paddedDecimalRange(min, max), kind: distroTokenNumber})
+		}
+	}
+	return patterns
+}
+
+// equal matches the token t with the separators before it. No token equals the end
+// marker.
+func (o rpmTokenOrder) equal(t distroToken, prev distroTokenKind) []distroTokenPattern {
+	var pattern string
+	switch t.kind {
+	case distroTokenEnd:
+		return nil
+	case distroTokenNumber:
+		pattern = paddedDecimalRange(t.value, t.value)
+	default:
+		pattern = regexp.QuoteMeta(t.text)
+	}
+	return []distroTokenPattern{{pattern: o.separator(prev, t.kind) + pattern, kind: t.kind}}
+}
+
+// tail matches any rest of a field. At the start, it has at least one token, and
+// after a segment, it cannot continue that segment.
+func (o rpmTokenOrder) tail(prev distroTokenKind) string {
+	any := distroTokenClass(rpmChars)
+	switch prev {
+	case distroTokenStart:
+		return rpmSeparator + "*" + distroTokenClass(rpmCharsWithout("+._")) + any + "*"
+	case distroTokenNumber:
+		return "(?:" + distroTokenClass(rpmCharsWithout(distroDigits)) + any + "*)?"
+	case distroTokenAlpha:
+		return "(?:" + distroTokenClass(rpmCharsWithout(distroLetters)) + any + "*)?"
+	default:
+		return any + "*"
+	}
+}
+
+// end matches the end of a field, with trailing separators.
+func (o rpmTokenOrder) end(prev distroTokenKind) string {
+	return rpmSeparator + "*"
+}
+
+// separator matches the separators before a token of kind next. Segments of the
+// same kind need one, as they would otherwise form a single segment.
+func (o rpmTokenOrder) separator(prev, next distroTokenKind) string {
+	if prev == next && (next == distroTokenAlpha || next == distroTokenNumber) {
+		return rpmSeparator + "+"
+	}
+	return rpmSeparator + "*"
+}
+
+// rpmTokenText returns the text of ~ and ^ tokens.
+func rpmTokenText(kind distroTokenKind) string {
+	if kind == distroTokenTilde {
+		return "~"
+	}
+	return "^"
+}
+
+// rpmCharsWithout returns rpmChars without the given characters.
+func rpmCharsWithout(chars string) string {
+	var kept strings.Builder
+	for i := 0; i < len(rpmChars); i++ {
+		if strings.IndexByte(chars, rpmChars[i]) < 0 {
+			kept.WriteByte(rpmChars[i])
+		}
+	}
+	return kept.String()
+}
+
+// rpmAlphaPatterns renders lexPieces over letters as patterns of whole alphabetic
+// segments, leaving out the empty string.
+func rpmAlphaPatterns(pieces []lexPiece) []string {
+	var patterns []string
+	for _, piece := range pieces {
+		switch {
+		case piece.chars != "":
+			pattern := piece.literal + letterClass(piece.chars)
+			if piece.rest {
+				pattern += "[A-Za-z]*"
+			}
+			patterns = append(patterns, pattern)
+		case !piece.rest && piece.literal != "":
+			patterns = append(patterns, piece.literal)
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,116 @@
+// Package convert provides tests for RPM package version handling functionality.
+// This file contains unit tests for rpmvercmp ordering and requirement regexes.
+package convert
+
+import (
+	"testing"
+)
+
+// rpmCorpus lists RPM versions used to check requirement regexes against
+// compareRPMVersions.
+var rpmCorpus = []string{
+	"0", "1", "1.0", "1.00", "1_0", "1.0.0", "1.0.", "1.0~rc1", "1.0~~", "1.0~", "1.0^", "1.0^git1",
+	"1.0^git1~1", "1.0a", "1.0A", "1.0.a", "1.0a1", "1.0ab", "1.0.1", "1.0-1", "1.0-1.el8",
+	"1.0-1.el8_2", "1.0-2", "1.0-10", "1.0-1~1", "1.0-1^1", "1.0-0", "1.0-el8", "1.1", "1.10",
+	"1.9", "2", "2.0~beta", "2.30", "2.30-1", "2.30-1.el8", "2.30-1.el9", "2.30-2.el8", "2.31-1",
+	"1:0.1", "1:2.30", "1:2.30-1.el8", "1:2.30-1.el8_1", "1:2.30-2", "01:2.30-1.el8", "1:2.31",
+	"2:1.0", "0:1.0", "10:0", "a", "1.0b", "1.0+1", "1.0.z", "1.0^20240115103045000000",
+	"1.0^20240115103045000001", "1.0^020240115103045000000", "1.0^9223372036854775808",
+}
+
+// TestCompareRPMVersions tests rpmvercmp ordering on known pairs.
+func TestCompareRPMVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.0~rc1", "1.0", -1},
+		{"1.0", "1.0^git1", -1},
+		{"1.0^git1", "1.0.1", -1},
+		{"1.0^git1~1", "1.0^git1", -1},
+		{"1.0a", "1.0.1", -1},
+		{"1.0a", "1.0.a", 0},
+		{"1.00", "1.0", 0},
+		{"1.0.", "1.0", 0},
+		{"1.0^20240115103045000001", "1.0^20240115103045000000", 1},
+		{"1.0^020240115103045000000", "1.0^20240115103045000000", 0},
+		{"1.0^9223372036854775808", "1.0^20240115103045000000", -1},
+		{"1.0-1", "1.0", 0},
+		{"1.0-1.el8", "1.0-1", 1},
+		{"1.0-10", "1.0-2", 1},
+		{"1:0.1", "2.30", 1},
+		{"0:1.0", "1.0", 0},
+		{"1.0A", "1.0a", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			c, err := compareRPMVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("compareRPMVersions(%q, %q) returned unexpected error: %v", tt.a, tt.b, err)
+			}
+			if c != tt.expected {
+				t.Errorf("compareRPMVersions(%q, %q) = %d, expected %d", tt.a, tt.b, c, tt.expected)
+			}
+		})
+	}
+}
+
+// TestRPMRequirementRegex compares requirement regexes with compareRPMVersions over
+// rpmCorpus.
+func TestRPMRequirementRegex(t *testing.T) {
+	bounds := []string{"1.0", "1.0~rc1", "1.0^git1", "1.0-1", "1.0-1.el8", "1.0a", "2.30-1.el8", "1:2.30-1.el8", "1:2.30", "0.1", "2", "1.0^20240115103045000000"}
+	operators := map[string]func(int) bool{
+		"<":  func(c int) bool { return c < 0 },
+		"<=": func(c int) bool { return c <= 0 },
+		"=":  func(c int) bool { return c == 0 },
+		">=": func(c int) bool { return c >= 0 },
+		">":  func(c int) bool { return c > 0 },
+	}
+
+	check := func(t *testing.T, requirement string, want func(string) bool) {
+		regex, err := VersionToRegexWithOptions(requirement, Options{Ecosystem: ECOSYSTEM_RPM})
+		if err != nil {
+			t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", requirement, err)
+		}
+		for _, version := range rpmCorpus {
+			if expected := want(version); regex.MatchString(version) != expected {
+				t.Errorf("%s: pattern %q matching %q: expected %v", requirement, regex.String(), version, expected)
+			}
+		}
+	}
+	satisfies := func(version, operator, bound string) bool {
+		c, err := compareRPMVersions(version, bound)
+		if err != nil {
+			t.Fatalf("compareRPMVersions(%q, %q) returned unexpected error: %v", version, bound, err)
+		}
+		return operators[operator](c)
+	}
+
+	for operator := range operators {
+		for _, bound := range bounds {
+			check(t, operator+" "+bound, func(v string) bool { return satisfies(v, operator, bound) })
+		}
+	}
+	for _, lo := range bounds {
+		for _, hi := range bounds {
+			for _, loOperator := range []string{">=", ">"} {
+				for _, hiOperator := range []string{"<=", "<"} {
+					requirement := loOperator + " " + lo + ", " + hiOperator + " " + hi
+					check(t, requirement, func(v string) bool {
+						return satisfies(v, loOperator, lo) && satisfies(v, hiOperator, hi)
+					})
+				}
+			}
+		}
+	}
+}
+
+// TestRPMRequirementErrors tests rejection of invalid requirements.
+func TestRPMRequirementErrors(t *testing.T) {
+	for _, requirement := range []string{"", ">= ", "=> 1.0", ">= 1.0-1-1", ">= 1.0,", ">= x:1.0", ">= 1.0/2"} {
+		if _, err := VersionToRegexWithOptions(requirement, Options{Ecosystem: ECOSYSTEM_RPM}); err == nil {
+			t.Errorf("VersionToRegexWithOptions(%q) expected error but got none", requirement)
+		}
+	}
+}
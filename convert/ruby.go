@@ -0,0 +1,576 @@
+// Package convert provides RubyGems version handling functionality.
+// This file contains functions specific to RubyGems requirement lists, such as
+// those written in a Gemfile or gemspec ('~> 2.1', '>= 2.1.3'), and to the
+// ordering rules of Gem::Version.
+//
+// Gem::Version splits a version into segments at dots and at every switch between
+// digits and letters, so 1.0.0.rc1 is [1, 0, 0, "rc", 1]. A version is a
+// pre-release as soon as one segment contains a letter. Trailing zero segments are
+// ignored (1.0 == 1), and when comparing, letter segments sort before numbers,
+// including the implicit zeros of a shorter version. Hence 1.0.0.a < 1.0.0.rc1 < 1.0.0.
+package convert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RUBY_PRERELEASE_PATTERN matches any optional RubyGems pre-release part
+// Format: .pre, .rc1, .beta.2, a, .pre.rc.1, etc.
+const RUBY_PRERELEASE_PATTERN = `(?:\.?[A-Za-z]+(?:\.?[A-Za-z0-9]+)*)?`
+
+// rubyRequirementEntryRegex recognizes a single requirement, with an optional operator.
+var rubyRequirementEntryRegex = regexp.MustCompile(`^(=|!=|>=|<=|>|<|~>)?\s*(\S+)$`)
+
+// rubyVersionRegex splits a gem version into its release segments and its pre-release part.
+var rubyVersionRegex = regexp.MustCompile(`^(\d+(?:\.\d+)*)(` + RUBY_PRERELEASE_PATTERN + `)$`)
+
+// rubySegmentRegex extracts the segments of a gem pre-release part.
+var rubySegmentRegex = regexp.MustCompile(`[A-Za-z]+|\d+`)
+
+// RUBY_BEFORE_PRERELEASES is the suffix of a bound placed before every pre-release
+// of its release segments, which no gem version can spell
+const RUBY_BEFORE_PRERELEASES = "~"
+
+// rubyRequirement is a single parsed entry of a requirement list.
+type rubyRequirement struct {
+	operator string
+	version  string
+}
+
+// parseRubyRequirement parses a RubyGems requirement list such as '~> 2.1', '>= 2.1.3'.
+//
+// Entries are separated by commas and may be quoted, as they appear in a Gemfile.
+// An entry without an operator is an exact match (=). An empty list matches every
+// version, like Gem::Requirement.default (>= 0).
+//
+// The returned constraint holds the normalized list, e.g. "~> 2.1, >= 2.1.3".
+func parseRubyRequirement(versionStr string) (*VersionConstraint, error) {
+	requirements, err := parseRubyRequirementList(versionStr)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]string, len(requirements))
+	for i, requirement := range requirements {
+		entries[i] = requirement.operator + " " + requirement.version
+	}
+
+	return &VersionConstraint{
+		Operator: OP_RUBY_REQUIREMENT,
+		Version:  strings.Join(entries, ", "),
+	}, nil
+}
+
+// parseRubyRequirementList splits and validates the entries of a requirement list.
+func parseRubyRequirementList(list string) ([]rubyRequirement, error) {
+	if strings.TrimSpace(list) == "" {
+		return []rubyRequirement{{operator: OP_GREATER_EQUAL, version: "0"}}, nil
+	}
+
+	var requirements []rubyRequirement
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.Trim(strings.TrimSpace(entry), `'"`)
+		matches := rubyRequirementEntryRegex.FindStringSubmatch(strings.TrimSpace(entry))
+		if matches == nil {
+			return nil, fmt.Errorf("invalid RubyGems requirement: %q", entry)
+		}
+		if !rubyVersionRegex.MatchString(matches[2]) {
+			return nil, fmt.Errorf("invalid gem version: %s", matches[2])
+		}
+
+		operator := matches[1]
+		if operator == "" {
+			operator = OP_EQUAL
+		}
+		requirements = append(requirements, rubyRequirement{operator: operator, version: matches[2]})
+	}
+	return requirements, nil
+}
+
+// rubyRequirementRegex creates a regex matching the gem versions that satisfy every
+// entry of a RubyGems requirement list.
+// Result: ^2(?:...)$ matching 2.1, 2.5.0, 2.9.rc1 but not 3.0 (for "~> 2.1")
+func rubyRequirementRegex(list string, opts Options) (string, error) {
+	requirements, err := parseRubyRequirementList(list)
+	if err != nil {
+		return "", err
+	}
+
+	order := rubyPrereleaseOrder{}
+	ranges := []versionRange{{}}
+	for _, requirement := range requirements {
+		requirementRanges, err := rubyRequirementRanges(requirement)
+		if err != nil {
+			return "", err
+		}
+		ranges = intersectRanges(ranges, requirementRanges, order)
+	}
+
+	pattern, ok := seqBuilder{order: order, zeros: opts.LeadingZeros}.rangesPattern(ranges)
+	if !ok {
+		return NEVER_MATCH_PATTERN, nil
+	}
+	return REGEX_START + pattern + REGEX_END, nil
+}
+
+// rubyRequirementRanges returns the version ranges allowed by a single requirement.
+//
+// The pessimistic operator ~> drops the last release segment and increments the
+// one before it: ~> 2.1 means >= 2.1, < 3 and ~> 2.1.3 means >= 2.1.3, < 2.2.
+// Like Gem::Requirement, the upper bound compares release segments only, so the
+// pre-releases of the bumped version (3.0.0.pre) are excluded as well.
+func rubyRequirementRanges(requirement rubyRequirement) ([]versionRange, error) {
+	bound, err := parseRubyBound(requirement.version)
+	if err != nil {
+		return nil, err
+	}
+	exclusive := *bound
+	exclusive.inclusive = false
+
+	switch requirement.operator {
+	case OP_EQUAL:
+		return []versionRange{{lower: bound, upper: bound}}, nil
+	case OP_NOT_EQUAL:
+		return []versionRange{{upper: &exclusive}, {lower: &exclusive}}, nil
+	case OP_GREATER_EQUAL:
+		return []versionRange{{lower: bound}}, nil
+	case OP_GREATER:
+		return []versionRange{{lower: &exclusive}}, nil
+	case OP_LESS_EQUAL:
+		return []versionRange{{upper: bound}}, nil
+	case OP_LESS:
+		return []versionRange{{upper: &exclusive}}, nil
+	case OP_PESSIMISTIC:
+		bumped := append([]string(nil), bound.parts...)
+		if len(bumped) > 1 {
+			bumped = bumped[:len(bumped)-1]
+		}
+		bumped[len(bumped)-1] = incrementDecimal(bumped[len(bumped)-1])
+		return []versionRange{{lower: bound, upper: &rangeBound{parts: bumped, suffix: RUBY_BEFORE_PRERELEASES}}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported RubyGems operator: %s", requirement.operator)
+	}
+}
+
+// parseRubyBound parses a gem version such as 2.1, 1.0.0.rc1 or 1.0a into an inclusive range bound.
+func parseRubyBound(version string) (*rangeBound, error) {
+	matches := rubyVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return nil, fmt.Errorf("invalid gem version: %s", version)
+	}
+
+	parts, ok := parseDecimalParts(matches[1])
+	if !ok {
+		return nil, fmt.Errorf("invalid gem version: %s", version)
+	}
+	return &rangeBound{parts: parts, suffix: matches[2], inclusive: true}, nil
+}
+
+// rubyBoundText formats a gem version bound, leaving out RUBY_BEFORE_PRERELEASES.
+func rubyBoundText(b *rangeBound) string {
+	return strings.Join(b.parts, ".") + strings.TrimSuffix(b.suffix, RUBY_BEFORE_PRERELEASES)
+}
+
+// rubySegment is one segment of a gem pre-release part: a word or a number.
+type rubySegment struct {
+	word   string
+	number int
+	isWord bool
+}
+
+// rubySegments splits a gem pre-release part (.rc1, .beta.2) into its segments.
+func rubySegments(prerelease string) []rubySegment {
+	var segments []rubySegment
+	for _, token := range rubySegmentRegex.FindAllString(prerelease, -1) {
+		if value, err := strconv.Atoi(token); err == nil {
+			segments = append(segments, rubySegment{number: value})
+		} else {
+			segments = append(segments, rubySegment{word: token, isWord: true})
+		}
+	}
+	return segments
+}
+
+// compareRubySegments orders two segments like Gem::Version: words compare as
+// strings, numbers numerically, and a word sorts before any number.
+func compareRubySegments(a, b rubySegment) int {
+	switch {
+	case a.isWord && b.isWord:
+		return strings.Compare(a.word, b.word)
+	case a.isWord:
+		return -1
+	case b.isWord:
+		return 1
+	default:
+		return compareInts(a.number, b.number)
+	}
+}
+
+// rubySegmentAt returns segment i, treating missing segments as zero.
+func rubySegmentAt(segments []rubySegment, i int) rubySegment {
+	if i < len(segments) {
+		return segments[i]
+	}
+	return rubySegment{}
+}
+
+// compareZeros compares implicit zero segments with segments[i:]. It returns 1 when
+// the first segment that is not zero is a word, -1 when it is a positive number,
+// and 0 when there is none.
+func compareZeros(segments []rubySegment, i int) int {
+	for ; i < len(segments); i++ {
+		if segments[i].isWord {
+			return 1
+		}
+		if segments[i].number != 0 {
+			return -1
+		}
+	}
+	return 0
+}
+
+// Segment positions used to decide which separators may precede the next segment
+const (
+	rubyAfterRelease = iota // the release segments just ended; a word must come next
+	rubyAfterWord
+	rubyAfterNumber
+)
+
+// rubyPrereleaseOrder implements suffixOrder for gem pre-release parts.
+type rubyPrereleaseOrder struct{}
+
+// rubyWindow is one active bound of rubyPrereleaseOrder.window.
+type rubyWindow struct {
+	segments  []rubySegment
+	inclusive bool
+}
+
+// compare orders two gem pre-release parts. An empty part (a release) sorts last.
+func (o rubyPrereleaseOrder) compare(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == RUBY_BEFORE_PRERELEASES:
+		return -1
+	case b == RUBY_BEFORE_PRERELEASES:
+		return 1
+	}
+
+	sa, sb := rubySegments(a), rubySegments(b)
+	for i := 0; i < max(len(sa), len(sb)); i++ {
+		if c := compareRubySegments(rubySegmentAt(sa, i), rubySegmentAt(sb, i)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// any matches every gem pre-release part.
+// Result: (?:\.?[A-Za-z]+(?:\.?[A-Za-z0-9]+)*)?
+func (o rubyPrereleaseOrder) any() string {
+	return RUBY_PRERELEASE_PATTERN
+}
+
+// atLeast matches pre-release parts ordered after s (or equal to it when inclusive).
+func (o rubyPrereleaseOrder) atLeast(s string, inclusive bool) (string, bool) {
+	if s == RUBY_BEFORE_PRERELEASES {
+		return o.any(), true
+	}
+	return groupAlternatives(o.window(0, rubyAfterRelease, &rubyWindow{rubySegments(s), inclusive}, nil))
+}
+
+// atMost matches pre-release parts ordered before s (or equal to it when inclusive).
+func (o rubyPrereleaseOrder) atMost(s string, inclusive bool) (string, bool) {
+	if s == RUBY_BEFORE_PRERELEASES {
+		return "", false
+	}
+	return groupAlternatives(o.window(0, rubyAfterRelease, nil, &rubyWindow{rubySegments(s), inclusive}))
+}
+
+// between matches pre-release parts ordered between lo and hi.
+func (o rubyPrereleaseOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	switch {
+	case hi == RUBY_BEFORE_PRERELEASES:
+		return "", false
+	case lo == RUBY_BEFORE_PRERELEASES:
+		return o.atMost(hi, hiInclusive)
+	}
+	return groupAlternatives(o.window(0, rubyAfterRelease,
+		&rubyWindow{rubySegments(lo), loInclusive}, &rubyWindow{rubySegments(hi), hiInclusive}))
+}
+
+// window returns alternatives for the segments from index i on that lie between the
+// active bounds lo and hi, whose segments before i equal the ones already matched.
+// A nil bound has been decided already and no longer restricts the segments.
+func (o rubyPrereleaseOrder) window(i, position int, lo, hi *rubyWindow) []string {
+	if lo == nil && hi == nil {
+		return []string{rubyRest(position)}
+	}
+	if (lo == nil || i >= len(lo.segments)) && (hi == nil || i >= len(hi.segments)) {
+		return rubyZeroTails(position, lo, hi)
+	}
+
+	var alternatives []string
+
+	// The pre-release part may stop here, which compares as trailing zeros
+	if rubyStopAllowed(lo, i, 1) && rubyStopAllowed(hi, i, -1) {
+		alternatives = append(alternatives, "")
+	}
+
+	var loSegment, hiSegment *rubySegment
+	if lo != nil {
+		segment := rubySegmentAt(lo.segments, i)
+		loSegment = &segment
+	}
+	if hi != nil {
+		segment := rubySegmentAt(hi.segments, i)
+		hiSegment = &segment
+	}
+	if loSegment != nil && hiSegment != nil && compareRubySegments(*loSegment, *hiSegment) > 0 {
+		return nil
+	}
+
+	// Segment equal to the lower bound's: keep comparing with both bounds when they agree
+	if loSegment != nil {
+		next := hi
+		if hiSegment == nil || compareRubySegments(*loSegment, *hiSegment) != 0 {
+			next = nil
+		}
+		alternatives = append(alternatives, o.segmentThen(i, position, *loSegment, lo, next)...)
+	}
+
+	// Segments strictly between the bounds: the rest no longer matters
+	for _, candidate := range rubySegmentsBetween(loSegment, hiSegment) {
+		if sep, ok := rubySeparator(position, candidate.isWord); ok {
+			next := rubyAfterNumber
+			if candidate.isWord {
+				next = rubyAfterWord
+			}
+			alternatives = append(alternatives, sep+candidate.pattern+rubyRest(next))
+		}
+	}
+
+	// Segment equal to the upper bound's
+	if hiSegment != nil && (loSegment == nil || compareRubySegments(*loSegment, *hiSegment) < 0) {
+		alternatives = append(alternatives, o.segmentThen(i, position, *hiSegment, nil, hi)...)
+	}
+	return alternatives
+}
+
+// segmentThen returns the alternative that matches segment at index i and continues
+// with the bounds lo and hi, if any version can follow.
+func (o rubyPrereleaseOrder) segmentThen(i, position int, segment rubySegment, lo, hi *rubyWindow) []string {
+	sep, ok := rubySeparator(position, segment.isWord)
+	if !ok {
+		return nil
+	}
+	literal, next := strconv.Itoa(segment.number), rubyAfterNumber
+	if segment.isWord {
+		literal, next = regexp.QuoteMeta(segment.word), rubyAfterWord
+	}
+	rest, ok := groupAlternatives(o.window(i+1, next, lo, hi))
+	if !ok {
+		return nil
+	}
+	return []string{sep + literal + rest}
+}
+
+// rubyStopAllowed reports whether segments that stop at index i still satisfy the
+// bound w, which is a lower bound when direction is 1 and an upper bound when it is -1.
+func rubyStopAllowed(w *rubyWindow, i, direction int) bool {
+	if w == nil {
+		return true
+	}
+	c := compareZeros(w.segments, i) * direction
+	return c > 0 || (c == 0 && w.inclusive)
+}
+
+// rubyZeroTails returns alternatives for the segments that follow once every active
+// bound is exhausted, where they are compared with implicit zeros.
+func rubyZeroTails(position int, lo, hi *rubyWindow) []string {
+	var alternatives []string
+	if (lo == nil || lo.inclusive) && (hi == nil || hi.inclusive) {
+		alternatives = append(alternatives, rubyZeros(position))
+	}
+
+	nonZero := NumGreaterOrEqual(1) + rubyRest(rubyAfterNumber)
+	word := `[A-Za-z]+` + rubyRest(rubyAfterWord)
+	switch {
+	case lo != nil && hi == nil:
+		// Greater than zeros: zeros followed by a positive number
+		switch position {
+		case rubyAfterWord:
+			alternatives = append(alternatives, `\.?(?:0\.)*`+nonZero)
+		case rubyAfterNumber:
+			alternatives = append(alternatives, `\.(?:0\.)*`+nonZero)
+		}
+	case hi != nil && lo == nil:
+		// Less than zeros: zeros followed by a word
+		switch position {
+		case rubyAfterRelease:
+			alternatives = append(alternatives, `\.?`+word)
+		case rubyAfterWord:
+			alternatives = append(alternatives, `(?:\.?0(?:\.0)*\.?|\.)`+word)
+		case rubyAfterNumber:
+			alternatives = append(alternatives, `(?:\.0)*\.?`+word)
+		}
+	}
+	return alternatives
+}
+
+// rubyZeros matches any number of zero segments at the given position.
+func rubyZeros(position int) string {
+	switch position {
+	case rubyAfterWord:
+		return `(?:\.?0(?:\.0)*)?`
+	case rubyAfterNumber:
+		return `(?:\.0)*`
+	default:
+		return ""
+	}
+}
+
+// rubyRest matches any segments at the given position. After a word or number, the
+// next segment must start a new segment so it cannot extend the previous one.
+func rubyRest(position int) string {
+	switch position {
+	case rubyAfterWord:
+		return `(?:(?:\.?\d+|\.[A-Za-z]+)(?:\.?[A-Za-z0-9]+)*)?`
+	case rubyAfterNumber:
+		return `(?:(?:\.?[A-Za-z]+|\.\d+)(?:\.?[A-Za-z0-9]+)*)?`
+	default:
+		return RUBY_PRERELEASE_PATTERN
+	}
+}
+
+// rubySeparator returns the separator allowed before a segment at the given position.
+// Segments of the same kind need a dot between them, and the pre-release part always
+// starts with a word.
+func rubySeparator(position int, isWord bool) (string, bool) {
+	switch {
+	case position == rubyAfterRelease:
+		return `\.?`, isWord
+	case (position == rubyAfterWord) == isWord:
+		return VERSION_DOT, true
+	default:
+		return `\.?`, true
+	}
+}
+
+// rubySegmentPattern matches a set of segments that are all words or all numbers.
+type rubySegmentPattern struct {
+	pattern string
+	isWord  bool
+}
+
+// rubySegmentsBetween returns patterns for the segments strictly between lo and hi
+// (nil means unbounded).
+func rubySegmentsBetween(lo, hi *rubySegment) []rubySegmentPattern {
+	var segments []rubySegmentPattern
+
+	// Words sort before numbers, so words only fit when the lower bound is a word
+	if lo == nil || lo.isWord {
+		var low, high *string
+		if lo != nil {
+			low = &lo.word
+		}
+		if hi != nil && hi.isWord {
+			high = &hi.word
+		}
+		if alternatives := wordsBetween(low, high); len(alternatives) > 0 {
+			segments = append(segments, rubySegmentPattern{pattern: joinPatterns(alternatives), isWord: true})
+		}
+	}
+
+	// Numbers only fit when the upper bound is a number (or unbounded)
+	if hi == nil || !hi.isWord {
+		low := 0
+		if lo != nil && !lo.isWord {
+			low = lo.number + 1
+		}
+		switch {
+		case hi == nil:
+			segments = append(segments, rubySegmentPattern{pattern: NumGreaterOrEqual(low)})
+		case low <= hi.number-1:
+			segments = append(segments, rubySegmentPattern{pattern: NumRange(low, hi.number-1)})
+		}
+	}
+	return segments
+}
+
+// RUBY_LETTERS lists the letters of gem version words in byte order
+const RUBY_LETTERS = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// wordsBetween returns alternatives matching the non-empty words strictly between
+// lo and hi in byte order (nil means unbounded).
+func wordsBetween(lo, hi *string) []string {
+	var alternatives []string
+	for _, alternative := range stringsBetween(lo, hi) {
+		if alternative != "" {
+			alternatives = append(alternatives, alternative)
+		}
+	}
+	return alternatives
+}
+
+// stringsBetween returns alternatives matching the letter strings strictly between
+// lo and hi in byte order (nil means unbounded). The empty alternative stands for
+// the empty string.
+func stringsBetween(lo, hi *string) []string {
+	if hi != nil && *hi == "" {
+		return nil
+	}
+
+	var alternatives []string
+	if lo == nil {
+		alternatives = append(alternatives, "")
+	}
+
+	var loFirst, hiFirst byte
+	loOpen := lo == nil || *lo == ""
+	if !loOpen {
+		loFirst = (*lo)[0]
+	}
+	if hi != nil {
+		hiFirst = (*hi)[0]
+	}
+
+	// First letter equal to the lower bound's
+	if !loOpen {
+		rest := (*lo)[1:]
+		var next *string
+		if hi != nil && hiFirst == loFirst {
+			hiRest := (*hi)[1:]
+			next = &hiRest
+		} else if hi != nil && hiFirst < loFirst {
+			return nil
+		}
+		for _, tail := range stringsBetween(&rest, next) {
+			alternatives = append(alternatives, string(loFirst)+tail)
+		}
+	}
+
+	// First letter strictly between the bounds' first letters
+	letters := ""
+	for i := 0; i < len(RUBY_LETTERS); i++ {
+		c := RUBY_LETTERS[i]
+		if (loOpen || c > loFirst) && (hi == nil || c < hiFirst) {
+			letters += string(c)
+		}
+	}
+	if letters != "" {
+		alternatives = append(alternatives, letterClass(letters)+`[A-Za-z]*`)
+	}
+
+	// First letter equal to the upper bound's
+	if hi != nil && (loOpen || hiFirst > loFirst) {
+		rest := (*hi)[1:]
+		for _, tail := range stringsBetween(nil, &rest) {
+			alternatives = append(alternatives, string(hiFirst)+tail)
+		}
+	}
+	return alternatives
+}
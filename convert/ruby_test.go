@@ -0,0 +1,202 @@
+// Package convert provides tests for RubyGems version handling functionality.
+// This file contains unit tests for requirement list parsing, gem version ordering
+// and regex generation.
+package convert
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestParseRubyRequirement tests parsing and normalization of RubyGems requirement lists.
+func TestParseRubyRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"'~> 2.1', '>= 2.1.3'", false, "~> 2.1, >= 2.1.3"},
+		{`"~>2.1"`, false, "~> 2.1"},
+		{"1.0.0.rc1", false, "= 1.0.0.rc1"},
+		{"!= 1.2, < 2", false, "!= 1.2, < 2"},
+		{"", false, ">= 0"},
+		{"~> ", true, ""},
+		{">= 1.0, ", true, ""},
+		{"=> 1.0", true, ""},
+		{">= 1.0-rc1", true, ""},
+		{">= 1..0", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			constraint, err := parseRubyRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseRubyRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseRubyRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if constraint.Operator != OP_RUBY_REQUIREMENT {
+				t.Errorf("expected operator %q, got %q", OP_RUBY_REQUIREMENT, constraint.Operator)
+			}
+			if constraint.Version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, constraint.Version)
+			}
+		})
+	}
+}
+
+// TestRubyPrereleaseOrder tests that gem versions are ordered like Gem::Version.
+func TestRubyPrereleaseOrder(t *testing.T) {
+	ordered := []string{".A", ".a.a", ".a", ".a.1", ".alpha", ".beta.2", ".pre", ".pre.1", ".rc", ".rc.1", ".rc2", ".rc10", ""}
+	order := rubyPrereleaseOrder{}
+	for i := 1; i < len(ordered); i++ {
+		if c := order.compare(ordered[i-1], ordered[i]); c >= 0 {
+			t.Errorf("compare(%q, %q) = %d, expected -1", ordered[i-1], ordered[i], c)
+		}
+	}
+
+	equal := [][2]string{{".rc1", "rc.1"}, {".rc", ".rc.0"}, {".pre.0.0", "pre"}}
+	for _, pair := range equal {
+		if c := order.compare(pair[0], pair[1]); c != 0 {
+			t.Errorf("compare(%q, %q) = %d, expected 0", pair[0], pair[1], c)
+		}
+	}
+}
+
+// TestRubyRequirementRegex tests regex generation for RubyGems requirement lists.
+func TestRubyRequirementRegex(t *testing.T) {
+	tests := []struct {
+		requirement    string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			requirement:    "~> 2.1",
+			shouldMatch:    []string{"2.1", "2.1.0", "2.5", "2.10.3", "2.99.rc1"},
+			shouldNotMatch: []string{"2.0", "2.1.rc1", "3", "3.0.0", "3.0.a", "3.0.0.pre", "1.9.9"},
+		},
+		{
+			requirement:    "~> 2",
+			shouldMatch:    []string{"2", "2.0.1", "2.99", "2.99.rc1"},
+			shouldNotMatch: []string{"2.0.rc1", "3", "3.0.0.pre", "3.a", "1.9"},
+		},
+		{
+			requirement:    "'~> 2.1', '>= 2.1.3'",
+			shouldMatch:    []string{"2.1.3", "2.1.4", "2.2", "2.9.9.9"},
+			shouldNotMatch: []string{"2.1", "2.1.2", "2.1.3.pre", "3.0", "3.1"},
+		},
+		{
+			requirement:    "~> 2.1.3",
+			shouldMatch:    []string{"2.1.3", "2.1.10", "2.1.99.beta"},
+			shouldNotMatch: []string{"2.1.2", "2.2", "2.2.0", "2.2.0.beta"},
+		},
+		{
+			requirement:    "~> 1.0.0.rc1",
+			shouldMatch:    []string{"1.0.0.rc1", "1.0.0.rc2", "1.0.0", "1.0.5"},
+			shouldNotMatch: []string{"1.0.0.beta", "1.0.0.rc", "1.1", "1.1.0"},
+		},
+		{
+			requirement:    "= 1.0.0",
+			shouldMatch:    []string{"1", "1.0", "1.0.0", "1.0.0.0"},
+			shouldNotMatch: []string{"1.0.0.rc1", "1.0.1", "1.0.0.1"},
+		},
+		{
+			requirement:    ">= 1.0.0.pre",
+			shouldMatch:    []string{"1.0.0.pre", "1.0.0.pre.1", "1.0.0.preview", "1.0.0.rc1", "1.0.0", "1.0.1.a"},
+			shouldNotMatch: []string{"1.0.0.beta", "1.0.0.a", "0.9", "1.0.0.Pre"},
+		},
+		{
+			requirement:    "< 1.0",
+			shouldMatch:    []string{"0.9", "0.0.1", "1.0.0.rc1", "1.0.a"},
+			shouldNotMatch: []string{"1.0", "1", "1.0.0.1", "2.0.rc1"},
+		},
+		{
+			requirement:    "!= 1.2, < 2",
+			shouldMatch:    []string{"1.1", "1.2.1", "1.2.rc1", "1.9"},
+			shouldNotMatch: []string{"1.2", "1.2.0", "2.0"},
+		},
+		{
+			requirement:    "> 1.0, < 1.0",
+			shouldNotMatch: []string{"1.0", "0.9", "1.1"},
+		},
+		{
+			requirement:    "",
+			shouldMatch:    []string{"0", "1.2.3", "1.0.0.rc1"},
+			shouldNotMatch: []string{"v1.0", "1.0-rc1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.requirement, func(t *testing.T) {
+			regex, err := VersionToRegexWithOptions(tt.requirement, Options{Ecosystem: ECOSYSTEM_RUBY})
+			if err != nil {
+				t.Fatalf("VersionToRegexWithOptions(%q) returned unexpected error: %v", tt.requirement, err)
+			}
+
+			for _, match := range tt.shouldMatch {
+				if !regex.MatchString(match) {
+					t.Errorf("pattern %q should match %q but doesn't", regex.String(), match)
+				}
+			}
+
+			for _, noMatch := range tt.shouldNotMatch {
+				if regex.MatchString(noMatch) {
+					t.Errorf("pattern %q should not match %q but does", regex.String(), noMatch)
+				}
+			}
+		})
+	}
+}
+
+// TestRubyPrereleaseOrderPatterns compares the suffix patterns of rubyPrereleaseOrder
+// with its compare method over a corpus of pre-release parts.
+func TestRubyPrereleaseOrderPatterns(t *testing.T) {
+	corpus := []string{
+		"", "a", ".a", ".A", ".Z", ".b", ".ab", ".a.a", ".a.b", ".a.1", ".a.0", ".a0",
+		".alpha", ".alpha.1", ".beta", ".beta.2", ".beta2", ".pre", ".pre.0", ".pre.1",
+		".pre.rc", ".pre.rc.1", ".pre.2.a", ".pr", ".prf", ".rc", ".rc1", ".rc.1", ".rc.2",
+		".rc10", ".rc.0.1", ".rc.1.a", ".rca", ".rc.a", ".z", ".zz",
+	}
+	bounds := []string{"", ".a", ".pre", ".pre.1", ".rc1", ".rc.0.1", ".beta.a", ".b.2"}
+	order := rubyPrereleaseOrder{}
+
+	check := func(name, pattern string, ok bool, want func(string) bool) {
+		re := regexp.MustCompile(NEVER_MATCH_PATTERN)
+		if ok {
+			re = regexp.MustCompile(REGEX_START + pattern + REGEX_END)
+		}
+		for _, suffix := range corpus {
+			if re.MatchString(suffix) != want(suffix) {
+				t.Errorf("%s: pattern %q matching %q: expected %v", name, pattern, suffix, want(suffix))
+			}
+		}
+	}
+
+	for _, lo := range bounds {
+		for _, inclusive := range []bool{true, false} {
+			pattern, ok := order.atLeast(lo, inclusive)
+			check("atLeast "+lo, pattern, ok, func(s string) bool {
+				c := order.compare(s, lo)
+				return c > 0 || (c == 0 && inclusive)
+			})
+
+			pattern, ok = order.atMost(lo, inclusive)
+			check("atMost "+lo, pattern, ok, func(s string) bool {
+				c := order.compare(s, lo)
+				return c < 0 || (c == 0 && inclusive)
+			})
+
+			for _, hi := range bounds {
+				pattern, ok = order.between(lo, inclusive, hi, !inclusive)
+				check("between "+lo+" "+hi, pattern, ok, func(s string) bool {
+					cl, ch := order.compare(s, lo), order.compare(s, hi)
+					return (cl > 0 || (cl == 0 && inclusive)) && (ch < 0 || (ch == 0 && !inclusive))
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,271 @@
+// Package convert provides SemVer 2.0 version ordering functionality.
+// This file contains the pre-release ordering of SemVer 2.0 (semver.org, section 11),
+// shared by the ecosystems that follow it strictly:
+//   - a release sorts after all its pre-releases: 1.0.0-rc.1 < 1.0.0
+//   - pre-release identifiers are compared one by one from left to right
+//   - numeric identifiers compare numerically and sort before alphanumeric ones
+//   - alphanumeric identifiers compare in ASCII order: 1.0.0-Beta < 1.0.0-alpha
+//   - a shorter list of identifiers sorts first when all shared ones are equal:
+//     1.0.0-alpha < 1.0.0-alpha.1
+package convert
+
+import (
+	"strconv"
+	"strings"
+)
+
+// SemVer identifier patterns
+const (
+	// SEMVER_IDENTIFIER matches one pre-release identifier
+	SEMVER_IDENTIFIER = `[0-9A-Za-z-]+`
+
+	// SEMVER_ALPHANUMERIC_IDENTIFIER matches a pre-release identifier that is not numeric
+	SEMVER_ALPHANUMERIC_IDENTIFIER = `[0-9A-Za-z-]*[A-Za-z-][0-9A-Za-z-]*`
+
+	// SEMVER_MORE_IDENTIFIERS matches any further pre-release identifiers
+	SEMVER_MORE_IDENTIFIERS = `(?:\.` + SEMVER_IDENTIFIER + `)*`
+)
+
+// semverIdentifierChars lists the characters allowed in identifiers, in ASCII order.
+const semverIdentifierChars = "-0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// semverPrereleaseOrder implements suffixOrder for SemVer 2.0 pre-releases. Suffixes
+// are either empty (a release) or a pre-release label with its dash (-alpha.1).
+// Build metadata is not part of the suffix.
+type semverPrereleaseOrder struct {
+	// releasesOnly restricts the patterns to releases, while keeping the full order
+	// for comparisons
+	releasesOnly bool
+}
+
+// compare orders two pre-release suffixes. An empty suffix (a release) sorts last.
+func (o semverPrereleaseOrder) compare(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	ia, ib := semverIdentifiers(a), semverIdentifiers(b)
+	for i := 0; i < len(ia) && i < len(ib); i++ {
+		if c := compareSemverIdentifiers(ia[i], ib[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInts(len(ia), len(ib))
+}
+
+// any matches every suffix.
+func (o semverPrereleaseOrder) any() string {
+	if o.releasesOnly {
+		return ""
+	}
+	return SEMVER_PRE_RELEASE_PATTERN
+}
+
+// atLeast matches suffixes ordered after s (or equal to it when inclusive).
+func (o semverPrereleaseOrder) atLeast(s string, inclusive bool) (string, bool) {
+	if s == "" {
+		return "", inclusive
+	}
+	return o.suffixes(semverIdentifiers(s), inclusive, nil, false, true)
+}
+
+// atMost matches suffixes ordered before s (or equal to it when inclusive).
+func (o semverPrereleaseOrder) atMost(s string, inclusive bool) (string, bool) {
+	if s == "" {
+		return o.suffixes(nil, false, nil, false, inclusive)
+	}
+	return o.suffixes(nil, false, semverIdentifiers(s), inclusive, false)
+}
+
+// between matches suffixes ordered between lo and hi.
+func (o semverPrereleaseOrder) between(lo string, loInclusive bool, hi string, hiInclusive bool) (string, bool) {
+	if c := o.compare(lo, hi); c > 0 || (c == 0 && !(loInclusive && hiInclusive)) {
+		return "", false
+	}
+	if lo == "" {
+		return "", true
+	}
+	if hi == "" {
+		return o.suffixes(semverIdentifiers(lo), loInclusive, nil, false, hiInclusive)
+	}
+	return o.suffixes(semverIdentifiers(lo), loInclusive, semverIdentifiers(hi), hiInclusive, false)
+}
+
+// suffixes combines the pre-releases between the identifier lists lo and hi (nil
+// for unbounded) with the release when it is allowed.
+func (o semverPrereleaseOrder) suffixes(lo []string, loInclusive bool, hi []string, hiInclusive bool, release bool) (string, bool) {
+	var alternatives []string
+	if release {
+		alternatives = append(alternatives, "")
+	}
+	if !o.releasesOnly {
+		if pattern, ok := groupAlternatives(semverListRange(lo, loInclusive, hi, hiInclusive, 0)); ok {
+			alternatives = append(alternatives, "-"+pattern)
+		}
+	}
+	return groupAlternatives(alternatives)
+}
+
+// semverListRange returns alternatives for the identifiers from index i on of the
+// pre-releases between lo and hi, knowing that the identifiers before i equal those
+// of every non-nil bound.
+func semverListRange(lo []string, loInclusive bool, hi []string, hiInclusive bool, i int) []string {
+	var alternatives []string
+	sep := ""
+	if i > 0 {
+		sep = VERSION_DOT
+
+		// Ending here gives a pre-release equal to or shorter (so smaller) than the bounds
+		loOK := lo == nil || (len(lo) == i && loInclusive)
+		hiOK := hi == nil || len(hi) > i || hiInclusive
+		if loOK && hiOK {
+			alternatives = append(alternatives, "")
+		}
+	}
+
+	// A longer pre-release with the same leading identifiers is greater than the bound
+	if lo != nil && len(lo) == i {
+		lo = nil
+	}
+	if hi != nil && len(hi) == i {
+		return alternatives
+	}
+
+	// Identifiers only contain [0-9A-Za-z-], so they need no escaping
+	next := func(lo []string, hi []string, id string) {
+		if rest, ok := groupAlternatives(semverListRange(lo, loInclusive, hi, hiInclusive, i+1)); ok {
+			alternatives = append(alternatives, sep+id+rest)
+		}
+	}
+
+	switch {
+	case lo == nil && hi == nil:
+		alternatives = append(alternatives, sep+SEMVER_IDENTIFIER+SEMVER_MORE_IDENTIFIERS)
+	case hi == nil:
+		alternatives = append(alternatives, sep+semverIdentifierRange(lo[i], "")+SEMVER_MORE_IDENTIFIERS)
+		next(lo, nil, lo[i])
+	case lo == nil:
+		if pattern, ok := groupAlternatives(semverIdentifiersBelow(hi[i])); ok {
+			alternatives = append(alternatives, sep+pattern+SEMVER_MORE_IDENTIFIERS)
+		}
+		next(nil, hi, hi[i])
+	case compareSemverIdentifiers(lo[i], hi[i]) == 0:
+		next(lo, hi, lo[i])
+	default:
+		if pattern := semverIdentifierRange(lo[i], hi[i]); pattern != NEVER_MATCH {
+			alternatives = append(alternatives, sep+pattern+SEMVER_MORE_IDENTIFIERS)
+		}
+		next(lo, nil, lo[i])
+		next(nil, hi, hi[i])
+	}
+	return alternatives
+}
+
+// semverIdentifierRange matches identifiers strictly between lo and hi, where an
+// empty hi means no upper limit. It returns NEVER_MATCH when there are none.
+func semverIdentifierRange(lo, hi string) string {
+	var alternatives []string
+	if isDigits(lo) {
+		n, _ := strconv.Atoi(lo)
+		switch {
+		case hi == "":
+			alternatives = append(alternatives, NumGreaterOrEqual(n+1), SEMVER_ALPHANUMERIC_IDENTIFIER)
+		case isDigits(hi):
+			if m, _ := strconv.Atoi(hi); m-n > 1 {
+				alternatives = append(alternatives, NumRange(n+1, m-1))
+			}
+		default:
+			alternatives = append(alternatives, NumGreaterOrEqual(n+1))
+			alternatives = append(alternatives, alphanumericPatterns(lexBelow(hi, semverIdentifierChars))...)
+		}
+	} else if hi == "" {
+		alternatives = alphanumericPatterns(lexAbove(lo, semverIdentifierChars))
+	} else {
+		alternatives = alphanumericPatterns(lexBetween(lo, hi, semverIdentifierChars))
+	}
+
+	pattern, ok := groupAlternatives(alternatives)
+	if !ok {
+		return NEVER_MATCH
+	}
+	return pattern
+}
+
+// semverIdentifiersBelow returns alternatives for the identifiers ordered before id.
+func semverIdentifiersBelow(id string) []string {
+	if isDigits(id) {
+		n, _ := strconv.Atoi(id)
+		if n == 0 {
+			return nil
+		}
+		return []string{NumRange(0, n-1)}
+	}
+	return append([]string{VERSION_DIGITS}, alphanumericPatterns(lexBelow(id, semverIdentifierChars))...)
+}
+
+// semverIdentifiers splits a pre-release suffix (-alpha.1) into its identifiers.
+func semverIdentifiers(suffix string) []string {
+	return strings.Split(strings.TrimPrefix(suffix, "-"), ".")
+}
+
+// compareSemverIdentifiers orders two pre-release identifiers.
+func compareSemverIdentifiers(a, b string) int {
+	da, db := isDigits(a), isDigits(b)
+	switch {
+	case da && db:
+		na, _ := strconv.Atoi(a)
+		nb, _ := strconv.Atoi(b)
+		return compareInts(na, nb)
+	case da:
+		return -1
+	case db:
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// alphanumericPatterns renders lexPieces as patterns, keeping only the non-empty
+// strings that contain a non-digit, since all-digit identifiers are numeric.
+func alphanumericPatterns(pieces []lexPiece) []string {
+	var patterns []string
+	for _, piece := range pieces {
+		hasLetter := !isDigits(piece.literal) && piece.literal != ""
+		if piece.chars == "" {
+			if hasLetter {
+				patterns = append(patterns, piece.literal)
+			}
+			continue
+		}
+
+		var digits, others string
+		for i := 0; i < len(piece.chars); i++ {
+			if c := piece.chars[i]; c >= '0' && c <= '9' {
+				digits += string(c)
+			} else {
+				others += string(c)
+			}
+		}
+
+		rest := ""
+		if piece.rest {
+			rest = `[0-9A-Za-z-]*`
+		}
+		if others != "" {
+			patterns = append(patterns, piece.literal+letterClass(others)+rest)
+		}
+		if digits != "" {
+			switch {
+			case hasLetter:
+				patterns = append(patterns, piece.literal+letterClass(digits)+rest)
+			case piece.rest:
+				patterns = append(patterns, piece.literal+letterClass(digits)+SEMVER_ALPHANUMERIC_IDENTIFIER)
+			}
+		}
+	}
+	return patterns
+}
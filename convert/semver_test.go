@@ -0,0 +1,78 @@
+// Package convert provides tests for SemVer 2.0 version ordering functionality.
+// This file checks the pre-release patterns of semverPrereleaseOrder against its
+// compare method.
+package convert
+
+import (
+	"regexp"
+	"testing"
+)
+
+// TestSemverPrereleaseOrder tests the SemVer 2.0 precedence examples.
+func TestSemverPrereleaseOrder(t *testing.T) {
+	ordered := []string{"-0", "-2", "-10", "-Beta", "-alpha", "-alpha.1", "-alpha.beta", "-beta", "-beta.2", "-beta.11", "-rc.1", ""}
+	order := semverPrereleaseOrder{}
+	for i := 1; i < len(ordered); i++ {
+		if c := order.compare(ordered[i-1], ordered[i]); c >= 0 {
+			t.Errorf("compare(%q, %q) = %d, expected -1", ordered[i-1], ordered[i], c)
+		}
+		if c := order.compare(ordered[i], ordered[i-1]); c <= 0 {
+			t.Errorf("compare(%q, %q) = %d, expected 1", ordered[i], ordered[i-1], c)
+		}
+	}
+}
+
+// TestSemverPrereleaseOrderPatterns compares the suffix patterns of semverPrereleaseOrder
+// with its compare method over a corpus of pre-release suffixes.
+func TestSemverPrereleaseOrderPatterns(t *testing.T) {
+	corpus := []string{
+		"", "-0", "-1", "-2", "-9", "-10", "-0.1", "-1.a", "-A", "-B1", "-Beta", "-a", "-a-",
+		"-a0", "-a1", "-a9", "-aa", "-al", "-alpha", "-alpha.0", "-alpha.1", "-alpha.2",
+		"-alpha.10", "-alpha.1.1", "-alpha.beta", "-alphA", "-alphb", "-alpha-1", "-b",
+		"-beta", "-beta.2", "-beta.11", "-rc", "-rc.1", "-z", "-zz", "-1a", "-9z", "--",
+	}
+	bounds := []string{"", "-0", "-2", "-a1", "-alpha", "-alpha.1", "-alpha.beta", "-beta.2", "-rc", "-1a"}
+
+	for _, releasesOnly := range []bool{false, true} {
+		order := semverPrereleaseOrder{releasesOnly: releasesOnly}
+		check := func(name, pattern string, ok bool, want func(string) bool) {
+			re := regexp.MustCompile(NEVER_MATCH_PATTERN)
+			if ok {
+				re = regexp.MustCompile(REGEX_START + pattern + REGEX_END)
+			}
+			for _, suffix := range corpus {
+				expected := want(suffix) && (suffix == "" || !releasesOnly)
+				if re.MatchString(suffix) != expected {
+					t.Errorf("%s (releasesOnly=%v): pattern %q matching %q: expected %v", name, releasesOnly, pattern, suffix, expected)
+				}
+			}
+		}
+
+		check("any", order.any(), true, func(string) bool { return true })
+		for _, lo := range bounds {
+			for _, inclusive := range []bool{true, false} {
+				pattern, ok := order.atLeast(lo, inclusive)
+				check("atLeast "+lo, pattern, ok, func(s string) bool {
+					c := order.compare(s, lo)
+					return c > 0 || (c == 0 && inclusive)
+				})
+
+				pattern, ok = order.atMost(lo, inclusive)
+				check("atMost "+lo, pattern, ok, func(s string) bool {
+					c := order.compare(s, lo)
+					return c < 0 || (c == 0 && inclusive)
+				})
+
+				for _, hi := range bounds {
+					for _, hiInclusive := range []bool{true, false} {
+						pattern, ok = order.between(lo, inclusive, hi, hiInclusive)
+						check("between "+lo+" "+hi, pattern, ok, func(s string) bool {
+							cl, ch := order.compare(s, lo), order.compare(s, hi)
+							return (cl > 0 || (cl == 0 && inclusive)) && (ch < 0 || (ch == 0 && hiInclusive))
+						})
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,250 @@
+// Package convert provides SQL predicate output for version constraints.
+// This file contains the functions that turn a constraint into a WHERE clause
+// condition: a regex match for PostgreSQL and MySQL, and a comparison of integer
+// version columns for SQLite, which has no built-in regex operator.
+package convert
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SQLDialect selects the database a predicate is written for.
+type SQLDialect string
+
+// Supported SQL dialects
+const (
+	// SQL_POSTGRES matches the version column with PostgreSQL's ~ operator
+	SQL_POSTGRES SQLDialect = "postgres"
+	// SQL_MYSQL matches the version column with REGEXP_LIKE (MySQL 8.0 and later)
+	SQL_MYSQL SQLDialect = "mysql"
+	// SQL_SQLITE compares the major, minor and patch integer columns
+	SQL_SQLITE SQLDialect = "sqlite"
+)
+
+// SQLColumns names the columns that store versions. Names are written into
+// predicates as they are, so quote them beforehand if needed.
+type SQLColumns struct {
+	// Version is the text column holding whole versions, matched by regex
+	Version string
+	// Major, Minor and Patch are the integer columns of the SQLite fallback;
+	// versions with fewer components store 0 in the missing columns
+	Major string
+	Minor string
+	Patch string
+}
+
+// SQLPredicate converts a version constraint string to a condition for the WHERE
+// clause of a query.
+//
+// PostgreSQL and MySQL match columns.Version against the regex of the constraint,
+// written in POSIX ERE for PostgreSQL and in ICU syntax for MySQL, and quoted as a
+// string literal. MySQL string literals are escaped for the default SQL mode, in which
+// backslashes are escape characters. REGEXP_LIKE is given the c flag, so that the
+// match is case-sensitive whatever the collation of the column.
+//
+// SQLite compares the integer columns as row values (SQLite 3.15 and later). Only
+// the operators of the default ecosystem are supported, on versions of at most three
+// components. Pre-release and build suffixes are ignored as by the comparison
+// operators, and rejected in exact versions, which integer columns cannot tell apart.
+// ^, ~, ~> and ~= compare the columns with both bounds of their range, so ^1.2.3
+// matches 1.9.0 but neither 1.0.0 nor 2.0.0. Unlike regexes, the fallback can express !=.
+//
+// Parameters:
+//   - versionStr: The version constraint string to convert
+//   - dialect: The database the predicate is written for
+//   - columns: The columns that store versions
+//   - opts: Conversion options, such as the ecosystem
+//
+// Returns:
+//   - string: Condition for a WHERE clause
+//   - error: Error if the constraint cannot be converted, or the database cannot express it
+//
+// Examples:
+//
+//	SQLPredicate("^1.2.3", SQL_POSTGRES, SQLColumns{Version: "version"}, Options{})
+//	// version ~ '^1\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?$'
+//
+//	SQLPredicate(">=1.2.3", SQL_SQLITE, SQLColumns{Major: "major", Minor: "minor", Patch: "patch"}, Options{})
+//	// (major, minor, patch) >= (1, 2, 3)
+func SQLPredicate(versionStr string, dialect SQLDialect, columns SQLColumns, opts Options) (string, error) {
+	if opts.CaptureGroups {
+		return "", fmt.Errorf("capture groups are not supported in SQL predicates")
+	}
+
+	switch dialect {
+	case SQL_POSTGRES, SQL_MYSQL:
+		if columns.Version == "" {
+			return "", fmt.Errorf("%s predicates need a version column", dialect)
+		}
+	case SQL_SQLITE:
+		if columns.Major == "" || columns.Minor == "" || columns.Patch == "" {
+			return "", fmt.Errorf("%s predicates need major, minor and patch columns", dialect)
+		}
+		return sqliteRangePredicate(versionStr, columns, opts)
+	default:
+		return "", fmt.Errorf("unsupported SQL dialect: %s", dialect)
+	}
+
+	if opts.Boundary == BOUNDARY_WORD {
+		return "", fmt.Errorf("word boundaries are not supported in SQL predicates")
+	}
+
+	if dialect == SQL_POSTGRES {
+		pattern, err := Pattern(versionStr, DIALECT_POSIX_ERE, opts)
+		if err != nil {
+			return "", err
+		}
+		return columns.Version + " ~ " + sqlString(pattern, false), nil
+	}
+
+	// ICU agrees with .NET on the syntax of generated patterns: (?:...) groups,
+	// [0-9] for ASCII digits, and \z for the very end of the input
+	pattern, err := Pattern(versionStr, DIALECT_DOTNET, opts)
+	if err != nil {
+		return "", err
+	}
+	return "REGEXP_LIKE(" + columns.Version + ", " + sqlString(pattern, true) + ", 'c')", nil
+}
+
+// sqlString quotes s as an SQL string literal, doubling backslashes for databases
+// that treat them as escape characters.
+func sqlString(s string, backslashEscapes bool) string {
+	if backslashEscapes {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// sqliteRangePredicate compares the integer version columns with the versions of a
+// constraint of the default ecosystem.
+func sqliteRangePredicate(versionStr string, columns SQLColumns, opts Options) (string, error) {
+	if opts.Ecosystem != ECOSYSTEM_AUTO {
+		return "", fmt.Errorf("the %s fallback does not support %s constraints", SQL_SQLITE, opts.Ecosystem)
+	}
+
+	constraint, err := parseConstraint(versionStr, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse version constraint: %w", err)
+	}
+	version := strings.TrimPrefix(constraint.Version, "v")
+	names := []string{columns.Major, columns.Minor, columns.Patch}
+
+	switch constraint.Operator {
+	case OP_EQUAL_EQUAL, OP_EQUAL, OP_NOT_EQUAL:
+		if strings.Contains(version, "*") {
+			if constraint.Operator == OP_NOT_EQUAL {
+				return "", fmt.Errorf("the %s fallback does not support wildcards with %s", SQL_SQLITE, OP_NOT_EQUAL)
+			}
+			return sqliteWildcardPredicate(version, names)
+		}
+		if strings.ContainsAny(version, "-+") {
+			return "", fmt.Errorf("integer version columns cannot match the pre-release or build of %s", version)
+		}
+		operator := "="
+		if constraint.Operator == OP_NOT_EQUAL {
+			operator = "<>"
+		}
+		return sqliteComparison(version, names, operator)
+	case OP_GREATER_EQUAL:
+		return sqliteComparison(version, names, ">=")
+	case OP_GREATER:
+		return sqliteComparison(version, names, ">")
+	case OP_LESS_EQUAL:
+		return sqliteComparison(version, names, "<=")
+	case OP_LESS:
+		return sqliteComparison(version, names, "<")
+	case OP_CARET, OP_TILDE, OP_PESSIMISTIC, OP_COMPATIBLE:
+		lower, err := sqliteComparison(version, names, ">=")
+		if err != nil {
+			return "", err
+		}
+		given, err := parseVersionComponents(version)
+		if err != nil {
+			return "", err
+		}
+		upper := sqliteUpperBound(constraint.Operator, given)
+		return lower + " AND (" + strings.Join(names, ", ") + ") < (" + strings.Join(upper, ", ") + ")", nil
+	default:
+		return "", fmt.Errorf("the %s fallback does not support %s constraints", SQL_SQLITE, constraint.Operator)
+	}
+}
+
+// sqliteComparison compares the row of version columns with the components of version.
+func sqliteComparison(version string, names []string, operator string) (string, error) {
+	parts, err := sqliteParts(version)
+	if err != nil {
+		return "", err
+	}
+	return "(" + strings.Join(names, ", ") + ") " + operator + " (" + strings.Join(parts, ", ") + ")", nil
+}
+
+// sqliteUpperBound returns the exclusive upper bound of a ^, ~, ~> or ~= constraint
+// on the given components, padded to major, minor and patch:
+//   - ^ keeps the first non-zero component, or the last given one: ^1.2.3 is <2.0.0,
+//     ^0.2.3 is <0.3.0, ^0.0.3 is <0.0.4 and ^0.0 is <0.1.0
+//   - ~ keeps the major and minor components given: ~1.2.3 is <1.3.0 and ~1 is <2.0.0
+//   - ~> and ~= keep every component but the last: ~=1.4 is <2.0.0 and ~>1.4.2 is <1.5.0
+func sqliteUpperBound(operator string, given []string) []string {
+	n := len(given)
+	position := max(n-2, 0)
+	switch operator {
+	case OP_CARET:
+		position = n - 1
+		for i, part := range given {
+			if part != "0" {
+				position = i
+				break
+			}
+		}
+	case OP_TILDE:
+		position = min(n-1, 1)
+	}
+
+	upper := cargoBump(given, position).parts
+	for len(upper) < 3 {
+		upper = append(upper, "0")
+	}
+	return upper
+}
+
+// sqliteWildcardPredicate fixes the columns of the components of a wildcard version
+// that are not *.
+func sqliteWildcardPredicate(version string, names []string) (string, error) {
+	fields := strings.Split(version, ".")
+	if len(fields) > len(names) {
+		return "", fmt.Errorf("integer version columns cannot compare more than %d components, got %s", len(names), version)
+	}
+
+	var conditions []string
+	for i, field := range fields {
+		switch {
+		case field == "*":
+		case isDigits(field):
+			conditions = append(conditions, names[i]+" = "+trimDecimal(field))
+		default:
+			return "", fmt.Errorf("invalid %s version: %s", componentName(i), field)
+		}
+	}
+	if len(conditions) == 0 {
+		// Every version matches *
+		return "1 = 1", nil
+	}
+	return strings.Join(conditions, " AND "), nil
+}
+
+// sqliteParts returns the major, minor and patch components of version, missing
+// components being 0.
+func sqliteParts(version string) ([]string, error) {
+	parts, err := parseVersionComponents(version)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("integer version columns cannot compare more than 3 components, got %s", version)
+	}
+	for len(parts) < 3 {
+		parts = append(parts, "0")
+	}
+	return parts, nil
+}
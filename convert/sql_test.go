@@ -0,0 +1,145 @@
+// Package convert provides tests for SQL predicate output.
+// This file checks the WHERE clause conditions written for each database.
+package convert
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestSQLPredicate tests the predicates written for each database.
+func TestSQLPredicate(t *testing.T) {
+	columns := SQLColumns{Version: "version", Major: "major", Minor: "minor", Patch: "patch"}
+
+	tests := []struct {
+		constraint string
+		dialect    SQLDialect
+		opts       Options
+		expected   string
+	}{
+		{"^1.2.3", SQL_POSTGRES, Options{}, `version ~ '^1\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?$'`},
+		{"^1.2.3", SQL_MYSQL, Options{}, `REGEXP_LIKE(version, '^1\\.(?:0|[1-9][0-9]*)\\.(?:0|[1-9][0-9]*)(?:-[\\-.0-9A-Za-z]+)?(?:\\+[\\-.0-9A-Za-z]+)?\\z', 'c')`},
+		{"^1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) >= (1, 2, 3) AND (major, minor, patch) < (2, 0, 0)`},
+		{"^0.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) >= (0, 2, 3) AND (major, minor, patch) < (0, 3, 0)`},
+		{"~1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) >= (1, 2, 3) AND (major, minor, patch) < (1, 3, 0)`},
+		{"~=2.1", SQL_SQLITE, Options{}, `(major, minor, patch) >= (2, 1, 0) AND (major, minor, patch) < (3, 0, 0)`},
+		{">=1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) >= (1, 2, 3)`},
+		{">= 1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) >= (1, 2, 3)`},
+		{">1.2", SQL_SQLITE, Options{}, `(major, minor, patch) > (1, 2, 0)`},
+		{"<=01.002.3-rc.1", SQL_SQLITE, Options{}, `(major, minor, patch) <= (1, 2, 3)`},
+		{"<2", SQL_SQLITE, Options{}, `(major, minor, patch) < (2, 0, 0)`},
+		{"1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) = (1, 2, 3)`},
+		{"v1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) = (1, 2, 3)`},
+		{"!=1.2.3", SQL_SQLITE, Options{}, `(major, minor, patch) <> (1, 2, 3)`},
+		{"1.*.0", SQL_SQLITE, Options{}, `major = 1 AND patch = 0`},
+		{"*", SQL_SQLITE, Options{}, `1 = 1`},
+		{"1.2.3", SQL_POSTGRES, Options{Prefix: "it's-"}, `version ~ '^it''s-1\.2\.3(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?$'`},
+		{"~1.2", SQL_POSTGRES, Options{Boundary: BOUNDARY_NONE}, `version ~ '1\.2\.(0|[1-9][0-9]*)(-[.0-9A-Za-z-]+)?(\+[.0-9A-Za-z-]+)?'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect)+" "+tt.constraint, func(t *testing.T) {
+			predicate, err := SQLPredicate(tt.constraint, tt.dialect, columns, tt.opts)
+			if err != nil {
+				t.Fatalf("SQLPredicate(%q, %s) returned unexpected error: %v", tt.constraint, tt.dialect, err)
+			}
+			if predicate != tt.expected {
+				t.Errorf("SQLPredicate(%q, %s) = %s, expected %s", tt.constraint, tt.dialect, predicate, tt.expected)
+			}
+		})
+	}
+}
+
+// sqliteRangeRegex splits the bounds of an SQLite range predicate.
+var sqliteRangeRegex = regexp.MustCompile(`^\(major, minor, patch\) >= \((\d+), (\d+), (\d+)\) AND \(major, minor, patch\) < \((\d+), (\d+), (\d+)\)$`)
+
+// TestSQLiteRangeBounds tests the rows just below and just above the bounds of the
+// SQLite predicates of ^, ~, ~> and ~=, evaluating the row value comparisons as
+// SQLite does, component by component.
+func TestSQLiteRangeBounds(t *testing.T) {
+	columns := SQLColumns{Major: "major", Minor: "minor", Patch: "patch"}
+
+	tests := []struct {
+		constraint     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{"^1.2.3", []string{"1.2.3", "1.9.0", "1.99.99"}, []string{"1.0.0", "1.2.2", "2.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.2.2", "0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.1", "0.0.2", "0.0.4"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^0", []string{"0.0.0", "0.9.9"}, []string{"1.0.0"}},
+		{"^1.2", []string{"1.2.0", "1.9.0"}, []string{"1.1.9", "2.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.2.2", "1.3.0"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.1.9", "1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"0.9.9", "2.0.0"}},
+		{"~>1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.4.1", "1.5.0"}},
+		{"~>1.4", []string{"1.4.0", "1.9.0"}, []string{"1.3.9", "2.0.0"}},
+		{"~=1.4.2", []string{"1.4.2", "1.4.9"}, []string{"1.4.1", "1.5.0"}},
+		{"~=1.4", []string{"1.4.0", "1.9.0"}, []string{"1.3.9", "2.0.0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			predicate, err := SQLPredicate(tt.constraint, SQL_SQLITE, columns, Options{})
+			if err != nil {
+				t.Fatalf("SQLPredicate(%q) returned unexpected error: %v", tt.constraint, err)
+			}
+			bounds := sqliteRangeRegex.FindStringSubmatch(predicate)
+			if bounds == nil {
+				t.Fatalf("SQLPredicate(%q) = %s, expected a range of row values", tt.constraint, predicate)
+			}
+			lower, upper := strings.Join(bounds[1:4], "."), strings.Join(bounds[4:7], ".")
+
+			matches := func(row string) bool {
+				return compareParts(strings.Split(row, "."), strings.Split(lower, ".")) >= 0 &&
+					compareParts(strings.Split(row, "."), strings.Split(upper, ".")) < 0
+			}
+			for _, row := range tt.shouldMatch {
+				if !matches(row) {
+					t.Errorf("SQLPredicate(%q) = %s should match row %s", tt.constraint, predicate, row)
+				}
+			}
+			for _, row := range tt.shouldNotMatch {
+				if matches(row) {
+					t.Errorf("SQLPredicate(%q) = %s should not match row %s", tt.constraint, predicate, row)
+				}
+			}
+		})
+	}
+}
+
+// TestSQLPredicateErrors tests the constraints a database cannot express.
+func TestSQLPredicateErrors(t *testing.T) {
+	columns := SQLColumns{Version: "version", Major: "major", Minor: "minor", Patch: "patch"}
+
+	tests := []struct {
+		name       string
+		constraint string
+		dialect    SQLDialect
+		columns    SQLColumns
+		opts       Options
+	}{
+		{"unknown dialect", "1.2.3", SQLDialect("oracle"), columns, Options{}},
+		{"no version column", "1.2.3", SQL_POSTGRES, SQLColumns{Major: "major", Minor: "minor", Patch: "patch"}, Options{}},
+		{"no integer columns", "1.2.3", SQL_SQLITE, SQLColumns{Version: "version"}, Options{}},
+		{"word boundary", "1.2.3", SQL_MYSQL, columns, Options{Boundary: BOUNDARY_WORD}},
+		{"capture groups", "1.2.3", SQL_POSTGRES, columns, Options{CaptureGroups: true}},
+		{"regex not equal", "!=1.2.3", SQL_POSTGRES, columns, Options{}},
+		{"pre-release in integer columns", "1.2.3-alpha", SQL_SQLITE, columns, Options{}},
+		{"four components in integer columns", ">=1.2.3.4", SQL_SQLITE, columns, Options{}},
+		{"wildcard not equal", "!=1.*", SQL_SQLITE, columns, Options{}},
+		{"Maven range in integer columns", "[1.0,2.0)", SQL_SQLITE, columns, Options{}},
+		{"ecosystem in integer columns", "~> 2.1", SQL_SQLITE, columns, Options{Ecosystem: ECOSYSTEM_RUBY}},
+		{"invalid version", ">=abc", SQL_SQLITE, columns, Options{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if predicate, err := SQLPredicate(tt.constraint, tt.dialect, tt.columns, tt.opts); err == nil {
+				t.Errorf("SQLPredicate(%q, %s) = %s, expected an error", tt.constraint, tt.dialect, predicate)
+			}
+		})
+	}
+}
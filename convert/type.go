@@ -32,6 +32,24 @@ const (
 	OP_TILDE = "~"
 	// OP_MAVEN_RANGE represents Maven version range with brackets
 	OP_MAVEN_RANGE = "maven-range"
+	// OP_RUBY_REQUIREMENT represents a RubyGems requirement list such as "~> 2.1, >= 2.1.3"
+	OP_RUBY_REQUIREMENT = "ruby-requirement"
+	// OP_COMPOSER_CONSTRAINT represents a PHP Composer constraint such as "^1.2@beta || dev-main"
+	OP_COMPOSER_CONSTRAINT = "composer-constraint"
+	// OP_CARGO_REQUIREMENT represents a Rust Cargo version requirement such as ">=1.2, <1.5"
+	OP_CARGO_REQUIREMENT = "cargo-requirement"
+	// OP_DEBIAN_RELATION represents a Debian version relation list such as ">= 1:2.30-1ubuntu2, << 3.0"
+	OP_DEBIAN_RELATION = "debian-relation"
+	// OP_RPM_REQUIREMENT represents an RPM version requirement list such as ">= 1:2.30-1.el8, < 3.0"
+	OP_RPM_REQUIREMENT = "rpm-requirement"
+	// OP_CALVER_CONSTRAINT represents a calendar version constraint such as ">=2024.10, <2025"
+	OP_CALVER_CONSTRAINT = "calver-constraint"
+	// OP_ADVISORY_RANGE represents a security advisory version range such as "< 0.3.1 || >= 1.0.0, < 1.0.4"
+	OP_ADVISORY_RANGE = "advisory-range"
+	// OP_NPM_RANGE represents an npm version range such as "^1.2.3 || >=2.1.0 <3"
+	OP_NPM_RANGE = "npm-range"
+	// OP_PEP440_SPECIFIERS represents PEP 440 version specifiers such as "~=1.4.2, !=1.4.5"
+	OP_PEP440_SPECIFIERS = "pep440-specifiers"
 )
 
 // VersionConstraint represents a semantic version constraint parsed from a version string.
@@ -47,9 +65,10 @@ const (
 // - NPM/Node.js (^, ~, exact matches)
 // - Maven (version ranges with brackets)
 // - Go modules (v-prefixed versions)
-// - C# NuGet (4-part versions, pre-release patterns)
+// - C# NuGet (4-part versions, and every version in NuGet mode)
 // - Python (compatible releases with ~=)
 // - Ruby (pessimistic operator ~>)
+// - Rust Cargo (bare versions as caret requirements, comma-separated comparators)
 //
 // The Operator field contains the constraint type, while the Version field
 // contains the version string or range specification.
@@ -59,7 +78,7 @@ const (
 //   - NPM caret: Operator="^", Version="1.2.3"
 //   - NPM tilde: Operator="~", Version="1.2.3"
 //   - Greater than: Operator=">", Version="1.2.3"
-//   - Maven range: Operator="maven-range", Version="1.0,2.0"
+//   - Maven range: Operator="maven-range", Version="[1.0,2.0)"
 //   - Python compatible: Operator="~=", Version="1.2.3"
 //   - Ruby pessimistic: Operator="~>", Version="1.2.3"
 type VersionConstraint struct {
@@ -77,7 +96,13 @@ type VersionConstraint struct {
 	//   - "~>": Ruby pessimistic operator (compatible release)
 	//   - "~=": Python compatible release operator
 	//   - "maven-range": Maven version range with brackets
-	Operator string
+	//   - "ruby-requirement": RubyGems requirement list (Ruby ecosystem mode)
+	//   - "composer-constraint": PHP Composer constraint (Composer ecosystem mode)
+	//   - "cargo-requirement": Rust Cargo version requirement (Cargo ecosystem mode)
+	//   - "debian-relation": Debian version relation list (Debian ecosystem mode)
+	//   - "rpm-requirement": RPM version requirement list (RPM ecosystem mode)
+	//   - "calver-constraint": calendar version constraint (CalVer ecosystem mode)
+	Operator string `json:"operator"`
 
 	// Version contains the version string or range specification.
 	//
@@ -85,11 +110,14 @@ type VersionConstraint struct {
 	//   - For exact matches: semantic version string (e.g., "1.2.3")
 	//   - For comparison operators: semantic version string (e.g., "1.2.3")
 	//   - For NPM ranges: semantic version string (e.g., "1.2.3")
-	//   - For Maven ranges: comma-separated range (e.g., "1.0,2.0")
+	//   - For Maven ranges: bracketed range set, brackets included (e.g., "[1.0,2.0)")
+	//   - For RubyGems requirements: comma-separated requirement list (e.g., "~> 2.1, >= 2.1.3")
+	//   - For Composer constraints: the whole constraint (e.g., "~1.2 || dev-main")
+	//   - For Cargo requirements: comma-separated comparators, bare versions made explicit (e.g., "^1.2.3, <1.5")
 	//   - For Go modules: v-prefixed version (e.g., "v1.2.3")
 	//   - For C# NuGet: may include 4-part versions (e.g., "1.2.3.4567")
 	//
 	// The version string may include pre-release identifiers and build metadata
 	// following semantic versioning conventions, depending on the ecosystem.
-	Version string
+	Version string `json:"version"`
 }
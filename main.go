@@ -1,40 +1,712 @@
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/ildyria/version-to-regex/convert"
+	"github.com/ildyria/version-to-regex/manifest"
+	"github.com/ildyria/version-to-regex/osv"
 )
 
+// Exit codes of the subcommands, as in grep
+const (
+	// EXIT_NO_MATCH reports that a version did not match, or no line was printed
+	EXIT_NO_MATCH = 1
+	// EXIT_ERROR reports an invalid constraint, flag or input
+	EXIT_ERROR = 2
+)
+
+// cliOptions holds the flags shared by the subcommands.
+type cliOptions struct {
+	dialect      string
+	prefix       string
+	suffix       string
+	ecosystem    string
+	calverFormat string
+	find         bool
+	glob         bool
+}
+
+// sharedFlags selects the shared flags a subcommand registers, so that it rejects
+// the ones it would ignore.
+type sharedFlags int
+
+// Groups of shared flags, combined with |
+const (
+	// FLAGS_SYNTAX registers -ecosystem and -calver-format
+	FLAGS_SYNTAX sharedFlags = 1 << iota
+	// FLAGS_AFFIXES registers -prefix and -suffix
+	FLAGS_AFFIXES
+	// FLAGS_DIALECT registers -dialect
+	FLAGS_DIALECT
+	// FLAGS_FORMS registers -find and -glob
+	FLAGS_FORMS
+	// FLAGS_ALL registers every shared flag
+	FLAGS_ALL = FLAGS_SYNTAX | FLAGS_AFFIXES | FLAGS_DIALECT | FLAGS_FORMS
+)
+
+// newFlagSet creates the flag set of a subcommand, registering the shared flags
+// selected by shared in cli.
+func newFlagSet(name, usage string, cli *cliOptions, shared sharedFlags) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	cli.dialect = string(convert.DIALECT_GO)
+	if shared&FLAGS_DIALECT != 0 {
+		flags.StringVar(&cli.dialect, "dialect", string(convert.DIALECT_GO), "regex syntax of the output: go, pcre, ecmascript, posix-ere, dotnet or python")
+	}
+	if shared&FLAGS_AFFIXES != 0 {
+		flags.StringVar(&cli.prefix, "prefix", "", "literal text before versions, such as mylib-")
+		flags.StringVar(&cli.suffix, "suffix", "", "literal text after versions, such as .tgz")
+	}
+	if shared&FLAGS_SYNTAX != 0 {
+		flags.StringVar(&cli.ecosystem, "ecosystem", "", "constraint syntax: ruby, composer, cargo, npm, pypi, nuget, debian, rpm, calver or advisory (default: auto-detected)")
+		flags.StringVar(&cli.calverFormat, "calver-format", "", "format of calendar versions, such as YYYY.MM.MICRO")
+	}
+	if shared&FLAGS_FORMS != 0 {
+		flags.BoolVar(&cli.find, "find", false, "print a pattern for find -regextype posix-extended -regex")
+		flags.BoolVar(&cli.glob, "glob", false, "print a glob approximating the regex")
+	}
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), usage)
+		fmt.Fprintln(flags.Output(), "Flags:")
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// options returns the conversion options selected by the flags.
+func (cli *cliOptions) options() convert.Options {
+	return convert.Options{
+		Ecosystem:    convert.Ecosystem(cli.ecosystem),
+		CalVerFormat: cli.calverFormat,
+		Prefix:       cli.prefix,
+		Suffix:       cli.suffix,
+	}
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: version-to-regex <version-constraint>")
-		fmt.Println("Examples:")
-		fmt.Println("  version-to-regex '>=1.2.3'")
-		fmt.Println("  version-to-regex '^1.2.3'")
-		fmt.Println("  version-to-regex '~1.2.3'")
-		fmt.Println("  version-to-regex '1.*.0'")
-		os.Exit(1)
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "regex":
+			os.Exit(runRegex(os.Args[2:]))
+		case "match":
+			os.Exit(runMatch(os.Args[2:]))
+		case "filter":
+			os.Exit(runFilter(os.Args[2:]))
+		case "explain":
+			os.Exit(runExplain(os.Args[2:]))
+		case "batch":
+			os.Exit(runBatch(os.Args[2:]))
+		case "manifest":
+			os.Exit(runManifest(os.Args[2:]))
+		case "verify":
+			os.Exit(runVerify(os.Args[2:]))
+		case "scan":
+			os.Exit(runScan(os.Args[2:]))
+		}
+	}
+	os.Exit(runReport(os.Args[1:]))
+}
+
+// fail prints an error and returns the error exit code.
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	return EXIT_ERROR
+}
+
+// parseArgs parses the flags of a subcommand and checks that it got at least min
+// arguments. It returns the exit code of the subcommand when it must stop, or -1.
+func parseArgs(flags *flag.FlagSet, args []string, min int) int {
+	if err := flags.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return 0
+		}
+		return EXIT_ERROR
 	}
+	if flags.NArg() < min {
+		flags.Usage()
+		return EXIT_ERROR
+	}
+	return -1
+}
+
+// runRegex prints the pattern of a constraint alone, for use in scripts:
+// version-to-regex regex [flags] <version-constraint>
+func runRegex(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("regex", "Usage: version-to-regex regex [flags] <version-constraint>\nPrints the pattern only: the regex, or the find pattern or glob when asked.", &cli, FLAGS_ALL)
+	if code := parseArgs(flags, args, 1); code != -1 {
+		return code
+	}
+	if flags.NArg() > 1 {
+		return fail(fmt.Errorf("regex takes a single constraint, got %d arguments", flags.NArg()))
+	}
+	if cli.find && cli.glob {
+		return fail(fmt.Errorf("-find and -glob cannot be combined"))
+	}
+
+	constraint, opts := flags.Arg(0), cli.options()
+	switch {
+	case cli.find:
+		pattern, err := convert.FindPattern(constraint, opts)
+		if err != nil {
+			return fail(err)
+		}
+		fmt.Println(pattern)
+	case cli.glob:
+		glob, err := convert.Glob(constraint, opts)
+		if err != nil {
+			return fail(err)
+		}
+		fmt.Println(glob.Pattern)
+	default:
+		dialect, err := convert.ParseDialect(cli.dialect)
+		if err != nil {
+			return fail(err)
+		}
+		pattern, err := convert.Pattern(constraint, dialect, opts)
+		if err != nil {
+			return fail(err)
+		}
+		fmt.Println(pattern)
+	}
+	return 0
+}
+
+// runMatch checks versions against a constraint, printing nothing:
+// version-to-regex match [flags] <version-constraint> <version>...
+// It exits with 0 when every version matches and EXIT_NO_MATCH otherwise.
+func runMatch(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("match", "Usage: version-to-regex match [flags] <version-constraint> <version>...\nExits with 0 when every version matches, 1 when one does not, and 2 on errors.", &cli, FLAGS_SYNTAX|FLAGS_AFFIXES)
+	if code := parseArgs(flags, args, 2); code != -1 {
+		return code
+	}
+
+	regex, err := convert.VersionToRegexWithOptions(flags.Arg(0), cli.options())
+	if err != nil {
+		return fail(err)
+	}
+	for _, version := range flags.Args()[1:] {
+		if !regex.MatchString(version) {
+			return EXIT_NO_MATCH
+		}
+	}
+	return 0
+}
 
-	constraint := os.Args[1]
+// runFilter prints the lines of input that match a constraint, like grep:
+// version-to-regex filter [flags] <version-constraint> < versions.txt
+// Lines are trimmed, and empty lines are skipped. It exits with EXIT_NO_MATCH when
+// no line matches.
+func runFilter(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("filter", "Usage: version-to-regex filter [flags] <version-constraint> < versions.txt\nPrints the versions read from standard input, one per line, that match.", &cli, FLAGS_SYNTAX|FLAGS_AFFIXES)
+	if code := parseArgs(flags, args, 1); code != -1 {
+		return code
+	}
+	if flags.NArg() > 1 {
+		return fail(fmt.Errorf("filter reads versions from standard input, got %d arguments", flags.NArg()))
+	}
 
-	regex, err := convert.VersionToRegex(constraint)
+	regex, err := convert.VersionToRegexWithOptions(flags.Arg(0), cli.options())
 	if err != nil {
+		return fail(err)
+	}
+	matched, err := filterLines(regex, os.Stdin, os.Stdout)
+	if err != nil {
+		return fail(err)
+	}
+	if !matched {
+		return EXIT_NO_MATCH
+	}
+	return 0
+}
+
+// filterLines copies the trimmed lines of input that regex matches to output, and
+// reports whether any did.
+func filterLines(regex *regexp.Regexp, input io.Reader, output io.Writer) (bool, error) {
+	matched := false
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || !regex.MatchString(line) {
+			continue
+		}
+		matched = true
+		if _, err := fmt.Fprintln(output, line); err != nil {
+			return matched, err
+		}
+	}
+	return matched, scanner.Err()
+}
+
+// runExplain describes a constraint in plain words:
+// version-to-regex explain [flags] <version-constraint>
+func runExplain(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("explain", "Usage: version-to-regex explain [flags] <version-constraint>\nDescribes the versions the constraint matches.", &cli, FLAGS_SYNTAX|FLAGS_AFFIXES)
+	if code := parseArgs(flags, args, 1); code != -1 {
+		return code
+	}
+	if flags.NArg() > 1 {
+		return fail(fmt.Errorf("explain takes a single constraint, got %d arguments", flags.NArg()))
+	}
+
+	description, err := convert.Explain(flags.Arg(0), cli.options())
+	if err != nil {
+		return fail(err)
+	}
+	fmt.Println(description)
+	return 0
+}
+
+// runBatch converts the constraints of a file, or of standard input, in parallel:
+// version-to-regex batch [flags] [file]
+// Each input gets one line of output, its pattern or its error. It exits with
+// EXIT_ERROR when some constraints could not be converted.
+func runBatch(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("batch", `Usage: version-to-regex batch [flags] [file]
+Converts every constraint of the file, or of standard input, printing one line per
+constraint: its id and pattern, or its id and error. The format defaults to the file
+extension (.csv, .json), or to one constraint per line. Exits with 2 when some
+constraints could not be converted.`, &cli, FLAGS_SYNTAX|FLAGS_AFFIXES|FLAGS_DIALECT)
+	formatName := flags.String("format", "", "format of the input: lines, csv (id,constraint[,ecosystem]) or json ([{id, constraint, ecosystem}])")
+	workers := flags.Int("workers", 0, "number of parallel conversions (default: one per CPU)")
+	output := flags.String("output", "text", "format of the results: text (id, tab, pattern or error) or json (one object per line)")
+	if code := parseArgs(flags, args, 0); code != -1 {
+		return code
+	}
+	if flags.NArg() > 1 {
+		return fail(fmt.Errorf("batch reads a single file, got %d arguments", flags.NArg()))
+	}
+	if *output != "text" && *output != "json" {
+		return fail(fmt.Errorf("unsupported output format: %s", *output))
+	}
+	dialect, err := convert.ParseDialect(cli.dialect)
+	if err != nil {
+		return fail(err)
+	}
+
+	input := io.Reader(os.Stdin)
+	name := ""
+	if flags.NArg() == 1 {
+		name = flags.Arg(0)
+		file, err := os.Open(name)
+		if err != nil {
+			return fail(err)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	if *formatName == "" {
+		switch {
+		case strings.HasSuffix(name, ".csv"):
+			*formatName = string(convert.BATCH_CSV)
+		case strings.HasSuffix(name, ".json"):
+			*formatName = string(convert.BATCH_JSON)
+		default:
+			*formatName = string(convert.BATCH_LINES)
+		}
+	}
+	format, err := convert.ParseBatchFormat(*formatName)
+	if err != nil {
+		return fail(err)
+	}
+
+	inputs, err := convert.ReadBatch(input, format)
+	if err != nil {
+		return fail(err)
+	}
+
+	code := 0
+	writer := bufio.NewWriter(os.Stdout)
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+	for _, result := range convert.ConvertBatch(inputs, dialect, cli.options(), *workers) {
+		if result.Error != "" {
+			code = EXIT_ERROR
+		}
+		switch {
+		case *output == "json":
+			err = encoder.Encode(result)
+		case result.Error != "":
+			_, err = fmt.Fprintf(writer, "%s\terror: %s\n", result.ID, result.Error)
+		default:
+			_, err = fmt.Fprintf(writer, "%s\t%s\n", result.ID, result.Pattern)
+		}
+		if err != nil {
+			return fail(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fail(err)
+	}
+	return code
+}
+
+// runManifest prints the pattern of every dependency of manifest files:
+// version-to-regex manifest [flags] <file>...
+func runManifest(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("manifest", `Usage: version-to-regex manifest [flags] <file>...
+Reads the dependencies of package.json, requirements.txt, pyproject.toml,
+composer.json, go.mod, pom.xml and .csproj files, and prints each package with the
+pattern of its version requirement. The ecosystem is the one of each file, and a
+package listed twice keeps its first requirement. Dependencies that cannot be
+converted, such as git URLs, are reported on standard error, and the command then
+exits with 2.`, &cli, FLAGS_AFFIXES|FLAGS_DIALECT)
+	output := flags.String("output", "text", "format of the map: text (package, tab, pattern) or json ({package: pattern})")
+	if code := parseArgs(flags, args, 1); code != -1 {
+		return code
+	}
+	if *output != "text" && *output != "json" {
+		return fail(fmt.Errorf("unsupported output format: %s", *output))
+	}
+	dialect, err := convert.ParseDialect(cli.dialect)
+	if err != nil {
+		return fail(err)
+	}
+
+	var dependencies []manifest.Dependency
+	for _, path := range flags.Args() {
+		fileDependencies, err := manifest.ParseFile(path)
+		if err != nil {
+			return fail(err)
+		}
+		dependencies = append(dependencies, fileDependencies...)
+	}
+
+	code := 0
+	patterns, errs := manifest.Patterns(dependencies, dialect, cli.options())
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		code = EXIT_ERROR
+	}
+
+	if *output == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(patterns); err != nil {
+			return fail(err)
+		}
+		return code
+	}
+
+	packages := make([]string, 0, len(patterns))
+	for name := range patterns {
+		packages = append(packages, name)
+	}
+	sort.Strings(packages)
+	writer := bufio.NewWriter(os.Stdout)
+	for _, name := range packages {
+		fmt.Fprintf(writer, "%s\t%s\n", name, patterns[name])
+	}
+	if err := writer.Flush(); err != nil {
+		return fail(err)
+	}
+	return code
+}
+
+// runVerify checks that the versions of a lockfile satisfy the requirements of its
+// manifest: version-to-regex verify [flags] <manifest> [lockfile]
+func runVerify(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("verify", `Usage: version-to-regex verify [flags] <manifest> [lockfile]
+Checks that every dependency of the manifest is locked at a version satisfying its
+requirement, and prints the violations. The lockfile defaults to the one next to the
+manifest: package-lock.json, go.sum, poetry.lock, composer.lock or packages.lock.json.
+Exits with 0 when every dependency is satisfied, 1 on violations or dependencies that
+cannot be converted, and 2 on errors.`, &cli, 0)
+	output := flags.String("output", "text", "format of the violations: text (one per line) or json (array)")
+	if code := parseArgs(flags, args, 1); code != -1 {
+		return code
+	}
+	if flags.NArg() > 2 {
+		return fail(fmt.Errorf("verify reads a manifest and a lockfile, got %d arguments", flags.NArg()))
+	}
+	if *output != "text" && *output != "json" {
+		return fail(fmt.Errorf("unsupported output format: %s", *output))
+	}
+
+	lockfile := flags.Arg(1)
+	if lockfile == "" {
+		var err error
+		if lockfile, err = manifest.LockfileFor(flags.Arg(0)); err != nil {
+			return fail(err)
+		}
+	}
+	dependencies, err := manifest.ParseFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+	locked, err := manifest.ParseLockfileFile(lockfile)
+	if err != nil {
+		return fail(err)
+	}
+
+	code := 0
+	violations, errs := manifest.Verify(dependencies, locked, cli.options())
+	for _, err := range errs {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		code = EXIT_NO_MATCH
+	}
+	if len(violations) > 0 {
+		code = EXIT_NO_MATCH
+	}
+
+	if *output == "json" {
+		if violations == nil {
+			violations = []manifest.Violation{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(violations); err != nil {
+			return fail(err)
+		}
+		return code
+	}
+	for _, violation := range violations {
+		fmt.Println(violation)
+	}
+	return code
+}
+
+// runScan checks the versions of a lockfile against OSV advisories:
+// version-to-regex scan [flags] <lockfile> <advisories>...
+func runScan(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("scan", `Usage: version-to-regex scan [flags] <lockfile> <advisories>...
+Checks the versions of a lockfile against OSV advisories, read from JSON files or
+from directories of JSON files such as a local mirror of an OSV dump, and prints the
+affected versions. Advisories that cannot be converted are reported as warnings.
+Exits with 0 when no version is affected, 1 when one is, and 2 on errors.`, &cli, 0)
+	output := flags.String("output", "text", "format of the findings: text (one per line) or json (array)")
+	if code := parseArgs(flags, args, 2); code != -1 {
+		return code
+	}
+	if *output != "text" && *output != "json" {
+		return fail(fmt.Errorf("unsupported output format: %s", *output))
+	}
+
+	locked, err := manifest.ParseLockfileFile(flags.Arg(0))
+	if err != nil {
+		return fail(err)
+	}
+	advisories, err := osv.Load(flags.Args()[1:]...)
+	if err != nil {
+		return fail(err)
+	}
+
+	matcher, errs := osv.NewMatcher(advisories, cli.options())
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	findings := matcher.Scan(locked)
+	code := 0
+	if len(findings) > 0 {
+		code = EXIT_NO_MATCH
+	}
+
+	if *output == "json" {
+		if findings == nil {
+			findings = []osv.Finding{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(findings); err != nil {
+			return fail(err)
+		}
+		return code
+	}
+	for _, finding := range findings {
+		fmt.Println(finding)
 	}
+	return code
+}
+
+// report is the result of the default command, printed as text or JSON.
+type report struct {
+	Constraint string `json:"constraint"`
+	convert.VersionConstraint
+	// Ecosystem is the constraint syntax, "auto" for the default one
+	Ecosystem string         `json:"ecosystem"`
+	Dialect   string         `json:"dialect"`
+	Pattern   string         `json:"pattern"`
+	Find      string         `json:"find,omitempty"`
+	Glob      *reportGlob    `json:"glob,omitempty"`
+	Results   []reportResult `json:"results"`
+}
+
+// reportGlob is the glob approximating the regex, asked for with -glob.
+type reportGlob struct {
+	Pattern string `json:"pattern"`
+	Exact   bool   `json:"exact"`
+}
+
+// reportResult tells whether a version given on the command line matches.
+type reportResult struct {
+	Version string `json:"version"`
+	Matches bool   `json:"matches"`
+}
+
+// runReport prints the report of a constraint and tests versions against it:
+// version-to-regex [flags] <version-constraint> [versions...]
+func runReport(args []string) int {
+	var cli cliOptions
+	flags := newFlagSet("version-to-regex", `Usage: version-to-regex [flags] <version-constraint> [versions...]
+       version-to-regex regex|match|filter|explain [flags] <version-constraint> ...
+       version-to-regex batch [flags] [file]
+       version-to-regex manifest [flags] <file>...
+       version-to-regex verify [flags] <manifest> [lockfile]
+       version-to-regex scan [flags] <lockfile> <advisories>...
+Subcommands:
+  regex    print the pattern only
+  match    exit with 0 when every version given matches
+  filter   print the versions read from standard input that match
+  explain  describe the versions the constraint matches
+  batch    convert every constraint of a file or of standard input
+  manifest print the pattern of every dependency of manifest files
+  verify   check that a lockfile satisfies the requirements of its manifest
+  scan     check the versions of a lockfile against OSV advisories
+Examples:
+  version-to-regex '>=1.2.3'
+  version-to-regex '^1.2.3'
+  version-to-regex '~1.2.3'
+  version-to-regex '1.*.0'
+  version-to-regex -dialect posix-ere '^1.2.3'
+  version-to-regex -prefix mylib- -suffix .tgz -find -glob '~1.2'
+  version-to-regex -output json '^1.2.3' 1.3.0 2.0.0
+  git tag | version-to-regex filter -prefix v '^1.2'
+  version-to-regex match '~1.2' "$VERSION" && echo supported
+  version-to-regex manifest -output json package.json go.mod
+  version-to-regex verify package.json package-lock.json
+  version-to-regex scan package-lock.json osv/npm`, &cli, FLAGS_ALL)
+	output := flags.String("output", "text", "format of the report: text or json")
+	if code := parseArgs(flags, args, 1); code != -1 {
+		return code
+	}
+	if *output != "text" && *output != "json" {
+		return fail(fmt.Errorf("unsupported output format: %s", *output))
+	}
+
+	r, err := buildReport(flags.Arg(0), flags.Args()[1:], cli)
+	if *output == "json" {
+		return printJSONReport(r, err)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	printTextReport(r)
+	return 0
+}
+
+// buildReport converts a constraint with the options of cli and tests versions against it.
+func buildReport(constraint string, versions []string, cli cliOptions) (report, error) {
+	r := report{Constraint: constraint, Ecosystem: cli.ecosystem, Dialect: cli.dialect, Results: []reportResult{}}
+	if r.Ecosystem == "" {
+		r.Ecosystem = "auto"
+	}
+
+	dialect, err := convert.ParseDialect(cli.dialect)
+	if err != nil {
+		return r, err
+	}
+	r.Dialect = string(dialect)
 
-	fmt.Printf("Version constraint: %s\n", constraint)
-	fmt.Printf("Generated regex: %s\n", regex.String())
+	opts := cli.options()
+	parsed, err := convert.ParseConstraint(constraint, opts)
+	if err != nil {
+		return r, fmt.Errorf("failed to parse version constraint: %w", err)
+	}
+	r.VersionConstraint = *parsed
+
+	regex, err := convert.VersionToRegexWithOptions(constraint, opts)
+	if err != nil {
+		return r, err
+	}
+	if r.Pattern, err = convert.Pattern(constraint, dialect, opts); err != nil {
+		return r, err
+	}
+
+	if cli.find {
+		if r.Find, err = convert.FindPattern(constraint, opts); err != nil {
+			return r, err
+		}
+	}
+	if cli.glob {
+		glob, err := convert.Glob(constraint, opts)
+		if err != nil {
+			return r, err
+		}
+		r.Glob = &reportGlob{Pattern: glob.Pattern, Exact: glob.Exact}
+	}
+
+	for _, version := range versions {
+		r.Results = append(r.Results, reportResult{Version: version, Matches: regex.MatchString(version)})
+	}
+	return r, nil
+}
+
+// printTextReport prints a report for people.
+func printTextReport(r report) {
+	fmt.Printf("Version constraint: %s\n", r.Constraint)
+	if r.Dialect == string(convert.DIALECT_GO) {
+		fmt.Printf("Generated regex: %s\n", r.Pattern)
+	} else {
+		fmt.Printf("Generated regex (%s): %s\n", r.Dialect, r.Pattern)
+	}
+
+	if r.Find != "" {
+		fmt.Printf("find pattern: %s\n", r.Find)
+	}
+
+	if r.Glob != nil {
+		exactness := "over-approximation"
+		if r.Glob.Exact {
+			exactness = "exact"
+		}
+		fmt.Printf("Glob (%s): %s\n", exactness, r.Glob.Pattern)
+	}
 
 	// Test with example versions if provided
-	if len(os.Args) > 2 {
+	if len(r.Results) > 0 {
 		fmt.Println("\nTesting versions:")
-		for _, version := range os.Args[2:] {
-			matches := regex.MatchString(version)
-			fmt.Printf("  %s: %v\n", version, matches)
+		for _, result := range r.Results {
+			fmt.Printf("  %s: %v\n", result.Version, result.Matches)
 		}
 	}
 }
+
+// printJSONReport prints a report as a JSON object on standard output, or an object
+// holding the constraint and the error when err is not nil. It returns the exit code.
+func printJSONReport(r report, err error) int {
+	var value any = r
+	code := 0
+	if err != nil {
+		value = struct {
+			Constraint string `json:"constraint"`
+			Error      string `json:"error"`
+		}{r.Constraint, err.Error()}
+		code = EXIT_ERROR
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fail(err)
+	}
+	return code
+}
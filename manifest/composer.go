@@ -0,0 +1,89 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the composer.json and composer.lock readers.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseComposerJSON reads the requirements of a composer.json file.
+//
+// The require and require-dev sections are read, each sorted by package name, with
+// the dev scope for require-dev. Platform requirements such as php or ext-json,
+// whose names have no vendor, are skipped.
+//
+// Parameters:
+//   - data: The content of the composer.json file
+//
+// Returns:
+//   - []Dependency: The Composer requirements
+//   - error: Error if the file is not valid JSON or a section is not an object of strings
+func ParseComposerJSON(data []byte) ([]Dependency, error) {
+	var manifest struct {
+		Require    map[string]string `json:"require"`
+		RequireDev map[string]string `json:"require-dev"`
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to read composer.json: %w", err)
+	}
+
+	var dependencies []Dependency
+	for _, section := range []struct {
+		requirements map[string]string
+		scope        string
+	}{{manifest.Require, ""}, {manifest.RequireDev, "dev"}} {
+		names := make([]string, 0, len(section.requirements))
+		for name := range section.requirements {
+			if strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dependencies = append(dependencies, Dependency{
+				Package:    name,
+				Constraint: section.requirements[name],
+				Manager:    MANAGER_COMPOSER,
+				Scope:      section.scope,
+			})
+		}
+	}
+	return dependencies, nil
+}
+
+// ParseComposerLock reads the locked versions of a composer.lock file, from its
+// packages and packages-dev lists.
+//
+// Parameters:
+//   - data: The content of the composer.lock file
+//
+// Returns:
+//   - []LockedVersion: The version of each locked package
+//   - error: Error if the file is not valid JSON or a package has no name or version
+func ParseComposerLock(data []byte) ([]LockedVersion, error) {
+	var lock struct {
+		Packages    []composerLockedPackage `json:"packages"`
+		PackagesDev []composerLockedPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to read composer.lock: %w", err)
+	}
+
+	var locked []LockedVersion
+	for _, lockedPackage := range append(lock.Packages, lock.PackagesDev...) {
+		if lockedPackage.Name == "" || lockedPackage.Version == "" {
+			return nil, fmt.Errorf("locked package without name or version: %+v", lockedPackage)
+		}
+		locked = append(locked, LockedVersion{Package: lockedPackage.Name, Version: lockedPackage.Version, Manager: MANAGER_COMPOSER})
+	}
+	return locked, nil
+}
+
+// composerLockedPackage is a package of a composer.lock file
+type composerLockedPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
@@ -0,0 +1,61 @@
+// Package manifest provides tests for Composer manifest handling.
+// This file checks the composer.json and composer.lock readers.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseComposerJSON tests the require sections, platform packages excluded.
+func TestParseComposerJSON(t *testing.T) {
+	data := []byte(`{
+		"name": "acme/app",
+		"require": {"php": ">=8.1", "ext-json": "*", "symfony/console": "^6.4 || ^7.0", "monolog/monolog": "^3.0@beta"},
+		"require-dev": {"phpunit/phpunit": "~10.5"}
+	}`)
+	expected := []Dependency{
+		{Package: "monolog/monolog", Constraint: "^3.0@beta", Manager: MANAGER_COMPOSER},
+		{Package: "symfony/console", Constraint: "^6.4 || ^7.0", Manager: MANAGER_COMPOSER},
+		{Package: "phpunit/phpunit", Constraint: "~10.5", Manager: MANAGER_COMPOSER, Scope: "dev"},
+	}
+
+	dependencies, err := ParseComposerJSON(data)
+	if err != nil {
+		t.Fatalf("ParseComposerJSON returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParseComposerJSON = %+v, expected %+v", dependencies, expected)
+	}
+
+	if _, err := ParseComposerJSON([]byte(`{"require": ["symfony/console"]}`)); err == nil {
+		t.Errorf("ParseComposerJSON expected error for a require list but got none")
+	}
+}
+
+// TestParseComposerLock tests the packages and packages-dev lists of composer.lock.
+func TestParseComposerLock(t *testing.T) {
+	data := []byte(`{
+		"packages": [{"name": "symfony/console", "version": "v7.0.4"}, {"name": "monolog/monolog", "version": "3.5.0"}],
+		"packages-dev": [{"name": "phpunit/phpunit", "version": "10.5.11"}]
+	}`)
+	expected := []LockedVersion{
+		{Package: "symfony/console", Version: "v7.0.4", Manager: MANAGER_COMPOSER},
+		{Package: "monolog/monolog", Version: "3.5.0", Manager: MANAGER_COMPOSER},
+		{Package: "phpunit/phpunit", Version: "10.5.11", Manager: MANAGER_COMPOSER},
+	}
+
+	locked, err := ParseComposerLock(data)
+	if err != nil {
+		t.Fatalf("ParseComposerLock returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(locked, expected) {
+		t.Errorf("ParseComposerLock = %+v, expected %+v", locked, expected)
+	}
+
+	for _, invalid := range []string{`{"packages": {}}`, `{"packages": [{"name": "a/b"}]}`} {
+		if _, err := ParseComposerLock([]byte(invalid)); err == nil {
+			t.Errorf("ParseComposerLock(%s) expected error but got none", invalid)
+		}
+	}
+}
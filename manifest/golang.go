@@ -0,0 +1,106 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the go.mod reader.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ParseGoMod reads the requirements of a go.mod file.
+//
+// Both single require directives and require blocks are read. Go modules require
+// minimum versions, but the build list selects exactly one version of each module,
+// so the constraint is the required version itself; requirements marked
+// "// indirect" have the indirect scope.
+//
+// Parameters:
+//   - data: The content of the go.mod file
+//
+// Returns:
+//   - []Dependency: The module requirements, in the order of the file
+//   - error: Error if a requirement has no version or a block is not closed
+func ParseGoMod(data []byte) ([]Dependency, error) {
+	var dependencies []Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	block := false
+	line := 0
+	for scanner.Scan() {
+		line++
+		text, comment, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(text)
+
+		switch {
+		case block && len(fields) == 1 && fields[0] == ")":
+			block = false
+			continue
+		case block:
+		case len(fields) == 2 && fields[0] == "require" && fields[1] == "(":
+			block = true
+			continue
+		case len(fields) > 0 && fields[0] == "require":
+			fields = fields[1:]
+		default:
+			continue
+		}
+
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected module path and version: %s", line, strings.TrimSpace(text))
+		}
+		dependency := Dependency{
+			Package:    strings.Trim(fields[0], `"`),
+			Constraint: fields[1],
+			Manager:    MANAGER_GO,
+		}
+		if strings.HasPrefix(strings.TrimSpace(comment), "indirect") {
+			dependency.Scope = "indirect"
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
+	}
+	if block {
+		return nil, fmt.Errorf("unclosed require block")
+	}
+	return dependencies, nil
+}
+
+// ParseGoSum reads the module versions of a go.sum file. Each module version appears
+// once, whether go.sum holds the checksum of its content, of its go.mod file, or both.
+//
+// Parameters:
+//   - data: The content of the go.sum file
+//
+// Returns:
+//   - []LockedVersion: The module versions, in the order of the file
+//   - error: Error if a line does not hold a module, a version and a checksum
+func ParseGoSum(data []byte) ([]LockedVersion, error) {
+	var locked []LockedVersion
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: expected module path, version and checksum", line)
+		}
+		version := strings.TrimSuffix(fields[1], "/go.mod")
+		if seen[fields[0]+"@"+version] {
+			continue
+		}
+		seen[fields[0]+"@"+version] = true
+		locked = append(locked, LockedVersion{Package: fields[0], Version: version, Manager: MANAGER_GO})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read go.sum: %w", err)
+	}
+	return locked, nil
+}
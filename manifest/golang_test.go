@@ -0,0 +1,88 @@
+// Package manifest provides tests for Go module manifest handling.
+// This file checks the go.mod reader.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestParseGoMod tests single require directives and require blocks.
+func TestParseGoMod(t *testing.T) {
+	data := []byte(`module example.com/app
+
+go 1.22
+
+require github.com/pkg/errors v0.9.1
+
+require (
+	golang.org/x/text v0.14.0 // indirect
+	github.com/google/uuid v1.6.0
+	// comment
+	github.com/old/lib v2.1.0+incompatible
+	golang.org/x/sys v0.0.0-20240101000000-abcdefabcdef // indirect; via x/text
+)
+
+replace github.com/pkg/errors => ../errors
+`)
+	expected := []Dependency{
+		{Package: "github.com/pkg/errors", Constraint: "v0.9.1", Manager: MANAGER_GO},
+		{Package: "golang.org/x/text", Constraint: "v0.14.0", Manager: MANAGER_GO, Scope: "indirect"},
+		{Package: "github.com/google/uuid", Constraint: "v1.6.0", Manager: MANAGER_GO},
+		{Package: "github.com/old/lib", Constraint: "v2.1.0+incompatible", Manager: MANAGER_GO},
+		{Package: "golang.org/x/sys", Constraint: "v0.0.0-20240101000000-abcdefabcdef", Manager: MANAGER_GO, Scope: "indirect"},
+	}
+
+	dependencies, err := ParseGoMod(data)
+	if err != nil {
+		t.Fatalf("ParseGoMod returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParseGoMod = %+v, expected %+v", dependencies, expected)
+	}
+
+	for _, dependency := range dependencies {
+		regex, err := dependency.Regex(convert.Options{})
+		if err != nil {
+			t.Fatalf("%s: Regex returned unexpected error: %v", dependency.Package, err)
+		}
+		if !regex.MatchString(dependency.Constraint) {
+			t.Errorf("%s: %s should match its own version", dependency.Package, regex)
+		}
+	}
+
+	for _, invalid := range []string{"require github.com/pkg/errors\n", "require (\n\tgolang.org/x/text v0.14.0\n"} {
+		if _, err := ParseGoMod([]byte(invalid)); err == nil {
+			t.Errorf("ParseGoMod(%q) expected error but got none", invalid)
+		}
+	}
+}
+
+// TestParseGoSum tests that each module version of go.sum is read once.
+func TestParseGoSum(t *testing.T) {
+	data := []byte(`github.com/pkg/errors v0.9.1 h1:FEBLx1zS214owpjy7qsBeixbURkuhQAwrK5UwLGTwt4=
+github.com/pkg/errors v0.9.1/go.mod h1:bwawxfHBFNV+L2hUp1rHADufV3IMtnDRdf1r5NINEl0=
+golang.org/x/text v0.13.0/go.mod h1:TvPlkZtksWOMsz7fbANvkp4WM8x/WCo/om8BMLbz+aE=
+golang.org/x/text v0.14.0 h1:ScX5w1eTa3QqT8oi6+ziP7dTV1S2+ALU0bI+0zXKWiQ=
+
+`)
+	expected := []LockedVersion{
+		{Package: "github.com/pkg/errors", Version: "v0.9.1", Manager: MANAGER_GO},
+		{Package: "golang.org/x/text", Version: "v0.13.0", Manager: MANAGER_GO},
+		{Package: "golang.org/x/text", Version: "v0.14.0", Manager: MANAGER_GO},
+	}
+
+	locked, err := ParseGoSum(data)
+	if err != nil {
+		t.Fatalf("ParseGoSum returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(locked, expected) {
+		t.Errorf("ParseGoSum = %+v, expected %+v", locked, expected)
+	}
+
+	if _, err := ParseGoSum([]byte("github.com/pkg/errors v0.9.1\n")); err == nil {
+		t.Errorf("ParseGoSum expected error for a line without checksum but got none")
+	}
+}
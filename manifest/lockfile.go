@@ -0,0 +1,202 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the LockedVersion type, the detection of lockfile formats, and
+// the verification that locked versions satisfy the requirements of a manifest.
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// LOCKFILES maps manifest file names to the name of their lockfile, in the same
+// directory. MSBuild projects use packages.lock.json.
+var LOCKFILES = map[string]string{
+	"package.json":   "package-lock.json",
+	"go.mod":         "go.sum",
+	"pyproject.toml": "poetry.lock",
+	"composer.json":  "composer.lock",
+}
+
+// pythonNameSeparators matches the runs of separators that PEP 503 normalizes to "-"
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// LockedVersion is the version of a package pinned by a lockfile.
+type LockedVersion struct {
+	Package string  `json:"package"`
+	Version string  `json:"version"`
+	Manager Manager `json:"manager"`
+}
+
+// Violation is a dependency whose locked versions do not satisfy its requirement,
+// or that the lockfile does not pin.
+type Violation struct {
+	Dependency Dependency `json:"dependency"`
+	// Locked lists the locked versions outside the requirement; it is empty when the
+	// package is missing from the lockfile
+	Locked []string `json:"locked,omitempty"`
+}
+
+// String describes the violation, such as "react: locked 17.0.2 does not satisfy ^18.2.0".
+func (v Violation) String() string {
+	if len(v.Locked) == 0 {
+		return fmt.Sprintf("%s: %s is not locked", v.Dependency.Package, v.Dependency.Constraint)
+	}
+	return fmt.Sprintf("%s: locked %s does not satisfy %s", v.Dependency.Package, strings.Join(v.Locked, ", "), v.Dependency.Constraint)
+}
+
+// LockfileFor returns the path of the lockfile of the manifest at path: package-lock.json
+// for package.json, go.sum for go.mod, poetry.lock for pyproject.toml, composer.lock
+// for composer.json and packages.lock.json for MSBuild projects.
+func LockfileFor(path string) (string, error) {
+	base := filepath.Base(path)
+	if lockfile, ok := LOCKFILES[base]; ok {
+		return filepath.Join(filepath.Dir(path), lockfile), nil
+	}
+	switch strings.ToLower(filepath.Ext(base)) {
+	case ".csproj", ".fsproj", ".vbproj":
+		return filepath.Join(filepath.Dir(path), "packages.lock.json"), nil
+	}
+	return "", fmt.Errorf("no known lockfile for %s", path)
+}
+
+// ParseLockfile reads the locked versions of a lockfile, whose format is detected from
+// its file name: package-lock.json or npm-shrinkwrap.json, go.sum, poetry.lock,
+// composer.lock or packages.lock.json.
+//
+// Parameters:
+//   - name: The file name or path of the lockfile
+//   - data: The content of the lockfile
+//
+// Returns:
+//   - []LockedVersion: The locked versions
+//   - error: Error if the format is unknown or the lockfile is malformed
+func ParseLockfile(name string, data []byte) ([]LockedVersion, error) {
+	switch filepath.Base(name) {
+	case "package-lock.json", "npm-shrinkwrap.json":
+		return ParsePackageLock(data)
+	case "go.sum":
+		return ParseGoSum(data)
+	case "poetry.lock":
+		return ParsePoetryLock(data)
+	case "composer.lock":
+		return ParseComposerLock(data)
+	case "packages.lock.json":
+		return ParseNuGetLock(data)
+	default:
+		return nil, fmt.Errorf("unsupported lockfile: %s", name)
+	}
+}
+
+// ParseLockfileFile reads the locked versions of the lockfile at path, as ParseLockfile does.
+func ParseLockfileFile(path string) ([]LockedVersion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	locked, err := ParseLockfile(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return locked, nil
+}
+
+// Verify checks that the locked versions of every dependency satisfy its requirement.
+//
+// Every locked version of a package must match the regex of its requirement, except
+// for Go modules: go.sum holds the checksums of every version in the module graph,
+// so the required version only has to be among them, exactly as written. Package
+// names are compared as their package manager does, case-insensitively with PEP 503
+// normalization for Python and case-insensitively for Composer and NuGet.
+// Dependencies that cannot be converted, such as git URLs, are reported in the
+// returned errors.
+//
+// Parameters:
+//   - dependencies: The dependencies of the manifest
+//   - locked: The versions of the lockfile
+//   - opts: Conversion options; the ecosystem is the one of each package manager
+//
+// Returns:
+//   - []Violation: The dependencies that are not locked or locked outside their requirement
+//   - []error: Errors of the dependencies that could not be converted
+//
+// Example:
+//
+//	dependencies, _ := ParseFile("package.json")
+//	locked, _ := ParseLockfileFile("package-lock.json")
+//	violations, errs := Verify(dependencies, locked, convert.Options{})
+func Verify(dependencies []Dependency, locked []LockedVersion, opts convert.Options) ([]Violation, []error) {
+	versions := make(map[string][]string)
+	for _, lockedVersion := range locked {
+		key := lockKey(lockedVersion.Manager, lockedVersion.Package)
+		versions[key] = append(versions[key], lockedVersion.Version)
+	}
+
+	var violations []Violation
+	var errs []error
+	for _, dependency := range dependencies {
+		regex, err := dependency.Regex(opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		lockedVersions := versions[lockKey(dependency.Manager, dependency.Package)]
+		if len(lockedVersions) == 0 {
+			violations = append(violations, Violation{Dependency: dependency})
+			continue
+		}
+		if dependency.Manager == MANAGER_GO {
+			// The required version itself must be in go.sum, not a version it matches
+			if !containsVersion(lockedVersions, dependency.Constraint) {
+				violations = append(violations, Violation{Dependency: dependency, Locked: lockedVersions})
+			}
+			continue
+		}
+		var outside []string
+		for _, version := range lockedVersions {
+			if !regex.MatchString(version) {
+				outside = append(outside, version)
+			}
+		}
+		if len(outside) > 0 {
+			violations = append(violations, Violation{Dependency: dependency, Locked: outside})
+		}
+	}
+	return violations, errs
+}
+
+// containsVersion reports whether version is one of versions, as written.
+func containsVersion(versions []string, version string) bool {
+	for _, candidate := range versions {
+		if candidate == version {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizePythonName returns the PEP 503 normalized name of a Python package, under
+// which pip, Poetry and PyPI find it.
+//
+// Example:
+//
+//	NormalizePythonName("Zope.Interface") // "zope-interface"
+func NormalizePythonName(name string) string {
+	return pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+}
+
+// lockKey returns the name under which a package manager finds a package.
+func lockKey(manager Manager, name string) string {
+	switch manager {
+	case MANAGER_PIP, MANAGER_POETRY:
+		return NormalizePythonName(name)
+	case MANAGER_COMPOSER, MANAGER_NUGET:
+		return strings.ToLower(name)
+	default:
+		return name
+	}
+}
@@ -0,0 +1,129 @@
+// Package manifest provides tests for lockfile verification.
+// This file checks the detection of lockfiles and the verification of locked versions.
+package manifest
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestLockfileFor tests the lockfile paths of manifests.
+func TestLockfileFor(t *testing.T) {
+	tests := []struct {
+		manifest string
+		expected string
+	}{
+		{"app/package.json", "app/package-lock.json"},
+		{"go.mod", "go.sum"},
+		{"pyproject.toml", "poetry.lock"},
+		{"composer.json", "composer.lock"},
+		{"src/App.csproj", "src/packages.lock.json"},
+	}
+
+	for _, tt := range tests {
+		lockfile, err := LockfileFor(tt.manifest)
+		if err != nil {
+			t.Errorf("LockfileFor(%q) returned unexpected error: %v", tt.manifest, err)
+			continue
+		}
+		if lockfile != filepath.FromSlash(tt.expected) {
+			t.Errorf("LockfileFor(%q) = %q, expected %q", tt.manifest, lockfile, tt.expected)
+		}
+	}
+
+	for _, manifest := range []string{"requirements.txt", "pom.xml"} {
+		if _, err := LockfileFor(manifest); err == nil {
+			t.Errorf("LockfileFor(%q) expected error but got none", manifest)
+		}
+	}
+}
+
+// TestParseLockfile tests that lockfiles are read by the parser of their file name.
+func TestParseLockfile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"package-lock.json", `{"packages": {"node_modules/a": {"version": "1.0.0"}}}`},
+		{"npm-shrinkwrap.json", `{"dependencies": {"a": {"version": "1.0.0"}}}`},
+		{"go.sum", "example.com/a v1.0.0 h1:abc=\n"},
+		{"poetry.lock", "[[package]]\nname = \"a\"\nversion = \"1.0.0\"\n"},
+		{"composer.lock", `{"packages": [{"name": "a/a", "version": "1.0.0"}]}`},
+		{"obj/packages.lock.json", `{"dependencies": {"net8.0": {"a": {"resolved": "1.0.0"}}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locked, err := ParseLockfile(tt.name, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseLockfile returned unexpected error: %v", err)
+			}
+			if len(locked) != 1 || strings.TrimPrefix(locked[0].Version, "v") != "1.0.0" {
+				t.Errorf("ParseLockfile = %+v, expected one package locked at 1.0.0", locked)
+			}
+		})
+	}
+
+	if _, err := ParseLockfile("yarn.lock", nil); err == nil {
+		t.Errorf("ParseLockfile(yarn.lock) expected error but got none")
+	}
+}
+
+// TestVerify tests violations of each kind and the package manager rules.
+func TestVerify(t *testing.T) {
+	dependencies := []Dependency{
+		{Package: "react", Constraint: "^18.2.0", Manager: MANAGER_NPM},
+		{Package: "lodash", Constraint: "~4.17", Manager: MANAGER_NPM},
+		{Package: "left-pad", Constraint: "^1.3.0", Manager: MANAGER_NPM},
+		{Package: "local", Constraint: "file:../local", Manager: MANAGER_NPM},
+		{Package: "golang.org/x/text", Constraint: "v0.14.0", Manager: MANAGER_GO},
+		{Package: "github.com/pkg/errors", Constraint: "v0.9.1", Manager: MANAGER_GO},
+		{Package: "example.com/mod", Constraint: "v1.2.0", Manager: MANAGER_GO},
+		{Package: "Django", Constraint: ">=4.2,<5.0", Manager: MANAGER_PIP},
+		{Package: "zope.interface", Constraint: "^6.0", Manager: MANAGER_POETRY},
+		{Package: "Newtonsoft.Json", Constraint: "13.0.3", Manager: MANAGER_NUGET},
+		{Package: "Monolog/Monolog", Constraint: "~2.0", Manager: MANAGER_COMPOSER},
+	}
+	locked := []LockedVersion{
+		{Package: "react", Version: "17.0.2", Manager: MANAGER_NPM},
+		{Package: "lodash", Version: "4.17.21", Manager: MANAGER_NPM},
+		{Package: "local", Version: "1.0.0", Manager: MANAGER_NPM},
+		{Package: "golang.org/x/text", Version: "v0.13.0", Manager: MANAGER_GO},
+		{Package: "golang.org/x/text", Version: "v0.14.0", Manager: MANAGER_GO},
+		{Package: "github.com/pkg/errors", Version: "v0.8.0", Manager: MANAGER_GO},
+		{Package: "example.com/mod", Version: "v1.1.0", Manager: MANAGER_GO},
+		{Package: "example.com/mod", Version: "v1.2.0-rc.1", Manager: MANAGER_GO},
+		{Package: "django", Version: "4.2.11", Manager: MANAGER_POETRY},
+		{Package: "zope-interface", Version: "6.2", Manager: MANAGER_POETRY},
+		{Package: "newtonsoft.json", Version: "13.0.3", Manager: MANAGER_NUGET},
+		{Package: "monolog/monolog", Version: "2.9.1", Manager: MANAGER_COMPOSER},
+	}
+	expected := []Violation{
+		{Dependency: dependencies[0], Locked: []string{"17.0.2"}},
+		{Dependency: dependencies[2]},
+		{Dependency: dependencies[5], Locked: []string{"v0.8.0"}},
+		{Dependency: dependencies[6], Locked: []string{"v1.1.0", "v1.2.0-rc.1"}},
+	}
+
+	violations, errs := Verify(dependencies, locked, convert.Options{})
+	if !reflect.DeepEqual(violations, expected) {
+		t.Errorf("Verify = %+v, expected %+v", violations, expected)
+	}
+	if len(errs) != 1 {
+		t.Errorf("Verify returned errors %v, expected one for the file: dependency", errs)
+	}
+
+	messages := []string{
+		"react: locked 17.0.2 does not satisfy ^18.2.0",
+		"left-pad: ^1.3.0 is not locked",
+	}
+	for i, message := range messages {
+		if violations[i].String() != message {
+			t.Errorf("violation %d = %q, expected %q", i, violations[i].String(), message)
+		}
+	}
+}
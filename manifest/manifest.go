@@ -0,0 +1,208 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the Dependency type, the detection of manifest formats from
+// file names, and the conversion of dependencies to regexes with the semantics of
+// their package manager.
+//
+// Each package manager writes version requirements in its own syntax. Dependencies
+// keep the requirement as written, and translate it on conversion into the syntax of
+// the convert package ecosystem with the same semantics:
+//
+//   - npm (package.json): npm ranges as they are, once paths, URLs and dist-tags are rejected
+//   - pip (requirements.txt, pyproject.toml): PEP 440 version specifiers as they are
+//   - Poetry (pyproject.toml): PEP 440 version specifiers, ^ and ~ ranges written as bounds
+//   - Composer (composer.json): Composer constraints as they are
+//   - Go modules (go.mod): exact module versions
+//   - Maven (pom.xml): Maven ranges, soft requirements such as 1.2.3 becoming [1.2.3]
+//   - NuGet (.csproj): minimum versions, Maven-style ranges and floating versions
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// Manager identifies the package manager a dependency was declared for.
+type Manager string
+
+// Supported package managers
+const (
+	// MANAGER_NPM reads package.json dependencies
+	MANAGER_NPM Manager = "npm"
+	// MANAGER_PIP reads requirements.txt requirements and pyproject.toml PEP 621 dependencies
+	MANAGER_PIP Manager = "pip"
+	// MANAGER_POETRY reads pyproject.toml Poetry dependencies
+	MANAGER_POETRY Manager = "poetry"
+	// MANAGER_COMPOSER reads composer.json requirements
+	MANAGER_COMPOSER Manager = "composer"
+	// MANAGER_GO reads go.mod require directives
+	MANAGER_GO Manager = "go"
+	// MANAGER_MAVEN reads pom.xml dependencies
+	MANAGER_MAVEN Manager = "maven"
+	// MANAGER_NUGET reads PackageReference and PackageVersion items of MSBuild projects
+	MANAGER_NUGET Manager = "nuget"
+)
+
+// Dependency is a package required by a manifest, with its version requirement.
+type Dependency struct {
+	// Package is the name of the package: an npm or PyPI name, a Go module path,
+	// a Maven groupId:artifactId or a NuGet package id
+	Package string `json:"package"`
+	// Constraint is the version requirement as written in the manifest
+	Constraint string `json:"constraint"`
+	// Manager is the package manager whose syntax Constraint uses
+	Manager Manager `json:"manager"`
+	// Scope is the group the dependency was declared in, such as devDependencies,
+	// indirect or test, or empty for regular dependencies
+	Scope string `json:"scope,omitempty"`
+}
+
+// Requirement translates the constraint into the syntax of the convert package,
+// returning it with the ecosystem that gives it the semantics of the package manager.
+//
+// Returns:
+//   - string: The constraint in the syntax of the ecosystem
+//   - convert.Ecosystem: The ecosystem to convert the constraint with
+//   - error: Error if the constraint is not a version requirement, such as a git URL
+func (d Dependency) Requirement() (string, convert.Ecosystem, error) {
+	switch d.Manager {
+	case MANAGER_NPM:
+		requirement, err := npmRequirement(d.Constraint)
+		return requirement, convert.ECOSYSTEM_NPM, err
+	case MANAGER_PIP:
+		requirement, err := pipRequirement(d.Constraint)
+		return requirement, convert.ECOSYSTEM_PYPI, err
+	case MANAGER_POETRY:
+		requirement, err := poetryRequirement(d.Constraint)
+		return requirement, convert.ECOSYSTEM_PYPI, err
+	case MANAGER_COMPOSER:
+		return d.Constraint, convert.ECOSYSTEM_COMPOSER, nil
+	case MANAGER_GO:
+		return d.Constraint, convert.ECOSYSTEM_AUTO, nil
+	case MANAGER_MAVEN:
+		requirement, err := mavenRequirement(d.Constraint)
+		return requirement, convert.ECOSYSTEM_AUTO, err
+	case MANAGER_NUGET:
+		requirement, err := nugetRequirement(d.Constraint)
+		return requirement, convert.ECOSYSTEM_NUGET, err
+	default:
+		return "", "", fmt.Errorf("unsupported package manager: %s", d.Manager)
+	}
+}
+
+// Pattern converts the requirement of the dependency to a pattern in the syntax of
+// dialect. The ecosystem of opts is replaced by the one of the package manager.
+//
+// Parameters:
+//   - dialect: The regex syntax of the pattern
+//   - opts: Conversion options, such as the boundary
+//
+// Returns:
+//   - string: Pattern matching the versions allowed by the requirement
+//   - error: Error if the requirement cannot be translated or converted
+func (d Dependency) Pattern(dialect convert.Dialect, opts convert.Options) (string, error) {
+	requirement, ecosystem, err := d.Requirement()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", d.Package, err)
+	}
+	opts.Ecosystem = ecosystem
+	pattern, err := convert.Pattern(requirement, dialect, opts)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", d.Package, err)
+	}
+	return pattern, nil
+}
+
+// Regex converts the requirement of the dependency to a compiled regex. The
+// ecosystem of opts is replaced by the one of the package manager.
+func (d Dependency) Regex(opts convert.Options) (*regexp.Regexp, error) {
+	pattern, err := d.Pattern(convert.DIALECT_GO, opts)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+// Patterns converts dependencies to a map from package names to patterns.
+//
+// A package declared several times keeps its first requirement. Dependencies that
+// cannot be converted are left out of the map and reported in the returned errors,
+// so that one git URL does not hide the other packages.
+//
+// Parameters:
+//   - dependencies: The dependencies to convert
+//   - dialect: The regex syntax of the patterns
+//   - opts: Conversion options, such as the boundary
+//
+// Returns:
+//   - map[string]string: Pattern of each converted package
+//   - []error: Errors of the dependencies that could not be converted
+func Patterns(dependencies []Dependency, dialect convert.Dialect, opts convert.Options) (map[string]string, []error) {
+	patterns := make(map[string]string, len(dependencies))
+	seen := make(map[string]bool, len(dependencies))
+	var errs []error
+	for _, dependency := range dependencies {
+		if seen[dependency.Package] {
+			continue
+		}
+		seen[dependency.Package] = true
+
+		pattern, err := dependency.Pattern(dialect, opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		patterns[dependency.Package] = pattern
+	}
+	return patterns, errs
+}
+
+// Parse reads the dependencies of a manifest, whose format is detected from its file
+// name: package.json, requirements*.txt, pyproject.toml, composer.json, go.mod,
+// pom.xml, *.csproj, *.fsproj, *.vbproj or *.props.
+//
+// Parameters:
+//   - name: The file name or path of the manifest
+//   - data: The content of the manifest
+//
+// Returns:
+//   - []Dependency: The dependencies in the order of the manifest
+//   - error: Error if the format is unknown or the manifest is malformed
+func Parse(name string, data []byte) ([]Dependency, error) {
+	base := filepath.Base(name)
+	switch extension := strings.ToLower(filepath.Ext(base)); {
+	case base == "package.json":
+		return ParsePackageJSON(data)
+	case strings.HasPrefix(base, "requirements") && extension == ".txt":
+		return ParseRequirements(data)
+	case base == "pyproject.toml":
+		return ParsePyProject(data)
+	case base == "composer.json":
+		return ParseComposerJSON(data)
+	case base == "go.mod":
+		return ParseGoMod(data)
+	case base == "pom.xml":
+		return ParsePOM(data)
+	case extension == ".csproj" || extension == ".fsproj" || extension == ".vbproj" || extension == ".props":
+		return ParseMSBuild(data)
+	default:
+		return nil, fmt.Errorf("unsupported manifest: %s", name)
+	}
+}
+
+// ParseFile reads the dependencies of the manifest at path, as Parse does.
+func ParseFile(path string) ([]Dependency, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := Parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return dependencies, nil
+}
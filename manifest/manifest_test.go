@@ -0,0 +1,119 @@
+// Package manifest provides tests for package manager manifests.
+// This file checks the detection of manifest formats and the conversion of
+// dependencies to patterns.
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestParse tests that manifests are read by the parser of their file name.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		manager Manager
+	}{
+		{"package.json", `{"dependencies": {"a": "^1.0.0"}}`, MANAGER_NPM},
+		{"app/requirements.txt", "a>=1.0\n", MANAGER_PIP},
+		{"requirements-dev.txt", "a>=1.0\n", MANAGER_PIP},
+		{"pyproject.toml", "[tool.poetry.dependencies]\na = \"^1.0\"\n", MANAGER_POETRY},
+		{"composer.json", `{"require": {"php": ">=8.1", "a/a": "^1.0"}}`, MANAGER_COMPOSER},
+		{"go.mod", "require example.com/a v1.0.0\n", MANAGER_GO},
+		{"pom.xml", `<project><dependencies><dependency><groupId>g</groupId><artifactId>a</artifactId><version>1.0</version></dependency></dependencies></project>`, MANAGER_MAVEN},
+		{"src/App.csproj", `<Project><ItemGroup><PackageReference Include="a" Version="1.0" /></ItemGroup></Project>`, MANAGER_NUGET},
+		{"Directory.Packages.props", `<Project><ItemGroup><PackageVersion Include="a" Version="1.0" /></ItemGroup></Project>`, MANAGER_NUGET},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dependencies, err := Parse(tt.name, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse returned unexpected error: %v", err)
+			}
+			if len(dependencies) != 1 || dependencies[0].Manager != tt.manager {
+				t.Errorf("Parse = %+v, expected one %s dependency", dependencies, tt.manager)
+			}
+		})
+	}
+
+	for _, name := range []string{"Gemfile", "Cargo.toml", "notes.txt"} {
+		if _, err := Parse(name, nil); err == nil {
+			t.Errorf("Parse(%q) expected error but got none", name)
+		}
+	}
+}
+
+// TestParseFile tests reading a manifest from disk.
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(path, []byte("require example.com/a v1.2.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	dependencies, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile returned unexpected error: %v", err)
+	}
+	if len(dependencies) != 1 || dependencies[0].Constraint != "v1.2.3" {
+		t.Errorf("ParseFile = %+v, expected example.com/a v1.2.3", dependencies)
+	}
+
+	if _, err := ParseFile(filepath.Join(t.TempDir(), "package.json")); err == nil {
+		t.Errorf("ParseFile expected error for a missing file but got none")
+	}
+}
+
+// TestPatterns tests the package to pattern map.
+func TestPatterns(t *testing.T) {
+	dependencies := []Dependency{
+		{Package: "react", Constraint: "^18.2.0", Manager: MANAGER_NPM},
+		{Package: "local", Constraint: "file:../local", Manager: MANAGER_NPM},
+		{Package: "react", Constraint: "^17.0.0", Manager: MANAGER_NPM, Scope: "peerDependencies"},
+		{Package: "junit:junit", Constraint: "4.13.2", Manager: MANAGER_MAVEN},
+		{Package: "Django", Constraint: ">=4.2,<5.0", Manager: MANAGER_PIP},
+		{Package: "broken", Constraint: "1.0", Manager: Manager("conda")},
+	}
+
+	patterns, errs := Patterns(dependencies, convert.DIALECT_GO, convert.Options{})
+	if len(patterns) != 3 {
+		t.Errorf("Patterns returned %d patterns, expected 3: %v", len(patterns), patterns)
+	}
+	if len(errs) != 2 {
+		t.Errorf("Patterns returned %d errors, expected 2: %v", len(errs), errs)
+	}
+
+	tests := []struct {
+		name           string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{"react", []string{"18.2.0", "18.9.1"}, []string{"17.0.2", "19.0.0"}},
+		{"junit:junit", []string{"4.13.2"}, []string{"4.13.1", "4.13"}},
+		{"Django", []string{"4.2.7"}, []string{"5.0"}},
+	}
+	for _, tt := range tests {
+		regex := regexp.MustCompile(patterns[tt.name])
+		for _, version := range tt.shouldMatch {
+			if !regex.MatchString(version) {
+				t.Errorf("%s pattern %s should match %q", tt.name, regex, version)
+			}
+		}
+		for _, version := range tt.shouldNotMatch {
+			if regex.MatchString(version) {
+				t.Errorf("%s pattern %s should not match %q", tt.name, regex, version)
+			}
+		}
+	}
+
+	// The ecosystem of the options is replaced by the one of the package manager
+	pattern, err := dependencies[0].Pattern(convert.DIALECT_POSIX_ERE, convert.Options{Ecosystem: convert.ECOSYSTEM_COMPOSER})
+	expected, _ := convert.Pattern("^18.2.0", convert.DIALECT_POSIX_ERE, convert.Options{Ecosystem: convert.ECOSYSTEM_NPM})
+	if err != nil || pattern != expected {
+		t.Errorf("Pattern = %q, %v, expected %q", pattern, err, expected)
+	}
+}
@@ -0,0 +1,143 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the pom.xml reader, with property resolution, and the
+// translation of Maven versions to ranges.
+package manifest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// mavenProperty matches a ${name} property reference
+var mavenProperty = regexp.MustCompile(`\$\{([^}]+)\}`)
+
+// pomProject is the part of a pom.xml file holding dependencies
+type pomProject struct {
+	GroupID string `xml:"groupId"`
+	Version string `xml:"version"`
+	Parent  struct {
+		GroupID string `xml:"groupId"`
+		Version string `xml:"version"`
+	} `xml:"parent"`
+	Properties struct {
+		Entries []struct {
+			XMLName xml.Name
+			Value   string `xml:",chardata"`
+		} `xml:",any"`
+	} `xml:"properties"`
+	Dependencies         []pomDependency `xml:"dependencies>dependency"`
+	DependencyManagement []pomDependency `xml:"dependencyManagement>dependencies>dependency"`
+}
+
+// pomDependency is one dependency element of a pom.xml file
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+}
+
+// ParsePOM reads the dependencies of a Maven pom.xml file.
+//
+// Dependencies and managed dependencies are read, named groupId:artifactId, with
+// their scope. ${...} references to the properties of the file and to the project
+// or parent version and groupId are resolved. Dependencies without a version, whose
+// version is managed by a parent or an imported BOM, are skipped.
+//
+// Parameters:
+//   - data: The content of the pom.xml file
+//
+// Returns:
+//   - []Dependency: The Maven dependencies, in the order of the file
+//   - error: Error if the file is not valid XML or a property is undefined
+func ParsePOM(data []byte) ([]Dependency, error) {
+	var project pomProject
+	if err := xml.Unmarshal(data, &project); err != nil {
+		return nil, fmt.Errorf("failed to read pom.xml: %w", err)
+	}
+
+	properties := map[string]string{
+		"project.version":        firstNonEmpty(project.Version, project.Parent.Version),
+		"project.groupId":        firstNonEmpty(project.GroupID, project.Parent.GroupID),
+		"project.parent.version": project.Parent.Version,
+		"project.parent.groupId": project.Parent.GroupID,
+	}
+	for _, entry := range project.Properties.Entries {
+		properties[entry.XMLName.Local] = strings.TrimSpace(entry.Value)
+	}
+
+	var dependencies []Dependency
+	for _, dependency := range append(project.Dependencies, project.DependencyManagement...) {
+		if strings.TrimSpace(dependency.Version) == "" {
+			continue
+		}
+		name, err := resolveMavenProperties(dependency.GroupID+":"+dependency.ArtifactID, properties)
+		if err != nil {
+			return nil, err
+		}
+		version, err := resolveMavenProperties(dependency.Version, properties)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		dependencies = append(dependencies, Dependency{
+			Package:    strings.TrimSpace(name),
+			Constraint: strings.TrimSpace(version),
+			Manager:    MANAGER_MAVEN,
+			Scope:      strings.TrimSpace(dependency.Scope),
+		})
+	}
+	return dependencies, nil
+}
+
+// resolveMavenProperties replaces the ${name} references of value, following
+// properties that refer to other properties.
+func resolveMavenProperties(value string, properties map[string]string) (string, error) {
+	for range len(properties) + 1 {
+		match := mavenProperty.FindStringSubmatch(value)
+		if match == nil {
+			return value, nil
+		}
+		replacement, ok := properties[match[1]]
+		if !ok || replacement == "" {
+			return "", fmt.Errorf("undefined property: %s", match[1])
+		}
+		value = strings.ReplaceAll(value, match[0], replacement)
+	}
+	return "", fmt.Errorf("recursive property: %s", value)
+}
+
+// firstNonEmpty returns the first of values that is not empty.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// mavenRequirement translates a Maven dependency version to a Maven range.
+//
+// Ranges are kept as they are. A plain version is a soft requirement that Maven
+// resolves to that very version unless a conflict forces another one, so it
+// becomes the exact range [version].
+//
+// Example:
+//
+//	mavenRequirement("1.2.3")      // "[1.2.3]"
+//	mavenRequirement("[1.0,2.0)")  // "[1.0,2.0)"
+func mavenRequirement(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	switch {
+	case constraint == "":
+		return "", fmt.Errorf("empty version")
+	case strings.HasPrefix(constraint, "[") || strings.HasPrefix(constraint, "("):
+		return constraint, nil
+	case strings.ContainsAny(constraint, "[](),"):
+		return "", fmt.Errorf("invalid version: %s", constraint)
+	default:
+		return "[" + constraint + "]", nil
+	}
+}
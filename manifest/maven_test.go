@@ -0,0 +1,116 @@
+// Package manifest provides tests for Maven manifest handling.
+// This file checks the pom.xml reader and the translation of Maven versions.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParsePOM tests dependencies, managed dependencies and property resolution.
+func TestParsePOM(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <parent>
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0.0</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <properties>
+    <jackson.version>2.15.2</jackson.version>
+    <jackson.range>[${jackson.version},3.0)</jackson.range>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.slf4j</groupId>
+        <artifactId>slf4j-api</artifactId>
+        <version>2.0.9</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId>
+      <artifactId>jackson-databind</artifactId>
+      <version>${jackson.range}</version>
+    </dependency>
+    <dependency>
+      <groupId>${project.groupId}</groupId>
+      <artifactId>core</artifactId>
+      <version>${project.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+    </dependency>
+  </dependencies>
+</project>
+`)
+	expected := []Dependency{
+		{Package: "com.fasterxml.jackson.core:jackson-databind", Constraint: "[2.15.2,3.0)", Manager: MANAGER_MAVEN},
+		{Package: "com.example:core", Constraint: "2.0.0", Manager: MANAGER_MAVEN},
+		{Package: "junit:junit", Constraint: "4.13.2", Manager: MANAGER_MAVEN, Scope: "test"},
+		{Package: "org.slf4j:slf4j-api", Constraint: "2.0.9", Manager: MANAGER_MAVEN},
+	}
+
+	dependencies, err := ParsePOM(data)
+	if err != nil {
+		t.Fatalf("ParsePOM returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParsePOM = %+v, expected %+v", dependencies, expected)
+	}
+
+	invalid := []string{
+		`<project><dependencies>`,
+		`<project><dependencies><dependency><groupId>a</groupId><artifactId>b</artifactId><version>${missing}</version></dependency></dependencies></project>`,
+		`<project><properties><a>${b}</a><b>${a}</b></properties><dependencies><dependency><groupId>a</groupId><artifactId>b</artifactId><version>${a}</version></dependency></dependencies></project>`,
+	}
+	for _, pom := range invalid {
+		if _, err := ParsePOM([]byte(pom)); err == nil {
+			t.Errorf("ParsePOM(%s) expected error but got none", pom)
+		}
+	}
+}
+
+// TestMavenRequirement tests the translation of Maven versions to ranges.
+func TestMavenRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"1.2.3", false, "[1.2.3]"},
+		{"2.0.0-SNAPSHOT", false, "[2.0.0-SNAPSHOT]"},
+		{"[1.0,2.0)", false, "[1.0,2.0)"},
+		{"(,1.0],[1.2,)", false, "(,1.0],[1.2,)"},
+		{"", true, ""},
+		{"1.0,2.0", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			requirement, err := mavenRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("mavenRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mavenRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if requirement != tt.expected {
+				t.Errorf("mavenRequirement(%q) = %q, expected %q", tt.input, requirement, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the package.json reader and the check that npm dependencies are
+// version ranges.
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NPM_DEPENDENCY_SECTIONS lists the package.json sections holding dependencies, in
+// the order they are read.
+var NPM_DEPENDENCY_SECTIONS = []string{"dependencies", "devDependencies", "peerDependencies", "optionalDependencies"}
+
+// npmDistTag matches the dist-tags, such as latest or next, that dependencies may
+// name instead of a range: names starting with a letter, but not v1-style versions
+var npmDistTag = regexp.MustCompile(`^(?:[A-Za-uw-z]|v(?:$|[-A-Za-z._]))[-A-Za-z0-9._]*$`)
+
+// npmSpecifiers lists the prefixes of dependencies that are not version ranges
+var npmSpecifiers = []string{"file:", "link:", "git:", "git+", "github:", "gitlab:", "bitbucket:", "npm:", "workspace:", "http:", "https:"}
+
+// ParsePackageJSON reads the dependencies of a package.json manifest.
+//
+// The dependencies, devDependencies, peerDependencies and optionalDependencies
+// sections are read in that order, each sorted by package name, with the section
+// name as scope.
+//
+// Parameters:
+//   - data: The content of the package.json file
+//
+// Returns:
+//   - []Dependency: The npm dependencies
+//   - error: Error if the file is not valid JSON or a section is not an object of strings
+func ParsePackageJSON(data []byte) ([]Dependency, error) {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("failed to read package.json: %w", err)
+	}
+
+	var dependencies []Dependency
+	for _, section := range NPM_DEPENDENCY_SECTIONS {
+		raw, ok := sections[section]
+		if !ok {
+			continue
+		}
+		var ranges map[string]string
+		if err := json.Unmarshal(raw, &ranges); err != nil {
+			return nil, fmt.Errorf("failed to read package.json %s: %w", section, err)
+		}
+
+		names := make([]string, 0, len(ranges))
+		for name := range ranges {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dependencies = append(dependencies, Dependency{
+				Package:    name,
+				Constraint: ranges[name],
+				Manager:    MANAGER_NPM,
+				Scope:      section,
+			})
+		}
+	}
+	return dependencies, nil
+}
+
+// npmRequirement checks that an npm dependency is a version range, which the
+// npm ecosystem of the convert package reads with the rules of node-semver.
+//
+// Dependencies can also be installed from paths, URLs, git repositories, aliases
+// or dist-tags, which name no version.
+//
+// Example:
+//
+//	npmRequirement(" ~1.2 || >= 2.1.x ") // "~1.2 || >= 2.1.x"
+//	npmRequirement("github:user/repo")  // error
+func npmRequirement(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	for _, specifier := range npmSpecifiers {
+		if strings.HasPrefix(constraint, specifier) {
+			return "", fmt.Errorf("not a version range: %s", constraint)
+		}
+	}
+	if strings.Contains(constraint, "/") {
+		// GitHub shorthand such as user/repo#v1.2.3
+		return "", fmt.Errorf("not a version range: %s", constraint)
+	}
+	if npmDistTag.MatchString(constraint) && constraint != "x" && constraint != "X" {
+		return "", fmt.Errorf("not a version range: dist-tag %s", constraint)
+	}
+	return constraint, nil
+}
+
+// ParsePackageLock reads the locked versions of a package-lock.json or
+// npm-shrinkwrap.json file.
+//
+// Only packages installed at the top of node_modules are read, since nested copies
+// are locked for other dependents: the "packages" map of lockfile versions 2 and 3,
+// or the "dependencies" map of version 1.
+//
+// Parameters:
+//   - data: The content of the lockfile
+//
+// Returns:
+//   - []LockedVersion: The version of each top-level package, sorted by name
+//   - error: Error if the file is not valid JSON
+func ParsePackageLock(data []byte) ([]LockedVersion, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to read package-lock.json: %w", err)
+	}
+
+	versions := make(map[string]string)
+	if lock.Packages != nil {
+		for path, lockedPackage := range lock.Packages {
+			name, ok := strings.CutPrefix(path, "node_modules/")
+			if !ok || strings.Contains(name, "/node_modules/") || lockedPackage.Version == "" {
+				// The root package, workspaces, nested copies and links
+				continue
+			}
+			versions[name] = lockedPackage.Version
+		}
+	} else {
+		for name, lockedPackage := range lock.Dependencies {
+			versions[name] = lockedPackage.Version
+		}
+	}
+
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	locked := make([]LockedVersion, 0, len(names))
+	for _, name := range names {
+		locked = append(locked, LockedVersion{Package: name, Version: versions[name], Manager: MANAGER_NPM})
+	}
+	return locked, nil
+}
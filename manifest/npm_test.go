@@ -0,0 +1,174 @@
+// Package manifest provides tests for npm manifest handling.
+// This file checks the package.json reader and the translation of npm ranges.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestParsePackageJSON tests that every dependency section is read, sorted by name.
+func TestParsePackageJSON(t *testing.T) {
+	data := []byte(`{
+		"name": "app",
+		"version": "1.0.0",
+		"dependencies": {"react": "^18.2.0", "lodash": "~4.17"},
+		"devDependencies": {"jest": ">= 29 < 30"},
+		"peerDependencies": {"react-dom": "18.x"}
+	}`)
+	expected := []Dependency{
+		{Package: "lodash", Constraint: "~4.17", Manager: MANAGER_NPM, Scope: "dependencies"},
+		{Package: "react", Constraint: "^18.2.0", Manager: MANAGER_NPM, Scope: "dependencies"},
+		{Package: "jest", Constraint: ">= 29 < 30", Manager: MANAGER_NPM, Scope: "devDependencies"},
+		{Package: "react-dom", Constraint: "18.x", Manager: MANAGER_NPM, Scope: "peerDependencies"},
+	}
+
+	dependencies, err := ParsePackageJSON(data)
+	if err != nil {
+		t.Fatalf("ParsePackageJSON returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParsePackageJSON = %+v, expected %+v", dependencies, expected)
+	}
+
+	for _, invalid := range []string{`{"dependencies": `, `{"dependencies": ["react"]}`, `{"dependencies": {"react": 18}}`} {
+		if _, err := ParsePackageJSON([]byte(invalid)); err == nil {
+			t.Errorf("ParsePackageJSON(%s) expected error but got none", invalid)
+		}
+	}
+}
+
+// TestNpmRequirement tests the check that npm dependencies are version ranges.
+func TestNpmRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"^1.2.3", false, "^1.2.3"},
+		{" ~1.2.x ", false, "~1.2.x"},
+		{"v1", false, "v1"},
+		{"", false, ""},
+		{"x", false, "x"},
+		{">= 1.2.0 < 2", false, ">= 1.2.0 < 2"},
+		{"1.2 - 2.3.4", false, "1.2 - 2.3.4"},
+		{"<0.3.1 || >= 1.0.0 < 1.0.4", false, "<0.3.1 || >= 1.0.0 < 1.0.4"},
+		{"latest", true, ""},
+		{"beta", true, ""},
+		{"file:../lib", true, ""},
+		{"git+https://github.com/user/repo.git", true, ""},
+		{"user/repo#v1.2.3", true, ""},
+		{"npm:other@^1.0.0", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			requirement, err := npmRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("npmRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("npmRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if requirement != tt.expected {
+				t.Errorf("npmRequirement(%q) = %q, expected %q", tt.input, requirement, tt.expected)
+			}
+		})
+	}
+}
+
+// TestNpmRegex tests that npm ranges match the versions npm allows.
+func TestNpmRegex(t *testing.T) {
+	tests := []struct {
+		constraint     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0", "1.1.9"}},
+		{"1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0", "1.20.0"}},
+		{">1.2", []string{"1.3.0", "2.0.0"}, []string{"1.2.5", "1.2.0"}},
+		{"<=1.2", []string{"1.2.9", "0.1.0"}, []string{"1.3.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"1.2 - 2", []string{"1.2.0", "2.9.9"}, []string{"1.1.9", "3.0.0"}},
+		{">= 1.0.0 < 1.0.4 || 2.x", []string{"1.0.3", "2.5.0"}, []string{"1.0.4", "3.0.0"}},
+		{"^1.2.3-beta.1", []string{"1.2.3-beta.2", "1.3.0"}, []string{"1.3.0-beta.1", "2.0.0"}},
+		{">=1.2.3 <2", []string{"1.5.0"}, []string{"1.5.0.1", "2.0.0-rc.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			dependency := Dependency{Package: "pkg", Constraint: tt.constraint, Manager: MANAGER_NPM}
+			regex, err := dependency.Regex(convert.Options{})
+			if err != nil {
+				t.Fatalf("Regex returned unexpected error: %v", err)
+			}
+			for _, version := range tt.shouldMatch {
+				if !regex.MatchString(version) {
+					t.Errorf("%q should match %q (pattern %s)", tt.constraint, version, regex)
+				}
+			}
+			for _, version := range tt.shouldNotMatch {
+				if regex.MatchString(version) {
+					t.Errorf("%q should not match %q (pattern %s)", tt.constraint, version, regex)
+				}
+			}
+		})
+	}
+}
+
+// TestParsePackageLock tests top-level packages of each lockfile version.
+func TestParsePackageLock(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []LockedVersion
+	}{
+		{
+			name: "lockfile version 3",
+			data: `{"lockfileVersion": 3, "packages": {
+				"": {"name": "app", "version": "1.0.0"},
+				"node_modules/react": {"version": "18.2.0"},
+				"node_modules/@babel/core": {"version": "7.24.0"},
+				"node_modules/@babel/core/node_modules/semver": {"version": "6.3.1"},
+				"node_modules/local": {"resolved": "../local", "link": true},
+				"packages/workspace": {"version": "0.1.0"}
+			}}`,
+			expected: []LockedVersion{
+				{Package: "@babel/core", Version: "7.24.0", Manager: MANAGER_NPM},
+				{Package: "react", Version: "18.2.0", Manager: MANAGER_NPM},
+			},
+		},
+		{
+			name: "lockfile version 1",
+			data: `{"lockfileVersion": 1, "dependencies": {
+				"lodash": {"version": "4.17.21"},
+				"debug": {"version": "4.3.4", "dependencies": {"ms": {"version": "2.1.2"}}}
+			}}`,
+			expected: []LockedVersion{
+				{Package: "debug", Version: "4.3.4", Manager: MANAGER_NPM},
+				{Package: "lodash", Version: "4.17.21", Manager: MANAGER_NPM},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			locked, err := ParsePackageLock([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParsePackageLock returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(locked, tt.expected) {
+				t.Errorf("ParsePackageLock = %+v, expected %+v", locked, tt.expected)
+			}
+		})
+	}
+
+	if _, err := ParsePackageLock([]byte(`{"packages": `)); err == nil {
+		t.Errorf("ParsePackageLock expected error for invalid JSON but got none")
+	}
+}
@@ -0,0 +1,148 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the reader of NuGet package references in MSBuild projects and
+// the translation of NuGet version ranges.
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// msbuildItem is a PackageReference or PackageVersion item of an MSBuild project
+type msbuildItem struct {
+	Include         string `xml:"Include,attr"`
+	Update          string `xml:"Update,attr"`
+	Version         string `xml:"Version,attr"`
+	VersionChild    string `xml:"Version"`
+	VersionOverride string `xml:"VersionOverride,attr"`
+}
+
+// ParseMSBuild reads the NuGet package references of an MSBuild project, such as a
+// .csproj file or a Directory.Packages.props file for central package management.
+//
+// PackageReference and PackageVersion items are read with their Include or Update
+// package id and their Version, given as an attribute or a child element;
+// VersionOverride takes precedence over Version. References without a version,
+// whose version is managed centrally, are skipped. PackageVersion items have the
+// central scope.
+//
+// Parameters:
+//   - data: The content of the project file
+//
+// Returns:
+//   - []Dependency: The NuGet package references, in the order of the file
+//   - error: Error if the file is not valid XML
+func ParseMSBuild(data []byte) ([]Dependency, error) {
+	var dependencies []Dependency
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return dependencies, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read project: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || (start.Name.Local != "PackageReference" && start.Name.Local != "PackageVersion") {
+			continue
+		}
+
+		var item msbuildItem
+		if err := decoder.DecodeElement(&item, &start); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", start.Name.Local, err)
+		}
+		version := strings.TrimSpace(firstNonEmpty(item.VersionOverride, item.Version, item.VersionChild))
+		if version == "" {
+			continue
+		}
+		dependency := Dependency{
+			Package:    strings.TrimSpace(firstNonEmpty(item.Include, item.Update)),
+			Constraint: version,
+			Manager:    MANAGER_NUGET,
+		}
+		if start.Name.Local == "PackageVersion" {
+			dependency.Scope = "central"
+		}
+		dependencies = append(dependencies, dependency)
+	}
+}
+
+// nugetRequirement translates a NuGet version to a constraint of the NuGet
+// ecosystem, which shares the syntax of the default one.
+//
+// Interval notation shares the Maven range syntax, and floating versions such as
+// 1.2.* are wildcards. A plain version is the minimum version NuGet accepts, so it
+// becomes a >= comparison.
+//
+// Example:
+//
+//	nugetRequirement("13.0.1")     // ">=13.0.1"
+//	nugetRequirement("[1.0,2.0)")  // "[1.0,2.0)"
+func nugetRequirement(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	switch {
+	case constraint == "":
+		return "", fmt.Errorf("empty version")
+	case strings.HasPrefix(constraint, "[") || strings.HasPrefix(constraint, "("):
+		return constraint, nil
+	case strings.Contains(constraint, "*"):
+		return constraint, nil
+	case strings.ContainsAny(constraint, "[](),$"):
+		return "", fmt.Errorf("invalid version: %s", constraint)
+	default:
+		return ">=" + constraint, nil
+	}
+}
+
+// ParseNuGetLock reads the resolved versions of a NuGet packages.lock.json file, for
+// every target framework. Each package version appears once, and project references,
+// which have no resolved version, are skipped.
+//
+// Parameters:
+//   - data: The content of the packages.lock.json file
+//
+// Returns:
+//   - []LockedVersion: The resolved package versions, sorted by framework and package id
+//   - error: Error if the file is not valid JSON
+func ParseNuGetLock(data []byte) ([]LockedVersion, error) {
+	var lock struct {
+		Dependencies map[string]map[string]struct {
+			Resolved string `json:"resolved"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("failed to read packages.lock.json: %w", err)
+	}
+
+	var locked []LockedVersion
+	seen := make(map[string]bool)
+	frameworks := make([]string, 0, len(lock.Dependencies))
+	for framework := range lock.Dependencies {
+		frameworks = append(frameworks, framework)
+	}
+	sort.Strings(frameworks)
+	for _, framework := range frameworks {
+		packages := lock.Dependencies[framework]
+		ids := make([]string, 0, len(packages))
+		for id := range packages {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			version := packages[id].Resolved
+			if version == "" || seen[id+"@"+version] {
+				continue
+			}
+			seen[id+"@"+version] = true
+			locked = append(locked, LockedVersion{Package: id, Version: version, Manager: MANAGER_NUGET})
+		}
+	}
+	return locked, nil
+}
@@ -0,0 +1,116 @@
+// Package manifest provides tests for NuGet manifest handling.
+// This file checks the reader of MSBuild package references and the translation of
+// NuGet versions.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseMSBuild tests PackageReference and PackageVersion items.
+func TestParseMSBuild(t *testing.T) {
+	data := []byte(`<Project Sdk="Microsoft.NET.Sdk">
+  <PropertyGroup>
+    <TargetFramework>net8.0</TargetFramework>
+  </PropertyGroup>
+  <ItemGroup>
+    <PackageReference Include="Newtonsoft.Json" Version="13.0.3" />
+    <PackageReference Include="Serilog">
+      <Version>[3.0,4.0)</Version>
+    </PackageReference>
+    <PackageReference Include="Polly" VersionOverride="8.*" Version="7.2.4" />
+    <PackageReference Include="Central.Managed" />
+    <PackageReference Update="xunit" Version="2.6.1" />
+    <PackageVersion Include="Dapper" Version="2.1.24" />
+  </ItemGroup>
+</Project>
+`)
+	expected := []Dependency{
+		{Package: "Newtonsoft.Json", Constraint: "13.0.3", Manager: MANAGER_NUGET},
+		{Package: "Serilog", Constraint: "[3.0,4.0)", Manager: MANAGER_NUGET},
+		{Package: "Polly", Constraint: "8.*", Manager: MANAGER_NUGET},
+		{Package: "xunit", Constraint: "2.6.1", Manager: MANAGER_NUGET},
+		{Package: "Dapper", Constraint: "2.1.24", Manager: MANAGER_NUGET, Scope: "central"},
+	}
+
+	dependencies, err := ParseMSBuild(data)
+	if err != nil {
+		t.Fatalf("ParseMSBuild returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParseMSBuild = %+v, expected %+v", dependencies, expected)
+	}
+
+	if _, err := ParseMSBuild([]byte(`<Project><ItemGroup><PackageReference Include="a" Version="1.0">`)); err == nil {
+		t.Errorf("ParseMSBuild expected error for truncated XML but got none")
+	}
+}
+
+// TestNugetRequirement tests the translation of NuGet versions.
+func TestNugetRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"13.0.3", false, ">=13.0.3"},
+		{"1.2.3-beta.1", false, ">=1.2.3-beta.1"},
+		{"[3.0,4.0)", false, "[3.0,4.0)"},
+		{"[1.2.3]", false, "[1.2.3]"},
+		{"8.*", false, "8.*"},
+		{"$(PollyVersion)", true, ""},
+		{"", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			requirement, err := nugetRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("nugetRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("nugetRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if requirement != tt.expected {
+				t.Errorf("nugetRequirement(%q) = %q, expected %q", tt.input, requirement, tt.expected)
+			}
+		})
+	}
+}
+
+// TestParseNuGetLock tests the resolved versions of every target framework.
+func TestParseNuGetLock(t *testing.T) {
+	data := []byte(`{
+		"version": 1,
+		"dependencies": {
+			"net8.0": {
+				"Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.3, )", "resolved": "13.0.3"},
+				"Serilog": {"type": "Direct", "requested": "[3.0.0, 4.0.0)", "resolved": "3.1.1"},
+				"Shared": {"type": "Project"}
+			},
+			"net6.0": {
+				"Newtonsoft.Json": {"type": "Direct", "requested": "[13.0.3, )", "resolved": "13.0.3"}
+			}
+		}
+	}`)
+	expected := []LockedVersion{
+		{Package: "Newtonsoft.Json", Version: "13.0.3", Manager: MANAGER_NUGET},
+		{Package: "Serilog", Version: "3.1.1", Manager: MANAGER_NUGET},
+	}
+
+	locked, err := ParseNuGetLock(data)
+	if err != nil {
+		t.Fatalf("ParseNuGetLock returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(locked, expected) {
+		t.Errorf("ParseNuGetLock = %+v, expected %+v", locked, expected)
+	}
+
+	if _, err := ParseNuGetLock([]byte(`{"dependencies": []}`)); err == nil {
+		t.Errorf("ParseNuGetLock expected error for a dependency list but got none")
+	}
+}
@@ -0,0 +1,251 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the pyproject.toml and poetry.lock readers, and the translation
+// of Poetry constraints to PEP 440 version specifiers.
+package manifest
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// poetryComparator matches one comparator of a Poetry constraint
+var poetryComparator = regexp.MustCompile(`^(\^|~=|~|==|!=|>=|<=|>|<|=)?\s*v?([0-9][0-9A-Za-z.+*-]*|\*)$`)
+
+// poetryRelease matches the release segment at the start of a version, such as 1.0 in 1.0a1
+var poetryRelease = regexp.MustCompile(`^[0-9.]*`)
+
+// ParsePyProject reads the dependencies of a pyproject.toml file.
+//
+// PEP 621 dependencies ([project] dependencies and optional-dependencies) are PEP 508
+// strings with pip semantics. Poetry dependencies ([tool.poetry.dependencies],
+// dev-dependencies and [tool.poetry.group.<name>.dependencies]) use the Poetry
+// constraint syntax; the python requirement is skipped, as are dependencies without
+// a version, such as git or path dependencies. A Poetry dependency with several
+// constraints depending on markers keeps the first one.
+//
+// Parameters:
+//   - data: The content of the pyproject.toml file
+//
+// Returns:
+//   - []Dependency: The dependencies, PEP 621 first, each Poetry table sorted by name
+//   - error: Error if the file is not valid TOML or a requirement is malformed
+func ParsePyProject(data []byte) ([]Dependency, error) {
+	tables, err := readTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pyproject.toml: %w", err)
+	}
+
+	var dependencies []Dependency
+	for _, table := range tables {
+		switch {
+		case table.Name == "project":
+			specifications, _ := table.Values["dependencies"].([]any)
+			pep621, err := pep621Dependencies(specifications, "")
+			if err != nil {
+				return nil, err
+			}
+			dependencies = append(dependencies, pep621...)
+		case table.Name == "project.optional-dependencies":
+			for _, extra := range sortedKeys(table.Values) {
+				specifications, _ := table.Values[extra].([]any)
+				pep621, err := pep621Dependencies(specifications, extra)
+				if err != nil {
+					return nil, err
+				}
+				dependencies = append(dependencies, pep621...)
+			}
+		}
+	}
+
+	for _, table := range tables {
+		scope, ok := poetryScope(table.Name)
+		if !ok {
+			continue
+		}
+		for _, name := range sortedKeys(table.Values) {
+			if name == "python" {
+				continue
+			}
+			constraint, ok := poetryVersion(table.Values[name])
+			if !ok {
+				continue
+			}
+			dependencies = append(dependencies, Dependency{
+				Package:    name,
+				Constraint: constraint,
+				Manager:    MANAGER_POETRY,
+				Scope:      scope,
+			})
+		}
+	}
+	return dependencies, nil
+}
+
+// pep621Dependencies reads a list of PEP 508 dependency specifications.
+func pep621Dependencies(specifications []any, scope string) ([]Dependency, error) {
+	var dependencies []Dependency
+	for _, specification := range specifications {
+		text, ok := specification.(string)
+		if !ok {
+			return nil, fmt.Errorf("dependency is not a string: %v", specification)
+		}
+		dependency, ok, err := parsePEP508(text)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			dependency.Scope = scope
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies, nil
+}
+
+// poetryScope tells whether a table holds Poetry dependencies, returning the scope of
+// its dependencies: empty for main dependencies, the group name otherwise.
+func poetryScope(table string) (string, bool) {
+	switch table {
+	case "tool.poetry.dependencies":
+		return "", true
+	case "tool.poetry.dev-dependencies":
+		return "dev", true
+	}
+	group, ok := strings.CutPrefix(table, "tool.poetry.group.")
+	if !ok {
+		return "", false
+	}
+	group, ok = strings.CutSuffix(group, ".dependencies")
+	return group, ok
+}
+
+// poetryVersion returns the constraint of a Poetry dependency, given as a string, an
+// inline table with a version, or an array of such tables.
+func poetryVersion(value any) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case map[string]any:
+		version, ok := value["version"].(string)
+		return version, ok
+	case []any:
+		if len(value) > 0 {
+			return poetryVersion(value[0])
+		}
+	}
+	return "", false
+}
+
+// sortedKeys returns the keys of values in alphabetical order.
+func sortedKeys(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// poetryRequirement translates a Poetry constraint to PEP 440 version specifiers.
+//
+// Poetry compares versions with PEP 440, as pip does: ^ ranges become bounds, ~
+// ranges and wildcards become "~=" and "==" prefix specifiers, and a bare version
+// is an exact specifier. Specifier lists have no alternatives, so || is not
+// supported.
+//
+// Example:
+//
+//	poetryRequirement("^1.2")            // ">=1.2, <2"
+//	poetryRequirement("~1.2.3, !=1.2.5") // "~=1.2.3, !=1.2.5"
+func poetryRequirement(constraint string) (string, error) {
+	constraint = strings.TrimSpace(constraint)
+	if strings.Contains(constraint, "|") {
+		return "", fmt.Errorf("unsupported alternatives: %s", constraint)
+	}
+	if constraint == "" || constraint == "*" {
+		return "", nil
+	}
+
+	var specifiers []string
+	for _, comparator := range strings.Split(constraint, ",") {
+		comparator = strings.TrimSpace(comparator)
+		match := poetryComparator.FindStringSubmatch(comparator)
+		if match == nil {
+			return "", fmt.Errorf("invalid Poetry constraint: %s", comparator)
+		}
+		operator, version := match[1], match[2]
+
+		if _, ok := strings.CutSuffix(version, ".*"); ok || version == "*" {
+			if version == "*" {
+				continue
+			}
+			if operator != "" && operator != "==" && operator != "=" {
+				return "", fmt.Errorf("unsupported wildcard: %s", comparator)
+			}
+			specifiers = append(specifiers, "=="+version)
+			continue
+		}
+
+		switch operator {
+		case "^":
+			specifiers = append(specifiers, ">="+version, "<"+caretUpperBound(version))
+		case "~":
+			// ~1 allows 1.x, ~1.2 and ~1.2.3 the 1.2 series
+			if strings.Count(version, ".") < 2 {
+				version += ".0"
+			}
+			specifiers = append(specifiers, "~="+version)
+		case "", "=", "==":
+			specifiers = append(specifiers, "=="+version)
+		default:
+			specifiers = append(specifiers, operator+version)
+		}
+	}
+	return strings.Join(specifiers, ", "), nil
+}
+
+// caretUpperBound returns the exclusive upper bound of a caret range: the version
+// with its first non-zero component incremented, such as 2 for 1.2.3 and 0.3 for
+// 0.2.3.
+func caretUpperBound(version string) string {
+	release := strings.TrimRight(poetryRelease.FindString(version), ".")
+	components := strings.Split(release, ".")
+	for i, component := range components {
+		value, _ := strconv.Atoi(component)
+		if value != 0 || i == len(components)-1 {
+			return strings.Join(append(components[:i:i], strconv.Itoa(value+1)), ".")
+		}
+	}
+	return "1"
+}
+
+// ParsePoetryLock reads the locked versions of a poetry.lock file.
+//
+// Parameters:
+//   - data: The content of the poetry.lock file
+//
+// Returns:
+//   - []LockedVersion: The version of each locked package
+//   - error: Error if the file is not valid TOML or a package has no name or version
+func ParsePoetryLock(data []byte) ([]LockedVersion, error) {
+	tables, err := readTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read poetry.lock: %w", err)
+	}
+
+	var locked []LockedVersion
+	for _, table := range tables {
+		if table.Name != "package" {
+			continue
+		}
+		name, _ := table.Values["name"].(string)
+		version, _ := table.Values["version"].(string)
+		if name == "" || version == "" {
+			return nil, fmt.Errorf("locked package without name or version: %v", table.Values)
+		}
+		locked = append(locked, LockedVersion{Package: name, Version: version, Manager: MANAGER_POETRY})
+	}
+	return locked, nil
+}
@@ -0,0 +1,179 @@
+// Package manifest provides tests for pyproject.toml and Poetry handling.
+// This file checks the pyproject.toml and poetry.lock readers and the translation of
+// Poetry constraints.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestParsePyProject tests PEP 621 and Poetry dependency tables.
+func TestParsePyProject(t *testing.T) {
+	data := []byte(`[project]
+name = "app"
+dependencies = [
+    "requests>=2.31",
+    "Django[bcrypt] (>=4.2, <5) ; python_version >= '3.10'",
+    "mylib @ https://example.com/mylib.tar.gz",
+]
+
+[project.optional-dependencies]
+test = ["pytest~=7.4"]
+
+[tool.poetry.dependencies]
+python = "^3.10"
+flask = "^3.0"
+httpx = { version = "~0.27", extras = ["http2"] }
+local = { path = "../local" }
+numpy = [
+    { version = "<1.25", python = "<3.9" },
+    { version = "^1.26", python = ">=3.9" },
+]
+
+[tool.poetry.group.dev.dependencies]
+black = "24.3.0"
+`)
+	expected := []Dependency{
+		{Package: "requests", Constraint: ">=2.31", Manager: MANAGER_PIP},
+		{Package: "Django", Constraint: ">=4.2, <5", Manager: MANAGER_PIP},
+		{Package: "pytest", Constraint: "~=7.4", Manager: MANAGER_PIP, Scope: "test"},
+		{Package: "flask", Constraint: "^3.0", Manager: MANAGER_POETRY},
+		{Package: "httpx", Constraint: "~0.27", Manager: MANAGER_POETRY},
+		{Package: "numpy", Constraint: "<1.25", Manager: MANAGER_POETRY},
+		{Package: "black", Constraint: "24.3.0", Manager: MANAGER_POETRY, Scope: "dev"},
+	}
+
+	dependencies, err := ParsePyProject(data)
+	if err != nil {
+		t.Fatalf("ParsePyProject returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParsePyProject = %+v, expected %+v", dependencies, expected)
+	}
+
+	for _, invalid := range []string{"[project]\ndependencies = [1]\n", "[project]\ndependencies = [\"!!!\"]\n", "[project\nname\n"} {
+		if _, err := ParsePyProject([]byte(invalid)); err == nil {
+			t.Errorf("ParsePyProject(%q) expected error but got none", invalid)
+		}
+	}
+}
+
+// TestPoetryRequirement tests the translation of Poetry constraints to PEP 440 specifiers.
+func TestPoetryRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"^1.2.3", false, ">=1.2.3, <2"},
+		{"^0.2.3", false, ">=0.2.3, <0.3"},
+		{"^0.0.3", false, ">=0.0.3, <0.0.4"},
+		{"^0", false, ">=0, <1"},
+		{"^1.0a1", false, ">=1.0a1, <2"},
+		{"~1.2", false, "~=1.2.0"},
+		{"~1", false, "~=1.0"},
+		{"~1.2.3", false, "~=1.2.3"},
+		{"~=1.2", false, "~=1.2"},
+		{"1.2.*", false, "==1.2.*"},
+		{"*", false, ""},
+		{"24.3.0", false, "==24.3.0"},
+		{"==2.0", false, "==2.0"},
+		{">=1.2, <2.0, !=1.5", false, ">=1.2, <2.0, !=1.5"},
+		{"^1.2 || ^2.0", true, ""},
+		{">=1.*", true, ""},
+		{"latest", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			requirement, err := poetryRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("poetryRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("poetryRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if requirement != tt.expected {
+				t.Errorf("poetryRequirement(%q) = %q, expected %q", tt.input, requirement, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPoetryRegex tests that Poetry constraints match the versions Poetry allows.
+func TestPoetryRegex(t *testing.T) {
+	tests := []struct {
+		constraint     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{"^1.2", []string{"1.2", "1.2.5", "1.9"}, []string{"1.1", "2.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3", "1.1"}},
+		{"24.3.0", []string{"24.3.0", "24.3"}, []string{"24.3.1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			dependency := Dependency{Package: "pkg", Constraint: tt.constraint, Manager: MANAGER_POETRY}
+			regex, err := dependency.Regex(convert.Options{})
+			if err != nil {
+				t.Fatalf("Regex returned unexpected error: %v", err)
+			}
+			for _, version := range tt.shouldMatch {
+				if !regex.MatchString(version) {
+					t.Errorf("%q should match %q (pattern %s)", tt.constraint, version, regex)
+				}
+			}
+			for _, version := range tt.shouldNotMatch {
+				if regex.MatchString(version) {
+					t.Errorf("%q should not match %q (pattern %s)", tt.constraint, version, regex)
+				}
+			}
+		})
+	}
+}
+
+// TestParsePoetryLock tests the packages of a poetry.lock file.
+func TestParsePoetryLock(t *testing.T) {
+	data := []byte(`# This file is automatically @generated by Poetry
+[[package]]
+name = "flask"
+version = "3.0.2"
+files = [
+    {file = "flask-3.0.2.tar.gz", hash = "sha256:abc"},
+]
+
+[package.dependencies]
+click = ">=8.1.3"
+
+[[package]]
+name = "black"
+version = "24.3.0"
+
+[metadata]
+lock-version = "2.0"
+`)
+	expected := []LockedVersion{
+		{Package: "flask", Version: "3.0.2", Manager: MANAGER_POETRY},
+		{Package: "black", Version: "24.3.0", Manager: MANAGER_POETRY},
+	}
+
+	locked, err := ParsePoetryLock(data)
+	if err != nil {
+		t.Fatalf("ParsePoetryLock returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(locked, expected) {
+		t.Errorf("ParsePoetryLock = %+v, expected %+v", locked, expected)
+	}
+
+	if _, err := ParsePoetryLock([]byte("[[package]]\nname = \"flask\"\n")); err == nil {
+		t.Errorf("ParsePoetryLock expected error for a package without version but got none")
+	}
+}
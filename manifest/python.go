@@ -0,0 +1,118 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains the requirements.txt reader and the normalization of PEP 440
+// version specifiers.
+package manifest
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// pipRequirementLine matches a requirement: a project name, optional extras and the
+// version specifiers, optionally in parentheses
+var pipRequirementLine = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(?:\[[^\]]*\])?\s*\(?([^()]*)\)?$`)
+
+// pipSpecifier matches one PEP 440 version specifier
+var pipSpecifier = regexp.MustCompile(`^(~=|===|==|!=|<=|>=|<|>)\s*([0-9A-Za-z]\S*)$`)
+
+// ParseRequirements reads the requirements of a pip requirements file.
+//
+// Comments, blank lines, options such as -r or --index-url, and direct references
+// such as "name @ https://..." or bare URLs are skipped. Environment markers after
+// ";" and options such as --hash after a requirement are ignored, and lines ending
+// with a backslash continue on the next line.
+//
+// Parameters:
+//   - data: The content of the requirements file
+//
+// Returns:
+//   - []Dependency: The pip requirements, in the order of the file
+//   - error: Error if a line is not a requirement
+func ParseRequirements(data []byte) ([]Dependency, error) {
+	var dependencies []Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	logical := ""
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if strings.HasSuffix(text, `\`) {
+			logical += strings.TrimSpace(strings.TrimSuffix(text, `\`)) + " "
+			continue
+		}
+		if logical != "" {
+			text, logical = logical+strings.TrimSpace(text), ""
+		}
+
+		if index := strings.Index(text, " #"); index >= 0 {
+			text = text[:index]
+		}
+		if index := strings.Index(text, " --"); index >= 0 {
+			// Per-requirement options such as --hash
+			text = text[:index]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" || strings.HasPrefix(text, "#") || strings.HasPrefix(text, "-") || strings.Contains(text, "://") {
+			continue
+		}
+
+		dependency, ok, err := parsePEP508(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if ok {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read requirements: %w", err)
+	}
+	return dependencies, nil
+}
+
+// parsePEP508 reads a PEP 508 dependency specification such as
+// "requests[security] >=2.8, <3 ; python_version >= '3.8'". The environment marker is
+// ignored, and direct references such as "name @ https://..." are skipped, which is
+// reported by a false ok.
+func parsePEP508(text string) (Dependency, bool, error) {
+	text, _, _ = strings.Cut(text, ";")
+	text = strings.TrimSpace(text)
+	if strings.Contains(text, "@") {
+		return Dependency{}, false, nil
+	}
+
+	match := pipRequirementLine.FindStringSubmatch(text)
+	if match == nil {
+		return Dependency{}, false, fmt.Errorf("not a requirement: %s", text)
+	}
+	return Dependency{
+		Package:    match[1],
+		Constraint: strings.TrimSpace(match[2]),
+		Manager:    MANAGER_PIP,
+	}, true, nil
+}
+
+// pipRequirement checks PEP 440 version specifiers and writes them without spaces,
+// for the PyPI ecosystem of the convert package. Prefix matches, local labels and
+// the other constructs that ecosystem cannot express are rejected on conversion.
+//
+// Example:
+//
+//	pipRequirement(">= 1.2, ==1.*, != 1.5") // ">=1.2, ==1.*, !=1.5"
+func pipRequirement(constraint string) (string, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return "", nil
+	}
+
+	var specifiers []string
+	for _, specifier := range strings.Split(constraint, ",") {
+		specifier = strings.TrimSpace(specifier)
+		match := pipSpecifier.FindStringSubmatch(specifier)
+		if match == nil {
+			return "", fmt.Errorf("invalid version specifier: %s", specifier)
+		}
+		specifiers = append(specifiers, match[1]+match[2])
+	}
+	return strings.Join(specifiers, ", "), nil
+}
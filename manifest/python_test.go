@@ -0,0 +1,124 @@
+// Package manifest provides tests for pip manifest handling.
+// This file checks the requirements.txt reader and the translation of PEP 440
+// version specifiers.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestParseRequirements tests the lines of a requirements file.
+func TestParseRequirements(t *testing.T) {
+	data := []byte(`# Web
+-r base.txt
+--index-url https://pypi.org/simple
+Django>=4.2,<5.0  # LTS
+requests[security] == 2.31.0 ; python_version >= "3.8"
+numpy
+urllib3 (>=1.26, !=1.26.0)
+cryptography==41.0.3 \
+    --hash=sha256:0123456789abcdef
+mylib @ https://example.com/mylib-1.0.tar.gz
+git+https://github.com/user/repo.git#egg=repo
+pytest~=7.4 \
+    ,!=7.4.1
+`)
+	expected := []Dependency{
+		{Package: "Django", Constraint: ">=4.2,<5.0", Manager: MANAGER_PIP},
+		{Package: "requests", Constraint: "== 2.31.0", Manager: MANAGER_PIP},
+		{Package: "numpy", Constraint: "", Manager: MANAGER_PIP},
+		{Package: "urllib3", Constraint: ">=1.26, !=1.26.0", Manager: MANAGER_PIP},
+		{Package: "cryptography", Constraint: "==41.0.3", Manager: MANAGER_PIP},
+		{Package: "pytest", Constraint: "~=7.4 ,!=7.4.1", Manager: MANAGER_PIP},
+	}
+
+	dependencies, err := ParseRequirements(data)
+	if err != nil {
+		t.Fatalf("ParseRequirements returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(dependencies, expected) {
+		t.Errorf("ParseRequirements = %+v, expected %+v", dependencies, expected)
+	}
+
+	if _, err := ParseRequirements([]byte("Django>=4.2\n!!!\n")); err == nil {
+		t.Errorf("ParseRequirements expected error for an invalid line but got none")
+	}
+}
+
+// TestPipRequirement tests the normalization of PEP 440 specifiers.
+func TestPipRequirement(t *testing.T) {
+	tests := []struct {
+		input    string
+		wantErr  bool
+		expected string
+	}{
+		{"", false, ""},
+		{">=4.2,<5.0", false, ">=4.2, <5.0"},
+		{"== 2.31.0", false, "==2.31.0"},
+		{"~=7.4, !=7.4.1", false, "~=7.4, !=7.4.1"},
+		{"==1.2.*", false, "==1.2.*"},
+		{"!=1.2.*", false, "!=1.2.*"},
+		{">=", true, ""},
+		{"1.0", true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			requirement, err := pipRequirement(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("pipRequirement(%q) expected error but got none", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("pipRequirement(%q) returned unexpected error: %v", tt.input, err)
+			}
+			if requirement != tt.expected {
+				t.Errorf("pipRequirement(%q) = %q, expected %q", tt.input, requirement, tt.expected)
+			}
+		})
+	}
+}
+
+// TestPipRegex tests that PEP 440 specifiers match the versions pip allows.
+func TestPipRegex(t *testing.T) {
+	tests := []struct {
+		constraint     string
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{">=4.2,<5.0", []string{"4.2", "4.2.7", "4.10"}, []string{"4.1.9", "5.0", "5.0.1"}},
+		{"~=7.4, !=7.4.1", []string{"7.4", "7.4.2", "7.9"}, []string{"7.4.1", "8.0", "7.3"}},
+		{"==1.2.*", []string{"1.2", "1.2.9"}, []string{"1.3", "1.1"}},
+		{"==2.31.0", []string{"2.31.0", "2.31"}, []string{"2.31.1"}},
+		{"!=1.2.*", []string{"1.1", "1.3"}, []string{"1.2", "1.2.5", "1.2rc1"}},
+		{">=1.0", []string{"1.0.post1", "1.0+local", "1!0.5"}, []string{"1.0rc1", "0.9"}},
+		{"~=1.4.2", []string{"1.4.2.post1", "1.4.9"}, []string{"1.5", "1.4.2rc1"}},
+		{"<2.0", []string{"1.9.post1"}, []string{"2.0rc1", "2.0.dev1", "2.0"}},
+		{"", []string{"0.1", "10.2.3"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			dependency := Dependency{Package: "pkg", Constraint: tt.constraint, Manager: MANAGER_PIP}
+			regex, err := dependency.Regex(convert.Options{})
+			if err != nil {
+				t.Fatalf("Regex returned unexpected error: %v", err)
+			}
+			for _, version := range tt.shouldMatch {
+				if !regex.MatchString(version) {
+					t.Errorf("%q should match %q (pattern %s)", tt.constraint, version, regex)
+				}
+			}
+			for _, version := range tt.shouldNotMatch {
+				if regex.MatchString(version) {
+					t.Errorf("%q should not match %q (pattern %s)", tt.constraint, version, regex)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+// Package manifest provides the dependencies declared in package manager manifests.
+// This file contains a reader for the subset of TOML used by pyproject.toml and
+// poetry.lock files: tables, arrays of tables, and key/value pairs whose values are
+// strings, arrays, inline tables or bare literals such as numbers and booleans.
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// errTOMLIncomplete reports a value that continues on the next line
+var errTOMLIncomplete = errors.New("incomplete TOML value")
+
+// tomlLiteral is a bare value, such as a number, a boolean or a date
+type tomlLiteral string
+
+// tomlTable is a table of a TOML document, with its dotted name. Each [[name]] header
+// of an array of tables starts a new table with the same name.
+type tomlTable struct {
+	Name   string
+	Values map[string]any
+}
+
+// readTOML reads the tables of a TOML document, starting with the unnamed root
+// table. Values are strings, []any or map[string]any; numbers, booleans and dates
+// are kept as the tomlLiteral of their text.
+func readTOML(data []byte) ([]tomlTable, error) {
+	tables := []tomlTable{{Values: map[string]any{}}}
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			header, _, _ := strings.Cut(line, "#")
+			name := strings.Trim(strings.TrimSpace(header), "[]")
+			tables = append(tables, tomlTable{Name: tomlKey(name), Values: map[string]any{}})
+			continue
+		}
+
+		key, text, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value: %s", i+1, line)
+		}
+		start := i
+		value, err := parseTOMLValue(text)
+		for errors.Is(err, errTOMLIncomplete) && i+1 < len(lines) {
+			// Arrays, inline tables and multi-line strings continue on the next lines
+			i++
+			text += "\n" + lines[i]
+			value, err = parseTOMLValue(text)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start+1, err)
+		}
+		tables[len(tables)-1].Values[tomlKey(key)] = value
+	}
+	return tables, nil
+}
+
+// tomlKey returns a dotted key or table name without spaces and quotes, such as
+// tool.poetry.dependencies for tool . "poetry".dependencies.
+func tomlKey(key string) string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return strings.Join(parts, ".")
+}
+
+// parseTOMLValue parses the value of a key/value pair, followed only by spaces and a
+// comment. It returns errTOMLIncomplete when the value is not terminated.
+func parseTOMLValue(text string) (any, error) {
+	parser := tomlParser{text: text}
+	value, err := parser.value()
+	if err != nil {
+		return nil, err
+	}
+	parser.skip(false)
+	if parser.pos < len(parser.text) {
+		return nil, fmt.Errorf("unexpected text after value: %s", strings.TrimSpace(parser.text[parser.pos:]))
+	}
+	return value, nil
+}
+
+// tomlParser reads TOML values from text.
+type tomlParser struct {
+	text string
+	pos  int
+}
+
+// skip moves past spaces and comments, and past newlines when multiline is true.
+func (p *tomlParser) skip(multiline bool) {
+	for p.pos < len(p.text) {
+		switch c := p.text[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '\n' && multiline:
+			p.pos++
+		case c == '#':
+			end := strings.IndexByte(p.text[p.pos:], '\n')
+			if end < 0 {
+				p.pos = len(p.text)
+			} else {
+				p.pos += end
+			}
+		default:
+			return
+		}
+	}
+}
+
+// value reads a string, array, inline table or bare literal.
+func (p *tomlParser) value() (any, error) {
+	p.skip(false)
+	if p.pos == len(p.text) {
+		return nil, errTOMLIncomplete
+	}
+	switch p.text[p.pos] {
+	case '"', '\'':
+		return p.string()
+	case '[':
+		return p.array()
+	case '{':
+		return p.inlineTable()
+	default:
+		start := p.pos
+		for p.pos < len(p.text) && !strings.ContainsRune(",]}# \t\r\n", rune(p.text[p.pos])) {
+			p.pos++
+		}
+		if p.pos == start {
+			return nil, fmt.Errorf("expected a value at %q", p.text[p.pos:])
+		}
+		return tomlLiteral(p.text[start:p.pos]), nil
+	}
+}
+
+// string reads a basic or literal string, on one line or between triple quotes.
+func (p *tomlParser) string() (string, error) {
+	quote := p.text[p.pos : p.pos+1]
+	if strings.HasPrefix(p.text[p.pos:], quote+quote+quote) {
+		delimiter := quote + quote + quote
+		end := strings.Index(p.text[p.pos+3:], delimiter)
+		if end < 0 {
+			return "", errTOMLIncomplete
+		}
+		value := p.text[p.pos+3 : p.pos+3+end]
+		p.pos += 3 + end + 3
+		return strings.TrimPrefix(value, "\n"), nil
+	}
+
+	start := p.pos
+	for p.pos++; p.pos < len(p.text); p.pos++ {
+		switch c := p.text[p.pos]; {
+		case c == '\\' && quote == `"`:
+			p.pos++
+		case c == '\n':
+			return "", fmt.Errorf("unterminated string: %s", p.text[start:p.pos])
+		case string(c) == quote:
+			p.pos++
+			raw := p.text[start:p.pos]
+			if quote == "'" {
+				return raw[1 : len(raw)-1], nil
+			}
+			value, err := strconv.Unquote(raw)
+			if err != nil {
+				return raw[1 : len(raw)-1], nil
+			}
+			return value, nil
+		}
+	}
+	return "", errTOMLIncomplete
+}
+
+// array reads the values of an array, which may span several lines.
+func (p *tomlParser) array() ([]any, error) {
+	values := []any{}
+	p.pos++
+	for {
+		p.skip(true)
+		if p.pos == len(p.text) {
+			return nil, errTOMLIncomplete
+		}
+		if p.text[p.pos] == ']' {
+			p.pos++
+			return values, nil
+		}
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+
+		p.skip(true)
+		if p.pos == len(p.text) {
+			return nil, errTOMLIncomplete
+		}
+		switch p.text[p.pos] {
+		case ',':
+			p.pos++
+		case ']':
+		default:
+			return nil, fmt.Errorf("expected , or ] in array at %q", p.text[p.pos:])
+		}
+	}
+}
+
+// inlineTable reads the key/value pairs of an inline table.
+func (p *tomlParser) inlineTable() (map[string]any, error) {
+	values := map[string]any{}
+	p.pos++
+	for {
+		p.skip(false)
+		if p.pos == len(p.text) {
+			return nil, errTOMLIncomplete
+		}
+		if p.text[p.pos] == '}' {
+			p.pos++
+			return values, nil
+		}
+		end := strings.IndexByte(p.text[p.pos:], '=')
+		if end < 0 {
+			return nil, fmt.Errorf("expected key = value in inline table at %q", p.text[p.pos:])
+		}
+		key := tomlKey(p.text[p.pos : p.pos+end])
+		p.pos += end + 1
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+
+		p.skip(false)
+		if p.pos == len(p.text) {
+			return nil, errTOMLIncomplete
+		}
+		switch p.text[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+		default:
+			return nil, fmt.Errorf("expected , or } in inline table at %q", p.text[p.pos:])
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Package manifest provides tests for the TOML reader.
+// This file checks tables, arrays of tables and the supported value types.
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestReadTOML tests the tables and values of a TOML document.
+func TestReadTOML(t *testing.T) {
+	data := []byte(`title = "demo" # comment
+count = 3
+
+[tool . "poetry".dependencies]
+requests = { version = "^2.31", extras = ["socks"] }
+"my-lib" = '~1.2'
+
+[[package]]
+name = "idna"
+files = [
+    {file = "idna-3.6.tar.gz", hash = "sha256:abc"}, # sdist
+    {file = "idna-3.6-py3-none-any.whl", hash = "sha256:def"},
+]
+description = """
+Multi-line
+text"""
+
+[[package]]
+name = "six"
+`)
+	expected := []tomlTable{
+		{Values: map[string]any{"title": "demo", "count": tomlLiteral("3")}},
+		{Name: "tool.poetry.dependencies", Values: map[string]any{
+			"requests": map[string]any{"version": "^2.31", "extras": []any{"socks"}},
+			"my-lib":   "~1.2",
+		}},
+		{Name: "package", Values: map[string]any{
+			"name": "idna",
+			"files": []any{
+				map[string]any{"file": "idna-3.6.tar.gz", "hash": "sha256:abc"},
+				map[string]any{"file": "idna-3.6-py3-none-any.whl", "hash": "sha256:def"},
+			},
+			"description": "Multi-line\ntext",
+		}},
+		{Name: "package", Values: map[string]any{"name": "six"}},
+	}
+
+	tables, err := readTOML(data)
+	if err != nil {
+		t.Fatalf("readTOML returned unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(tables, expected) {
+		t.Errorf("readTOML = %#v, expected %#v", tables, expected)
+	}
+
+	invalid := []string{
+		"key\n",
+		"key = [1, 2\n",
+		`key = "unterminated` + "\n",
+		`key = "value" extra` + "\n",
+		"key = {a = 1 b = 2}\n",
+	}
+	for _, document := range invalid {
+		if _, err := readTOML([]byte(document)); err == nil {
+			t.Errorf("readTOML(%q) expected error but got none", document)
+		}
+	}
+}
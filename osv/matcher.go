@@ -0,0 +1,168 @@
+// Package osv provides the affected versions of OSV vulnerability records.
+// This file contains the Matcher, which finds the advisories affecting package
+// versions, and the scan of the versions pinned by lockfiles.
+package osv
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ildyria/version-to-regex/convert"
+	"github.com/ildyria/version-to-regex/manifest"
+)
+
+// MANAGER_ECOSYSTEMS maps the package managers of lockfiles to the OSV ecosystem of
+// their packages.
+var MANAGER_ECOSYSTEMS = map[manifest.Manager]string{
+	manifest.MANAGER_NPM:      "npm",
+	manifest.MANAGER_PIP:      "PyPI",
+	manifest.MANAGER_POETRY:   "PyPI",
+	manifest.MANAGER_COMPOSER: "Packagist",
+	manifest.MANAGER_GO:       "Go",
+	manifest.MANAGER_MAVEN:    ECOSYSTEM_MAVEN,
+	manifest.MANAGER_NUGET:    "NuGet",
+}
+
+// Matcher finds the advisories affecting versions of packages, with one compiled
+// regex per affected package of each advisory.
+type Matcher struct {
+	entries map[string][]matcherEntry
+}
+
+// matcherEntry is the regex of the affected versions of a package in one advisory.
+type matcherEntry struct {
+	advisory *Advisory
+	regex    *regexp.Regexp
+}
+
+// Finding is a locked package version affected by an advisory.
+type Finding struct {
+	Package   string `json:"package"`
+	Version   string `json:"version"`
+	Ecosystem string `json:"ecosystem"`
+	Advisory  string `json:"advisory"`
+	Summary   string `json:"summary,omitempty"`
+}
+
+// String describes the finding, such as "lodash 4.17.20: GHSA-35jh-r3h4-6jhm".
+func (f Finding) String() string {
+	if f.Summary == "" {
+		return fmt.Sprintf("%s %s: %s", f.Package, f.Version, f.Advisory)
+	}
+	return fmt.Sprintf("%s %s: %s (%s)", f.Package, f.Version, f.Advisory, f.Summary)
+}
+
+// NewMatcher compiles the affected versions of advisories.
+//
+// Withdrawn advisories are skipped. Affected packages that cannot be converted, such
+// as those of unsupported ecosystems, are left out of the matcher and reported in the
+// returned errors, so that one record does not hide the others.
+//
+// Parameters:
+//   - advisories: The OSV records to match
+//   - opts: Conversion options; the ecosystem is the one of each package and the
+//     boundary is always anchored
+//
+// Returns:
+//   - *Matcher: The matcher of the converted packages
+//   - []error: Errors of the affected packages that could not be converted
+//
+// Example:
+//
+//	advisories, _ := Load("advisories/npm")
+//	matcher, errs := NewMatcher(advisories, convert.Options{})
+//	ids := matcher.Affects("npm", "lodash", "4.17.20")
+func NewMatcher(advisories []Advisory, opts convert.Options) (*Matcher, []error) {
+	opts.Boundary = convert.BOUNDARY_ANCHORED
+	matcher := &Matcher{entries: make(map[string][]matcherEntry)}
+	var errs []error
+	for i := range advisories {
+		advisory := &advisories[i]
+		if advisory.Withdrawn != "" {
+			continue
+		}
+		for _, affected := range advisory.Affected {
+			regex, err := affected.Regex(opts)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", advisory.ID, err))
+				continue
+			}
+			key := packageKey(affected.Package.BaseEcosystem(), affected.Package.Name)
+			matcher.entries[key] = append(matcher.entries[key], matcherEntry{advisory: advisory, regex: regex})
+		}
+	}
+	return matcher, errs
+}
+
+// Affects returns the advisories affecting a version of a package, in the order they
+// were given to the matcher, each at most once.
+//
+// Parameters:
+//   - ecosystem: The OSV ecosystem of the package, such as npm or PyPI; a release such
+//     as Debian:12 is ignored
+//   - name: The package name, compared as its ecosystem does
+//   - version: The version to check
+//
+// Returns:
+//   - []*Advisory: The advisories whose affected versions include version
+func (m *Matcher) Affects(ecosystem, name, version string) []*Advisory {
+	base, _, _ := strings.Cut(ecosystem, ":")
+	var advisories []*Advisory
+	seen := make(map[*Advisory]bool)
+	for _, entry := range m.entries[packageKey(base, name)] {
+		if !seen[entry.advisory] && entry.regex.MatchString(version) {
+			seen[entry.advisory] = true
+			advisories = append(advisories, entry.advisory)
+		}
+	}
+	return advisories
+}
+
+// Scan checks the versions pinned by a lockfile against the advisories of the matcher.
+//
+// Parameters:
+//   - locked: The versions of a lockfile, as read by manifest.ParseLockfile
+//
+// Returns:
+//   - []Finding: One finding per locked version and advisory affecting it, in the
+//     order of the lockfile
+//
+// Example:
+//
+//	locked, _ := manifest.ParseLockfileFile("package-lock.json")
+//	for _, finding := range matcher.Scan(locked) {
+//		fmt.Println(finding)
+//	}
+func (m *Matcher) Scan(locked []manifest.LockedVersion) []Finding {
+	var findings []Finding
+	for _, lockedVersion := range locked {
+		ecosystem, ok := MANAGER_ECOSYSTEMS[lockedVersion.Manager]
+		if !ok {
+			continue
+		}
+		for _, advisory := range m.Affects(ecosystem, lockedVersion.Package, lockedVersion.Version) {
+			findings = append(findings, Finding{
+				Package:   lockedVersion.Package,
+				Version:   lockedVersion.Version,
+				Ecosystem: ecosystem,
+				Advisory:  advisory.ID,
+				Summary:   advisory.Summary,
+			})
+		}
+	}
+	return findings
+}
+
+// packageKey returns the key under which a package of an ecosystem is found: PyPI
+// names are compared with PEP 503 normalization, NuGet and Packagist names
+// case-insensitively.
+func packageKey(ecosystem, name string) string {
+	switch ecosystem {
+	case "PyPI":
+		name = manifest.NormalizePythonName(name)
+	case "NuGet", "Packagist":
+		name = strings.ToLower(name)
+	}
+	return ecosystem + "/" + name
+}
@@ -0,0 +1,113 @@
+// Package osv provides tests for OSV vulnerability records.
+// This file checks the matching of package versions and the scan of lockfiles.
+package osv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+	"github.com/ildyria/version-to-regex/manifest"
+)
+
+// testAdvisories are the records the matcher tests are run against.
+var testAdvisories = []Advisory{
+	{
+		ID:      "GHSA-35jh-r3h4-6jhm",
+		Summary: "Command injection in lodash",
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "npm", Name: "lodash"},
+			Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}, {Fixed: "4.17.21"}}}},
+		}},
+	},
+	{
+		ID: "PYSEC-2023-100",
+		Affected: []Affected{
+			{
+				Package: Package{Ecosystem: "PyPI", Name: "Django"},
+				Ranges:  []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{{Introduced: "4.2"}, {Fixed: "4.2.8"}}}},
+			},
+			{
+				Package:  Package{Ecosystem: "PyPI", Name: "django"},
+				Versions: []string{"4.2.7"},
+			},
+		},
+	},
+	{
+		ID:        "GHSA-withdrawn",
+		Withdrawn: "2024-01-01T00:00:00Z",
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "npm", Name: "lodash"},
+			Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}}}},
+		}},
+	},
+	{
+		ID: "ALPINE-1",
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "Alpine:v3.18", Name: "openssl"},
+			Ranges:  []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{{Introduced: "0"}, {Fixed: "3.1.4-r0"}}}},
+		}},
+	},
+}
+
+// TestMatcherAffects tests the advisories found for package versions.
+func TestMatcherAffects(t *testing.T) {
+	matcher, errs := NewMatcher(testAdvisories, convert.Options{Boundary: convert.BOUNDARY_NONE})
+	if len(errs) != 1 {
+		t.Errorf("NewMatcher errors = %v, expected the Alpine record only", errs)
+	}
+
+	tests := []struct {
+		ecosystem string
+		name      string
+		version   string
+		expected  []string
+	}{
+		{"npm", "lodash", "4.17.20", []string{"GHSA-35jh-r3h4-6jhm"}},
+		{"npm", "lodash", "4.17.21", nil},
+		{"npm", "Lodash", "4.17.20", nil},
+		{"npm", "lodash", "14.17.20", nil},
+		{"PyPI", "django", "4.2.7", []string{"PYSEC-2023-100"}},
+		{"PyPI", "Django", "4.2.1", []string{"PYSEC-2023-100"}},
+		{"PyPI:3", "DJANGO", "4.2", []string{"PYSEC-2023-100"}},
+		{"PyPI", "django", "4.2.8", nil},
+		{"Go", "lodash", "4.17.20", nil},
+	}
+
+	for _, tt := range tests {
+		var ids []string
+		for _, advisory := range matcher.Affects(tt.ecosystem, tt.name, tt.version) {
+			ids = append(ids, advisory.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.expected) {
+			t.Errorf("Affects(%q, %q, %q) = %v, expected %v", tt.ecosystem, tt.name, tt.version, ids, tt.expected)
+		}
+	}
+}
+
+// TestMatcherScan tests the findings of locked versions.
+func TestMatcherScan(t *testing.T) {
+	matcher, _ := NewMatcher(testAdvisories, convert.Options{})
+	locked := []manifest.LockedVersion{
+		{Package: "lodash", Version: "4.17.15", Manager: manifest.MANAGER_NPM},
+		{Package: "react", Version: "18.2.0", Manager: manifest.MANAGER_NPM},
+		{Package: "django", Version: "4.2.7", Manager: manifest.MANAGER_POETRY},
+		{Package: "Django", Version: "5.0", Manager: manifest.MANAGER_PIP},
+	}
+
+	findings := matcher.Scan(locked)
+	expected := []Finding{
+		{Package: "lodash", Version: "4.17.15", Ecosystem: "npm", Advisory: "GHSA-35jh-r3h4-6jhm", Summary: "Command injection in lodash"},
+		{Package: "django", Version: "4.2.7", Ecosystem: "PyPI", Advisory: "PYSEC-2023-100"},
+	}
+	if !reflect.DeepEqual(findings, expected) {
+		t.Errorf("Scan = %+v, expected %+v", findings, expected)
+	}
+
+	if got := findings[0].String(); got != "lodash 4.17.15: GHSA-35jh-r3h4-6jhm (Command injection in lodash)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := findings[1].String(); got != "django 4.2.7: PYSEC-2023-100" {
+		t.Errorf("String() = %q", got)
+	}
+}
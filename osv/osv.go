@@ -0,0 +1,417 @@
+// Package osv provides the affected versions of OSV vulnerability records.
+// This file contains the OSV record types, the loaders of single records and of
+// mirrored advisory dumps, and the conversion of affected ranges to regexes with the
+// range generators of the convert package.
+//
+// OSV records (https://ossf.github.io/osv-schema/) list the affected versions of
+// each package as ranges of introduced, fixed, last_affected and limit events, and as
+// explicit versions. Versions are compared with the ordering of the package
+// ecosystem, which is mapped to a convert ecosystem:
+//
+//   - npm, Go, NuGet, Hex, Pub and SEMVER ranges: SemVer (convert.ECOSYSTEM_AUTO)
+//   - crates.io: Cargo
+//   - PyPI: PEP 440
+//   - RubyGems: RubyGems
+//   - Packagist: Composer
+//   - Debian and Ubuntu: Debian
+//   - Red Hat, AlmaLinux, Rocky Linux, openSUSE and SUSE: RPM
+//   - Maven: Maven range sets
+//
+// GIT ranges name commits instead of versions and are ignored.
+package osv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// Range types of OSV records
+const (
+	// RANGE_SEMVER ranges hold SemVer 2.0 versions
+	RANGE_SEMVER = "SEMVER"
+	// RANGE_ECOSYSTEM ranges hold versions ordered by the package ecosystem
+	RANGE_ECOSYSTEM = "ECOSYSTEM"
+	// RANGE_GIT ranges hold commit hashes
+	RANGE_GIT = "GIT"
+)
+
+// ECOSYSTEM_MAVEN is the OSV ecosystem of Maven packages, which are converted to
+// Maven range sets rather than intervals
+const ECOSYSTEM_MAVEN = "Maven"
+
+// ECOSYSTEMS maps OSV ecosystems to the convert ecosystem ordering their versions.
+var ECOSYSTEMS = map[string]convert.Ecosystem{
+	"npm":         convert.ECOSYSTEM_AUTO,
+	"Go":          convert.ECOSYSTEM_AUTO,
+	"NuGet":       convert.ECOSYSTEM_AUTO,
+	"Hex":         convert.ECOSYSTEM_AUTO,
+	"Pub":         convert.ECOSYSTEM_AUTO,
+	"crates.io":   convert.ECOSYSTEM_CARGO,
+	"PyPI":        convert.ECOSYSTEM_PYPI,
+	"RubyGems":    convert.ECOSYSTEM_RUBY,
+	"Packagist":   convert.ECOSYSTEM_COMPOSER,
+	"Debian":      convert.ECOSYSTEM_DEBIAN,
+	"Ubuntu":      convert.ECOSYSTEM_DEBIAN,
+	"Red Hat":     convert.ECOSYSTEM_RPM,
+	"AlmaLinux":   convert.ECOSYSTEM_RPM,
+	"Rocky Linux": convert.ECOSYSTEM_RPM,
+	"openSUSE":    convert.ECOSYSTEM_RPM,
+	"SUSE":        convert.ECOSYSTEM_RPM,
+}
+
+// Advisory is an OSV vulnerability record.
+type Advisory struct {
+	ID        string     `json:"id"`
+	Summary   string     `json:"summary,omitempty"`
+	Aliases   []string   `json:"aliases,omitempty"`
+	Withdrawn string     `json:"withdrawn,omitempty"`
+	Affected  []Affected `json:"affected"`
+}
+
+// Affected lists the affected versions of one package.
+type Affected struct {
+	Package  Package  `json:"package"`
+	Ranges   []Range  `json:"ranges,omitempty"`
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Package identifies a package in an OSV ecosystem.
+type Package struct {
+	// Ecosystem is the OSV ecosystem, optionally followed by a release such as Debian:12
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range is a list of events delimiting affected versions.
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event is one event of a range; exactly one field is set.
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// BaseEcosystem returns the ecosystem without its release: Debian for Debian:12.
+func (p Package) BaseEcosystem() string {
+	base, _, _ := strings.Cut(p.Ecosystem, ":")
+	return base
+}
+
+// Parse reads OSV records from JSON data holding a single record or an array of
+// records, as found in the files of advisory dumps.
+//
+// Parameters:
+//   - data: The JSON content
+//
+// Returns:
+//   - []Advisory: The records
+//   - error: Error if the data is not an OSV record or array of records
+func Parse(data []byte) ([]Advisory, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var advisories []Advisory
+		if err := json.Unmarshal(data, &advisories); err != nil {
+			return nil, fmt.Errorf("failed to parse OSV records: %w", err)
+		}
+		return advisories, nil
+	}
+
+	var advisory Advisory
+	if err := json.Unmarshal(data, &advisory); err != nil {
+		return nil, fmt.Errorf("failed to parse OSV record: %w", err)
+	}
+	if advisory.ID == "" {
+		return nil, fmt.Errorf("OSV record without id")
+	}
+	return []Advisory{advisory}, nil
+}
+
+// ParseFile reads the OSV records of the JSON file at path, as Parse does.
+func ParseFile(path string) ([]Advisory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	advisories, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return advisories, nil
+}
+
+// Load reads the OSV records of files and of the .json files found under
+// directories, such as an unpacked mirror of an OSV ecosystem dump.
+//
+// Parameters:
+//   - paths: The files and directories to read
+//
+// Returns:
+//   - []Advisory: The records, in the order of the paths, directories in lexical order
+//   - error: Error if a file cannot be read or is not an OSV record
+func Load(paths ...string) ([]Advisory, error) {
+	var advisories []Advisory
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			fileAdvisories, err := ParseFile(path)
+			if err != nil {
+				return nil, err
+			}
+			advisories = append(advisories, fileAdvisories...)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || filepath.Ext(file) != ".json" {
+				return err
+			}
+			fileAdvisories, err := ParseFile(file)
+			if err != nil {
+				return err
+			}
+			advisories = append(advisories, fileAdvisories...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return advisories, nil
+}
+
+// Ecosystem returns the convert ecosystem ordering the versions of the package. Maven
+// packages and packages of unknown ecosystems whose ranges are all SemVer or git
+// ranges use convert.ECOSYSTEM_AUTO.
+func (a Affected) Ecosystem() (convert.Ecosystem, error) {
+	base := a.Package.BaseEcosystem()
+	if ecosystem, ok := ECOSYSTEMS[base]; ok || base == ECOSYSTEM_MAVEN {
+		return ecosystem, nil
+	}
+	for _, r := range a.Ranges {
+		if r.Type != RANGE_SEMVER && r.Type != RANGE_GIT {
+			return "", fmt.Errorf("unsupported OSV ecosystem: %s", a.Package.Ecosystem)
+		}
+	}
+	if len(a.Versions) > 0 {
+		return "", fmt.Errorf("unsupported OSV ecosystem: %s", a.Package.Ecosystem)
+	}
+	return convert.ECOSYSTEM_AUTO, nil
+}
+
+// Intervals returns the affected versions as intervals: one per introduced event of
+// the version ranges, up to the next fixed (excluded) or last_affected (included)
+// event, and one per explicit version.
+//
+// Events are sorted by version, with the ordering of SemVer for SEMVER ranges and of
+// the package ecosystem for ECOSYSTEM ranges, as the schema does not require records
+// to list them in order. An introduced version of "0" starts before every version, an
+// introduced event without end affects every later version below the limit event of
+// its range, if any. GIT ranges are ignored.
+//
+// Example:
+//
+//	// introduced 0, fixed 1.2.5, introduced 2.0.0, last_affected 2.0.3
+//	// gives (, 1.2.5) and [2.0.0, 2.0.3]
+func (a Affected) Intervals() ([]convert.VersionInterval, error) {
+	var intervals []convert.VersionInterval
+	for _, r := range a.Ranges {
+		switch r.Type {
+		case RANGE_GIT:
+			continue
+		case RANGE_SEMVER, RANGE_ECOSYSTEM:
+		default:
+			return nil, fmt.Errorf("unsupported OSV range type: %s", r.Type)
+		}
+
+		limit := ""
+		for _, event := range r.Events {
+			if event.Limit != "" {
+				limit = event.Limit
+			}
+		}
+
+		events, err := a.sortEvents(r)
+		if err != nil {
+			return nil, err
+		}
+
+		var current *convert.VersionInterval
+		for _, event := range events {
+			switch {
+			case event.Introduced != "":
+				if current != nil {
+					return nil, fmt.Errorf("introduced %s follows introduced %s without end", event.Introduced, current.Lower)
+				}
+				current = &convert.VersionInterval{LowerInclusive: true}
+				if event.Introduced != "0" {
+					current.Lower = event.Introduced
+				}
+			case event.Fixed != "" || event.LastAffected != "":
+				if current == nil {
+					return nil, fmt.Errorf("range end without introduced event")
+				}
+				current.Upper = event.Fixed + event.LastAffected
+				current.UpperInclusive = event.LastAffected != ""
+				intervals = append(intervals, *current)
+				current = nil
+			}
+		}
+		if current != nil {
+			current.Upper = limit
+			intervals = append(intervals, *current)
+		}
+	}
+
+	for _, version := range a.Versions {
+		intervals = append(intervals, convert.VersionInterval{
+			Lower: version, LowerInclusive: true,
+			Upper: version, UpperInclusive: true,
+		})
+	}
+	return intervals, nil
+}
+
+// sortEvents returns the introduced, fixed and last_affected events of r sorted by
+// version, the introduced version "0" first. Events of equal versions keep the order of
+// the record.
+func (a Affected) sortEvents(r Range) ([]Event, error) {
+	compare, err := a.versionOrder(r.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	for _, event := range r.Events {
+		if event.Limit == "" {
+			events = append(events, event)
+		}
+	}
+
+	var sortErr error
+	sort.SliceStable(events, func(i, j int) bool {
+		x, y := events[i], events[j]
+		if x.Introduced == "0" || y.Introduced == "0" {
+			return x.Introduced == "0" && y.Introduced != "0"
+		}
+		c, err := compare(x.version(), y.version())
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return c < 0
+	})
+	if sortErr != nil {
+		return nil, fmt.Errorf("failed to sort OSV events: %w", sortErr)
+	}
+	return events, nil
+}
+
+// versionOrder returns the comparison of the versions of ranges of type rangeType:
+// SemVer for SEMVER ranges, the ordering of the package ecosystem for ECOSYSTEM ranges.
+func (a Affected) versionOrder(rangeType string) (func(x, y string) (int, error), error) {
+	if rangeType == RANGE_ECOSYSTEM && a.Package.BaseEcosystem() == ECOSYSTEM_MAVEN {
+		return convert.CompareMavenVersions, nil
+	}
+
+	opts := convert.Options{Ecosystem: convert.ECOSYSTEM_AUTO}
+	if rangeType == RANGE_ECOSYSTEM {
+		ecosystem, err := a.Ecosystem()
+		if err != nil {
+			return nil, err
+		}
+		opts.Ecosystem = ecosystem
+	}
+	return func(x, y string) (int, error) {
+		return convert.CompareVersions(x, y, opts)
+	}, nil
+}
+
+// version returns the version of the event, whichever field is set.
+func (e Event) version() string {
+	return e.Introduced + e.Fixed + e.LastAffected + e.Limit
+}
+
+// Pattern converts the affected versions to a pattern in the syntax of dialect. The
+// ecosystem of opts is replaced by the one of the package.
+//
+// Parameters:
+//   - dialect: The regex syntax of the pattern
+//   - opts: Conversion options, such as the boundary
+//
+// Returns:
+//   - string: Pattern matching the affected versions
+//   - error: Error if the ecosystem is not supported or a version cannot be parsed
+//
+// Example:
+//
+//	advisories, _ := ParseFile("GHSA-xxxx-xxxx-xxxx.json")
+//	pattern, err := advisories[0].Affected[0].Pattern(convert.DIALECT_GO, convert.Options{})
+func (a Affected) Pattern(dialect convert.Dialect, opts convert.Options) (string, error) {
+	ecosystem, err := a.Ecosystem()
+	if err != nil {
+		return "", err
+	}
+	intervals, err := a.Intervals()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", a.Package.Name, err)
+	}
+	opts.Ecosystem = ecosystem
+
+	var pattern string
+	if a.Package.BaseEcosystem() == ECOSYSTEM_MAVEN && len(intervals) > 0 {
+		pattern, err = convert.Pattern(mavenRangeSet(intervals), dialect, opts)
+	} else {
+		pattern, err = convert.IntervalsPattern(intervals, dialect, opts)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", a.Package.Name, err)
+	}
+	return pattern, nil
+}
+
+// Regex converts the affected versions to a compiled regex. The ecosystem of opts is
+// replaced by the one of the package.
+func (a Affected) Regex(opts convert.Options) (*regexp.Regexp, error) {
+	pattern, err := a.Pattern(convert.DIALECT_GO, opts)
+	if err != nil {
+		return nil, err
+	}
+	return regexp.Compile(pattern)
+}
+
+// mavenRangeSet writes intervals as a Maven range set such as "(,1.2.5),[2.0.0,2.0.3]",
+// exact versions as [v].
+func mavenRangeSet(intervals []convert.VersionInterval) string {
+	ranges := make([]string, len(intervals))
+	for i, interval := range intervals {
+		if interval.Lower != "" && interval.Lower == interval.Upper && interval.LowerInclusive && interval.UpperInclusive {
+			ranges[i] = "[" + interval.Lower + "]"
+			continue
+		}
+		open, end := "(", ")"
+		if interval.LowerInclusive && interval.Lower != "" {
+			open = "["
+		}
+		if interval.UpperInclusive && interval.Upper != "" {
+			end = "]"
+		}
+		ranges[i] = open + interval.Lower + "," + interval.Upper + end
+	}
+	return strings.Join(ranges, ",")
+}
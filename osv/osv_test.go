@@ -0,0 +1,306 @@
+// Package osv provides tests for OSV vulnerability records.
+// This file checks the loaders of records and the conversion of affected ranges.
+package osv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/ildyria/version-to-regex/convert"
+)
+
+// TestParse tests that single records and arrays of records are read.
+func TestParse(t *testing.T) {
+	record := `{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "summary": "Command injection in lodash",
+  "aliases": ["CVE-2021-23337"],
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+  }]
+}`
+	advisories, err := Parse([]byte(record))
+	if err != nil {
+		t.Fatalf("Parse returned unexpected error: %v", err)
+	}
+	expected := []Advisory{{
+		ID:      "GHSA-35jh-r3h4-6jhm",
+		Summary: "Command injection in lodash",
+		Aliases: []string{"CVE-2021-23337"},
+		Affected: []Affected{{
+			Package: Package{Ecosystem: "npm", Name: "lodash"},
+			Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}, {Fixed: "4.17.21"}}}},
+		}},
+	}}
+	if !reflect.DeepEqual(advisories, expected) {
+		t.Errorf("Parse = %+v, expected %+v", advisories, expected)
+	}
+
+	advisories, err = Parse([]byte(`[{"id": "A"}, {"id": "B"}]`))
+	if err != nil || len(advisories) != 2 {
+		t.Errorf("Parse of an array = %+v, %v, expected 2 records", advisories, err)
+	}
+
+	for _, data := range []string{`{"summary": "no id"}`, `not json`, `[{"id": 1}]`} {
+		if _, err := Parse([]byte(data)); err == nil {
+			t.Errorf("Parse(%q) expected error but got none", data)
+		}
+	}
+}
+
+// TestLoad tests that files and directories of records are read.
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"npm/GHSA-1.json":   `{"id": "GHSA-1"}`,
+		"npm/GHSA-2.json":   `{"id": "GHSA-2"}`,
+		"PyPI/PYSEC-1.json": `{"id": "PYSEC-1"}`,
+		"README.md":         `not a record`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	advisories, err := Load(filepath.Join(dir, "npm", "GHSA-2.json"), dir)
+	if err != nil {
+		t.Fatalf("Load returned unexpected error: %v", err)
+	}
+	var ids []string
+	for _, advisory := range advisories {
+		ids = append(ids, advisory.ID)
+	}
+	expected := []string{"GHSA-2", "PYSEC-1", "GHSA-1", "GHSA-2"}
+	if !reflect.DeepEqual(ids, expected) {
+		t.Errorf("Load = %v, expected %v", ids, expected)
+	}
+
+	if _, err := Load(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("Load of a missing path expected error but got none")
+	}
+}
+
+// TestIntervals tests the intervals of range events and explicit versions.
+func TestIntervals(t *testing.T) {
+	tests := []struct {
+		name     string
+		affected Affected
+		expected []convert.VersionInterval
+	}{
+		{
+			"introduced 0",
+			Affected{Ranges: []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}, {Fixed: "1.2.5"}}}}},
+			[]convert.VersionInterval{{LowerInclusive: true, Upper: "1.2.5"}},
+		},
+		{
+			"several intervals",
+			Affected{Package: Package{Ecosystem: "npm"}, Ranges: []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{
+				{Introduced: "1.0.0"}, {Fixed: "1.2.5"}, {Introduced: "2.0.0"}, {LastAffected: "2.0.3"},
+			}}}},
+			[]convert.VersionInterval{
+				{Lower: "1.0.0", LowerInclusive: true, Upper: "1.2.5"},
+				{Lower: "2.0.0", LowerInclusive: true, Upper: "2.0.3", UpperInclusive: true},
+			},
+		},
+		{
+			"open-ended",
+			Affected{Ranges: []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "3.0.0"}}}}},
+			[]convert.VersionInterval{{Lower: "3.0.0", LowerInclusive: true}},
+		},
+		{
+			"limit",
+			Affected{Package: Package{Ecosystem: "npm"}, Ranges: []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{
+				{Limit: "3.5.0"}, {Introduced: "3.0.0"},
+			}}}},
+			[]convert.VersionInterval{{Lower: "3.0.0", LowerInclusive: true, Upper: "3.5.0"}},
+		},
+		{
+			"events out of order",
+			Affected{Ranges: []Range{{Type: RANGE_SEMVER, Events: []Event{
+				{Fixed: "2.0.0"}, {Introduced: "1.5.0"}, {Fixed: "1.2.5"}, {Introduced: "0"},
+			}}}},
+			[]convert.VersionInterval{
+				{LowerInclusive: true, Upper: "1.2.5"},
+				{Lower: "1.5.0", LowerInclusive: true, Upper: "2.0.0"},
+			},
+		},
+		{
+			"events in the order of the ecosystem",
+			Affected{Package: Package{Ecosystem: "PyPI"}, Ranges: []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{
+				{Fixed: "2.0"}, {Introduced: "2.0rc1"}, {Fixed: "2.0b2"}, {Introduced: "2.0.dev1"},
+			}}}},
+			[]convert.VersionInterval{
+				{Lower: "2.0.dev1", LowerInclusive: true, Upper: "2.0b2"},
+				{Lower: "2.0rc1", LowerInclusive: true, Upper: "2.0"},
+			},
+		},
+		{
+			"git ranges and versions",
+			Affected{
+				Ranges:   []Range{{Type: RANGE_GIT, Events: []Event{{Introduced: "0"}, {Fixed: "abc123"}}}},
+				Versions: []string{"1.0.1"},
+			},
+			[]convert.VersionInterval{{Lower: "1.0.1", LowerInclusive: true, Upper: "1.0.1", UpperInclusive: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intervals, err := tt.affected.Intervals()
+			if err != nil {
+				t.Fatalf("Intervals returned unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(intervals, tt.expected) {
+				t.Errorf("Intervals = %+v, expected %+v", intervals, tt.expected)
+			}
+		})
+	}
+
+	for _, r := range []Range{
+		{Type: RANGE_SEMVER, Events: []Event{{Fixed: "1.0.0"}}},
+		{Type: RANGE_SEMVER, Events: []Event{{Introduced: "1.0.0"}, {Introduced: "2.0.0"}}},
+		{Type: "UNKNOWN", Events: []Event{{Introduced: "0"}}},
+		{Type: RANGE_SEMVER, Events: []Event{{Introduced: "1.0.0"}, {Fixed: "not-a-version"}}},
+		{Type: RANGE_ECOSYSTEM, Events: []Event{{Introduced: "1.0.0"}, {Fixed: "2.0.0"}}},
+	} {
+		if _, err := (Affected{Ranges: []Range{r}}).Intervals(); err == nil {
+			t.Errorf("Intervals(%+v) expected error but got none", r)
+		}
+	}
+}
+
+// TestAffectedRegex tests that affected versions are ordered as their ecosystem does.
+func TestAffectedRegex(t *testing.T) {
+	tests := []struct {
+		name           string
+		affected       Affected
+		shouldMatch    []string
+		shouldNotMatch []string
+	}{
+		{
+			"npm",
+			Affected{
+				Package: Package{Ecosystem: "npm", Name: "lodash"},
+				Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}, {Fixed: "4.17.21"}}}},
+			},
+			[]string{"4.17.20", "4.17.21-beta.1", "1.0.0"},
+			[]string{"4.17.21", "5.0.0"},
+		},
+		{
+			"Go",
+			Affected{
+				Package: Package{Ecosystem: "Go", Name: "golang.org/x/net"},
+				Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}, {Fixed: "0.17.0"}}}},
+			},
+			[]string{"v0.16.0", "v0.0.0-20230101000000-abcdefabcdef"},
+			[]string{"v0.17.0", "v1.0.0"},
+		},
+		{
+			"PyPI",
+			Affected{
+				Package: Package{Ecosystem: "PyPI", Name: "Django"},
+				Ranges: []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{
+					{Introduced: "4.2"}, {Fixed: "4.2.8"}, {Introduced: "5.0a1"}, {Fixed: "5.0"},
+				}}},
+			},
+			[]string{"4.2", "4.2.7", "4.2.7+ubuntu1", "4.2.8.dev1", "5.0a1", "5.0rc1"},
+			[]string{"4.1.9", "4.2.8", "4.2.8+ubuntu1", "5.0.dev1", "5.0", "5.0.post1", "5.0.1"},
+		},
+		{
+			"crates.io",
+			Affected{
+				Package: Package{Ecosystem: "crates.io", Name: "time"},
+				Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0.2.23"}, {Fixed: "0.2.24"}}}},
+			},
+			[]string{"0.2.23"},
+			[]string{"0.2.24", "0.2"},
+		},
+		{
+			"Packagist",
+			Affected{
+				Package: Package{Ecosystem: "Packagist", Name: "symfony/http-kernel"},
+				Ranges:  []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{{Introduced: "5.0.0"}, {Fixed: "5.4.20"}}}},
+			},
+			[]string{"v5.4.19", "5.0.0", "5.4.20-RC1"},
+			[]string{"5.4.20", "4.4.0"},
+		},
+		{
+			"Debian",
+			Affected{
+				Package: Package{Ecosystem: "Debian:12", Name: "openssl"},
+				Ranges:  []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{{Introduced: "0"}, {Fixed: "3.0.11-1~deb12u2"}}}},
+			},
+			[]string{"3.0.11-1~deb12u1", "3.0.9-1"},
+			[]string{"3.0.11-1~deb12u2", "3.0.11-1"},
+		},
+		{
+			"Maven",
+			Affected{
+				Package: Package{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core"},
+				Ranges: []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{
+					{Introduced: "2.0-beta9"}, {Fixed: "2.15.0"},
+				}}},
+				Versions: []string{"2.16.0"},
+			},
+			[]string{"2.14.1", "2.0", "2.16.0"},
+			[]string{"2.15.0", "2.17.0", "1.2.17"},
+		},
+		{
+			"unknown ecosystem with SemVer ranges",
+			Affected{
+				Package: Package{Ecosystem: "GitHub Actions", Name: "tj-actions/changed-files"},
+				Ranges:  []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "0"}, {Fixed: "46.0.1"}}}},
+			},
+			[]string{"45.0.0"},
+			[]string{"46.0.1"},
+		},
+		{
+			"no ranges",
+			Affected{Package: Package{Ecosystem: "npm", Name: "left-pad"}},
+			nil,
+			[]string{"1.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			regex, err := tt.affected.Regex(convert.Options{})
+			if err != nil {
+				t.Fatalf("Regex returned unexpected error: %v", err)
+			}
+			for _, s := range tt.shouldMatch {
+				if !regex.MatchString(s) {
+					t.Errorf("regex %q should match %q", regex, s)
+				}
+			}
+			for _, s := range tt.shouldNotMatch {
+				if regex.MatchString(s) {
+					t.Errorf("regex %q should not match %q", regex, s)
+				}
+			}
+		})
+	}
+}
+
+// TestAffectedRegexErrors tests the affected packages that cannot be converted.
+func TestAffectedRegexErrors(t *testing.T) {
+	tests := []Affected{
+		{Package: Package{Ecosystem: "Alpine:v3.18", Name: "openssl"}, Ranges: []Range{{Type: RANGE_ECOSYSTEM, Events: []Event{{Introduced: "0"}}}}},
+		{Package: Package{Ecosystem: "OSS-Fuzz", Name: "libxml2"}, Versions: []string{"v2.9.0"}},
+		{Package: Package{Ecosystem: "npm", Name: "lodash"}, Ranges: []Range{{Type: RANGE_SEMVER, Events: []Event{{Introduced: "latest"}}}}},
+	}
+
+	for _, affected := range tests {
+		if _, err := affected.Regex(convert.Options{}); err == nil {
+			t.Errorf("Regex(%+v) expected error but got none", affected)
+		}
+	}
+}